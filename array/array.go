@@ -3,6 +3,7 @@ package array
 import (
 	"github.com/apache/arrow/go/arrow"
 	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
 )
@@ -168,6 +169,38 @@ func (a *String) ValueLen(i int) int {
 	return len(a.value)
 }
 
+// NewStringData constructs a String from arrow data
+// with the String data type.
+func NewStringData(data *array.Data) *String {
+	return &String{
+		data: array.NewBinaryData(data),
+	}
+}
+
+// Data returns the arrow data that holds the values of the array.
+// If the array repeats a single value, the data is built with mem.
+// The returned data must be released.
+func (a *String) Data(mem memory.Allocator) *array.Data {
+	if a.data != nil {
+		data := a.data.Data()
+		data.Retain()
+		return data
+	}
+	b := array.NewBinaryBuilder(mem, StringType)
+	defer b.Release()
+	b.Reserve(a.length)
+	b.ReserveData(a.length * len(a.value))
+	for i := 0; i < a.length; i++ {
+		b.AppendString(a.value)
+	}
+	arr := b.NewBinaryArray()
+	defer arr.Release()
+
+	data := arr.Data()
+	data.Retain()
+	return data
+}
+
 type sliceable interface {
 	Slice(i, j int) Interface
 }
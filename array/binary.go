@@ -0,0 +1,77 @@
+package array
+
+import (
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// BinaryType is the data type of the bytes arrays.
+var BinaryType = arrow.BinaryTypes.Binary
+
+type Binary = array.Binary
+
+type BinaryBuilder struct {
+	b *array.BinaryBuilder
+}
+
+func NewBinaryBuilder(mem memory.Allocator) *BinaryBuilder {
+	return &BinaryBuilder{
+		b: array.NewBinaryBuilder(mem, BinaryType),
+	}
+}
+func (b *BinaryBuilder) Retain() {
+	b.b.Retain()
+}
+func (b *BinaryBuilder) Release() {
+	b.b.Release()
+}
+func (b *BinaryBuilder) Len() int {
+	return b.b.Len()
+}
+func (b *BinaryBuilder) Cap() int {
+	return b.b.Cap()
+}
+func (b *BinaryBuilder) Append(v []byte) {
+	b.b.Append(v)
+}
+func (b *BinaryBuilder) AppendValues(v [][]byte, valid []bool) {
+	b.b.AppendValues(v, valid)
+}
+func (b *BinaryBuilder) NullN() int {
+	return b.b.NullN()
+}
+func (b *BinaryBuilder) AppendNull() {
+	b.b.AppendNull()
+}
+func (b *BinaryBuilder) Reserve(n int) {
+	b.b.Reserve(n)
+}
+func (b *BinaryBuilder) ReserveData(n int) {
+	b.b.ReserveData(n)
+}
+func (b *BinaryBuilder) Resize(n int) {
+	b.b.Resize(n)
+}
+func (b *BinaryBuilder) NewArray() Interface {
+	return b.NewBinaryArray()
+}
+func (b *BinaryBuilder) NewBinaryArray() *Binary {
+	return b.b.NewBinaryArray()
+}
+
+func BinaryRepeat(v []byte, isNull bool, n int, mem memory.Allocator) *Binary {
+	b := NewBinaryBuilder(mem)
+	b.Resize(n)
+	if isNull {
+		for i := 0; i < n; i++ {
+			b.AppendNull()
+		}
+	} else {
+		b.ReserveData(n * len(v))
+		for i := 0; i < n; i++ {
+			b.Append(v)
+		}
+	}
+	return b.NewBinaryArray()
+}
@@ -0,0 +1,81 @@
+package array
+
+import (
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/decimal128"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// DecimalType is the data type of the decimal arrays.
+// The precision and scale are the same as values.Decimal.
+var DecimalType = &arrow.Decimal128Type{Precision: 38, Scale: 9}
+
+type Decimal = array.Decimal128
+
+type DecimalBuilder struct {
+	b *array.Decimal128Builder
+}
+
+func NewDecimalBuilder(mem memory.Allocator) *DecimalBuilder {
+	return &DecimalBuilder{
+		b: array.NewDecimal128Builder(mem, DecimalType),
+	}
+}
+func (b *DecimalBuilder) Retain() {
+	b.b.Retain()
+}
+func (b *DecimalBuilder) Release() {
+	b.b.Release()
+}
+func (b *DecimalBuilder) Len() int {
+	return b.b.Len()
+}
+func (b *DecimalBuilder) Cap() int {
+	return b.b.Cap()
+}
+func (b *DecimalBuilder) Append(v decimal128.Num) {
+	b.b.Append(v)
+}
+func (b *DecimalBuilder) AppendValues(v []decimal128.Num, valid []bool) {
+	b.b.AppendValues(v, valid)
+}
+func (b *DecimalBuilder) UnsafeAppend(v decimal128.Num) {
+	b.b.UnsafeAppend(v)
+}
+func (b *DecimalBuilder) NullN() int {
+	return b.b.NullN()
+}
+func (b *DecimalBuilder) AppendNull() {
+	b.b.AppendNull()
+}
+func (b *DecimalBuilder) UnsafeAppendBoolToBitmap(isValid bool) {
+	b.b.UnsafeAppendBoolToBitmap(isValid)
+}
+func (b *DecimalBuilder) Reserve(n int) {
+	b.b.Reserve(n)
+}
+func (b *DecimalBuilder) Resize(n int) {
+	b.b.Resize(n)
+}
+func (b *DecimalBuilder) NewArray() Interface {
+	return b.NewDecimalArray()
+}
+func (b *DecimalBuilder) NewDecimalArray() *Decimal {
+	return b.b.NewDecimal128Array()
+}
+
+func DecimalRepeat(v decimal128.Num, isNull bool, n int, mem memory.Allocator) *Decimal {
+	b := NewDecimalBuilder(mem)
+	b.Resize(n)
+	if isNull {
+		for i := 0; i < n; i++ {
+			b.AppendNull()
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			b.Append(v)
+		}
+	}
+	return b.NewDecimalArray()
+}
@@ -0,0 +1,26 @@
+package arrow
+
+import (
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+func NewDecimal(vs []values.Decimal, alloc *memory.Allocator) *array.Decimal {
+	b := NewDecimalBuilder(alloc)
+	b.Resize(len(vs))
+	for _, v := range vs {
+		b.UnsafeAppend(v.Num())
+	}
+	a := b.NewDecimalArray()
+	b.Release()
+	return a
+}
+
+func DecimalSlice(arr *array.Decimal, i, j int) *array.Decimal {
+	return Slice(arr, int64(i), int64(j)).(*array.Decimal)
+}
+
+func NewDecimalBuilder(a *memory.Allocator) *array.DecimalBuilder {
+	return array.NewDecimalBuilder(a)
+}
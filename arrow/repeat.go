@@ -49,6 +49,25 @@ func Repeat(colType flux.ColType, v values.Value, n int, mem memory.Allocator) a
 			tval = v.Time()
 		}
 		return array.IntRepeat(int64(tval), v.IsNull(), n, mem)
+	case flux.TDecimal:
+		var dval values.Decimal
+		if !v.IsNull() {
+			dval = v.Decimal()
+		}
+		return array.DecimalRepeat(dval.Num(), v.IsNull(), n, mem)
+	case flux.TDuration:
+		var nsecs int64
+		if !v.IsNull() {
+			// The durations in a column never have months.
+			nsecs, _ = v.Duration().FixedNanoseconds()
+		}
+		return array.IntRepeat(nsecs, v.IsNull(), n, mem)
+	case flux.TBytes:
+		var bval []byte
+		if !v.IsNull() {
+			bval = v.Bytes()
+		}
+		return array.BinaryRepeat(bval, v.IsNull(), n, mem)
 	default:
 		panic(errors.Newf(codes.Internal, "invalid arrow primitive type: %T", colType))
 	}
@@ -50,6 +50,15 @@ func (t *TableBuffer) Strings(j int) *array.String {
 func (t *TableBuffer) Times(j int) *array.Int {
 	return t.Values[j].(*array.Int)
 }
+func (t *TableBuffer) Decimals(j int) *array.Decimal {
+	return t.Values[j].(*array.Decimal)
+}
+func (t *TableBuffer) Durations(j int) *array.Int {
+	return t.Values[j].(*array.Int)
+}
+func (t *TableBuffer) Bytes(j int) *array.Binary {
+	return t.Values[j].(*array.Binary)
+}
 
 func (t *TableBuffer) Retain() {
 	for _, vs := range t.Values {
@@ -115,7 +124,7 @@ func (t *TableBuffer) Validate() error {
 
 func (t *TableBuffer) checkCol(typ flux.ColType, arr array.Interface) bool {
 	switch typ {
-	case flux.TInt, flux.TTime:
+	case flux.TInt, flux.TTime, flux.TDuration:
 		_, ok := arr.(*array.Int)
 		return ok
 	case flux.TUInt:
@@ -130,6 +139,12 @@ func (t *TableBuffer) checkCol(typ flux.ColType, arr array.Interface) bool {
 	case flux.TBool:
 		_, ok := arr.(*array.Boolean)
 		return ok
+	case flux.TDecimal:
+		_, ok := arr.(*array.Decimal)
+		return ok
+	case flux.TBytes:
+		_, ok := arr.(*array.Binary)
+		return ok
 	default:
 		return false
 	}
@@ -16,7 +16,7 @@ import (
 // column type. The allocator passed in must be non-nil.
 func NewBuilder(typ flux.ColType, mem memory.Allocator) array.Builder {
 	switch typ {
-	case flux.TInt, flux.TTime:
+	case flux.TInt, flux.TTime, flux.TDuration:
 		return array.NewIntBuilder(mem)
 	case flux.TUInt:
 		return array.NewUintBuilder(mem)
@@ -26,6 +26,10 @@ func NewBuilder(typ flux.ColType, mem memory.Allocator) array.Builder {
 		return array.NewStringBuilder(mem)
 	case flux.TBool:
 		return array.NewBooleanBuilder(mem)
+	case flux.TDecimal:
+		return array.NewDecimalBuilder(mem)
+	case flux.TBytes:
+		return array.NewBinaryBuilder(mem)
 	default:
 		panic(fmt.Errorf("unknown builder for type: %s", typ))
 	}
@@ -55,6 +59,12 @@ func AppendValue(b array.Builder, v values.Value) error {
 		return AppendBool(b, v.Bool())
 	case semantic.Time:
 		return AppendTime(b, v.Time())
+	case semantic.Decimal:
+		return AppendDecimal(b, v.Decimal())
+	case semantic.Duration:
+		return AppendDuration(b, v.Duration())
+	case semantic.Bytes:
+		return AppendBytes(b, v.Bytes())
 	default:
 		panic(fmt.Errorf("unknown builder for type: %s", v.Type()))
 	}
@@ -120,6 +130,42 @@ func AppendTime(b array.Builder, v values.Time) error {
 	return nil
 }
 
+// AppendDecimal will append a Decimal value to a compatible builder.
+func AppendDecimal(b array.Builder, v values.Decimal) error {
+	vb, ok := b.(*array.DecimalBuilder)
+	if !ok {
+		return errors.Newf(codes.Internal, "incompatible builder for type %s", flux.TDecimal)
+	}
+	vb.Append(v.Num())
+	return nil
+}
+
+// AppendDuration will append a Duration value to a compatible builder.
+// The duration is stored as a signed number of nanoseconds
+// so it is an error if the duration has months.
+func AppendDuration(b array.Builder, v values.Duration) error {
+	vb, ok := b.(*array.IntBuilder)
+	if !ok {
+		return errors.Newf(codes.Internal, "incompatible builder for type %s", flux.TDuration)
+	}
+	nsecs, err := v.FixedNanoseconds()
+	if err != nil {
+		return err
+	}
+	vb.Append(nsecs)
+	return nil
+}
+
+// AppendBytes will append a bytes value to a compatible builder.
+func AppendBytes(b array.Builder, v []byte) error {
+	vb, ok := b.(*array.BinaryBuilder)
+	if !ok {
+		return errors.Newf(codes.Internal, "incompatible builder for type %s", flux.TBytes)
+	}
+	vb.Append(v)
+	return nil
+}
+
 // Slice will construct a new slice of the array using the given
 // start and stop index. The returned array must be released.
 //
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/runtime"
+	"github.com/spf13/cobra"
+)
+
+// coverCmd represents the cover command
+var coverCmd = &cobra.Command{
+	Use:   "cover <profile>",
+	Short: "Report the coverage of a Flux coverage profile",
+	Long:  "Report the statement and branch coverage of each file in a coverage profile written by flux test --coverprofile, or write it as an HTML report with --html",
+	Args:  cobra.ExactArgs(1),
+	RunE:  reportCoverage,
+}
+
+var (
+	coverHTMLOutput string
+	coverSrcDir     string
+)
+
+func init() {
+	rootCmd.AddCommand(coverCmd)
+	coverCmd.SilenceUsage = true
+	coverCmd.Flags().StringVar(&coverHTMLOutput, "html", "", "write an HTML report that shows the covered source code to the file")
+	coverCmd.Flags().StringVar(&coverSrcDir, "src-dir", ".", "directory containing the sources of the covered packages, used by the HTML report")
+}
+
+func reportCoverage(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	blocks, err := interpreter.ReadCoverProfile(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	if coverHTMLOutput == "" {
+		return writeCoverageText(os.Stdout, blocks)
+	}
+	out, err := os.Create(coverHTMLOutput)
+	if err != nil {
+		return err
+	}
+	if err := writeCoverageHTML(out, coverSrcDir, blocks); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// newTestCoverage creates a coverage that records
+// the evaluations of the packages.
+func newTestCoverage(pkgs []string) (*interpreter.Coverage, error) {
+	coverage := interpreter.NewCoverage()
+	for _, p := range pkgs {
+		pkg, ok := runtime.LookupPackage(p)
+		if !ok {
+			return nil, fmt.Errorf("cannot record coverage of unknown package %q", p)
+		}
+		coverage.Register(p, pkg)
+	}
+	return coverage, nil
+}
+
+// writeCoverProfile writes the coverage blocks to the profile file.
+func writeCoverProfile(filename string, blocks []interpreter.CoverageBlock) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := interpreter.WriteCoverProfile(f, blocks); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// coverageSummary counts the covered statements and branches of a group of blocks.
+type coverageSummary struct {
+	name                      string
+	statements, statementsHit int
+	branches, branchesHit     int
+	blocks                    []interpreter.CoverageBlock
+}
+
+func (s *coverageSummary) add(b interpreter.CoverageBlock) {
+	switch b.Kind {
+	case interpreter.StatementCoverage:
+		s.statements++
+		if b.Count > 0 {
+			s.statementsHit++
+		}
+	case interpreter.BranchCoverage:
+		s.branches++
+		if b.Count > 0 {
+			s.branchesHit++
+		}
+	}
+	s.blocks = append(s.blocks, b)
+}
+
+func (s *coverageSummary) statementPercent() string {
+	return coveragePercent(s.statementsHit, s.statements)
+}
+
+func (s *coverageSummary) branchPercent() string {
+	return coveragePercent(s.branchesHit, s.branches)
+}
+
+func coveragePercent(hit, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(hit)/float64(total))
+}
+
+// summarizeCoverage groups the blocks by the name that
+// the group function returns and summarizes each group.
+func summarizeCoverage(blocks []interpreter.CoverageBlock, group func(b interpreter.CoverageBlock) string) []*coverageSummary {
+	groups := make(map[string]*coverageSummary)
+	var summaries []*coverageSummary
+	for _, b := range blocks {
+		name := group(b)
+		s, ok := groups[name]
+		if !ok {
+			s = &coverageSummary{name: name}
+			groups[name] = s
+			summaries = append(summaries, s)
+		}
+		s.add(b)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].name < summaries[j].name
+	})
+	return summaries
+}
+
+// writeCoverageText writes the statement and branch
+// coverage of each file and of all files together.
+func writeCoverageText(w io.Writer, blocks []interpreter.CoverageBlock) error {
+	var total coverageSummary
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FILE\tSTATEMENTS\tBRANCHES")
+	for _, s := range summarizeCoverage(blocks, func(b interpreter.CoverageBlock) string {
+		return b.File
+	}) {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.name, s.statementPercent(), s.branchPercent())
+		total.statements += s.statements
+		total.statementsHit += s.statementsHit
+		total.branches += s.branches
+		total.branchesHit += s.branchesHit
+	}
+	fmt.Fprintf(tw, "total\t%s\t%s\n", total.statementPercent(), total.branchPercent())
+	return tw.Flush()
+}
+
+// coverageSegment is a piece of source code that
+// is either covered, not covered or not counted.
+type coverageSegment struct {
+	Text  string
+	Class string
+}
+
+type coverageFile struct {
+	Name       string
+	Statements string
+	Branches   string
+	Segments   []coverageSegment
+}
+
+var coverageHTMLTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Flux coverage</title>
+<style>
+body { font-family: sans-serif; }
+pre { font-family: monospace; background: #1e1e1e; color: #9e9e9e; padding: 1em; }
+.covered { color: #2cc12c; }
+.uncovered { color: #e74c3c; }
+</style>
+</head>
+<body>
+{{- range .}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+<p>{{.Statements}} of statements, {{.Branches}} of branches</p>
+<pre>{{range .Segments}}{{if .Class}}<span class="{{.Class}}">{{.Text}}</span>{{else}}{{.Text}}{{end}}{{end}}</pre>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeCoverageHTML writes an HTML report that shows the source code
+// of each file with the covered and the uncovered blocks highlighted.
+// The sources are read from the files within the source directory.
+func writeCoverageHTML(w io.Writer, srcDir string, blocks []interpreter.CoverageBlock) error {
+	var files []coverageFile
+	for _, s := range summarizeCoverage(blocks, func(b interpreter.CoverageBlock) string {
+		return b.File
+	}) {
+		src, err := ioutil.ReadFile(filepath.Join(srcDir, filepath.FromSlash(s.name)))
+		if err != nil {
+			return err
+		}
+		files = append(files, coverageFile{
+			Name:       s.name,
+			Statements: s.statementPercent(),
+			Branches:   s.branchPercent(),
+			Segments:   coverageSegments(string(src), s.blocks),
+		})
+	}
+	return coverageHTMLTemplate.Execute(w, files)
+}
+
+// coverageSegments splits the source into segments that
+// have the class of the innermost block that contains them.
+func coverageSegments(src string, blocks []interpreter.CoverageBlock) []coverageSegment {
+	lines := []int{0}
+	for i, c := range src {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	offset := func(line, column int) int {
+		if line < 1 || line > len(lines) {
+			return len(src)
+		}
+		o := lines[line-1] + column - 1
+		if o < 0 {
+			return 0
+		} else if o > len(src) {
+			return len(src)
+		}
+		return o
+	}
+
+	type span struct {
+		start, end int
+		covered    bool
+	}
+	spans := make([]span, 0, len(blocks))
+	for _, b := range blocks {
+		spans = append(spans, span{
+			start:   offset(b.Start.Line, b.Start.Column),
+			end:     offset(b.End.Line, b.End.Column),
+			covered: b.Count > 0,
+		})
+	}
+	// Paint the largest spans first so the
+	// innermost span decides the class.
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].end-spans[i].start > spans[j].end-spans[j].start
+	})
+	classes := make([]string, len(src))
+	for _, s := range spans {
+		class := "uncovered"
+		if s.covered {
+			class = "covered"
+		}
+		for i := s.start; i < s.end; i++ {
+			classes[i] = class
+		}
+	}
+
+	var segments []coverageSegment
+	var text strings.Builder
+	for i := 0; i < len(src); i++ {
+		if i > 0 && classes[i] != classes[i-1] {
+			segments = append(segments, coverageSegment{Text: text.String(), Class: classes[i-1]})
+			text.Reset()
+		}
+		text.WriteByte(src[i])
+	}
+	if text.Len() > 0 {
+		segments = append(segments, coverageSegment{Text: text.String(), Class: classes[len(src)-1]})
+	}
+	return segments
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+)
+
+func TestCoverPackages(t *testing.T) {
+	file := func(pkg string, imports ...string) *ast.File {
+		f := &ast.File{
+			Package: &ast.PackageClause{Name: &ast.Identifier{Name: pkg}},
+		}
+		for _, imp := range imports {
+			f.Imports = append(f.Imports, &ast.ImportDeclaration{
+				Path: &ast.StringLiteral{Value: imp},
+			})
+		}
+		return f
+	}
+	runner := NewTestRunner(NewTestReporter(0))
+	for _, f := range []*ast.File{
+		file("strings_test", "testing", "strings"),
+		file("date_test", "experimental/date", "date"),
+		file("strings_test", "strings", "array"),
+		file("main", "math"),
+	} {
+		test := NewTest("test", &ast.Package{Files: []*ast.File{f}})
+		runner.tests = append(runner.tests, &test)
+	}
+
+	want := []string{"date", "experimental/date", "strings"}
+	if got := runner.CoverPackages(); !cmp.Equal(want, got) {
+		t.Fatalf("unexpected packages -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func coverageBlocks() []interpreter.CoverageBlock {
+	return []interpreter.CoverageBlock{
+		{
+			File:  "a/a.flux",
+			Start: ast.Position{Line: 2, Column: 5},
+			End:   ast.Position{Line: 2, Column: 14},
+			Kind:  interpreter.StatementCoverage,
+			Count: 1,
+		},
+		{
+			File:  "a/a.flux",
+			Start: ast.Position{Line: 3, Column: 5},
+			End:   ast.Position{Line: 3, Column: 44},
+			Kind:  interpreter.StatementCoverage,
+			Count: 1,
+		},
+		{
+			File:  "a/a.flux",
+			Start: ast.Position{Line: 3, Column: 26},
+			End:   ast.Position{Line: 3, Column: 31},
+			Kind:  interpreter.BranchCoverage,
+			Count: 1,
+		},
+		{
+			File:  "a/a.flux",
+			Start: ast.Position{Line: 3, Column: 37},
+			End:   ast.Position{Line: 3, Column: 44},
+			Kind:  interpreter.BranchCoverage,
+		},
+		{
+			File:  "b/b.flux",
+			Start: ast.Position{Line: 1, Column: 12},
+			End:   ast.Position{Line: 1, Column: 17},
+			Kind:  interpreter.StatementCoverage,
+		},
+	}
+}
+
+func TestCoverageText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCoverageText(&buf, coverageBlocks()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `FILE      STATEMENTS  BRANCHES
+a/a.flux  100.0%      50.0%
+b/b.flux  0.0%        -
+total     66.7%       50.0%
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Fatalf("unexpected report -want/+got:\n%s", diff)
+	}
+}
+
+func TestCoverageSegments(t *testing.T) {
+	src := `f = (x) => {
+    y = x + 1
+    return if y > 2 then "big" else "small"
+}
+`
+	want := []coverageSegment{
+		{Text: "f = (x) => {\n    "},
+		{Text: "y = x + 1", Class: "covered"},
+		{Text: "\n    "},
+		{Text: `return if y > 2 then "big" else `, Class: "covered"},
+		{Text: `"small"`, Class: "uncovered"},
+		{Text: "\n}\n"},
+	}
+	got := coverageSegments(src, coverageBlocks()[:4])
+	if !cmp.Equal(want, got) {
+		t.Fatalf("unexpected segments -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
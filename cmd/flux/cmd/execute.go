@@ -3,12 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/dependencies/influxdb"
 	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/flux/values"
 	"github.com/spf13/cobra"
 )
 
@@ -21,8 +27,16 @@ var executeCmd = &cobra.Command{
 	RunE:  execute,
 }
 
+var (
+	executeExplain string
+	executeParams  []string
+)
+
 func init() {
 	rootCmd.AddCommand(executeCmd)
+	executeCmd.Flags().StringVar(&executeExplain, "explain", "", "explain how the query is planned before executing it (text or json)")
+	executeCmd.Flags().Lookup("explain").NoOptDefVal = "text"
+	executeCmd.Flags().StringArrayVar(&executeParams, "param", nil, "bind a value to the params record of the query as key=value, may be repeated")
 }
 
 const DefaultInfluxDBHost = "http://localhost:8086"
@@ -47,11 +61,82 @@ func injectDependencies(ctx context.Context) (context.Context, flux.Dependencies
 }
 
 func execute(cmd *cobra.Command, args []string) error {
+	params, err := parseParamFlags(executeParams)
+	if err != nil {
+		return err
+	}
+
 	fluxinit.FluxInit()
 	ctx, deps := injectDependencies(context.Background())
+	if executeExplain != "" {
+		var opts []lang.CompileOption
+		if params != nil {
+			opts = append(opts, lang.WithParams(params))
+		}
+		if err := explainQuery(ctx, os.Stdout, args[0], executeExplain, opts...); err != nil {
+			return err
+		}
+	}
 	r := repl.New(ctx, deps)
+	if params != nil {
+		if err := r.SetParams(params); err != nil {
+			return fmt.Errorf("failed to set params: %v", err)
+		}
+	}
 	if err := r.Input(args[0]); err != nil {
 		return fmt.Errorf("failed to execute query: %v", err)
 	}
 	return nil
 }
+
+// parseParamFlags parses the key=value pairs of the param flags.
+// It returns nil if there are no params.
+func parseParamFlags(flags []string) (map[string]values.Value, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]values.Value, len(flags))
+	for _, f := range flags {
+		i := strings.Index(f, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid param %q, must be key=value", f)
+		}
+		k := f[:i]
+		if _, ok := params[k]; ok {
+			return nil, fmt.Errorf("param %q is set more than once", k)
+		}
+		v, err := parseParamValue(f[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for param %q: %v", k, err)
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+// parseParamValue parses the value of a param like a Flux literal.
+// Integers, floats, booleans, durations, RFC3339 times and
+// double quoted strings have their literal type.
+// Any other value is a string.
+func parseParamValue(s string) (values.Value, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return values.NewInt(i), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return values.NewFloat(f), nil
+	}
+	switch s {
+	case "true":
+		return values.NewBool(true), nil
+	case "false":
+		return values.NewBool(false), nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		str, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", s)
+		}
+		return values.NewString(str), nil
+	}
+	return lang.ParseParamString(s), nil
+}
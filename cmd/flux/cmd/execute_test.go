@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/values"
+)
+
+func TestParseParamFlags(t *testing.T) {
+	params, err := parseParamFlags([]string{
+		"n=5",
+		"f=1.5",
+		"b=true",
+		"every=-1h30m",
+		"start=2020-01-01T00:00:00Z",
+		`quoted="5"`,
+		"bucket=telegraf/autogen",
+		"expr=a=b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]values.Value{
+		"n":      values.NewInt(5),
+		"f":      values.NewFloat(1.5),
+		"b":      values.NewBool(true),
+		"every":  values.NewDuration(values.ConvertDurationNsecs(-90 * time.Minute)),
+		"start":  values.NewTime(values.ConvertTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+		"quoted": values.NewString("5"),
+		"bucket": values.NewString("telegraf/autogen"),
+		"expr":   values.NewString("a=b"),
+	}
+	if len(want) != len(params) {
+		t.Fatalf("unexpected number of params: want %d, got %d", len(want), len(params))
+	}
+	for k, v := range want {
+		if got, ok := params[k]; !ok || !v.Equal(got) {
+			t.Errorf("unexpected value for param %q: want %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestParseParamFlags_Invalid(t *testing.T) {
+	for _, flags := range [][]string{
+		{"novalue"},
+		{"=5"},
+		{"a=1", "a=2"},
+		{`s="unterminated`},
+	} {
+		if _, err := parseParamFlags(flags); err == nil {
+			t.Errorf("expected an error for %q", flags)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/flux/runtime"
+	"github.com/spf13/cobra"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain how a Flux script is planned",
+	Long:  "Print the logical and physical plans for a Flux script from string or file (use @ as prefix to the file)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  explain,
+}
+
+var explainFormat string
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().StringVar(&explainFormat, "format", "text", "output format of the explanation (text or json)")
+}
+
+func explain(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+	ctx, _ := injectDependencies(context.Background())
+	return explainQuery(ctx, os.Stdout, args[0], explainFormat)
+}
+
+// explainQuery plans the query and writes the explanation
+// to w in the given format.
+func explainQuery(ctx context.Context, w io.Writer, query, format string, opts ...lang.CompileOption) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown explain format %q, must be text or json", format)
+	}
+
+	if len(query) > 0 && query[0] == '@' {
+		q, err := repl.LoadQuery(query)
+		if err != nil {
+			return err
+		}
+		query = q
+	}
+
+	program, err := lang.Compile(query, runtime.Default, time.Now(), opts...)
+	if err != nil {
+		return err
+	}
+	e, err := program.Explain(ctx, &memory.Allocator{})
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %v", err)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	}
+	return e.WriteText(w)
+}
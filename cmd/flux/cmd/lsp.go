@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lsp"
+	"github.com/spf13/cobra"
+)
+
+// lspCmd represents the lsp command
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a Flux language server",
+	Long:  "Run a Flux language server that speaks the Language Server Protocol over stdin and stdout",
+	Args:  cobra.NoArgs,
+	RunE:  runLanguageServer,
+}
+
+var lspStdlibDir string
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+	lspCmd.SilenceUsage = true
+	lspCmd.Flags().StringVar(&lspStdlibDir, "stdlib-dir", "", "directory containing the Flux standard library sources, used to find definitions in imported packages")
+}
+
+func runLanguageServer(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+	s := lsp.NewServer(lsp.Options{
+		StdlibDir: lspStdlibDir,
+	})
+	return s.Serve(os.Stdin, os.Stdout)
+}
@@ -11,8 +11,13 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
@@ -24,6 +29,7 @@ import (
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/fluxinit"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/parser"
@@ -35,6 +41,13 @@ type testFlags struct {
 	testNames     []string
 	paths         []string
 	skipTestCases []string
+	run           string
+	parallel      int
+	format        string
+	output        string
+	cover         bool
+	coverProfile  string
+	coverPkgs     []string
 	verbosity     int
 }
 
@@ -52,6 +65,13 @@ func TestCommand(setup TestSetupFunc) *cobra.Command {
 	testCommand.Flags().StringSliceVarP(&flags.paths, "path", "p", nil, "The root level directory for all packages.")
 	testCommand.Flags().StringSliceVar(&flags.testNames, "test", []string{}, "The name of a specific test to run.")
 	testCommand.Flags().StringSliceVar(&flags.skipTestCases, "skip", []string{}, "Comma-separated list of test cases to skip.")
+	testCommand.Flags().StringVar(&flags.run, "run", "", "Only run the test cases whose names match the regular expression.")
+	testCommand.Flags().IntVar(&flags.parallel, "parallel", 1, "The number of test cases to run concurrently.")
+	testCommand.Flags().StringVar(&flags.format, "format", "text", "The format of the test report (text, junit or json).")
+	testCommand.Flags().StringVarP(&flags.output, "output", "o", "", "The file to write the junit or json test report to. Defaults to stdout.")
+	testCommand.Flags().BoolVar(&flags.cover, "cover", false, "Record which statements and branches of the tested packages are evaluated and print a coverage summary.")
+	testCommand.Flags().StringVar(&flags.coverProfile, "coverprofile", "", "Write a coverage profile to the file. Implies --cover.")
+	testCommand.Flags().StringSliceVar(&flags.coverPkgs, "coverpkg", nil, "Comma-separated list of packages to record coverage for. Defaults to the packages tested by the test files. Implies --cover.")
 	testCommand.Flags().CountVarP(&flags.verbosity, "verbose", "v", "verbose (-v, or -vv)")
 	return testCommand
 }
@@ -67,30 +87,83 @@ func runFluxTests(setup TestSetupFunc, flags testFlags) {
 		flags.paths = []string{"."}
 	}
 
+	writeReport, err := testReportWriter(flags.format)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	reporter := NewTestReporter(flags.verbosity)
+	if writeReport != nil && flags.output == "" {
+		// The report is written to stdout so the
+		// progress is written to stderr instead.
+		reporter.w = os.Stderr
+	}
 	runner := NewTestRunner(reporter)
 	if err := runner.Gather(flags.paths, flags.testNames); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	if flags.run != "" {
+		re, err := regexp.Compile(flags.run)
+		if err != nil {
+			fmt.Printf("invalid --run expression: %s\n", err)
+			os.Exit(1)
+		}
+		runner.Filter(re)
+	}
+
+	ctx := context.Background()
+	var coverage *interpreter.Coverage
+	if flags.cover || flags.coverProfile != "" || len(flags.coverPkgs) > 0 {
+		pkgs := flags.coverPkgs
+		if len(pkgs) == 0 {
+			pkgs = runner.CoverPackages()
+		}
+		coverage, err = newTestCoverage(pkgs)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		ctx = interpreter.WithCoverage(ctx, coverage)
+	}
 
-	executor, err := setup(context.Background())
+	executor, err := setup(ctx)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 	defer func() { _ = executor.Close() }()
 
-	runner.Run(executor, flags.verbosity, flags.skipTestCases)
+	runner.RunParallel(executor, flags.parallel, flags.skipTestCases)
+	if writeReport != nil {
+		if err := writeTestReport(writeReport, flags.output, runner.tests); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	if coverage != nil {
+		blocks := coverage.Blocks()
+		runner.reporter.SummarizeCoverage(blocks)
+		if flags.coverProfile != "" {
+			if err := writeCoverProfile(flags.coverProfile, blocks); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	}
 	runner.Finish()
 }
 
 // Test wraps the functionality of a single testcase statement,
 // to handle its execution and its pass/fail state.
 type Test struct {
-	name string
-	ast  *ast.Package
-	err  error
+	name     string
+	file     string
+	ast      *ast.Package
+	err      error
+	duration time.Duration
+	skipped  bool
 }
 
 // NewTest creates a new Test instance from an ast.Package.
@@ -106,14 +179,31 @@ func (t *Test) Name() string {
 	return t.name
 }
 
+// Get the name of the file that contains the Test.
+func (t *Test) File() string {
+	return t.file
+}
+
 // Get the error from the test, if one exists.
 func (t *Test) Error() error {
 	return t.err
 }
 
+// Get how long the test took to run.
+func (t *Test) Duration() time.Duration {
+	return t.duration
+}
+
+// Skipped reports whether the test was skipped.
+func (t *Test) Skipped() bool {
+	return t.skipped
+}
+
 // Run the test, saving the error to the err property of the struct.
 func (t *Test) Run(executor TestExecutor) {
+	start := time.Now()
 	t.err = executor.Run(t.ast)
+	t.duration = time.Since(start)
 }
 
 // contains checks a slice of strings for a given string.
@@ -187,6 +277,7 @@ func (t *TestRunner) Gather(roots []string, names []string) error {
 			}
 			for i, astf := range asts {
 				test := NewTest(tcnames[i], astf)
+				test.file = file
 				if len(names) == 0 || contains(names, test.Name()) {
 					t.tests = append(t.tests, &test)
 				}
@@ -527,19 +618,89 @@ func findParentTestRoot(path string) (string, filesystem.Service, bool, error) {
 	return "", nil, false, nil
 }
 
+// Filter removes the tests whose names do not match the regular expression.
+func (t *TestRunner) Filter(re *regexp.Regexp) {
+	tests := t.tests[:0]
+	for _, test := range t.tests {
+		if re.MatchString(test.name) {
+			tests = append(tests, test)
+		}
+	}
+	t.tests = tests
+}
+
+// CoverPackages returns the import paths of the packages tested by the
+// gathered tests. A test file in the package foo_test tests the package
+// that it imports whose path ends with foo.
+func (t *TestRunner) CoverPackages() []string {
+	seen := make(map[string]bool)
+	var pkgs []string
+	for _, test := range t.tests {
+		for _, file := range test.ast.Files {
+			if file.Package == nil || file.Package.Name == nil {
+				continue
+			}
+			name := strings.TrimSuffix(file.Package.Name.Name, "_test")
+			if name == file.Package.Name.Name {
+				continue
+			}
+			for _, imp := range file.Imports {
+				if imp.Path == nil {
+					continue
+				}
+				p := imp.Path.Value
+				if path.Base(p) == name && !seen[p] {
+					seen[p] = true
+					pkgs = append(pkgs, p)
+				}
+			}
+		}
+	}
+	sort.Strings(pkgs)
+	return pkgs
+}
+
 // Run runs all tests, reporting their results.
 func (t *TestRunner) Run(executor TestExecutor, verbosity int, skipTestCases []string) {
+	t.RunParallel(executor, 1, skipTestCases)
+}
+
+// RunParallel runs all tests with up to parallel tests running
+// at the same time, reporting their results as each test finishes.
+func (t *TestRunner) RunParallel(executor TestExecutor, parallel int, skipTestCases []string) {
+	if parallel < 1 {
+		parallel = 1
+	}
 	skipMap := make(map[string]struct{})
 	for _, n := range skipTestCases {
 		skipMap[n] = struct{}{}
 	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, parallel)
+	)
 	for _, test := range t.tests {
 		if _, ok := skipMap[test.name]; ok {
+			test.skipped = true
 			continue
 		}
-		test.Run(executor)
-		t.reporter.ReportTestRun(test)
+
+		test := test
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			test.Run(executor)
+			mu.Lock()
+			t.reporter.ReportTestRun(test)
+			mu.Unlock()
+		}()
 	}
+	wg.Wait()
 }
 
 // Finish summarizes the test run, and returns the
@@ -557,11 +718,15 @@ func (t *TestRunner) Finish() {
 // TestReporter handles reporting of test results.
 type TestReporter struct {
 	verbosity int
+	w         io.Writer
 }
 
 // NewTestReporter creates a new TestReporter with a provided verbosity.
 func NewTestReporter(verbosity int) TestReporter {
-	return TestReporter{verbosity: verbosity}
+	return TestReporter{
+		verbosity: verbosity,
+		w:         os.Stdout,
+	}
 }
 
 // ReportTestRun reports the result a single test run, intended to be run as
@@ -569,44 +734,66 @@ func NewTestReporter(verbosity int) TestReporter {
 func (t *TestReporter) ReportTestRun(test *Test) {
 	if t.verbosity == 0 {
 		if test.Error() != nil {
-			fmt.Print("x")
+			fmt.Fprint(t.w, "x")
 		} else {
-			fmt.Print(".")
+			fmt.Fprint(t.w, ".")
 		}
 	} else {
 		if err := test.Error(); err != nil {
-			fmt.Printf("%s...fail: %s\n", test.Name(), err)
+			fmt.Fprintf(t.w, "%s...fail (%s): %s\n", test.Name(), test.Duration(), err)
 		} else {
-			fmt.Printf("%s...success\n", test.Name())
+			fmt.Fprintf(t.w, "%s...success (%s)\n", test.Name(), test.Duration())
 		}
 	}
 }
 
 // Summarize summarizes the test run.
 func (t *TestReporter) Summarize(tests []*Test) {
-	failures := 0
+	ran, failures := 0, 0
 	for _, test := range tests {
+		if test.Skipped() {
+			continue
+		}
+		ran++
 		if test.Error() != nil {
 			failures = failures + 1
 		}
 	}
-	fmt.Printf("\n---\nRan %d tests with %d failure(s)\n", len(tests), failures)
+	fmt.Fprintf(t.w, "\n---\nRan %d tests with %d failure(s)\n", ran, failures)
+}
+
+// SummarizeCoverage prints the statement and branch coverage
+// of each package that coverage was recorded for.
+func (t *TestReporter) SummarizeCoverage(blocks []interpreter.CoverageBlock) {
+	for _, s := range summarizeCoverage(blocks, func(b interpreter.CoverageBlock) string {
+		return path.Dir(b.File)
+	}) {
+		fmt.Fprintf(t.w, "coverage: %s of statements, %s of branches in %s\n",
+			s.statementPercent(), s.branchPercent(), s.name)
+	}
 }
 
 type TestSetupFunc func(ctx context.Context) (TestExecutor, error)
 
+// TestExecutor runs the test cases. Run may be called concurrently
+// when the test cases are run in parallel.
 type TestExecutor interface {
 	Run(pkg *ast.Package) error
 	io.Closer
 }
 
+// NewTestExecutor creates the default TestExecutor. When the context
+// has an interpreter.Coverage, the evaluation of each test case is
+// recorded in the coverage.
 func NewTestExecutor(ctx context.Context) (TestExecutor, error) {
-	return testExecutor{}, nil
+	return testExecutor{coverage: interpreter.GetCoverage(ctx)}, nil
 }
 
-type testExecutor struct{}
+type testExecutor struct {
+	coverage *interpreter.Coverage
+}
 
-func (testExecutor) Run(pkg *ast.Package) error {
+func (t testExecutor) Run(pkg *ast.Package) error {
 	jsonAST, err := json.Marshal(pkg)
 	if err != nil {
 		return err
@@ -615,6 +802,9 @@ func (testExecutor) Run(pkg *ast.Package) error {
 
 	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
 	ctx = testing.Inject(ctx)
+	if t.coverage != nil {
+		ctx = interpreter.WithCoverage(ctx, t.coverage)
+	}
 	program, err := c.Compile(ctx, runtime.Default)
 	if err != nil {
 		return errors.Wrap(err, codes.Invalid, "failed to compile")
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// testReportFunc writes a machine readable report of the test results.
+type testReportFunc func(w io.Writer, tests []*Test) error
+
+// testReportWriter returns the function that writes the report for
+// the format. It returns nil for the human readable text format that
+// is printed while the tests run.
+func testReportWriter(format string) (testReportFunc, error) {
+	switch format {
+	case "", "text":
+		return nil, nil
+	case "junit":
+		return writeJUnitReport, nil
+	case "json":
+		return writeJSONReport, nil
+	default:
+		return nil, fmt.Errorf("unknown test report format %q, expected text, junit or json", format)
+	}
+}
+
+// writeTestReport writes the report to the output file or to stdout
+// when no output file is given.
+func writeTestReport(write testReportFunc, output string, tests []*Test) error {
+	if output == "" {
+		return write(os.Stdout, tests)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := write(f, tests); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeJUnitReport writes the results as a JUnit XML report
+// with a test suite for each file that contains test cases.
+func writeJUnitReport(w io.Writer, tests []*Test) error {
+	var (
+		report   junitTestSuites
+		duration time.Duration
+		suites   = make(map[string]*junitTestSuite)
+	)
+	for _, test := range tests {
+		suite, ok := suites[test.File()]
+		if !ok {
+			suite = &junitTestSuite{Name: test.File()}
+			suites[test.File()] = suite
+			report.Suites = append(report.Suites, suite)
+		}
+
+		tc := &junitTestCase{
+			Name:      test.Name(),
+			ClassName: test.File(),
+			Time:      junitTime(test.Duration()),
+		}
+		if test.Skipped() {
+			tc.Skipped = &struct{}{}
+			suite.Skipped++
+			report.Skipped++
+		} else if err := test.Error(); err != nil {
+			msg := err.Error()
+			tc.Failure = &junitFailure{
+				Message:  strings.SplitN(msg, "\n", 2)[0],
+				Contents: msg,
+			}
+			suite.Failures++
+			report.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+		suite.duration += test.Duration()
+		report.Tests++
+		duration += test.Duration()
+	}
+	for _, suite := range report.Suites {
+		suite.Time = junitTime(suite.duration)
+	}
+	report.Time = junitTime(duration)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type jsonTestReport struct {
+	Tests    []jsonTestResult `json:"tests"`
+	Total    int              `json:"total"`
+	Passed   int              `json:"passed"`
+	Failed   int              `json:"failed"`
+	Skipped  int              `json:"skipped"`
+	Duration float64          `json:"duration"`
+}
+
+type jsonTestResult struct {
+	Name     string  `json:"name"`
+	File     string  `json:"file"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// writeJSONReport writes the results as a JSON document.
+// Durations are in seconds.
+func writeJSONReport(w io.Writer, tests []*Test) error {
+	report := jsonTestReport{
+		Tests: make([]jsonTestResult, 0, len(tests)),
+	}
+	var duration time.Duration
+	for _, test := range tests {
+		result := jsonTestResult{
+			Name:     test.Name(),
+			File:     test.File(),
+			Status:   "pass",
+			Duration: test.Duration().Seconds(),
+		}
+		if test.Skipped() {
+			result.Status = "skip"
+			report.Skipped++
+		} else if err := test.Error(); err != nil {
+			result.Status = "fail"
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			report.Passed++
+		}
+		report.Tests = append(report.Tests, result)
+		report.Total++
+		duration += test.Duration()
+	}
+	report.Duration = duration.Seconds()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(&report)
+}
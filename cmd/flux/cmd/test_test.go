@@ -3,12 +3,18 @@ package cmd
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"context"
 	"io/ioutil"
 	"os"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/internal/errors"
 )
@@ -211,3 +217,156 @@ func testGatherFromArchive(t *testing.T, fn gatherFunc, filename string) {
 		}
 	}
 }
+
+// fakeExecutor fails the tests whose package is named "fail"
+// and records how many tests ran at the same time.
+type fakeExecutor struct {
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (e *fakeExecutor) Run(pkg *ast.Package) error {
+	e.mu.Lock()
+	e.running++
+	if e.running > e.max {
+		e.max = e.running
+	}
+	e.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	e.mu.Lock()
+	e.running--
+	e.mu.Unlock()
+	if pkg.Package == "fail" {
+		return errors.New(codes.FailedPrecondition, "tables differ\nmore details")
+	}
+	return nil
+}
+
+func (e *fakeExecutor) Close() error { return nil }
+
+func newTestRunner(names ...string) *TestRunner {
+	runner := NewTestRunner(TestReporter{w: ioutil.Discard})
+	for _, name := range names {
+		test := NewTest(name, &ast.Package{Package: name})
+		test.file = "a/a_test.flux"
+		runner.tests = append(runner.tests, &test)
+	}
+	return &runner
+}
+
+func TestRunParallel(t *testing.T) {
+	runner := newTestRunner("a", "b", "c", "d", "fail", "skip")
+	executor := &fakeExecutor{}
+	runner.RunParallel(executor, 3, []string{"skip"})
+
+	if want, got := 3, executor.max; want != got {
+		t.Errorf("unexpected number of concurrent tests -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+	for _, test := range runner.tests {
+		switch test.Name() {
+		case "skip":
+			if !test.Skipped() || test.Duration() != 0 {
+				t.Errorf("expected test %q to be skipped", test.Name())
+			}
+		case "fail":
+			if test.Error() == nil {
+				t.Errorf("expected test %q to fail", test.Name())
+			}
+		default:
+			if test.Error() != nil {
+				t.Errorf("unexpected error in test %q: %s", test.Name(), test.Error())
+			}
+		}
+		if !test.Skipped() && test.Duration() < 10*time.Millisecond {
+			t.Errorf("unexpected duration for test %q: %s", test.Name(), test.Duration())
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	runner := newTestRunner("sum_ints", "sum_floats", "mean_ints")
+	runner.Filter(regexp.MustCompile(`^sum_`))
+
+	var names []string
+	for _, test := range runner.tests {
+		names = append(names, test.Name())
+	}
+	if want, got := []string{"sum_ints", "sum_floats"}, names; !cmp.Equal(want, got) {
+		t.Errorf("unexpected tests -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func reportTests() []*Test {
+	return []*Test{
+		{name: "pass", file: "a/a_test.flux", duration: 1500 * time.Millisecond},
+		{name: "fail", file: "a/a_test.flux", duration: 250 * time.Millisecond, err: errors.New(codes.FailedPrecondition, "tables differ\nmore details")},
+		{name: "skip", file: "b/b_test.flux", skipped: true},
+	}
+}
+
+func TestJUnitReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJUnitReport(&buf, reportTests()); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" skipped="1" time="1.750">
+  <testsuite name="a/a_test.flux" tests="2" failures="1" skipped="0" time="1.750">
+    <testcase name="pass" classname="a/a_test.flux" time="1.500"></testcase>
+    <testcase name="fail" classname="a/a_test.flux" time="0.250">
+      <failure message="tables differ">tables differ&#xA;more details</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="b/b_test.flux" tests="1" failures="0" skipped="1" time="0.000">
+    <testcase name="skip" classname="b/b_test.flux" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if got := buf.String(); want != got {
+		t.Errorf("unexpected report -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSONReport(&buf, reportTests()); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "tests": [
+    {
+      "name": "pass",
+      "file": "a/a_test.flux",
+      "status": "pass",
+      "duration": 1.5
+    },
+    {
+      "name": "fail",
+      "file": "a/a_test.flux",
+      "status": "fail",
+      "duration": 0.25,
+      "error": "tables differ\nmore details"
+    },
+    {
+      "name": "skip",
+      "file": "b/b_test.flux",
+      "status": "skip",
+      "duration": 0
+    }
+  ],
+  "total": 3,
+  "passed": 1,
+  "failed": 1,
+  "skipped": 1,
+  "duration": 1.75
+}
+`
+	if got := buf.String(); want != got {
+		t.Errorf("unexpected report -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
@@ -159,6 +159,9 @@ func (t *TableObject) Time() values.Time {
 func (t *TableObject) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (t *TableObject) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (t *TableObject) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -271,6 +274,9 @@ func (f *function) Time() values.Time {
 func (f *function) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Duration))
 }
+func (f *function) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 func (f *function) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Regexp))
 }
@@ -401,6 +401,8 @@ func (e *unaryEvaluator) Eval(ctx context.Context, scope Scope) (values.Value, e
 			return values.NewBool(!v.Bool()), nil
 		case semantic.Duration:
 			return values.NewDuration(v.Duration().Mul(-1)), nil
+		case semantic.Decimal:
+			return values.NewDecimal(v.Decimal().Neg()), nil
 		default:
 			panic(values.UnexpectedKind(e.t.Nature(), v.Type().Nature()))
 		}
@@ -693,6 +695,9 @@ func (f *functionValue) Time() values.Time {
 func (f *functionValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Duration))
 }
+func (f *functionValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 func (f *functionValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Regexp))
 }
@@ -0,0 +1,465 @@
+// Package controller runs flux programs while enforcing limits on
+// how many queries may execute at once and how much memory they may use.
+//
+// Queries wait in a queue until they are admitted. The queue is ordered by
+// the priority of each query and then by the time it was submitted.
+// A query is admitted once fewer than the concurrency quota of queries are
+// executing and the initial memory for the query can be reserved from the
+// memory pool shared by all executing queries.
+package controller
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"go.uber.org/zap"
+)
+
+// Config configures the limits enforced by a Controller.
+type Config struct {
+	// ConcurrencyQuota is the number of queries that may execute concurrently.
+	ConcurrencyQuota int
+
+	// QueueSize is the number of queries that may wait to be executed.
+	// Queries that are submitted while the queue is full are rejected.
+	QueueSize int
+
+	// InitialMemoryBytesQuotaPerQuery is the amount of memory
+	// reserved from the memory pool when a query starts executing.
+	InitialMemoryBytesQuotaPerQuery int64
+
+	// MemoryBytesQuotaPerQuery is the maximum amount of memory a single query may use.
+	// A zero value means a query is only limited by the memory pool.
+	MemoryBytesQuotaPerQuery int64
+
+	// MaxMemoryBytes is the size of the memory pool shared by all executing queries.
+	// A zero value means the memory pool is unlimited.
+	MaxMemoryBytes int64
+
+	// Logger is used to log the queries that fail.
+	// If it is nil, nothing is logged.
+	Logger *zap.Logger
+}
+
+// Validate reports an error if the configuration cannot be used to create a Controller.
+func (c Config) Validate() error {
+	if c.ConcurrencyQuota <= 0 {
+		return errors.New(codes.Invalid, "ConcurrencyQuota must be positive")
+	}
+	if c.QueueSize <= 0 {
+		return errors.New(codes.Invalid, "QueueSize must be positive")
+	}
+	if c.InitialMemoryBytesQuotaPerQuery < 0 {
+		return errors.New(codes.Invalid, "InitialMemoryBytesQuotaPerQuery must not be negative")
+	}
+	if c.MemoryBytesQuotaPerQuery < 0 {
+		return errors.New(codes.Invalid, "MemoryBytesQuotaPerQuery must not be negative")
+	}
+	if c.MaxMemoryBytes < 0 {
+		return errors.New(codes.Invalid, "MaxMemoryBytes must not be negative")
+	}
+	if c.MemoryBytesQuotaPerQuery > 0 && c.InitialMemoryBytesQuotaPerQuery > c.MemoryBytesQuotaPerQuery {
+		return errors.New(codes.Invalid, "InitialMemoryBytesQuotaPerQuery must not be greater than MemoryBytesQuotaPerQuery")
+	}
+	if c.MaxMemoryBytes > 0 {
+		if c.MemoryBytesQuotaPerQuery > c.MaxMemoryBytes {
+			return errors.New(codes.Invalid, "MemoryBytesQuotaPerQuery must not be greater than MaxMemoryBytes")
+		}
+		if c.InitialMemoryBytesQuotaPerQuery*int64(c.ConcurrencyQuota) > c.MaxMemoryBytes {
+			return errors.New(codes.Invalid, "MaxMemoryBytes must be enough for the initial memory of ConcurrencyQuota queries")
+		}
+	}
+	return nil
+}
+
+// Controller queues and executes flux programs.
+type Controller struct {
+	config Config
+	logger *zap.Logger
+	pool   *memoryPool
+
+	mu        sync.Mutex
+	queue     queryQueue
+	executing int
+	seq       uint64
+	shutdown  bool
+
+	// done is signaled each time a query is done
+	// so Shutdown can wait for the executing queries.
+	done chan struct{}
+}
+
+// New creates a Controller with the given configuration.
+func New(config Config) (*Controller, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Controller{
+		config: config,
+		logger: logger,
+		pool:   &memoryPool{limit: config.MaxMemoryBytes},
+		done:   make(chan struct{}, 1),
+	}, nil
+}
+
+// Query submits the program to be executed once it is admitted by the controller.
+// The priority of the query and its memory quota are read from resources.
+// A memory quota lower than the configured MemoryBytesQuotaPerQuery
+// further limits the memory of this query.
+//
+// The returned query must be consumed the same way as any other flux.Query
+// and Done must be called to release the resources it holds in the controller.
+func (c *Controller) Query(ctx context.Context, program flux.Program, resources flux.ResourceManagement) (flux.Query, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.shutdown {
+		return nil, errors.New(codes.Unavailable, "query controller is shut down")
+	}
+	if len(c.queue) >= c.config.QueueSize {
+		return nil, errors.New(codes.ResourceExhausted, "queue length exceeded")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	q := &Query{
+		c:         c,
+		ctx:       ctx,
+		cancel:    cancel,
+		program:   program,
+		priority:  resources.Priority,
+		memLimit:  c.memoryLimit(resources),
+		seq:       c.seq,
+		state:     queued,
+		results:   make(chan flux.Result),
+		submitted: time.Now(),
+		admitted:  make(chan struct{}),
+		finished:  make(chan struct{}),
+	}
+	c.seq++
+	heap.Push(&c.queue, q)
+	go q.waitForCancel()
+
+	c.dispatch()
+	return q, nil
+}
+
+// memoryLimit returns the maximum amount of memory a query may use.
+// A zero value means the query is only limited by the memory pool.
+func (c *Controller) memoryLimit(resources flux.ResourceManagement) int64 {
+	limit := c.config.MemoryBytesQuotaPerQuery
+	if quota := resources.MemoryBytesQuota; quota > 0 && (limit == 0 || quota < limit) {
+		limit = quota
+	}
+	return limit
+}
+
+// dispatch starts executing queued queries until the concurrency quota
+// is reached or the memory pool cannot reserve the initial memory of the
+// query with the highest priority. The lock must be held when calling dispatch.
+func (c *Controller) dispatch() {
+	for c.executing < c.config.ConcurrencyQuota && len(c.queue) > 0 {
+		q := c.queue[0]
+		initial := q.initialMemory()
+		if !c.pool.reserve(initial) {
+			// Queries with a lower priority must wait
+			// until enough memory has been released.
+			if q.requeued.IsZero() {
+				q.requeued = time.Now()
+			}
+			return
+		}
+		heap.Pop(&c.queue)
+		c.executing++
+		q.state = executing
+		close(q.admitted)
+		go q.execute()
+	}
+}
+
+// release returns the memory reserved by an executing query
+// and admits the next queries in the queue.
+func (c *Controller) release(reserved int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pool.release(reserved)
+	c.executing--
+	c.dispatch()
+
+	select {
+	case c.done <- struct{}{}:
+	default:
+	}
+}
+
+// dequeue removes a query that has not been admitted from the queue.
+// It reports whether the query was still in the queue.
+func (c *Controller) dequeue(q *Query) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if q.state != queued {
+		return false
+	}
+	heap.Remove(&c.queue, q.index)
+	q.state = canceled
+
+	// The query may have been blocking the queries behind it.
+	c.dispatch()
+	return true
+}
+
+// Shutdown stops accepting new queries and cancels the queries
+// that are waiting in the queue. It then waits for the executing
+// queries to be done or for the context to be canceled.
+func (c *Controller) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.shutdown = true
+	queued := make([]*Query, len(c.queue))
+	copy(queued, c.queue)
+	c.mu.Unlock()
+
+	for _, q := range queued {
+		q.Cancel()
+	}
+
+	for {
+		c.mu.Lock()
+		executing := c.executing
+		c.mu.Unlock()
+		if executing == 0 {
+			return nil
+		}
+
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Stats reports the number of queries in the queue,
+// the number of executing queries and the memory they reserved.
+type Stats struct {
+	Queued         int
+	Executing      int
+	ReservedMemory int64
+}
+
+// Stats returns the current state of the controller.
+func (c *Controller) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Queued:         len(c.queue),
+		Executing:      c.executing,
+		ReservedMemory: c.pool.reserved(),
+	}
+}
+
+type queryState int
+
+const (
+	queued queryState = iota
+	executing
+	canceled
+)
+
+// Query is a query that has been submitted to the Controller.
+type Query struct {
+	c       *Controller
+	ctx     context.Context
+	cancel  context.CancelFunc
+	program flux.Program
+
+	priority flux.Priority
+	memLimit int64
+
+	// These fields are guarded by the controller's lock.
+	seq      uint64
+	index    int
+	state    queryState
+	requeued time.Time
+
+	results  chan flux.Result
+	admitted chan struct{}
+	finished chan struct{}
+
+	submitted time.Time
+	started   time.Time
+
+	// These fields are only written by execute before finished is closed
+	// or by waitForCancel when the query is canceled in the queue.
+	query flux.Query
+	mem   *queryMemoryManager
+	err   error
+
+	once  sync.Once
+	stats flux.Statistics
+}
+
+// initialMemory returns the amount of memory to reserve
+// from the memory pool when the query is admitted.
+func (q *Query) initialMemory() int64 {
+	initial := q.c.config.InitialMemoryBytesQuotaPerQuery
+	if q.memLimit > 0 && initial > q.memLimit {
+		initial = q.memLimit
+	}
+	return initial
+}
+
+// waitForCancel removes the query from the queue
+// if its context is canceled before it is admitted.
+func (q *Query) waitForCancel() {
+	select {
+	case <-q.ctx.Done():
+		if q.c.dequeue(q) {
+			q.err = errors.Wrap(q.ctx.Err(), codes.Canceled, "query canceled while queued")
+			close(q.results)
+			close(q.finished)
+		}
+	case <-q.admitted:
+	}
+}
+
+// execute starts the program and forwards its results
+// until they are all consumed or the query is canceled.
+func (q *Query) execute() {
+	defer close(q.finished)
+	defer close(q.results)
+
+	q.started = time.Now()
+	initial := q.initialMemory()
+	q.mem = newQueryMemoryManager(q.c.pool, initial, q.memLimit)
+	alloc := &memory.Allocator{}
+	if q.memLimit > 0 || q.c.config.MaxMemoryBytes > 0 {
+		alloc.Limit = &initial
+		alloc.Manager = q.mem
+	}
+
+	query, err := q.program.Start(q.ctx, alloc)
+	if err != nil {
+		q.err = err
+		return
+	}
+	q.query = query
+
+	for {
+		select {
+		case r, ok := <-query.Results():
+			if !ok {
+				return
+			}
+			select {
+			case q.results <- r:
+			case <-q.ctx.Done():
+				return
+			}
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+// Results returns the results of the program once it has been admitted.
+// The channel is closed without any results if the query
+// is canceled while it waits in the queue.
+func (q *Query) Results() <-chan flux.Result {
+	return q.results
+}
+
+// Cancel stops the query. A query in the queue is removed from the queue.
+// Done must still be called to free resources.
+func (q *Query) Cancel() {
+	q.cancel()
+}
+
+// Done releases the resources held by the query. It is safe to call Done multiple times.
+func (q *Query) Done() {
+	q.once.Do(func() {
+		q.cancel()
+		<-q.finished
+
+		// The query was never admitted if it was canceled while queued.
+		select {
+		case <-q.admitted:
+		default:
+			q.stats = flux.Statistics{
+				TotalDuration: time.Since(q.submitted),
+				QueueDuration: time.Since(q.submitted),
+			}
+			return
+		}
+
+		if q.query != nil {
+			q.query.Done()
+			q.stats = q.query.Statistics()
+			if q.err == nil {
+				q.err = q.query.Err()
+			}
+		}
+
+		q.c.release(q.mem.reserved())
+
+		q.c.mu.Lock()
+		requeued := q.requeued
+		q.c.mu.Unlock()
+
+		now := time.Now()
+		q.stats.TotalDuration = now.Sub(q.submitted)
+		if requeued.IsZero() {
+			q.stats.QueueDuration = q.started.Sub(q.submitted)
+		} else {
+			q.stats.QueueDuration = requeued.Sub(q.submitted)
+			q.stats.RequeueDuration = q.started.Sub(requeued)
+		}
+		if q.stats.ExecuteDuration == 0 {
+			q.stats.ExecuteDuration = now.Sub(q.started)
+		}
+		if q.err != nil {
+			q.c.logger.Info("Query failed", zap.Error(q.err))
+		}
+	})
+}
+
+// Err reports the error encountered by the query.
+// It is only complete once Done has been called.
+func (q *Query) Err() error {
+	select {
+	case <-q.finished:
+		return q.err
+	default:
+		return nil
+	}
+}
+
+// Statistics reports the statistics of the program along with
+// the time the query spent in the queue.
+// The statistics are not complete until Done is called.
+func (q *Query) Statistics() flux.Statistics {
+	return q.stats
+}
+
+// ProfilerResults returns the profiler results of the program.
+func (q *Query) ProfilerResults() (flux.ResultIterator, error) {
+	select {
+	case <-q.finished:
+	default:
+		return nil, nil
+	}
+	if q.query == nil {
+		return nil, nil
+	}
+	return q.query.ProfilerResults()
+}
@@ -0,0 +1,312 @@
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/controller"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/mock"
+)
+
+// blockingProgram returns a program that sends its name on started
+// once it executes and then blocks until it is canceled.
+func blockingProgram(name string, started chan<- string) flux.Program {
+	return &mock.Program{
+		ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+			started <- name
+			<-ctx.Done()
+		},
+	}
+}
+
+func newController(t *testing.T, config controller.Config) *controller.Controller {
+	t.Helper()
+	c, err := controller.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func mustQuery(t *testing.T, c *controller.Controller, program flux.Program, resources flux.ResourceManagement) flux.Query {
+	t.Helper()
+	q, err := c.Query(context.Background(), program, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func expectStarted(t *testing.T, started <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-started:
+		if got != want {
+			t.Fatalf("unexpected query started: want %q, got %q", want, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %q to start", want)
+	}
+}
+
+func expectNotStarted(t *testing.T, started <-chan string) {
+	t.Helper()
+	select {
+	case got := <-started:
+		t.Fatalf("unexpected query started: %q", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  controller.Config
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			config: controller.Config{ConcurrencyQuota: 2, QueueSize: 10, InitialMemoryBytesQuotaPerQuery: 10, MemoryBytesQuotaPerQuery: 100, MaxMemoryBytes: 200},
+		},
+		{
+			name:    "no concurrency",
+			config:  controller.Config{QueueSize: 10},
+			wantErr: true,
+		},
+		{
+			name:    "no queue",
+			config:  controller.Config{ConcurrencyQuota: 1},
+			wantErr: true,
+		},
+		{
+			name:    "initial memory greater than query quota",
+			config:  controller.Config{ConcurrencyQuota: 1, QueueSize: 1, InitialMemoryBytesQuotaPerQuery: 10, MemoryBytesQuotaPerQuery: 5},
+			wantErr: true,
+		},
+		{
+			name:    "pool too small for initial memory",
+			config:  controller.Config{ConcurrencyQuota: 4, QueueSize: 1, InitialMemoryBytesQuotaPerQuery: 10, MaxMemoryBytes: 30},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.config.Validate(); (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestController_ConcurrencyQuota(t *testing.T) {
+	c := newController(t, controller.Config{ConcurrencyQuota: 1, QueueSize: 10})
+	started := make(chan string)
+
+	q0 := mustQuery(t, c, blockingProgram("q0", started), flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+
+	q1 := mustQuery(t, c, blockingProgram("q1", started), flux.ResourceManagement{})
+	expectNotStarted(t, started)
+	if got := c.Stats(); got.Queued != 1 || got.Executing != 1 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+
+	q0.Done()
+	expectStarted(t, started, "q1")
+	q1.Done()
+
+	if got := c.Stats(); got.Queued != 0 || got.Executing != 0 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+	if stats := q1.Statistics(); stats.QueueDuration <= 0 {
+		t.Errorf("expected a queue duration, got %v", stats.QueueDuration)
+	}
+}
+
+func TestController_Priority(t *testing.T) {
+	c := newController(t, controller.Config{ConcurrencyQuota: 1, QueueSize: 10})
+	started := make(chan string)
+
+	q0 := mustQuery(t, c, blockingProgram("q0", started), flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+
+	low := mustQuery(t, c, blockingProgram("low", started), flux.ResourceManagement{Priority: flux.Low})
+	normal := mustQuery(t, c, blockingProgram("normal", started), flux.ResourceManagement{Priority: 10})
+	high := mustQuery(t, c, blockingProgram("high", started), flux.ResourceManagement{Priority: flux.High})
+
+	q0.Done()
+	expectStarted(t, started, "high")
+	high.Done()
+	expectStarted(t, started, "normal")
+	normal.Done()
+	expectStarted(t, started, "low")
+	low.Done()
+}
+
+func TestController_QueueFull(t *testing.T) {
+	c := newController(t, controller.Config{ConcurrencyQuota: 1, QueueSize: 1})
+	started := make(chan string)
+
+	q0 := mustQuery(t, c, blockingProgram("q0", started), flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+	q1 := mustQuery(t, c, blockingProgram("q1", started), flux.ResourceManagement{})
+
+	_, err := c.Query(context.Background(), blockingProgram("q2", started), flux.ResourceManagement{})
+	if got, want := errors.Code(err), codes.ResourceExhausted; got != want {
+		t.Fatalf("unexpected error code: want %v, got %v (%v)", want, got, err)
+	}
+
+	q0.Done()
+	expectStarted(t, started, "q1")
+	q1.Done()
+}
+
+func TestController_CancelQueued(t *testing.T) {
+	c := newController(t, controller.Config{ConcurrencyQuota: 1, QueueSize: 10})
+	started := make(chan string)
+
+	q0 := mustQuery(t, c, blockingProgram("q0", started), flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q1, err := c.Query(ctx, blockingProgram("q1", started), flux.ResourceManagement{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	// The results are closed without the program being started.
+	for range q1.Results() {
+		t.Fatal("unexpected result")
+	}
+	q1.Done()
+	if got, want := errors.Code(q1.Err()), codes.Canceled; got != want {
+		t.Fatalf("unexpected error code: want %v, got %v", want, got)
+	}
+	if got := c.Stats(); got.Queued != 0 {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+
+	q0.Done()
+	expectNotStarted(t, started)
+}
+
+func TestController_MemoryQuota(t *testing.T) {
+	c := newController(t, controller.Config{
+		ConcurrencyQuota:                2,
+		QueueSize:                       10,
+		InitialMemoryBytesQuotaPerQuery: 10,
+		MemoryBytesQuotaPerQuery:        50,
+		MaxMemoryBytes:                  100,
+	})
+
+	for _, tc := range []struct {
+		name      string
+		size      int
+		resources flux.ResourceManagement
+		wantErr   bool
+	}{
+		{name: "within quota", size: 40},
+		{name: "exceeds quota", size: 60, wantErr: true},
+		{name: "exceeds query quota", size: 40, resources: flux.ResourceManagement{MemoryBytesQuota: 20}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errC := make(chan error, 1)
+			program := &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					errC <- alloc.Account(tc.size)
+				},
+			}
+			q := mustQuery(t, c, program, tc.resources)
+			for range q.Results() {
+			}
+			err := <-errC
+			q.Done()
+
+			if tc.wantErr {
+				if got, want := errors.Code(err), codes.ResourceExhausted; got != want {
+					t.Fatalf("unexpected error code: want %v, got %v (%v)", want, got, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Stats().ReservedMemory; got != 0 {
+				t.Fatalf("expected memory to be released, got %d bytes", got)
+			}
+		})
+	}
+}
+
+func TestController_MemoryAdmission(t *testing.T) {
+	c := newController(t, controller.Config{
+		ConcurrencyQuota:                2,
+		QueueSize:                       10,
+		InitialMemoryBytesQuotaPerQuery: 10,
+		MaxMemoryBytes:                  20,
+	})
+	started := make(chan string)
+
+	program := &mock.Program{
+		ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+			// Use the rest of the memory pool.
+			if err := alloc.Account(20); err != nil {
+				t.Error(err)
+			}
+			started <- "q0"
+			<-ctx.Done()
+		},
+	}
+	q0 := mustQuery(t, c, program, flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+
+	// There is a free slot, but not enough memory to admit the query.
+	q1 := mustQuery(t, c, blockingProgram("q1", started), flux.ResourceManagement{})
+	expectNotStarted(t, started)
+
+	q0.Done()
+	expectStarted(t, started, "q1")
+	q1.Done()
+
+	if stats := q1.Statistics(); stats.RequeueDuration <= 0 {
+		t.Errorf("expected a requeue duration, got %v", stats.RequeueDuration)
+	}
+}
+
+func TestController_Shutdown(t *testing.T) {
+	c := newController(t, controller.Config{ConcurrencyQuota: 1, QueueSize: 10})
+	started := make(chan string)
+
+	q0 := mustQuery(t, c, blockingProgram("q0", started), flux.ResourceManagement{})
+	expectStarted(t, started, "q0")
+	q1 := mustQuery(t, c, blockingProgram("q1", started), flux.ResourceManagement{})
+
+	done := make(chan error)
+	go func() {
+		done <- c.Shutdown(context.Background())
+	}()
+
+	// The queued query is canceled.
+	for range q1.Results() {
+		t.Fatal("unexpected result")
+	}
+	q1.Done()
+
+	if _, err := c.Query(context.Background(), blockingProgram("q2", started), flux.ResourceManagement{}); errors.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the controller to reject queries, got %v", err)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("shutdown returned before the executing query was done: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	q0.Done()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+)
+
+// memoryPool is the memory shared by all of the executing queries.
+type memoryPool struct {
+	mu sync.Mutex
+	// limit is the size of the pool.
+	// A zero value means the pool is unlimited.
+	limit int64
+	used  int64
+}
+
+// reserve reserves n bytes from the pool.
+// It reports false if the pool does not have enough free memory.
+func (p *memoryPool) reserve(n int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.limit > 0 && p.used+n > p.limit {
+		return false
+	}
+	p.used += n
+	return true
+}
+
+// release returns n bytes to the pool.
+func (p *memoryPool) release(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.used -= n
+}
+
+func (p *memoryPool) reserved() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.used
+}
+
+var _ memory.Manager = (*queryMemoryManager)(nil)
+
+// queryMemoryManager implements memory.Manager for a single query.
+// It reserves the memory requested by the query's allocator
+// from the memory pool up to the memory limit of the query.
+type queryMemoryManager struct {
+	pool *memoryPool
+
+	mu sync.Mutex
+	// limit is the maximum amount of memory the query may use.
+	// A zero value means the query is only limited by the pool.
+	limit int64
+	used  int64
+}
+
+func newQueryMemoryManager(pool *memoryPool, initial, limit int64) *queryMemoryManager {
+	return &queryMemoryManager{
+		pool:  pool,
+		limit: limit,
+		used:  initial,
+	}
+}
+
+func (m *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.limit > 0 && m.used+want > m.limit {
+		return 0, errors.Newf(codes.ResourceExhausted, "query memory limit of %d bytes reached", m.limit)
+	}
+	if !m.pool.reserve(want) {
+		return 0, errors.New(codes.ResourceExhausted, "query controller memory pool is exhausted")
+	}
+	m.used += want
+	return want, nil
+}
+
+func (m *queryMemoryManager) FreeMemory(bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.used -= bytes
+	m.pool.release(bytes)
+}
+
+// reserved returns the memory currently reserved by the query.
+func (m *queryMemoryManager) reserved() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used
+}
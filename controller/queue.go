@@ -0,0 +1,38 @@
+package controller
+
+// queryQueue is a priority queue of the queries waiting to be executed.
+// It implements heap.Interface. Queries with a lower priority value
+// come first and queries with the same priority are ordered by
+// the time they were submitted.
+type queryQueue []*Query
+
+func (q queryQueue) Len() int { return len(q) }
+
+func (q queryQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q queryQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queryQueue) Push(x interface{}) {
+	query := x.(*Query)
+	query.index = len(*q)
+	*q = append(*q, query)
+}
+
+func (q *queryQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	query := old[n-1]
+	old[n-1] = nil
+	query.index = -1
+	*q = old[:n-1]
+	return query
+}
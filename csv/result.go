@@ -3,6 +3,7 @@ package csv
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
@@ -42,12 +43,15 @@ const (
 
 	commentPrefix = "#"
 
-	stringDatatype = "string"
-	timeDatatype   = "dateTime"
-	floatDatatype  = "double"
-	boolDatatype   = "boolean"
-	intDatatype    = "long"
-	uintDatatype   = "unsignedLong"
+	stringDatatype   = "string"
+	timeDatatype     = "dateTime"
+	floatDatatype    = "double"
+	boolDatatype     = "boolean"
+	intDatatype      = "long"
+	uintDatatype     = "unsignedLong"
+	decimalDatatype  = "decimal"
+	durationDatatype = "duration"
+	bytesDatatype    = "base64Binary"
 
 	timeDataTypeWithFmt = "dateTime:RFC3339"
 
@@ -1090,6 +1094,12 @@ func writeDatatypes(writer *csv.Writer, row []string, cols []colMeta) error {
 			row[j] = stringDatatype
 		case flux.TTime:
 			row[j] = timeDataTypeWithFmt
+		case flux.TDecimal:
+			row[j] = decimalDatatype
+		case flux.TDuration:
+			row[j] = durationDatatype
+		case flux.TBytes:
+			row[j] = bytesDatatype
 		default:
 			return fmt.Errorf("unknown column type %v", c.Type)
 		}
@@ -1163,6 +1173,24 @@ func decodeValue(value string, c colMeta) (values.Value, error) {
 			return nil, err
 		}
 		val = values.NewTime(v)
+	case flux.TDecimal:
+		v, err := values.ParseDecimal(value)
+		if err != nil {
+			return nil, err
+		}
+		val = values.NewDecimal(v)
+	case flux.TDuration:
+		v, err := decodeDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		val = values.NewDuration(v)
+	case flux.TBytes:
+		v, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		val = values.NewBytes(v)
 	default:
 		return nil, fmt.Errorf("unsupported type %v", c.Type)
 	}
@@ -1203,6 +1231,24 @@ func decodeValueInto(c colMeta, value string, b array.Builder) error {
 			return err
 		}
 		return arrow.AppendTime(b, t)
+	case flux.TDecimal:
+		v, err := values.ParseDecimal(value)
+		if err != nil {
+			return err
+		}
+		return arrow.AppendDecimal(b, v)
+	case flux.TDuration:
+		v, err := decodeDuration(value)
+		if err != nil {
+			return err
+		}
+		return arrow.AppendDuration(b, v)
+	case flux.TBytes:
+		v, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return err
+		}
+		return arrow.AppendBytes(b, v)
 	default:
 		return fmt.Errorf("unsupported type %v", c.Type)
 	}
@@ -1226,6 +1272,16 @@ func encodeValue(value values.Value, c colMeta) (string, error) {
 		return value.Str(), nil
 	case flux.TTime:
 		return encodeTime(value.Time(), c.fmt), nil
+	case flux.TDecimal:
+		return value.Decimal().String(), nil
+	case flux.TDuration:
+		nsecs, err := value.Duration().FixedNanoseconds()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(nsecs, 10), nil
+	case flux.TBytes:
+		return base64.StdEncoding.EncodeToString(value.Bytes()), nil
 	default:
 		return "", fmt.Errorf("unknown type %v", c.Type)
 	}
@@ -1258,6 +1314,18 @@ func encodeValueFrom(i, j int, c colMeta, cr flux.ColReader) (string, error) {
 		if cr.Times(j).IsValid(i) {
 			v = encodeTime(execute.Time(cr.Times(j).Value(i)), c.fmt)
 		}
+	case flux.TDecimal:
+		if cr.Decimals(j).IsValid(i) {
+			v = values.NewDecimalFromNum(cr.Decimals(j).Value(i)).String()
+		}
+	case flux.TDuration:
+		if cr.Durations(j).IsValid(i) {
+			v = strconv.FormatInt(cr.Durations(j).Value(i), 10)
+		}
+	case flux.TBytes:
+		if cr.Bytes(j).IsValid(i) {
+			v = base64.StdEncoding.EncodeToString(cr.Bytes(j).Value(i))
+		}
 	default:
 		return "", fmt.Errorf("unknown type %v", c.Type)
 	}
@@ -1303,12 +1371,27 @@ func decodeType(datatype string) (t flux.ColType, desc string, err error) {
 		t = flux.TString
 	case timeDatatype:
 		t = flux.TTime
+	case decimalDatatype:
+		t = flux.TDecimal
+	case durationDatatype:
+		t = flux.TDuration
+	case bytesDatatype:
+		t = flux.TBytes
 	default:
 		err = fmt.Errorf("unsupported data type %q", typ)
 	}
 	return
 }
 
+// decodeDuration decodes a duration encoded as a signed number
+// of nanoseconds. A duration literal is also accepted.
+func decodeDuration(value string) (values.Duration, error) {
+	if nsecs, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return values.ConvertDurationNsecs(time.Duration(nsecs)), nil
+	}
+	return values.ParseDuration(value)
+}
+
 func equalCols(a, b []colMeta) bool {
 	if len(a) != len(b) {
 		return false
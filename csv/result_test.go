@@ -316,6 +316,90 @@ func TestResultDecoder(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name:          "single table with decimal",
+			encoderConfig: csv.DefaultEncoderConfig(),
+			encoded: toCRLF(`#datatype,string,long,dateTime:RFC3339,string,decimal
+#group,false,false,false,true,false
+#default,_result,,,,
+,result,table,_time,_measurement,_value
+,,0,2018-04-17T00:00:00Z,price,12.3400
+,,0,2018-04-17T00:00:01Z,price,-0.000000001
+,,0,2018-04-17T00:00:02Z,price,
+`),
+			result: &executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_value", Type: flux.TDecimal},
+					},
+					Data: [][]interface{}{
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							"price",
+							mustParseDecimal("12.34"),
+						},
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 1, 0, time.UTC)),
+							"price",
+							mustParseDecimal("-0.000000001"),
+						},
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 2, 0, time.UTC)),
+							"price",
+							nil,
+						},
+					},
+				}},
+			},
+		},
+		{
+			name:          "single table with duration and bytes",
+			encoderConfig: csv.DefaultEncoderConfig(),
+			encoded: toCRLF(`#datatype,string,long,dateTime:RFC3339,duration,base64Binary,duration
+#group,false,false,false,true,false,false
+#default,_result,,,,,
+,result,table,_time,window,_value,elapsed
+,,0,2018-04-17T00:00:00Z,5400000000000,aGVsbG8=,10000000000
+,,0,2018-04-17T00:00:01Z,5400000000000,,-5000000
+,,0,2018-04-17T00:00:02Z,5400000000000,AAE=,
+`),
+			result: &executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"window"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "window", Type: flux.TDuration},
+						{Label: "_value", Type: flux.TBytes},
+						{Label: "elapsed", Type: flux.TDuration},
+					},
+					Data: [][]interface{}{
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							values.ConvertDurationNsecs(90 * time.Minute),
+							[]byte("hello"),
+							values.ConvertDurationNsecs(10 * time.Second),
+						},
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 1, 0, time.UTC)),
+							values.ConvertDurationNsecs(90 * time.Minute),
+							nil,
+							values.ConvertDurationNsecs(-5 * time.Millisecond),
+						},
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 2, 0, time.UTC)),
+							values.ConvertDurationNsecs(90 * time.Minute),
+							[]byte{0, 1},
+							nil,
+						},
+					},
+				}},
+			},
+		},
 		{
 			name:          "single table with null in group key column",
 			encoderConfig: csv.DefaultEncoderConfig(),
@@ -2389,3 +2473,11 @@ var crlfPattern = regexp.MustCompile(`\r?\n`)
 func toCRLF(data string) []byte {
 	return []byte(crlfPattern.ReplaceAllString(data, "\r\n"))
 }
+
+func mustParseDecimal(s string) values.Decimal {
+	d, err := values.ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
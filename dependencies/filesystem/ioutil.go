@@ -2,8 +2,12 @@ package filesystem
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 	"os"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
 )
 
 // ReadFile will open the file from the service and read
@@ -30,6 +34,20 @@ func OpenFile(ctx context.Context, filename string) (File, error) {
 	return fs.Open(filename)
 }
 
+// CreateFile will create the file using the service.
+// The service must implement WritableService.
+func CreateFile(ctx context.Context, filename string) (io.WriteCloser, error) {
+	fs, err := Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wfs, ok := fs.(WritableService)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "filesystem service does not support writing files")
+	}
+	return wfs.Create(filename)
+}
+
 // Stat will retrieve the os.FileInfo for a file.
 func Stat(ctx context.Context, filename string) (os.FileInfo, error) {
 	fs, err := Get(ctx)
@@ -20,6 +20,15 @@ type Service interface {
 	Open(fpath string) (File, error)
 }
 
+// WritableService is a Service that can also create files.
+type WritableService interface {
+	Service
+
+	// Create creates or truncates the file at the path,
+	// creating any missing parent directories.
+	Create(fpath string) (io.WriteCloser, error)
+}
+
 type key int
 
 const serviceKey key = iota
@@ -1,11 +1,13 @@
 package filesystem
 
 import (
+	"io"
 	"os"
+	"path/filepath"
 )
 
 // SystemFS implements the filesystem.Service by proxying all requests
-// to the filesystem.
+// to the filesystem. It also implements WritableService.
 var SystemFS Service = systemFS{}
 
 type systemFS struct{}
@@ -17,3 +19,10 @@ func (systemFS) Open(fpath string) (File, error) {
 	}
 	return f, nil
 }
+
+func (systemFS) Create(fpath string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(fpath)
+}
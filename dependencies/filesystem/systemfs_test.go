@@ -95,3 +95,32 @@ func TestSystemFS_ReadFile(t *testing.T) {
 		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
 	}
 }
+
+func TestSystemFS_CreateFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-systemfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	fpath := filepath.Join(dir, "a", "b", "file.txt")
+	ctx := filesystem.Inject(context.Background(), filesystem.SystemFS)
+	f, err := filesystem.CreateFile(ctx, fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "Hello, World!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := filesystem.ReadFile(ctx, fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "Hello, World!"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
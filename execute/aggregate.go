@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/flux/internal/feature"
 	fluxmemory "github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
 )
 
 // AggregateTransformation implements a transformation that aggregates
@@ -168,6 +169,11 @@ func (c SimpleAggregateConfig) Copy() SimpleAggregateConfig {
 	return nc
 }
 
+// ModifiedGroupKeyColumns reports that aggregates do not modify the group key.
+func (c SimpleAggregateConfig) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
 func (c *SimpleAggregateConfig) ReadArgs(args flux.Arguments) error {
 	if col, ok, err := args.GetString("column"); err != nil {
 		return err
@@ -259,6 +265,10 @@ func (t *simpleAggregateTransformation) Process(id DatasetID, tbl flux.Table) er
 			vf = t.agg.NewFloatAgg()
 		case flux.TString:
 			vf = t.agg.NewStringAgg()
+		case flux.TDecimal:
+			if agg, ok := t.agg.(DecimalAggregate); ok {
+				vf = agg.NewDecimalAgg()
+			}
 		}
 		if vf == nil {
 			return errors.Newf(codes.FailedPrecondition, "unsupported aggregate column type %v", c.Type)
@@ -294,6 +304,10 @@ func (t *simpleAggregateTransformation) Process(id DatasetID, tbl flux.Table) er
 				vf.(DoFloatAgg).DoFloat(cr.Floats(tj))
 			case flux.TString:
 				vf.(DoStringAgg).DoString(cr.Strings(tj))
+			case flux.TDecimal:
+				if err := vf.(DoDecimalAgg).DoDecimal(cr.Decimals(tj)); err != nil {
+					return err
+				}
 			default:
 				return errors.Newf(codes.Invalid, "unsupported aggregate type %v", c.Type)
 			}
@@ -340,6 +354,11 @@ func (t *simpleAggregateTransformation) Process(id DatasetID, tbl flux.Table) er
 			if err := builder.AppendString(bj, v); err != nil {
 				return err
 			}
+		case flux.TDecimal:
+			v := vf.(DecimalValueFunc).ValueDecimal()
+			if err := builder.AppendDecimal(bj, v); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -395,9 +414,16 @@ func (t *simpleAggregateTransformation2) initializeState(chunk table.Chunk, curr
 			state[i].agg = t.agg.NewFloatAgg()
 		case flux.TString:
 			state[i].agg = t.agg.NewStringAgg()
+		case flux.TDecimal:
+			if agg, ok := t.agg.(DecimalAggregate); ok {
+				state[i].agg = agg.NewDecimalAgg()
+			}
 		default:
 			return nil, errors.Newf(codes.FailedPrecondition, "unsupported aggregate column type %v", col.Type)
 		}
+		if state[i].agg == nil {
+			return nil, errors.Newf(codes.FailedPrecondition, "unsupported aggregate column type %v", col.Type)
+		}
 		state[i].inType = col.Type
 	}
 	return state, nil
@@ -432,6 +458,10 @@ func (t *simpleAggregateTransformation2) Aggregate(chunk table.Chunk, state inte
 			agg.(DoFloatAgg).DoFloat(chunk.Floats(idx))
 		case flux.TString:
 			agg.(DoStringAgg).DoString(chunk.Strings(idx))
+		case flux.TDecimal:
+			if err := agg.(DoDecimalAgg).DoDecimal(chunk.Decimals(idx)); err != nil {
+				return nil, false, err
+			}
 		default:
 			// This error should be impossible because loadState should have
 			// already caught invalid input types and we have already verified
@@ -480,6 +510,9 @@ func (t *simpleAggregateTransformation2) Compute(key flux.GroupKey, state interf
 		case flux.TString:
 			v := s.agg.(StringValueFunc).ValueString()
 			arr = array.StringRepeat(v, 1, mem)
+		case flux.TDecimal:
+			v := s.agg.(DecimalValueFunc).ValueDecimal()
+			arr = array.DecimalRepeat(v.Num(), isNull, 1, mem)
 		}
 		buffer.Values = append(buffer.Values, arr)
 	}
@@ -500,6 +533,12 @@ type SimpleAggregate interface {
 	NewStringAgg() DoStringAgg
 }
 
+// DecimalAggregate is implemented by a SimpleAggregate
+// that can also aggregate decimal columns.
+type DecimalAggregate interface {
+	NewDecimalAgg() DoDecimalAgg
+}
+
 type ValueFunc interface {
 	Type() flux.ColType
 	IsNull() bool
@@ -525,6 +564,14 @@ type DoStringAgg interface {
 	DoString(*array.String)
 }
 
+// DoDecimalAgg aggregates decimal values.
+// Unlike the other types, decimal arithmetic
+// can overflow so it may return an error.
+type DoDecimalAgg interface {
+	ValueFunc
+	DoDecimal(*array.Decimal) error
+}
+
 type BoolValueFunc interface {
 	ValueBool() bool
 }
@@ -540,3 +587,6 @@ type UIntValueFunc interface {
 type StringValueFunc interface {
 	ValueString() string
 }
+type DecimalValueFunc interface {
+	ValueDecimal() values.Decimal
+}
@@ -2,6 +2,7 @@ package execute
 
 import (
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
 )
 
 const (
@@ -11,6 +12,8 @@ const (
 	float64Size = 8
 	stringSize  = 16
 	timeSize    = 8
+	decimalSize = 16
+	bytesSize   = 24
 )
 
 // Allocator tracks the amount of memory being consumed by a query.
@@ -219,3 +222,65 @@ func (a *Allocator) GrowTimes(slice []Time, n int) []Time {
 	a.account(diff, timeSize)
 	return s
 }
+
+// Decimals makes a slice of Decimal values.
+func (a *Allocator) Decimals(l, c int) []values.Decimal {
+	a.account(c, decimalSize)
+	return make([]values.Decimal, l, c)
+}
+
+// AppendDecimals appends Decimals to a slice
+func (a *Allocator) AppendDecimals(slice []values.Decimal, vs ...values.Decimal) []values.Decimal {
+	if cap(slice)-len(slice) >= len(vs) {
+		return append(slice, vs...)
+	}
+	s := append(slice, vs...)
+	diff := cap(s) - cap(slice)
+	a.account(diff, decimalSize)
+	return s
+}
+
+func (a *Allocator) GrowDecimals(slice []values.Decimal, n int) []values.Decimal {
+	newCap := len(slice) + n
+	if newCap < cap(slice) {
+		return slice[:newCap]
+	}
+	// grow capacity same way as built-in append
+	newCap = newCap*3/2 + 1
+	s := make([]values.Decimal, len(slice)+n, newCap)
+	copy(s, slice)
+	diff := cap(s) - cap(slice)
+	a.account(diff, decimalSize)
+	return s
+}
+
+// Binaries makes a slice of bytes values.
+func (a *Allocator) Binaries(l, c int) [][]byte {
+	a.account(c, bytesSize)
+	return make([][]byte, l, c)
+}
+
+// AppendBinaries appends bytes values to a slice
+func (a *Allocator) AppendBinaries(slice [][]byte, vs ...[]byte) [][]byte {
+	if cap(slice)-len(slice) >= len(vs) {
+		return append(slice, vs...)
+	}
+	s := append(slice, vs...)
+	diff := cap(s) - cap(slice)
+	a.account(diff, bytesSize)
+	return s
+}
+
+func (a *Allocator) GrowBinaries(slice [][]byte, n int) [][]byte {
+	newCap := len(slice) + n
+	if newCap < cap(slice) {
+		return slice[:newCap]
+	}
+	// grow capacity same way as built-in append
+	newCap = newCap*3/2 + 1
+	s := make([][]byte, len(slice)+n, newCap)
+	copy(s, slice)
+	diff := cap(s) - cap(slice)
+	a.account(diff, bytesSize)
+	return s
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/metadata"
+	"github.com/influxdata/flux/plan"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +17,10 @@ const executionDependenciesKey key = iota
 type ExecutionOptions struct {
 	OperatorProfiler *OperatorProfiler
 	Profilers        []Profiler
+
+	// Location is the value of the location option.
+	// Windows without a location use it.
+	Location plan.Location
 }
 
 // ExecutionDependencies represents the dependencies that a function call
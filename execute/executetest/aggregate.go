@@ -50,6 +50,37 @@ func AggFuncTestHelper(t *testing.T, agg execute.SimpleAggregate, data *array.Fl
 	}
 }
 
+// DecimalAggFuncTestHelper splits the decimal data in half, runs DoDecimal over each split
+// and compares the decimal value to want.
+func DecimalAggFuncTestHelper(t *testing.T, agg execute.DecimalAggregate, data *array.Decimal, want interface{}) {
+	t.Helper()
+
+	h := data.Len() / 2
+	vf := agg.NewDecimalAgg()
+
+	d := arrow.DecimalSlice(data, 0, h)
+	if err := vf.DoDecimal(d); err != nil {
+		t.Fatal(err)
+	}
+	d.Release()
+	if h < data.Len() {
+		d := arrow.DecimalSlice(data, h, data.Len())
+		if err := vf.DoDecimal(d); err != nil {
+			t.Fatal(err)
+		}
+		d.Release()
+	}
+
+	var got interface{}
+	if !vf.IsNull() {
+		got = vf.(execute.DecimalValueFunc).ValueDecimal()
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected value -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
 // AggFuncBenchmarkHelper benchmarks the aggregate function over data and compares to wantValue
 func AggFuncBenchmarkHelper(b *testing.B, agg execute.SimpleAggregate, data *array.Float, want interface{}) {
 	b.Helper()
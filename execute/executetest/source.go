@@ -46,7 +46,11 @@ func (src *FromProcedureSpec) Copy() plan.ProcedureSpec {
 }
 
 func (src *FromProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
-	return plan.Cost{}, plan.Statistics{}
+	stats := plan.Statistics{GroupCardinality: int64(len(src.data))}
+	for _, tbl := range src.data {
+		stats.Cardinality += int64(len(tbl.Data))
+	}
+	return plan.Cost{}, stats
 }
 
 func (src *FromProcedureSpec) AddTransformation(t execute.Transformation) {
@@ -159,6 +159,43 @@ func (t *Table) Do(f func(flux.ColReader) error) error {
 			}
 			cols[j] = b.NewIntArray()
 			b.Release()
+		case flux.TDecimal:
+			b := arrow.NewDecimalBuilder(t.Alloc)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					b.Append(v.(values.Decimal).Num())
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewDecimalArray()
+			b.Release()
+		case flux.TDuration:
+			b := arrow.NewIntBuilder(t.Alloc)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					nsecs, err := v.(values.Duration).FixedNanoseconds()
+					if err != nil {
+						panic(err)
+					}
+					b.Append(nsecs)
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewIntArray()
+			b.Release()
+		case flux.TBytes:
+			b := arrow.NewBinaryBuilder(t.Alloc)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					b.Append(v.([]byte))
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewBinaryArray()
+			b.Release()
 		case flux.TUInt:
 			b := arrow.NewUintBuilder(t.Alloc)
 			for i := range t.Data {
@@ -231,6 +268,18 @@ func (cr *ColReader) Times(j int) *array.Int {
 	return cr.cols[j].(*array.Int)
 }
 
+func (cr *ColReader) Decimals(j int) *array.Decimal {
+	return cr.cols[j].(*array.Decimal)
+}
+
+func (cr *ColReader) Durations(j int) *array.Int {
+	return cr.cols[j].(*array.Int)
+}
+
+func (cr *ColReader) Bytes(j int) *array.Binary {
+	return cr.cols[j].(*array.Binary)
+}
+
 func (cr *ColReader) Retain() {
 	for _, col := range cr.cols {
 		col.Retain()
@@ -309,6 +358,43 @@ func (t *RowWiseTable) Do(f func(flux.ColReader) error) error {
 			}
 			cols[j] = b.NewIntArray()
 			b.Release()
+		case flux.TDecimal:
+			b := arrow.NewDecimalBuilder(nil)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					b.Append(v.(values.Decimal).Num())
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewDecimalArray()
+			b.Release()
+		case flux.TDuration:
+			b := arrow.NewIntBuilder(nil)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					nsecs, err := v.(values.Duration).FixedNanoseconds()
+					if err != nil {
+						panic(err)
+					}
+					b.Append(nsecs)
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewIntArray()
+			b.Release()
+		case flux.TBytes:
+			b := arrow.NewBinaryBuilder(nil)
+			for i := range t.Data {
+				if v := t.Data[i][j]; v != nil {
+					b.Append(v.([]byte))
+				} else {
+					b.AppendNull()
+				}
+			}
+			cols[j] = b.NewBinaryArray()
+			b.Release()
 		case flux.TUInt:
 			b := arrow.NewUintBuilder(nil)
 			for i := range t.Data {
@@ -345,6 +431,12 @@ func (t *RowWiseTable) Do(f func(flux.ColReader) error) error {
 				row[j] = arrow.StringSlice(cols[j].(*array.String), i, i+1)
 			case flux.TTime:
 				row[j] = arrow.IntSlice(cols[j].(*array.Int), i, i+1)
+			case flux.TDecimal:
+				row[j] = arrow.DecimalSlice(cols[j].(*array.Decimal), i, i+1)
+			case flux.TDuration:
+				row[j] = arrow.IntSlice(cols[j].(*array.Int), i, i+1)
+			case flux.TBytes:
+				row[j] = arrow.BinarySlice(cols[j].(*array.Binary), i, i+1)
 			case flux.TUInt:
 				row[j] = arrow.UintSlice(cols[j].(*array.Uint), i, i+1)
 			}
@@ -410,6 +502,12 @@ func ConvertTable(tbl flux.Table) (*Table, error) {
 					v = key.ValueString(j)
 				case flux.TTime:
 					v = key.ValueTime(j)
+				case flux.TDecimal:
+					v = key.Value(j).Decimal()
+				case flux.TDuration:
+					v = key.ValueDuration(j)
+				case flux.TBytes:
+					v = key.Value(j).Bytes()
 				default:
 					return nil, fmt.Errorf("unsupported column type %v", c.Type)
 				}
@@ -448,6 +546,18 @@ func ConvertTable(tbl flux.Table) (*Table, error) {
 					if col := cr.Times(j); col.IsValid(i) {
 						row[j] = values.Time(col.Value(i))
 					}
+				case flux.TDecimal:
+					if col := cr.Decimals(j); col.IsValid(i) {
+						row[j] = values.NewDecimalFromNum(col.Value(i))
+					}
+				case flux.TDuration:
+					if col := cr.Durations(j); col.IsValid(i) {
+						row[j] = values.ConvertDurationNsecs(time.Duration(col.Value(i)))
+					}
+				case flux.TBytes:
+					if col := cr.Bytes(j); col.IsValid(i) {
+						row[j] = append([]byte(nil), col.Value(i)...)
+					}
 				default:
 					panic(fmt.Errorf("unknown column type %s", c.Type))
 				}
@@ -796,6 +906,12 @@ func RunTableTests(t *testing.T, tt TableTest) {
 							return cr.Bools(i).Len()
 						case flux.TTime:
 							return cr.Times(i).Len()
+						case flux.TDecimal:
+							return cr.Decimals(i).Len()
+						case flux.TDuration:
+							return cr.Durations(i).Len()
+						case flux.TBytes:
+							return cr.Bytes(i).Len()
 						default:
 							panic(fmt.Errorf("unexpected column type: %v", cr.Cols()[i].Type))
 						}
@@ -853,6 +969,18 @@ func colReadersEqual(a, b flux.ColReader) bool {
 			if a.Times(i) != b.Times(i) {
 				return false
 			}
+		case flux.TDecimal:
+			if a.Decimals(i) != b.Decimals(i) {
+				return false
+			}
+		case flux.TDuration:
+			if a.Durations(i) != b.Durations(i) {
+				return false
+			}
+		case flux.TBytes:
+			if a.Bytes(i) != b.Bytes(i) {
+				return false
+			}
 		}
 	}
 	return true
@@ -15,6 +15,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/metadata"
 	"github.com/influxdata/flux/plan"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -104,8 +105,12 @@ func (e *executor) createExecutionState(ctx context.Context, p *plan.Spec, a *me
 		logger:     e.logger,
 	}
 	v := &createExecutionNodeVisitor{
-		es:    es,
-		nodes: make(map[plan.Node]Node),
+		es:       es,
+		nodes:    make(map[plan.Node]Node),
+		ops:      make(map[plan.Node]string),
+		profiler: operatorProfiler(ctx),
+		profiled: make(map[plan.Node]bool),
+		allocs:   make(map[plan.Node]*memory.Allocator),
 	}
 
 	if err := p.BottomUpWalk(v.Visit); err != nil {
@@ -125,6 +130,28 @@ func (e *executor) createExecutionState(ctx context.Context, p *plan.Spec, a *me
 type createExecutionNodeVisitor struct {
 	es    *executionState
 	nodes map[plan.Node]Node
+	// ops holds the operation type of each node.
+	ops map[plan.Node]string
+
+	// profiler is the operator profiler, if it is enabled,
+	// and profiled holds the nodes whose output it counts.
+	// When it is enabled, each node allocates from its own
+	// child allocator so its allocations can be reported.
+	profiler *OperatorProfiler
+	profiled map[plan.Node]bool
+	allocs   map[plan.Node]*memory.Allocator
+}
+
+// addTransformation adds a transformation that reads the output of the node.
+// When the operator profiler is enabled, the first transformation of each
+// node counts the rows that the node produces and reports the memory
+// that the node allocated once it finishes.
+func (v *createExecutionNodeVisitor) addTransformation(pn plan.Node, t Transformation) {
+	if v.profiler != nil && !v.profiled[pn] {
+		v.profiled[pn] = true
+		t = newRowsOutProfiler(t, v.profiler, v.ops[pn], string(pn.ID()), v.allocs[pn], v.es.alloc)
+	}
+	v.nodes[pn].AddTransformation(t)
 }
 
 func skipYields(pn plan.Node) plan.Node {
@@ -162,7 +189,7 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 	if yieldSpec, ok := spec.(plan.YieldProcedureSpec); ok {
 		r := newResult(yieldSpec.YieldName())
 		v.es.results[yieldSpec.YieldName()] = r
-		v.nodes[skipYields(node)].AddTransformation(r)
+		v.addTransformation(skipYields(node), r)
 		return nil
 	}
 
@@ -178,9 +205,14 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 	// Build execution context
 	ec := executionContext{
 		es:            v.es,
+		alloc:         v.es.alloc,
 		parents:       make([]DatasetID, len(node.Predecessors())),
 		streamContext: streamContext,
 	}
+	if v.profiler != nil {
+		ec.alloc = v.es.alloc.Child()
+		v.allocs[node] = ec.alloc
+	}
 
 	for i, pred := range nonYieldPredecessors(node) {
 		ec.parents[i] = DatasetIDFromNodeID(pred.ID())
@@ -203,6 +235,7 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 		source.SetLabel(string(node.ID()))
 		v.es.sources = append(v.es.sources, source)
 		v.nodes[node] = source
+		v.ops[node] = reflect.TypeOf(source).String()
 	} else {
 
 		// If node is internal, create a transformation.
@@ -228,19 +261,19 @@ func (v *createExecutionNodeVisitor) Visit(node plan.Node) error {
 		}
 		ds.SetTriggerSpec(ppn.TriggerSpec)
 		v.nodes[node] = ds
+		v.ops[node] = OperationType(tr)
 
 		for _, p := range nonYieldPredecessors(node) {
-			executionNode := v.nodes[p]
-			transport := newConsecutiveTransport(v.es.ctx, v.es.dispatcher, tr, node, v.es.logger, v.es.alloc)
+			transport := newConsecutiveTransport(v.es.ctx, v.es.dispatcher, tr, node, v.es.logger, ec.alloc)
 			v.es.transports = append(v.es.transports, transport)
-			executionNode.AddTransformation(transport)
+			v.addTransformation(p, transport)
 		}
 
 		if plan.HasSideEffect(spec) && len(node.Successors()) == 0 {
 			name := string(node.ID())
 			r := newResult(name)
 			v.es.results[name] = r
-			v.nodes[skipYields(node)].AddTransformation(r)
+			v.addTransformation(skipYields(node), r)
 		}
 	}
 
@@ -259,12 +292,19 @@ func (es *executionState) do() {
 	for _, src := range es.sources {
 		wg.Add(1)
 		go func(src Source) {
+			// The spans must be finished before the execution is done
+			// so the operator profiler receives the profile of the source.
+			defer wg.Done()
+
 			ctx := es.ctx
 			if ctxWithSpan, span := StartSpanFromContext(ctx, reflect.TypeOf(src).String(), src.Label()); span != nil {
 				ctx = ctxWithSpan
 				defer span.Finish()
 			}
-			defer wg.Done()
+			ctx, span := flux.Tracer(ctx).Start(ctx, reflect.TypeOf(src).String(),
+				trace.WithAttributes(operatorAttributes(reflect.TypeOf(src).String(), string(src.Label()))...),
+			)
+			defer span.End()
 
 			// Setup panic handling on the source goroutines
 			defer func() {
@@ -329,6 +369,7 @@ func (es *executionState) do() {
 // Need a unique stream context per execution context
 type executionContext struct {
 	es            *executionState
+	alloc         *memory.Allocator
 	parents       []DatasetID
 	streamContext streamContext
 }
@@ -350,7 +391,7 @@ func (ec executionContext) StreamContext() StreamContext {
 }
 
 func (ec executionContext) Allocator() *memory.Allocator {
-	return ec.es.alloc
+	return ec.alloc
 }
 
 func (ec executionContext) Parents() []DatasetID {
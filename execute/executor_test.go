@@ -9,6 +9,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/feature"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	_ "github.com/influxdata/flux/fluxinit/static"
@@ -18,6 +19,7 @@ import (
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -665,6 +667,152 @@ func TestExecutor_Execute(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: `parallel lanes`,
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from-test", executetest.NewFromProcedureSpec(
+						[]*executetest.Table{
+							{
+								KeyCols: []string{"_start", "_stop", "t0"},
+								ColMeta: []flux.ColMeta{
+									{Label: "_start", Type: flux.TTime},
+									{Label: "_stop", Type: flux.TTime},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "t0", Type: flux.TString},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{execute.Time(0), execute.Time(5), execute.Time(0), "a", 1.0},
+									{execute.Time(0), execute.Time(5), execute.Time(1), "a", 6.0},
+								},
+							},
+							{
+								KeyCols: []string{"_start", "_stop", "t0"},
+								ColMeta: []flux.ColMeta{
+									{Label: "_start", Type: flux.TTime},
+									{Label: "_stop", Type: flux.TTime},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "t0", Type: flux.TString},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{execute.Time(0), execute.Time(5), execute.Time(0), "b", 2.0},
+									{execute.Time(0), execute.Time(5), execute.Time(1), "b", 7.0},
+								},
+							},
+							{
+								KeyCols: []string{"_start", "_stop", "t0"},
+								ColMeta: []flux.ColMeta{
+									{Label: "_start", Type: flux.TTime},
+									{Label: "_stop", Type: flux.TTime},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "t0", Type: flux.TString},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{execute.Time(0), execute.Time(5), execute.Time(0), "c", 3.0},
+									{execute.Time(0), execute.Time(5), execute.Time(1), "c", 8.0},
+								},
+							},
+							{
+								KeyCols: []string{"_start", "_stop", "t0"},
+								ColMeta: []flux.ColMeta{
+									{Label: "_start", Type: flux.TTime},
+									{Label: "_stop", Type: flux.TTime},
+									{Label: "_time", Type: flux.TTime},
+									{Label: "t0", Type: flux.TString},
+									{Label: "_value", Type: flux.TFloat},
+								},
+								Data: [][]interface{}{
+									{execute.Time(0), execute.Time(5), execute.Time(0), "d", 4.0},
+									{execute.Time(0), execute.Time(5), execute.Time(1), "d", 9.0},
+								},
+							},
+						},
+					)),
+					plan.CreatePhysicalNode("filter_partition", &plan.PartitionLanesProcedureSpec{Lanes: 2}),
+					plan.CreatePhysicalNode("filter", &universe.FilterProcedureSpec{
+						Fn: interpreter.ResolvedFunction{
+							Scope: runtime.Prelude(),
+							Fn:    executetest.FunctionExpression(t, "(r) => r._value < 5.0"),
+						},
+					}),
+					plan.CreatePhysicalNode("filter_lane1", &universe.FilterProcedureSpec{
+						Fn: interpreter.ResolvedFunction{
+							Scope: runtime.Prelude(),
+							Fn:    executetest.FunctionExpression(t, "(r) => r._value < 5.0"),
+						},
+					}),
+					plan.CreatePhysicalNode("filter_merge", &plan.MergeLanesProcedureSpec{}),
+					plan.CreatePhysicalNode("yield", executetest.NewYieldProcedureSpec("_result")),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+					{2, 4},
+					{3, 4},
+					{4, 5},
+				},
+			},
+			want: map[string][]*executetest.Table{
+				"_result": []*executetest.Table{
+					{
+						KeyCols: []string{"_start", "_stop", "t0"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_start", Type: flux.TTime},
+							{Label: "_stop", Type: flux.TTime},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "t0", Type: flux.TString},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{execute.Time(0), execute.Time(5), execute.Time(0), "a", 1.0},
+						},
+					},
+					{
+						KeyCols: []string{"_start", "_stop", "t0"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_start", Type: flux.TTime},
+							{Label: "_stop", Type: flux.TTime},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "t0", Type: flux.TString},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{execute.Time(0), execute.Time(5), execute.Time(0), "b", 2.0},
+						},
+					},
+					{
+						KeyCols: []string{"_start", "_stop", "t0"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_start", Type: flux.TTime},
+							{Label: "_stop", Type: flux.TTime},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "t0", Type: flux.TString},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{execute.Time(0), execute.Time(5), execute.Time(0), "c", 3.0},
+						},
+					},
+					{
+						KeyCols: []string{"_start", "_stop", "t0"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_start", Type: flux.TTime},
+							{Label: "_stop", Type: flux.TTime},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "t0", Type: flux.TString},
+							{Label: "_value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{execute.Time(0), execute.Time(5), execute.Time(0), "d", 4.0},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "memory limit exceeded",
 			spec: &plantest.PlanSpec{
@@ -758,3 +906,107 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+// parallelLanesFlagger splits partitionable transformations into two lanes.
+type parallelLanesFlagger struct{}
+
+func (parallelLanesFlagger) FlagValue(ctx context.Context, flag feature.Flag) interface{} {
+	if flag.Key() == "queryParallelLanes" {
+		return int32(2)
+	}
+	return flag.Default()
+}
+
+func TestExecutor_ParallelLanes(t *testing.T) {
+	// The tables are assigned to the lanes with
+	// a hash of their duration and bytes key columns.
+	colMeta := []flux.ColMeta{
+		{Label: "d", Type: flux.TDuration},
+		{Label: "b", Type: flux.TBytes},
+		{Label: "_value", Type: flux.TFloat},
+	}
+	var (
+		tables []*executetest.Table
+		want   []*executetest.Table
+	)
+	for i := 0; i < 4; i++ {
+		d := values.ConvertDurationNsecs(time.Duration(i) * time.Second)
+		b := []byte{byte('a' + i)}
+		tables = append(tables, &executetest.Table{
+			KeyCols: []string{"d", "b"},
+			ColMeta: colMeta,
+			Data: [][]interface{}{
+				{d, b, float64(i)},
+				{d, b, float64(i + 5)},
+			},
+		})
+		want = append(want, &executetest.Table{
+			KeyCols: []string{"d", "b"},
+			ColMeta: colMeta,
+			Data: [][]interface{}{
+				{d, b, float64(i)},
+			},
+		})
+	}
+
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("from", executetest.NewFromProcedureSpec(tables)),
+			plan.CreatePhysicalNode("filter", &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Scope: runtime.Prelude(),
+					Fn:    executetest.FunctionExpression(t, "(r) => r._value < 5.0"),
+				},
+			}),
+			plan.CreatePhysicalNode("yield", executetest.NewYieldProcedureSpec("_result")),
+		},
+		Edges: [][2]int{
+			{0, 1},
+			{1, 2},
+		},
+		Resources: flux.ResourceManagement{
+			ConcurrencyQuota: 2,
+			MemoryBytesQuota: math.MaxInt64,
+		},
+		Now: time.Now(),
+	})
+
+	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+	ctx = feature.Inject(ctx, parallelLanesFlagger{})
+	ps, err := plan.NewPhysicalPlanner().Plan(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lanes int
+	_ = ps.BottomUpWalk(func(node plan.Node) error {
+		if node.Kind() == plan.PartitionLanesKind {
+			lanes = len(node.Successors())
+		}
+		return nil
+	})
+	if lanes != 2 {
+		t.Fatalf("expected the filter to be split into 2 lanes, got %d", lanes)
+	}
+
+	results, _, err := execute.NewExecutor(zaptest.NewLogger(t)).Execute(ctx, ps, executetest.UnlimitedAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*executetest.Table
+	if err := results["_result"].Tables().Do(func(tbl flux.Table) error {
+		cb, err := executetest.ConvertTable(tbl)
+		if err != nil {
+			return err
+		}
+		got = append(got, cb)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	executetest.NormalizeTables(got)
+	executetest.NormalizeTables(want)
+	if !cmp.Equal(want, got) {
+		t.Error("unexpected results -want/+got", cmp.Diff(want, got))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/values"
@@ -257,6 +258,18 @@ func (f *Formatter) valueBuf(i, j int, typ flux.ColType, cr flux.ColReader) []by
 		if cr.Times(j).IsValid(i) {
 			buf = []byte(values.Time(cr.Times(j).Value(i)).String())
 		}
+	case flux.TDecimal:
+		if cr.Decimals(j).IsValid(i) {
+			buf = []byte(values.NewDecimalFromNum(cr.Decimals(j).Value(i)).String())
+		}
+	case flux.TDuration:
+		if cr.Durations(j).IsValid(i) {
+			buf = []byte(values.ConvertDurationNsecs(time.Duration(cr.Durations(j).Value(i))).String())
+		}
+	case flux.TBytes:
+		if cr.Bytes(j).IsValid(i) {
+			buf = []byte(fmt.Sprintf("%x", cr.Bytes(j).Value(i)))
+		}
 	}
 	return buf
 }
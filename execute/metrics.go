@@ -0,0 +1,103 @@
+package execute
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+const (
+	// OperatorRowsMetric is the name of the OpenTelemetry counter
+	// of the rows that each operator receives.
+	OperatorRowsMetric = "flux.operator.rows"
+	// OperatorBytesMetric is the name of the OpenTelemetry counter
+	// of the bytes of the values that each operator receives.
+	OperatorBytesMetric = "flux.operator.bytes"
+
+	// OperationAttribute and LabelAttribute are the attributes
+	// of the operator spans and counters that identify the
+	// operation and the plan node of an operator.
+	OperationAttribute = attribute.Key("flux.operation")
+	LabelAttribute     = attribute.Key("flux.label")
+)
+
+// operatorMetrics counts the rows and bytes that an operator receives.
+type operatorMetrics struct {
+	ctx   context.Context
+	rows  syncint64.Counter
+	bytes syncint64.Counter
+	attrs []attribute.KeyValue
+}
+
+// newOperatorMetrics creates the counters of an operator with the meter
+// provider of the context. It returns nil if the context has no meter provider.
+func newOperatorMetrics(ctx context.Context, op, label string) *operatorMetrics {
+	meter, ok := flux.Meter(ctx)
+	if !ok {
+		return nil
+	}
+	rows, err := meter.SyncInt64().Counter(OperatorRowsMetric,
+		instrument.WithDescription("The number of rows received by the operator"),
+		instrument.WithUnit(unit.Dimensionless),
+	)
+	if err != nil {
+		return nil
+	}
+	bytes, err := meter.SyncInt64().Counter(OperatorBytesMetric,
+		instrument.WithDescription("The number of bytes of the values received by the operator"),
+		instrument.WithUnit(unit.Bytes),
+	)
+	if err != nil {
+		return nil
+	}
+	return &operatorMetrics{
+		ctx:   ctx,
+		rows:  rows,
+		bytes: bytes,
+		attrs: operatorAttributes(op, label),
+	}
+}
+
+func operatorAttributes(op, label string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		OperationAttribute.String(op),
+		LabelAttribute.String(label),
+	}
+}
+
+// record adds the rows and the bytes of the columns to the counters.
+func (m *operatorMetrics) record(n, ncols int, values func(j int) array.Interface) {
+	if m == nil || n == 0 {
+		return
+	}
+	var bytes int64
+	for j := 0; j < ncols; j++ {
+		bytes += arrayBytes(values(j))
+	}
+	m.rows.Add(m.ctx, int64(n), m.attrs...)
+	m.bytes.Add(m.ctx, bytes, m.attrs...)
+}
+
+// arrayBytes returns the number of bytes of the values in the array.
+// The validity bitmap is not included.
+func arrayBytes(arr array.Interface) int64 {
+	switch a := arr.(type) {
+	case *array.String:
+		var n int64
+		for i, l := 0, a.Len(); i < l; i++ {
+			n += int64(a.ValueLen(i))
+		}
+		return n
+	default:
+		if dt, ok := arr.DataType().(arrow.FixedWidthDataType); ok {
+			return (int64(arr.Len())*int64(dt.BitWidth()) + 7) / 8
+		}
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+package execute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/oteltest"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"go.uber.org/zap/zaptest"
+)
+
+type nopTransformation struct{}
+
+func (nopTransformation) RetractTable(id DatasetID, key flux.GroupKey) error { return nil }
+func (nopTransformation) Process(id DatasetID, tbl flux.Table) error {
+	return tbl.Do(func(flux.ColReader) error { return nil })
+}
+func (nopTransformation) UpdateWatermark(id DatasetID, t Time) error      { return nil }
+func (nopTransformation) UpdateProcessingTime(id DatasetID, t Time) error { return nil }
+func (nopTransformation) Finish(id DatasetID, err error)                  {}
+
+func TestConsecutiveTransport_OpenTelemetry(t *testing.T) {
+	tp, exp := oteltest.NewTracerProvider()
+	mp := oteltest.NewMeterProvider()
+	ctx := flux.WithTracerProvider(context.Background(), tp)
+	ctx = flux.WithMeterProvider(ctx, mp)
+
+	logger := zaptest.NewLogger(t)
+	dispatcher := newPoolDispatcher(10, logger)
+	dispatcher.Start(1, ctx)
+	defer func() { _ = dispatcher.Stop() }()
+
+	mem := &memory.Allocator{}
+	tr := newConsecutiveTransport(ctx, dispatcher, nopTransformation{}, plan.CreatePhysicalNode("nop0", nil), logger, mem)
+
+	b := NewColListTableBuilder(NewGroupKey(nil, nil), mem)
+	_, _ = b.AddCol(flux.ColMeta{Label: "_value", Type: flux.TFloat})
+	_, _ = b.AddCol(flux.ColMeta{Label: "host", Type: flux.TString})
+	for _, host := range []string{"a", "bb", "ccc"} {
+		_ = b.AppendFloat(0, 1.0)
+		_ = b.AppendString(1, host)
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		chunk := table.ChunkFromReader(cr)
+		chunk.Retain()
+		return tr.ProcessMessage(&processChunkMsg{chunk: chunk})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tbl, _ = b.Table()
+	if err := tr.Process(DatasetID{}, tbl); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(DatasetID{}, nil)
+	select {
+	case <-tr.Finished():
+	case err := <-dispatcher.Err():
+		t.Fatal(err)
+	}
+
+	op := OperationType(nopTransformation{})
+	attrs := operatorAttributes(op, "nop0")
+	// Each message has 3 rows of 8 byte floats and 6 bytes of strings.
+	if want, got := int64(6), mp.Value(OperatorRowsMetric, attrs...); want != got {
+		t.Errorf("unexpected rows: want %d, got %d (recorded %v)", want, got, mp.Series())
+	}
+	if want, got := int64(60), mp.Value(OperatorBytesMetric, attrs...); want != got {
+		t.Errorf("unexpected bytes: want %d, got %d (recorded %v)", want, got, mp.Series())
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	if want, got := op, spans[0].Name; want != got {
+		t.Errorf("unexpected span name: want %s, got %s", want, got)
+	}
+	if want, got := attrs, spans[0].Attributes; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("unexpected span attributes: want %v, got %v", want, got)
+	}
+}
+
+func TestConsecutiveTransport_NoMeterProvider(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	tr := newConsecutiveTransport(context.Background(), newPoolDispatcher(10, logger), nopTransformation{}, plan.CreatePhysicalNode("nop0", nil), logger, &memory.Allocator{})
+	if tr.metrics != nil {
+		t.Error("expected no operator metrics without a meter provider")
+	}
+}
@@ -0,0 +1,221 @@
+package execute
+
+import (
+	"math"
+	"sync"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/groupkey"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/values"
+)
+
+func init() {
+	RegisterTransformation(plan.PartitionLanesKind, createPartitionLanesTransformation)
+	RegisterTransformation(plan.MergeLanesKind, createMergeLanesTransformation)
+}
+
+func createPartitionLanesTransformation(id DatasetID, mode AccumulationMode, spec plan.ProcedureSpec, a Administration) (Transformation, Dataset, error) {
+	s, ok := spec.(*plan.PartitionLanesProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	d := &partitionLanesDataset{id: id}
+	return newPartitionLanesTransformation(d, s), d, nil
+}
+
+// partitionLanesDataset sends each table to exactly one of its transformations.
+// Each transformation that is added to the dataset is a separate lane.
+type partitionLanesDataset struct {
+	id    DatasetID
+	lanes TransformationSet
+}
+
+func (d *partitionLanesDataset) AddTransformation(t Transformation) {
+	d.lanes = append(d.lanes, t)
+}
+
+func (d *partitionLanesDataset) RetractTable(key flux.GroupKey) error {
+	return d.lanes.RetractTable(d.id, key)
+}
+
+func (d *partitionLanesDataset) UpdateProcessingTime(t Time) error {
+	return d.lanes.UpdateProcessingTime(d.id, t)
+}
+
+func (d *partitionLanesDataset) UpdateWatermark(mark Time) error {
+	return d.lanes.UpdateWatermark(d.id, mark)
+}
+
+func (d *partitionLanesDataset) Finish(err error) {
+	d.lanes.Finish(d.id, err)
+}
+
+func (d *partitionLanesDataset) SetTriggerSpec(t plan.TriggerSpec) {
+}
+
+type partitionLanesTransformation struct {
+	d       *partitionLanesDataset
+	exclude map[string]bool
+}
+
+// newPartitionLanesTransformation creates a transformation that assigns
+// each table to one of the lanes of d using a hash of its group key.
+// Tables with the same group key, ignoring the columns excluded by the spec,
+// are always assigned to the same lane.
+func newPartitionLanesTransformation(d *partitionLanesDataset, spec *plan.PartitionLanesProcedureSpec) *partitionLanesTransformation {
+	exclude := make(map[string]bool, len(spec.Exclude))
+	for _, label := range spec.Exclude {
+		exclude[label] = true
+	}
+	return &partitionLanesTransformation{
+		d:       d,
+		exclude: exclude,
+	}
+}
+
+func (t *partitionLanesTransformation) RetractTable(id DatasetID, key flux.GroupKey) error {
+	if len(t.d.lanes) == 0 {
+		return nil
+	}
+	return t.d.lanes[t.lane(key)].RetractTable(t.d.id, key)
+}
+
+func (t *partitionLanesTransformation) Process(id DatasetID, tbl flux.Table) error {
+	if len(t.d.lanes) == 0 {
+		tbl.Done()
+		return nil
+	}
+	return t.d.lanes[t.lane(tbl.Key())].Process(t.d.id, tbl)
+}
+
+func (t *partitionLanesTransformation) UpdateWatermark(id DatasetID, mark Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *partitionLanesTransformation) UpdateProcessingTime(id DatasetID, pt Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *partitionLanesTransformation) Finish(id DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// lane returns the index of the lane for the group key.
+func (t *partitionLanesTransformation) lane(key flux.GroupKey) int {
+	if len(t.exclude) > 0 {
+		cols := make([]flux.ColMeta, 0, len(key.Cols()))
+		vs := make([]values.Value, 0, len(key.Cols()))
+		for j, c := range key.Cols() {
+			if !t.exclude[c.Label] {
+				cols = append(cols, c)
+				vs = append(vs, key.Value(j))
+			}
+		}
+		key = NewGroupKey(cols, vs)
+	}
+	return int(groupkey.Hash64(key) % uint64(len(t.d.lanes)))
+}
+
+func createMergeLanesTransformation(id DatasetID, mode AccumulationMode, spec plan.ProcedureSpec, a Administration) (Transformation, Dataset, error) {
+	if _, ok := spec.(*plan.MergeLanesProcedureSpec); !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	d := NewPassthroughDataset(id)
+	return newMergeLanesTransformation(d, a.Parents()), d, nil
+}
+
+type mergeLanesParentState struct {
+	mark       Time
+	processing Time
+	finished   bool
+}
+
+type mergeLanesTransformation struct {
+	mu       sync.Mutex
+	d        *PassthroughDataset
+	parents  map[DatasetID]*mergeLanesParentState
+	finished bool
+}
+
+// newMergeLanesTransformation creates a transformation that passes through
+// the tables from all of its parents. It finishes once every parent has finished
+// or as soon as one of them finishes with an error.
+func newMergeLanesTransformation(d *PassthroughDataset, parents []DatasetID) *mergeLanesTransformation {
+	state := make(map[DatasetID]*mergeLanesParentState, len(parents))
+	for _, id := range parents {
+		state[id] = new(mergeLanesParentState)
+	}
+	return &mergeLanesTransformation{
+		d:       d,
+		parents: state,
+	}
+}
+
+func (t *mergeLanesTransformation) RetractTable(id DatasetID, key flux.GroupKey) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.d.RetractTable(key)
+}
+
+func (t *mergeLanesTransformation) Process(id DatasetID, tbl flux.Table) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.d.Process(tbl)
+}
+
+func (t *mergeLanesTransformation) UpdateWatermark(id DatasetID, mark Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.parents[id].mark = mark
+
+	min := Time(math.MaxInt64)
+	for _, state := range t.parents {
+		if state.mark < min {
+			min = state.mark
+		}
+	}
+	return t.d.UpdateWatermark(min)
+}
+
+func (t *mergeLanesTransformation) UpdateProcessingTime(id DatasetID, pt Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.parents[id].processing = pt
+
+	min := Time(math.MaxInt64)
+	for _, state := range t.parents {
+		if state.processing < min {
+			min = state.processing
+		}
+	}
+	return t.d.UpdateProcessingTime(min)
+}
+
+func (t *mergeLanesTransformation) Finish(id DatasetID, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.finished {
+		return
+	}
+	t.parents[id].finished = true
+
+	if err != nil {
+		t.finished = true
+		t.d.Finish(err)
+		return
+	}
+
+	for _, state := range t.parents {
+		if !state.finished {
+			return
+		}
+	}
+	t.finished = true
+	t.d.Finish(nil)
+}
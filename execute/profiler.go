@@ -3,11 +3,14 @@ package execute
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/metadata"
 	"github.com/influxdata/flux/values"
 	"github.com/opentracing/opentracing-go"
 )
@@ -44,6 +47,15 @@ type OperatorProfilingResult struct {
 	Label string
 	Start time.Time
 	Stop  time.Time
+
+	// The counters of the work done within the span.
+	// A result without a start time only reports counters.
+	TableCount     int64
+	ChunkCount     int64
+	RowsIn         int64
+	RowsOut        int64
+	BytesAllocated int64
+	DispatcherWait time.Duration
 }
 
 type OperatorProfilingSpan struct {
@@ -54,8 +66,8 @@ type OperatorProfilingSpan struct {
 
 func (t *OperatorProfilingSpan) finish(finishTime time.Time) time.Time {
 	t.Result.Stop = finishTime
-	if t.profiler != nil && t.profiler.chIn != nil {
-		t.profiler.chIn <- t.Result
+	if t.profiler != nil {
+		t.profiler.add(t.Result)
 	}
 	return t.Result.Stop
 }
@@ -79,29 +91,61 @@ func (t *OperatorProfilingSpan) FinishWithOptions(opts opentracing.FinishOptions
 
 const OperatorProfilerContextKey = "operator-profiler"
 
-type operatorProfilingResultAggregate struct {
-	operationType string
-	label         string
-	resultCount   int64
-	resultMin     int64
-	resultMax     int64
-	resultSum     int64
-	resultMean    float64
+// OperatorProfilerMetadataKey is the key of the operator
+// profiles in the metadata of the query statistics.
+const OperatorProfilerMetadataKey = "flux/operator-profile"
+
+// OperatorProfile is the aggregated profile of an operator.
+// The durations are in nanoseconds.
+type OperatorProfile struct {
+	Type         string  `json:"type"`
+	Label        string  `json:"label"`
+	Count        int64   `json:"count"`
+	MinDuration  int64   `json:"min_duration"`
+	MaxDuration  int64   `json:"max_duration"`
+	DurationSum  int64   `json:"duration_sum"`
+	MeanDuration float64 `json:"mean_duration"`
+
+	// TableCount and ChunkCount are the number of tables
+	// and chunks that the operator received.
+	TableCount int64 `json:"table_count"`
+	ChunkCount int64 `json:"chunk_count"`
+	// RowsIn and RowsOut are the number of rows that
+	// the operator received and produced.
+	RowsIn  int64 `json:"rows_in"`
+	RowsOut int64 `json:"rows_out"`
+	// BytesAllocated is the memory that the operator
+	// allocated from its allocator while it ran.
+	BytesAllocated int64 `json:"bytes_allocated"`
+	// DispatcherWaitDuration is the time the operator waited
+	// for the dispatcher to run it once it had work to do.
+	DispatcherWaitDuration int64 `json:"dispatcher_wait_duration"`
+}
+
+func (p OperatorProfile) String() string {
+	return fmt.Sprintf("%s %s: count=%d duration_sum=%d tables=%d chunks=%d rows_in=%d rows_out=%d bytes_allocated=%d dispatcher_wait=%d",
+		p.Type, p.Label, p.Count, p.DurationSum, p.TableCount, p.ChunkCount,
+		p.RowsIn, p.RowsOut, p.BytesAllocated, p.DispatcherWaitDuration)
 }
 
-type operatorProfilerLabelGroup = map[string]*operatorProfilingResultAggregate
+type operatorProfilerLabelGroup = map[string]*OperatorProfile
 type operatorProfilerTypeGroup = map[string]operatorProfilerLabelGroup
 
 type OperatorProfiler struct {
 	// Receive the profiling results from the spans.
-	chIn  chan OperatorProfilingResult
-	chOut chan operatorProfilingResultAggregate
+	mu     sync.RWMutex
+	chIn   chan OperatorProfilingResult
+	closed bool
+
+	// done is closed once the results have been aggregated into profiles.
+	done     chan struct{}
+	profiles []OperatorProfile
 }
 
 func createOperatorProfiler() Profiler {
 	p := &OperatorProfiler{
-		chIn:  make(chan OperatorProfilingResult),
-		chOut: make(chan operatorProfilingResultAggregate),
+		chIn: make(chan OperatorProfilingResult),
+		done: make(chan struct{}),
 	}
 	go func(p *OperatorProfiler) {
 		aggs := make(operatorProfilerTypeGroup)
@@ -112,33 +156,50 @@ func createOperatorProfiler() Profiler {
 			}
 			_, ok = aggs[result.Type][result.Label]
 			if !ok {
-				aggs[result.Type][result.Label] = &operatorProfilingResultAggregate{}
+				aggs[result.Type][result.Label] = &OperatorProfile{}
 			}
 			a := aggs[result.Type][result.Label]
 
 			// Aggregate the results
-			a.resultCount++
+			a.TableCount += result.TableCount
+			a.ChunkCount += result.ChunkCount
+			a.RowsIn += result.RowsIn
+			a.RowsOut += result.RowsOut
+			a.BytesAllocated += result.BytesAllocated
+			a.DispatcherWaitDuration += result.DispatcherWait.Nanoseconds()
+			if result.Start.IsZero() {
+				continue
+			}
+			a.Count++
 			duration := result.Stop.Sub(result.Start).Nanoseconds()
-			if duration > a.resultMax {
-				a.resultMax = duration
+			if duration > a.MaxDuration {
+				a.MaxDuration = duration
 			}
-			if duration < a.resultMin || a.resultMin == 0 {
-				a.resultMin = duration
+			if duration < a.MinDuration || a.MinDuration == 0 {
+				a.MinDuration = duration
 			}
-			a.resultSum += duration
+			a.DurationSum += duration
 		}
 
-		// Write the aggregated results to chOut, where they'll be
+		// Store the aggregated results, where they'll be
 		// converted into rows and appended to the final table
 		for typ, labels := range aggs {
 			for label, agg := range labels {
-				agg.resultMean = float64(agg.resultSum) / float64(agg.resultCount)
-				agg.operationType = typ
-				agg.label = label
-				p.chOut <- *agg
+				if agg.Count > 0 {
+					agg.MeanDuration = float64(agg.DurationSum) / float64(agg.Count)
+				}
+				agg.Type = typ
+				agg.Label = label
+				p.profiles = append(p.profiles, *agg)
 			}
 		}
-		close(p.chOut)
+		sort.Slice(p.profiles, func(i, j int) bool {
+			if p.profiles[i].Label != p.profiles[j].Label {
+				return p.profiles[i].Label < p.profiles[j].Label
+			}
+			return p.profiles[i].Type < p.profiles[j].Type
+		})
+		close(p.done)
 	}(p)
 
 	return p
@@ -148,15 +209,50 @@ func (o *OperatorProfiler) Name() string {
 	return "operator"
 }
 
+// add sends a result to be aggregated.
+// Results that arrive after the profiles were aggregated are dropped.
+func (o *OperatorProfiler) add(result OperatorProfilingResult) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if !o.closed {
+		o.chIn <- result
+	}
+}
+
 func (o *OperatorProfiler) closeIncomingChannel() {
-	if o.chIn != nil {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.closed {
 		close(o.chIn)
-		o.chIn = nil
+		o.closed = true
 	}
 }
 
-func (o *OperatorProfiler) GetResult(q flux.Query, alloc *memory.Allocator) (flux.Table, error) {
+// Profiles stops the profiler and returns the profile
+// of each operator sorted by label and type.
+func (o *OperatorProfiler) Profiles() []OperatorProfile {
 	o.closeIncomingChannel()
+	<-o.done
+	return o.profiles
+}
+
+// Metadata stops the profiler and returns the profiles
+// to be added to the metadata of the query statistics.
+func (o *OperatorProfiler) Metadata() metadata.Metadata {
+	profiles := o.Profiles()
+	if len(profiles) == 0 {
+		return nil
+	}
+	values := make([]interface{}, len(profiles))
+	for i, p := range profiles {
+		values[i] = p
+	}
+	return metadata.Metadata{
+		OperatorProfilerMetadataKey: values,
+	}
+}
+
+func (o *OperatorProfiler) GetResult(q flux.Query, alloc *memory.Allocator) (flux.Table, error) {
 	b, err := o.getTableBuilder(alloc)
 	if err != nil {
 		return nil, err
@@ -172,7 +268,6 @@ func (o *OperatorProfiler) GetResult(q flux.Query, alloc *memory.Allocator) (flu
 // on the ColListTableBuilder to make testing easier.
 // sortKeys and desc are passed directly into the Sort() call
 func (o *OperatorProfiler) GetSortedResult(q flux.Query, alloc *memory.Allocator, desc bool, sortKeys ...string) (flux.Table, error) {
-	o.closeIncomingChannel()
 	b, err := o.getTableBuilder(alloc)
 	if err != nil {
 		return nil, err
@@ -231,6 +326,30 @@ func (o *OperatorProfiler) getTableBuilder(alloc *memory.Allocator) (*ColListTab
 			Label: "MeanDuration",
 			Type:  flux.TFloat,
 		},
+		{
+			Label: "TableCount",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "ChunkCount",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "RowsIn",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "RowsOut",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "BytesAllocated",
+			Type:  flux.TInt,
+		},
+		{
+			Label: "DispatcherWaitDuration",
+			Type:  flux.TInt,
+		},
 	}
 	for _, col := range colMeta {
 		if _, err := b.AddCol(col); err != nil {
@@ -238,15 +357,21 @@ func (o *OperatorProfiler) getTableBuilder(alloc *memory.Allocator) (*ColListTab
 		}
 	}
 
-	for agg := range o.chOut {
+	for _, agg := range o.Profiles() {
 		b.AppendString(0, "profiler/operator")
-		b.AppendString(1, agg.operationType)
-		b.AppendString(2, agg.label)
-		b.AppendInt(3, agg.resultCount)
-		b.AppendInt(4, agg.resultMin)
-		b.AppendInt(5, agg.resultMax)
-		b.AppendInt(6, agg.resultSum)
-		b.AppendFloat(7, agg.resultMean)
+		b.AppendString(1, agg.Type)
+		b.AppendString(2, agg.Label)
+		b.AppendInt(3, agg.Count)
+		b.AppendInt(4, agg.MinDuration)
+		b.AppendInt(5, agg.MaxDuration)
+		b.AppendInt(6, agg.DurationSum)
+		b.AppendFloat(7, agg.MeanDuration)
+		b.AppendInt(8, agg.TableCount)
+		b.AppendInt(9, agg.ChunkCount)
+		b.AppendInt(10, agg.RowsIn)
+		b.AppendInt(11, agg.RowsOut)
+		b.AppendInt(12, agg.BytesAllocated)
+		b.AppendInt(13, agg.DispatcherWaitDuration)
 	}
 	return b, nil
 }
@@ -273,24 +398,116 @@ func StartSpanFromContext(ctx context.Context, operationName string, label strin
 		span, ctx = opentracing.StartSpanFromContext(ctx, operationName, opts...)
 	}
 
-	if HaveExecutionDependencies(ctx) {
-		deps := GetExecutionDependencies(ctx)
-		if deps.ExecutionOptions.OperatorProfiler != nil {
-			tfp := deps.ExecutionOptions.OperatorProfiler
-			span = &OperatorProfilingSpan{
-				Span:     span,
-				profiler: tfp,
-				Result: OperatorProfilingResult{
-					Type:  operationName,
-					Label: label,
-					Start: start,
-				},
-			}
+	if tfp := operatorProfiler(ctx); tfp != nil {
+		span = &OperatorProfilingSpan{
+			Span:     span,
+			profiler: tfp,
+			Result: OperatorProfilingResult{
+				Type:  operationName,
+				Label: label,
+				Start: start,
+			},
 		}
 	}
 	return ctx, span
 }
 
+// operatorProfiler returns the operator profiler
+// of the context, or nil if it is not enabled.
+func operatorProfiler(ctx context.Context) *OperatorProfiler {
+	if !HaveExecutionDependencies(ctx) {
+		return nil
+	}
+	return GetExecutionDependencies(ctx).ExecutionOptions.OperatorProfiler
+}
+
+// rowsOutProfiler reports the rows that an operator produces
+// to the operator profiler. It wraps the first transformation
+// that reads the output of the operator so each row is counted once.
+// When the operator finishes, it reports the memory that the
+// operator allocated from alloc.
+type rowsOutProfiler struct {
+	Transformation
+	transport Transport
+	profiler  *OperatorProfiler
+	op, label string
+	alloc     *memory.Allocator
+	finished  sync.Once
+}
+
+func newRowsOutProfiler(t Transformation, profiler *OperatorProfiler, op, label string, alloc, mem *memory.Allocator) *rowsOutProfiler {
+	transport, ok := t.(Transport)
+	if !ok {
+		transport = WrapTransformationInTransport(t, mem)
+	}
+	return &rowsOutProfiler{
+		Transformation: t,
+		transport:      transport,
+		profiler:       profiler,
+		op:             op,
+		label:          label,
+		alloc:          alloc,
+	}
+}
+
+func (p *rowsOutProfiler) Process(id DatasetID, tbl flux.Table) error {
+	return p.Transformation.Process(id, &rowsOutProfilerTable{Table: tbl, p: p})
+}
+
+func (p *rowsOutProfiler) ProcessMessage(m Message) error {
+	switch msg := m.(type) {
+	case ProcessChunkMsg:
+		p.record(msg.TableChunk().Len())
+	case *processMsg:
+		msg.table = &rowsOutProfilerTable{Table: msg.table, p: p}
+	case FinishMsg:
+		p.recordAllocations()
+	}
+	return p.transport.ProcessMessage(m)
+}
+
+func (p *rowsOutProfiler) Finish(id DatasetID, err error) {
+	p.recordAllocations()
+	p.Transformation.Finish(id, err)
+}
+
+func (p *rowsOutProfiler) record(n int) {
+	if n > 0 {
+		p.profiler.add(OperatorProfilingResult{
+			Type:    p.op,
+			Label:   p.label,
+			RowsOut: int64(n),
+		})
+	}
+}
+
+// recordAllocations reports the memory that the operator allocated.
+// It is called when the operator finishes, so it reports once.
+func (p *rowsOutProfiler) recordAllocations() {
+	if p.alloc == nil {
+		return
+	}
+	p.finished.Do(func() {
+		p.profiler.add(OperatorProfilingResult{
+			Type:           p.op,
+			Label:          p.label,
+			BytesAllocated: p.alloc.TotalAllocated(),
+		})
+	})
+}
+
+type rowsOutProfilerTable struct {
+	flux.Table
+	p *rowsOutProfiler
+}
+
+func (t *rowsOutProfilerTable) Do(f func(flux.ColReader) error) error {
+	return t.Table.Do(func(cr flux.ColReader) error {
+		t.p.record(cr.Len())
+		return f(cr)
+	})
+}
+
 type QueryProfiler struct{}
 
 func createQueryProfiler() Profiler {
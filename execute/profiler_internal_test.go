@@ -0,0 +1,113 @@
+package execute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestConsecutiveTransport_OperatorProfiler(t *testing.T) {
+	deps := DefaultExecutionDependencies()
+	profiler := createOperatorProfiler().(*OperatorProfiler)
+	deps.ExecutionOptions.OperatorProfiler = profiler
+	ctx := deps.Inject(context.Background())
+
+	logger := zaptest.NewLogger(t)
+	dispatcher := newPoolDispatcher(10, logger)
+	dispatcher.Start(1, ctx)
+	defer func() { _ = dispatcher.Stop() }()
+
+	mem := &memory.Allocator{}
+	tr := newConsecutiveTransport(ctx, dispatcher, nopTransformation{}, plan.CreatePhysicalNode("nop1", nil), logger, mem)
+	// The source reports the rows that it sends to the transport
+	// and the memory that it allocated from its own allocator.
+	alloc := mem.Child()
+	src := newRowsOutProfiler(tr, profiler, "src", "src0", alloc, mem)
+
+	b := NewColListTableBuilder(NewGroupKey(nil, nil), alloc)
+	_, _ = b.AddCol(flux.ColMeta{Label: "_value", Type: flux.TFloat})
+	for i := 0; i < 3; i++ {
+		_ = b.AppendFloat(0, float64(i))
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		chunk := table.ChunkFromReader(cr)
+		chunk.Retain()
+		return src.ProcessMessage(&processChunkMsg{chunk: chunk})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tbl, _ = b.Table()
+	if err := src.Process(DatasetID{}, tbl); err != nil {
+		t.Fatal(err)
+	}
+	src.Finish(DatasetID{}, nil)
+	select {
+	case <-tr.Finished():
+	case err := <-dispatcher.Err():
+		t.Fatal(err)
+	}
+
+	profiles := profiler.Profiles()
+	if len(profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %v", profiles)
+	}
+	nop, src0 := profiles[0], profiles[1]
+	if want, got := OperationType(nopTransformation{}), nop.Type; want != got {
+		t.Errorf("unexpected operation type: want %s, got %s", want, got)
+	}
+	// The chunk, the table and the finish message were each processed once.
+	if want, got := int64(3), nop.Count; want != got {
+		t.Errorf("unexpected count: want %d, got %d", want, got)
+	}
+	if want, got := int64(1), nop.TableCount; want != got {
+		t.Errorf("unexpected table count: want %d, got %d", want, got)
+	}
+	if want, got := int64(1), nop.ChunkCount; want != got {
+		t.Errorf("unexpected chunk count: want %d, got %d", want, got)
+	}
+	if want, got := int64(6), nop.RowsIn; want != got {
+		t.Errorf("unexpected rows in: want %d, got %d", want, got)
+	}
+	if nop.DispatcherWaitDuration <= 0 {
+		t.Errorf("expected the dispatcher wait to be recorded, got %d", nop.DispatcherWaitDuration)
+	}
+
+	// The source has only reported counters.
+	if want, got := "src0", src0.Label; want != got {
+		t.Errorf("unexpected label: want %s, got %s", want, got)
+	}
+	if want, got := int64(0), src0.Count; want != got {
+		t.Errorf("unexpected count: want %d, got %d", want, got)
+	}
+	if want, got := int64(6), src0.RowsOut; want != got {
+		t.Errorf("unexpected rows out: want %d, got %d", want, got)
+	}
+	if want, got := alloc.TotalAllocated(), src0.BytesAllocated; want == 0 || want != got {
+		t.Errorf("unexpected bytes allocated: want %d, got %d", want, got)
+	}
+
+	md := profiler.Metadata()
+	if want, got := 2, len(md[OperatorProfilerMetadataKey]); want != got {
+		t.Errorf("unexpected number of profiles in the metadata: want %d, got %d", want, got)
+	}
+}
+
+func TestOperatorProfiler_MetadataEmpty(t *testing.T) {
+	profiler := createOperatorProfiler().(*OperatorProfiler)
+	if md := profiler.Metadata(); md != nil {
+		t.Errorf("expected no metadata without profiles, got %v", md)
+	}
+	// The profiles can still be read after the profiler was stopped.
+	if profiles := profiler.Profiles(); len(profiles) != 0 {
+		t.Errorf("expected no profiles, got %v", profiles)
+	}
+}
@@ -44,22 +44,22 @@ func TestOperatorProfiler_GetResult(t *testing.T) {
 	// Build the "want" table.
 	var wantStr bytes.Buffer
 	wantStr.WriteString(`
-#datatype,string,long,string,string,string,long,long,long,long,double
-#group,false,false,true,false,false,false,false,false,false,false
-#default,_profiler,,,,,,,,,
-,result,table,_measurement,Type,Label,Count,MinDuration,MaxDuration,DurationSum,MeanDuration
+#datatype,string,long,string,string,string,long,long,long,long,double,long,long,long,long,long,long
+#group,false,false,true,false,false,false,false,false,false,false,false,false,false,false,false,false
+#default,_profiler,,,,,,,,,,,,,,,
+,result,table,_measurement,Type,Label,Count,MinDuration,MaxDuration,DurationSum,MeanDuration,TableCount,ChunkCount,RowsIn,RowsOut,BytesAllocated,DispatcherWaitDuration
 `)
-	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f\n",
-		"type0", "lab0", 4, 1000, 1606, 5212, 1303.0,
+	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f,%d,%d,%d,%d,%d,%d\n",
+		"type0", "lab0", 4, 1000, 1606, 5212, 1303.0, 4, 8, 40, 20, 4096, 400,
 	))
-	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f\n",
-		"type1", "lab0", 4, 1101, 1707, 5616, 1404.0,
+	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f,%d,%d,%d,%d,%d,%d\n",
+		"type1", "lab0", 4, 1101, 1707, 5616, 1404.0, 4, 8, 40, 20, 4096, 400,
 	))
-	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f\n",
-		"type0", "lab1", 4, 1808, 2414, 8444, 2111.0,
+	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f,%d,%d,%d,%d,%d,%d\n",
+		"type0", "lab1", 4, 1808, 2414, 8444, 2111.0, 4, 8, 40, 20, 4096, 400,
 	))
-	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f\n",
-		"type1", "lab1", 4, 1909, 2515, 8848, 2212.0,
+	wantStr.WriteString(fmt.Sprintf(",,0,profiler/operator,%s,%s,%d,%d,%d,%d,%f,%d,%d,%d,%d,%d,%d\n",
+		"type1", "lab1", 4, 1909, 2515, 8848, 2212.0, 4, 8, 40, 20, 4096, 400,
 	))
 	count := 16
 	wg := sync.WaitGroup{}
@@ -68,6 +68,12 @@ func TestOperatorProfiler_GetResult(t *testing.T) {
 		st := time.Date(2020, 10, 14, 12, 30, 0, 0, time.UTC)
 		_, span := execute.StartSpanFromContext(ctx, opType, label, opentracing.StartTime(st))
 		profilerSpan := span.(*execute.OperatorProfilingSpan)
+		profilerSpan.Result.TableCount = 1
+		profilerSpan.Result.ChunkCount = 2
+		profilerSpan.Result.RowsIn = 10
+		profilerSpan.Result.RowsOut = 5
+		profilerSpan.Result.BytesAllocated = 1024
+		profilerSpan.Result.DispatcherWait = 100 * time.Nanosecond
 		// Finish() will write the data to the profiler
 		// In Flux runtime, this is called when an execution node finishes execution
 		profilerSpan.FinishWithOptions(opentracing.FinishOptions{
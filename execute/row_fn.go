@@ -108,6 +108,12 @@ func ConvertToKind(t flux.ColType) semantic.Nature {
 		return semantic.String
 	case flux.TTime:
 		return semantic.Time
+	case flux.TDecimal:
+		return semantic.Decimal
+	case flux.TDuration:
+		return semantic.Duration
+	case flux.TBytes:
+		return semantic.Bytes
 	default:
 		return semantic.Invalid
 	}
@@ -130,6 +136,12 @@ func ConvertFromKind(k semantic.Nature) flux.ColType {
 		return flux.TString
 	case semantic.Time:
 		return flux.TTime
+	case semantic.Decimal:
+		return flux.TDecimal
+	case semantic.Duration:
+		return flux.TDuration
+	case semantic.Bytes:
+		return flux.TBytes
 	default:
 		return flux.TInvalid
 	}
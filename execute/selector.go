@@ -27,6 +27,11 @@ var DefaultSelectorConfig = SelectorConfig{
 	Column: DefaultValueColLabel,
 }
 
+// ModifiedGroupKeyColumns reports that selectors do not modify the group key.
+func (c SelectorConfig) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
 func (c *SelectorConfig) ReadArgs(args flux.Arguments) error {
 	if col, ok, err := args.GetString("column"); err != nil {
 		return err
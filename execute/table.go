@@ -1,9 +1,11 @@
 package execute
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
@@ -191,6 +193,12 @@ func AppendCol(bj, cj int, cr flux.ColReader, builder TableBuilder) error {
 		return builder.AppendStrings(bj, cr.Strings(cj))
 	case flux.TTime:
 		return builder.AppendTimes(bj, cr.Times(cj))
+	case flux.TDecimal:
+		return builder.AppendDecimals(bj, cr.Decimals(cj))
+	case flux.TDuration:
+		return builder.AppendDurations(bj, cr.Durations(cj))
+	case flux.TBytes:
+		return builder.AppendBinaries(bj, cr.Bytes(cj))
 	default:
 		PanicUnknownType(c.Type)
 	}
@@ -300,6 +308,15 @@ func TablesEqual(left, right flux.Table, alloc *memory.Allocator) (bool, error)
 			case flux.TTime:
 				eq = cmp.Equal(leftBuffer.cols[j].(*timeColumnBuilder).data,
 					rightBuffer.cols[j].(*timeColumnBuilder).data)
+			case flux.TDecimal:
+				eq = cmp.Equal(leftBuffer.cols[j].(*decimalColumnBuilder).data,
+					rightBuffer.cols[j].(*decimalColumnBuilder).data)
+			case flux.TDuration:
+				eq = cmp.Equal(leftBuffer.cols[j].(*durationColumnBuilder).data,
+					rightBuffer.cols[j].(*durationColumnBuilder).data)
+			case flux.TBytes:
+				eq = cmp.Equal(leftBuffer.cols[j].(*bytesColumnBuilder).data,
+					rightBuffer.cols[j].(*bytesColumnBuilder).data)
 			default:
 				PanicUnknownType(c.Type)
 			}
@@ -436,6 +453,21 @@ func ValueForRow(cr flux.ColReader, i, j int) values.Value {
 			return values.NewNull(semantic.BasicTime)
 		}
 		return values.NewTime(values.Time(cr.Times(j).Value(i)))
+	case flux.TDecimal:
+		if cr.Decimals(j).IsNull(i) {
+			return values.NewNull(semantic.BasicDecimal)
+		}
+		return values.NewDecimal(values.NewDecimalFromNum(cr.Decimals(j).Value(i)))
+	case flux.TDuration:
+		if cr.Durations(j).IsNull(i) {
+			return values.NewNull(semantic.BasicDuration)
+		}
+		return values.NewDuration(values.ConvertDurationNsecs(time.Duration(cr.Durations(j).Value(i))))
+	case flux.TBytes:
+		if cr.Bytes(j).IsNull(i) {
+			return values.NewNull(semantic.BasicBytes)
+		}
+		return values.NewBytes(copyBytes(cr.Bytes(j).Value(i)))
 	default:
 		PanicUnknownType(t)
 		return values.InvalidValue
@@ -468,6 +500,9 @@ type TableBuilder interface {
 	AppendFloat(j int, value float64) error
 	AppendString(j int, value string) error
 	AppendTime(j int, value Time) error
+	AppendDecimal(j int, value values.Decimal) error
+	AppendDuration(j int, value values.Duration) error
+	AppendBytes(j int, value []byte) error
 	AppendValue(j int, value values.Value) error
 	AppendNil(j int) error
 
@@ -480,6 +515,9 @@ type TableBuilder interface {
 	AppendFloats(j int, vs *array.Float) error
 	AppendStrings(j int, vs *array.String) error
 	AppendTimes(j int, vs *array.Int) error
+	AppendDecimals(j int, vs *array.Decimal) error
+	AppendDurations(j int, vs *array.Int) error
+	AppendBinaries(j int, vs *array.Binary) error
 
 	// TODO(adam): determine if there's a useful API for AppendValues
 	// AppendValues(j int, values []values.Value)
@@ -494,6 +532,9 @@ type TableBuilder interface {
 	GrowFloats(j, n int) error
 	GrowStrings(j, n int) error
 	GrowTimes(j, n int) error
+	GrowDecimals(j, n int) error
+	GrowDurations(j, n int) error
+	GrowBinaries(j, n int) error
 
 	// LevelColumns will check for columns that are too short and Grow them
 	// so that each column is of uniform size.
@@ -611,6 +652,33 @@ func (b *ColListTableBuilder) AddCol(c flux.ColMeta) (int, error) {
 				return -1, err
 			}
 		}
+	case flux.TDecimal:
+		b.cols = append(b.cols, &decimalColumnBuilder{
+			columnBuilderBase: colBase,
+		})
+		if b.NRows() > 0 {
+			if err := b.GrowDecimals(newIdx, b.NRows()); err != nil {
+				return -1, err
+			}
+		}
+	case flux.TDuration:
+		b.cols = append(b.cols, &durationColumnBuilder{
+			columnBuilderBase: colBase,
+		})
+		if b.NRows() > 0 {
+			if err := b.GrowDurations(newIdx, b.NRows()); err != nil {
+				return -1, err
+			}
+		}
+	case flux.TBytes:
+		b.cols = append(b.cols, &bytesColumnBuilder{
+			columnBuilderBase: colBase,
+		})
+		if b.NRows() > 0 {
+			if err := b.GrowBinaries(newIdx, b.NRows()); err != nil {
+				return -1, err
+			}
+		}
 	default:
 		PanicUnknownType(c.Type)
 	}
@@ -684,6 +752,39 @@ func (b *ColListTableBuilder) LevelColumns() error {
 				}
 			}
 
+			if toGrow < 0 {
+				_ = fmt.Errorf("column %s is longer than expected length of table", c.Label)
+			}
+		case flux.TDecimal:
+			toGrow := b.NRows() - b.cols[idx].Len()
+			if toGrow > 0 {
+				if err := b.GrowDecimals(idx, toGrow); err != nil {
+					return err
+				}
+			}
+
+			if toGrow < 0 {
+				_ = fmt.Errorf("column %s is longer than expected length of table", c.Label)
+			}
+		case flux.TDuration:
+			toGrow := b.NRows() - b.cols[idx].Len()
+			if toGrow > 0 {
+				if err := b.GrowDurations(idx, toGrow); err != nil {
+					return err
+				}
+			}
+
+			if toGrow < 0 {
+				_ = fmt.Errorf("column %s is longer than expected length of table", c.Label)
+			}
+		case flux.TBytes:
+			toGrow := b.NRows() - b.cols[idx].Len()
+			if toGrow > 0 {
+				if err := b.GrowBinaries(idx, toGrow); err != nil {
+					return err
+				}
+			}
+
 			if toGrow < 0 {
 				_ = fmt.Errorf("column %s is longer than expected length of table", c.Label)
 			}
@@ -1021,6 +1122,178 @@ func (b *ColListTableBuilder) GrowTimes(j, n int) error {
 
 }
 
+func (b *ColListTableBuilder) SetDecimal(i int, j int, value values.Decimal) error {
+	if err := b.checkCol(j, flux.TDecimal); err != nil {
+		return err
+	}
+	b.cols[j].(*decimalColumnBuilder).data[i] = value
+	b.cols[j].SetNil(i, false)
+	return nil
+}
+
+func (b *ColListTableBuilder) AppendDecimal(j int, value values.Decimal) error {
+	if err := b.checkCol(j, flux.TDecimal); err != nil {
+		return err
+	}
+	col := b.cols[j].(*decimalColumnBuilder)
+	col.data = b.alloc.AppendDecimals(col.data, value)
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) AppendDecimals(j int, vs *array.Decimal) error {
+	if err := b.checkCol(j, flux.TDecimal); err != nil {
+		return err
+	}
+	col := b.cols[j].(*decimalColumnBuilder)
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsNull(i) {
+			if err := b.AppendNil(j); err != nil {
+				return err
+			}
+		} else if err := b.AppendDecimal(j, values.NewDecimalFromNum(vs.Value(i))); err != nil {
+			return err
+		}
+	}
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) GrowDecimals(j, n int) error {
+	if err := b.checkCol(j, flux.TDecimal); err != nil {
+		return err
+	}
+	col := b.cols[j].(*decimalColumnBuilder)
+	i := len(col.data)
+	col.data = b.alloc.GrowDecimals(col.data, n)
+	b.nrows = len(col.data)
+	for ; i < b.nrows; i++ {
+		if err := b.SetNil(i, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetDuration sets the duration at the specified coordinates.
+// It is an error if the duration has months.
+func (b *ColListTableBuilder) SetDuration(i int, j int, value values.Duration) error {
+	if err := b.checkCol(j, flux.TDuration); err != nil {
+		return err
+	}
+	nsecs, err := value.FixedNanoseconds()
+	if err != nil {
+		return err
+	}
+	b.cols[j].(*durationColumnBuilder).data[i] = nsecs
+	b.cols[j].SetNil(i, false)
+	return nil
+}
+
+// AppendDuration appends a duration to column j.
+// It is an error if the duration has months.
+func (b *ColListTableBuilder) AppendDuration(j int, value values.Duration) error {
+	if err := b.checkCol(j, flux.TDuration); err != nil {
+		return err
+	}
+	nsecs, err := value.FixedNanoseconds()
+	if err != nil {
+		return err
+	}
+	col := b.cols[j].(*durationColumnBuilder)
+	col.data = b.alloc.AppendInts(col.data, nsecs)
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) AppendDurations(j int, vs *array.Int) error {
+	if err := b.checkCol(j, flux.TDuration); err != nil {
+		return err
+	}
+	col := b.cols[j].(*durationColumnBuilder)
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsNull(i) {
+			if err := b.AppendNil(j); err != nil {
+				return err
+			}
+			continue
+		}
+		col.data = b.alloc.AppendInts(col.data, vs.Value(i))
+		b.nrows = len(col.data)
+	}
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) GrowDurations(j, n int) error {
+	if err := b.checkCol(j, flux.TDuration); err != nil {
+		return err
+	}
+	col := b.cols[j].(*durationColumnBuilder)
+	i := len(col.data)
+	col.data = b.alloc.GrowInts(col.data, n)
+	b.nrows = len(col.data)
+	for ; i < b.nrows; i++ {
+		if err := b.SetNil(i, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *ColListTableBuilder) SetBytes(i int, j int, value []byte) error {
+	if err := b.checkCol(j, flux.TBytes); err != nil {
+		return err
+	}
+	b.cols[j].(*bytesColumnBuilder).data[i] = value
+	b.cols[j].SetNil(i, false)
+	return nil
+}
+
+func (b *ColListTableBuilder) AppendBytes(j int, value []byte) error {
+	if err := b.checkCol(j, flux.TBytes); err != nil {
+		return err
+	}
+	col := b.cols[j].(*bytesColumnBuilder)
+	col.data = b.alloc.AppendBinaries(col.data, value)
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) AppendBinaries(j int, vs *array.Binary) error {
+	if err := b.checkCol(j, flux.TBytes); err != nil {
+		return err
+	}
+	col := b.cols[j].(*bytesColumnBuilder)
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsNull(i) {
+			if err := b.AppendNil(j); err != nil {
+				return err
+			}
+		} else if err := b.AppendBytes(j, copyBytes(vs.Value(i))); err != nil {
+			return err
+		}
+	}
+	b.nrows = len(col.data)
+	return nil
+}
+
+func (b *ColListTableBuilder) GrowBinaries(j, n int) error {
+	if err := b.checkCol(j, flux.TBytes); err != nil {
+		return err
+	}
+	col := b.cols[j].(*bytesColumnBuilder)
+	i := len(col.data)
+	col.data = b.alloc.GrowBinaries(col.data, n)
+	b.nrows = len(col.data)
+	for ; i < b.nrows; i++ {
+		if err := b.SetNil(i, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (b *ColListTableBuilder) SetValue(i, j int, v values.Value) error {
 	if v.IsNull() {
 		return b.SetNil(i, j)
@@ -1039,6 +1312,12 @@ func (b *ColListTableBuilder) SetValue(i, j int, v values.Value) error {
 		return b.SetString(i, j, v.Str())
 	case semantic.Time:
 		return b.SetTime(i, j, v.Time())
+	case semantic.Decimal:
+		return b.SetDecimal(i, j, v.Decimal())
+	case semantic.Duration:
+		return b.SetDuration(i, j, v.Duration())
+	case semantic.Bytes:
+		return b.SetBytes(i, j, v.Bytes())
 	default:
 		panic(fmt.Errorf("unexpected value type %v", v.Type()))
 	}
@@ -1062,6 +1341,12 @@ func (b *ColListTableBuilder) AppendValue(j int, v values.Value) error {
 		return b.AppendString(j, v.Str())
 	case semantic.Time:
 		return b.AppendTime(j, v.Time())
+	case semantic.Decimal:
+		return b.AppendDecimal(j, v.Decimal())
+	case semantic.Duration:
+		return b.AppendDuration(j, v.Duration())
+	case semantic.Bytes:
+		return b.AppendBytes(j, v.Bytes())
 	default:
 		panic(fmt.Errorf("unexpected value type %v", v.Type()))
 	}
@@ -1109,6 +1394,18 @@ func (b *ColListTableBuilder) AppendNil(j int) error {
 		if err := b.AppendTime(j, 0); err != nil {
 			return err
 		}
+	case flux.TDecimal:
+		if err := b.AppendDecimal(j, values.Decimal{}); err != nil {
+			return err
+		}
+	case flux.TDuration:
+		if err := b.AppendDuration(j, values.Duration{}); err != nil {
+			return err
+		}
+	case flux.TBytes:
+		if err := b.AppendBytes(j, nil); err != nil {
+			return err
+		}
 	default:
 		panic(fmt.Errorf("unexpected value type %v", typ))
 	}
@@ -1159,6 +1456,20 @@ func (b *ColListTableBuilder) Times(j int) []values.Time {
 	CheckColType(b.colMeta[j], flux.TTime)
 	return b.cols[j].(*timeColumnBuilder).data
 }
+func (b *ColListTableBuilder) Decimals(j int) []values.Decimal {
+	CheckColType(b.colMeta[j], flux.TDecimal)
+	return b.cols[j].(*decimalColumnBuilder).data
+}
+
+// Durations returns the durations of column j as a signed number of nanoseconds.
+func (b *ColListTableBuilder) Durations(j int) []int64 {
+	CheckColType(b.colMeta[j], flux.TDuration)
+	return b.cols[j].(*durationColumnBuilder).data
+}
+func (b *ColListTableBuilder) Bytes(j int) [][]byte {
+	CheckColType(b.colMeta[j], flux.TBytes)
+	return b.cols[j].(*bytesColumnBuilder).data
+}
 
 // GetRow takes a row index and returns the record located at that index in the cache
 func (b *ColListTableBuilder) GetRow(row int) values.Object {
@@ -1181,6 +1492,12 @@ func (b *ColListTableBuilder) GetRow(row int) values.Object {
 					val = values.NewString(b.cols[j].(*stringColumnBuilder).data[row])
 				case flux.TTime:
 					val = values.NewTime(b.cols[j].(*timeColumnBuilder).data[row])
+				case flux.TDecimal:
+					val = values.NewDecimal(b.cols[j].(*decimalColumnBuilder).data[row])
+				case flux.TDuration:
+					val = values.NewDuration(values.ConvertDurationNsecs(time.Duration(b.cols[j].(*durationColumnBuilder).data[row])))
+				case flux.TBytes:
+					val = values.NewBytes(b.cols[j].(*bytesColumnBuilder).data[row])
 				}
 			}
 			set(col.Label, val)
@@ -1240,6 +1557,15 @@ func (b *ColListTableBuilder) SliceColumns(start, stop int) error {
 		case flux.TTime:
 			col := b.cols[i].(*timeColumnBuilder)
 			col.data = col.data[start:stop]
+		case flux.TDecimal:
+			col := b.cols[i].(*decimalColumnBuilder)
+			col.data = col.data[start:stop]
+		case flux.TDuration:
+			col := b.cols[i].(*durationColumnBuilder)
+			col.data = col.data[start:stop]
+		case flux.TBytes:
+			col := b.cols[i].(*bytesColumnBuilder)
+			col.data = col.data[start:stop]
 		default:
 			panic(fmt.Errorf("unexpected column type %v", c.Meta().Type))
 		}
@@ -1362,6 +1688,18 @@ func (t *ColListTable) Times(j int) *array.Int {
 	CheckColType(t.colMeta[j], flux.TTime)
 	return t.cols[j].(*timeColumn).data
 }
+func (t *ColListTable) Decimals(j int) *array.Decimal {
+	CheckColType(t.colMeta[j], flux.TDecimal)
+	return t.cols[j].(*decimalColumn).data
+}
+func (t *ColListTable) Durations(j int) *array.Int {
+	CheckColType(t.colMeta[j], flux.TDuration)
+	return t.cols[j].(*durationColumn).data
+}
+func (t *ColListTable) Bytes(j int) *array.Binary {
+	CheckColType(t.colMeta[j], flux.TBytes)
+	return t.cols[j].(*bytesColumn).data
+}
 
 // GetRow takes a row index and returns the record located at that index in the cache
 func (t *ColListTable) GetRow(row int) values.Object {
@@ -1381,6 +1719,12 @@ func (t *ColListTable) GetRow(row int) values.Object {
 				val = values.NewString(t.cols[j].(*stringColumnBuilder).data[row])
 			case flux.TTime:
 				val = values.NewTime(t.cols[j].(*timeColumnBuilder).data[row])
+			case flux.TDecimal:
+				val = values.NewDecimal(t.cols[j].(*decimalColumnBuilder).data[row])
+			case flux.TDuration:
+				val = values.NewDuration(values.ConvertDurationNsecs(time.Duration(t.cols[j].(*durationColumnBuilder).data[row])))
+			case flux.TBytes:
+				val = values.NewBytes(t.cols[j].(*bytesColumnBuilder).data[row])
 			}
 			set(col.Label, val)
 		}
@@ -2007,6 +2351,250 @@ func (c *timeColumnBuilder) Swap(i, j int) {
 	c.data[i], c.data[j] = c.data[j], c.data[i]
 }
 
+type decimalColumn struct {
+	flux.ColMeta
+	data *array.Decimal
+}
+
+func (c *decimalColumn) Meta() flux.ColMeta {
+	return c.ColMeta
+}
+
+func (c *decimalColumn) Clear() {
+	if c.data != nil {
+		c.data.Release()
+		c.data = nil
+	}
+}
+func (c *decimalColumn) Copy() column {
+	c.data.Retain()
+	return &decimalColumn{
+		ColMeta: c.ColMeta,
+		data:    c.data,
+	}
+}
+
+type decimalColumnBuilder struct {
+	columnBuilderBase
+	data []values.Decimal
+}
+
+func (c *decimalColumnBuilder) Clear() {
+	c.data = c.data[0:0]
+}
+
+func (c *decimalColumnBuilder) Release() {
+	c.alloc.Free(cap(c.data), decimalSize)
+	c.data = nil
+}
+
+func (c *decimalColumnBuilder) Copy() column {
+	b := array.NewDecimalBuilder(c.alloc.Allocator)
+	b.Reserve(len(c.data))
+	for i, v := range c.data {
+		if c.nils[i] {
+			b.UnsafeAppendBoolToBitmap(false)
+			continue
+		}
+		b.UnsafeAppend(v.Num())
+	}
+	col := &decimalColumn{
+		ColMeta: c.ColMeta,
+		data:    b.NewDecimalArray(),
+	}
+	b.Release()
+	return col
+}
+
+func (c *decimalColumnBuilder) Len() int {
+	return len(c.data)
+}
+
+func (c *decimalColumnBuilder) Equal(i, j int) bool {
+	return c.EqualFunc(i, j, func(i, j int) bool {
+		return c.data[i] == c.data[j]
+	})
+}
+
+func (c *decimalColumnBuilder) Less(i, j int) bool {
+	return c.LessFunc(i, j, func(i, j int) bool {
+		return c.data[i].Cmp(c.data[j]) < 0
+	})
+}
+
+func (c *decimalColumnBuilder) Swap(i, j int) {
+	c.columnBuilderBase.Swap(i, j)
+	c.data[i], c.data[j] = c.data[j], c.data[i]
+}
+
+type durationColumn struct {
+	flux.ColMeta
+	data *array.Int
+}
+
+func (c *durationColumn) Meta() flux.ColMeta {
+	return c.ColMeta
+}
+
+func (c *durationColumn) Clear() {
+	if c.data != nil {
+		c.data.Release()
+		c.data = nil
+	}
+}
+func (c *durationColumn) Copy() column {
+	c.data.Retain()
+	return &durationColumn{
+		ColMeta: c.ColMeta,
+		data:    c.data,
+	}
+}
+
+// durationColumnBuilder holds durations
+// as a signed number of nanoseconds.
+type durationColumnBuilder struct {
+	columnBuilderBase
+	data []int64
+}
+
+func (c *durationColumnBuilder) Clear() {
+	c.data = c.data[0:0]
+}
+
+func (c *durationColumnBuilder) Release() {
+	c.alloc.Free(cap(c.data), int64Size)
+	c.data = nil
+}
+
+func (c *durationColumnBuilder) Copy() column {
+	b := arrow.NewIntBuilder(c.alloc.Allocator)
+	b.Reserve(len(c.data))
+	for i, v := range c.data {
+		if c.nils[i] {
+			b.UnsafeAppendBoolToBitmap(false)
+			continue
+		}
+		b.UnsafeAppend(v)
+	}
+	col := &durationColumn{
+		ColMeta: c.ColMeta,
+		data:    b.NewIntArray(),
+	}
+	b.Release()
+	return col
+}
+
+func (c *durationColumnBuilder) Len() int {
+	return len(c.data)
+}
+
+func (c *durationColumnBuilder) Equal(i, j int) bool {
+	return c.EqualFunc(i, j, func(i, j int) bool {
+		return c.data[i] == c.data[j]
+	})
+}
+
+func (c *durationColumnBuilder) Less(i, j int) bool {
+	return c.LessFunc(i, j, func(i, j int) bool {
+		return c.data[i] < c.data[j]
+	})
+}
+
+func (c *durationColumnBuilder) Swap(i, j int) {
+	c.columnBuilderBase.Swap(i, j)
+	c.data[i], c.data[j] = c.data[j], c.data[i]
+}
+
+type bytesColumn struct {
+	flux.ColMeta
+	data *array.Binary
+}
+
+func (c *bytesColumn) Meta() flux.ColMeta {
+	return c.ColMeta
+}
+
+func (c *bytesColumn) Clear() {
+	if c.data != nil {
+		c.data.Release()
+		c.data = nil
+	}
+}
+func (c *bytesColumn) Copy() column {
+	c.data.Retain()
+	return &bytesColumn{
+		ColMeta: c.ColMeta,
+		data:    c.data,
+	}
+}
+
+type bytesColumnBuilder struct {
+	columnBuilderBase
+	data [][]byte
+}
+
+func (c *bytesColumnBuilder) Clear() {
+	c.data = c.data[0:0]
+}
+
+func (c *bytesColumnBuilder) Release() {
+	c.alloc.Free(cap(c.data), bytesSize)
+	c.data = nil
+}
+
+func (c *bytesColumnBuilder) Copy() column {
+	b := arrow.NewBinaryBuilder(c.alloc.Allocator)
+	b.Reserve(len(c.data))
+	sz := 0
+	for i, v := range c.data {
+		if c.nils[i] {
+			continue
+		}
+		sz += len(v)
+	}
+	b.ReserveData(sz)
+	for i, v := range c.data {
+		if c.nils[i] {
+			b.AppendNull()
+			continue
+		}
+		b.Append(v)
+	}
+	col := &bytesColumn{
+		ColMeta: c.ColMeta,
+		data:    b.NewBinaryArray(),
+	}
+	b.Release()
+	return col
+}
+
+func (c *bytesColumnBuilder) Len() int {
+	return len(c.data)
+}
+
+func (c *bytesColumnBuilder) Equal(i, j int) bool {
+	return c.EqualFunc(i, j, func(i, j int) bool {
+		return bytes.Equal(c.data[i], c.data[j])
+	})
+}
+
+func (c *bytesColumnBuilder) Less(i, j int) bool {
+	return c.LessFunc(i, j, func(i, j int) bool {
+		return bytes.Compare(c.data[i], c.data[j]) < 0
+	})
+}
+
+func (c *bytesColumnBuilder) Swap(i, j int) {
+	c.columnBuilderBase.Swap(i, j)
+	c.data[i], c.data[j] = c.data[j], c.data[i]
+}
+
+// copyBytes copies a value of a bytes array so the value
+// does not reference the memory of the array after it is released.
+func copyBytes(v []byte) []byte {
+	return append([]byte(nil), v...)
+}
+
 type TableBuilderCache interface {
 	// TableBuilder returns an existing or new TableBuilder for the given meta data.
 	// The boolean return value indicates if TableBuilder is new.
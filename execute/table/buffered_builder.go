@@ -7,6 +7,7 @@ import (
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
+	fluxmemory "github.com/influxdata/flux/memory"
 )
 
 // BufferedBuilder is a table builder that constructs
@@ -16,6 +17,10 @@ type BufferedBuilder struct {
 	Columns   []flux.ColMeta
 	Buffers   []*arrow.TableBuffer
 	Allocator memory.Allocator
+
+	// spill is set when this builder is registered with
+	// an allocator to write its buffers to disk.
+	spill *bufferedSpill
 }
 
 // NewBufferedBuilder constructs a new BufferedBuilder.
@@ -26,6 +31,25 @@ func NewBufferedBuilder(key flux.GroupKey, mem memory.Allocator) *BufferedBuilde
 	}
 }
 
+// NewSpillableBufferedBuilder constructs a new BufferedBuilder that
+// will move its buffers to disk when the allocator reaches its limit.
+//
+// The builder is only spillable when spilling is enabled for the
+// allocator. Otherwise, this is the same as NewBufferedBuilder.
+// The builder must be finished with either Table or Release
+// so it is removed from the allocator.
+func NewSpillableBufferedBuilder(key flux.GroupKey, mem *fluxmemory.Allocator) *BufferedBuilder {
+	b := NewBufferedBuilder(key, mem)
+	if mem.SpillEnabled() {
+		b.spill = &bufferedSpill{
+			mem:  mem,
+			lock: make(chan struct{}, 1),
+		}
+		mem.RegisterSpiller(b)
+	}
+	return b
+}
+
 // GetBufferedBuilder is a convenience method for retrieving a
 // BufferedBuilder from the BuilderCache.
 func GetBufferedBuilder(key flux.GroupKey, cache *BuilderCache) (builder *BufferedBuilder, created bool) {
@@ -60,6 +84,9 @@ func (b *BufferedBuilder) AppendBuffer(cr flux.ColReader) error {
 }
 
 func (b *BufferedBuilder) appendBuffer(cr flux.ColReader, mem memory.Allocator) error {
+	b.lock()
+	defer b.unlock()
+
 	// Construct a table buffer and put the arrays in the correct index.
 	buffer := &arrow.TableBuffer{
 		GroupKey: b.GroupKey,
@@ -87,6 +114,9 @@ func (b *BufferedBuilder) appendBuffer(cr flux.ColReader, mem memory.Allocator)
 // the same name have the same type. This returns an error if there
 // is a schema collision.
 func (b *BufferedBuilder) normalizeTableSchema(cols []flux.ColMeta, mem memory.Allocator) error {
+	b.lock()
+	defer b.unlock()
+
 	// If there are no columns set for this builder, inherit the ones
 	// that were passed in.
 	if b.Columns == nil {
@@ -136,6 +166,13 @@ func (b *BufferedBuilder) getAllocator() memory.Allocator {
 }
 
 func (b *BufferedBuilder) Table() (flux.Table, error) {
+	if b.spill != nil {
+		b.spill.mem.UnregisterSpiller(b)
+		if b.spill.file != nil {
+			return b.spilledTable(), nil
+		}
+	}
+
 	buffers := make([]flux.ColReader, 0, len(b.Buffers))
 	for _, buf := range b.Buffers {
 		buffers = append(buffers, buf)
@@ -149,7 +186,114 @@ func (b *BufferedBuilder) Table() (flux.Table, error) {
 }
 
 func (b *BufferedBuilder) Release() {
+	if b.spill != nil {
+		b.spill.mem.UnregisterSpiller(b)
+		if b.spill.file != nil {
+			_ = b.spill.file.Close()
+			b.spill.file = nil
+		}
+	}
 	for _, buf := range b.Buffers {
 		buf.Release()
 	}
 }
+
+// Spill writes the buffers held by this builder to a temporary file
+// and releases them. It returns the number of bytes written to disk.
+//
+// This implements the memory.Spiller interface. Spill is invoked
+// by the allocator when it reaches its memory limit. If the
+// builder is currently in use, it does nothing.
+func (b *BufferedBuilder) Spill() (int64, error) {
+	if b.spill == nil || !b.tryLock() {
+		return 0, nil
+	}
+	defer b.unlock()
+
+	if len(b.Buffers) == 0 {
+		return 0, nil
+	}
+
+	if b.spill.file == nil {
+		f, err := NewSpillFile(b.spill.mem.SpillDir)
+		if err != nil {
+			return 0, err
+		}
+		b.spill.file = f
+	}
+
+	start := b.spill.file.Size()
+	for _, buf := range b.Buffers {
+		if err := b.spill.file.Write(buf); err != nil {
+			return 0, err
+		}
+		b.spill.rows += buf.Len()
+	}
+
+	// The buffers are on disk so release them to free the memory.
+	for _, buf := range b.Buffers {
+		buf.Release()
+	}
+	b.Buffers = nil
+	return b.spill.file.Size() - start, nil
+}
+
+// lock acquires exclusive access to the buffers of this builder
+// so they are not spilled while they are being modified.
+func (b *BufferedBuilder) lock() {
+	if b.spill != nil {
+		b.spill.lock <- struct{}{}
+	}
+}
+
+// tryLock acquires exclusive access to the buffers of this builder
+// if nothing else is currently using them.
+func (b *BufferedBuilder) tryLock() bool {
+	select {
+	case b.spill.lock <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *BufferedBuilder) unlock() {
+	if b.spill != nil {
+		<-b.spill.lock
+	}
+}
+
+// spilledTable constructs a table that reads back the buffers
+// written to disk followed by the buffers still in memory.
+func (b *BufferedBuilder) spilledTable() flux.Table {
+	buffers := make([]flux.ColReader, 0, len(b.Buffers))
+	for _, buf := range b.Buffers {
+		buffers = append(buffers, buf)
+	}
+	tbl := &spilledTable{
+		BufferedTable: BufferedTable{
+			GroupKey: b.GroupKey,
+			Columns:  b.Columns,
+			Buffers:  buffers,
+		},
+		file: b.spill.file,
+		end:  b.spill.file.Size(),
+		rows: b.spill.rows,
+		mem:  b.getAllocator(),
+	}
+	b.Buffers = nil
+	b.spill.file = nil
+	return tbl
+}
+
+// bufferedSpill holds the state for a BufferedBuilder
+// that has been registered to spill its buffers.
+type bufferedSpill struct {
+	mem  *fluxmemory.Allocator
+	file *SpillFile
+	rows int
+
+	// lock is used as a mutex that can be acquired
+	// without blocking when the allocator asks to spill.
+	lock chan struct{}
+}
@@ -183,3 +183,66 @@ func TestBufferedBuilder_AppendTable(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferedBuilder_Spill(t *testing.T) {
+	in := static.TableGroup{
+		static.StringKey("_measurement", "m0"),
+		static.Table{
+			static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+			static.Floats("f0", 3, nil, 2),
+			static.Strings("s", "a", "b", "b"),
+			static.Booleans("b", true, nil, false),
+		},
+		static.Table{
+			static.Times("_time", "2020-01-01T00:00:30Z", 10, 20),
+			static.Floats("f0", 18, 2, 7),
+			static.Strings("s", "c", "d", "e"),
+			static.Booleans("b", false, true, true),
+			static.Uints("f1", 5, nil, 2),
+		},
+	}
+	want := static.Table{
+		static.StringKey("_measurement", "m0"),
+		static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, 30, 40, 50),
+		static.Floats("f0", 3, nil, 2, 18, 2, 7),
+		static.Strings("s", "a", "b", "b", "c", "d", "e"),
+		static.Booleans("b", true, nil, false, false, true, true),
+		static.Uints("f1", nil, nil, nil, 5, nil, 2),
+	}
+
+	mem := &memory.Allocator{SpillDir: t.TempDir()}
+	var b *table.BufferedBuilder
+	if err := in.Do(func(tbl flux.Table) error {
+		if b == nil {
+			b = table.NewSpillableBufferedBuilder(tbl.Key(), mem)
+		}
+		if err := b.AppendTable(tbl); err != nil {
+			return err
+		}
+
+		// Spill each table after it is appended.
+		_, err := b.Spill()
+		return err
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if mem.Allocated() != 0 {
+		t.Errorf("expected all memory to be released after spilling, got %d bytes", mem.Allocated())
+	}
+
+	out, err := b.Table()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if out.Empty() {
+		t.Fatal("expected table to not be empty")
+	}
+
+	if diff := table.Diff(want, table.Iterator{out}); diff != "" {
+		t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+	}
+	if mem.Allocated() != 0 {
+		t.Errorf("expected all memory to be released, got %d bytes", mem.Allocated())
+	}
+}
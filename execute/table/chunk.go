@@ -126,6 +126,24 @@ func (v Chunk) Strings(j int) *array.String {
 	return v.Values(j).(*array.String)
 }
 
+// Decimals is a convenience function for retrieving an array
+// as a decimal array.
+func (v Chunk) Decimals(j int) *array.Decimal {
+	return v.Values(j).(*array.Decimal)
+}
+
+// Durations is a convenience function for retrieving an array
+// as a duration array.
+func (v Chunk) Durations(j int) *array.Int {
+	return v.Values(j).(*array.Int)
+}
+
+// Bytes is a convenience function for retrieving an array
+// as a bytes array.
+func (v Chunk) Bytes(j int) *array.Binary {
+	return v.Values(j).(*array.Binary)
+}
+
 // Retain will retain a reference to this Chunk.
 func (v Chunk) Retain() {
 	v.buf.Retain()
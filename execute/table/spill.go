@@ -0,0 +1,447 @@
+package table
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/apache/arrow/go/arrow/decimal128"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// SpillFile is a temporary file that holds table buffers
+// that were moved out of memory.
+//
+// Buffers are appended to the end of the file with Write
+// and can be read back in the same order by opening a reader
+// over a range of the file. The file is removed from disk
+// when it is closed.
+type SpillFile struct {
+	f *os.File
+	w *bufio.Writer
+	n int64
+}
+
+// NewSpillFile creates a new spill file in the given directory.
+func NewSpillFile(dir string) (*SpillFile, error) {
+	f, err := ioutil.TempFile(dir, "flux-spill-")
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "could not create spill file")
+	}
+	return &SpillFile{
+		f: f,
+		w: bufio.NewWriter(f),
+	}, nil
+}
+
+// Size returns the number of bytes that have been written to the file.
+// It can be used to mark the boundaries of a range of buffers.
+func (s *SpillFile) Size() int64 {
+	return s.n
+}
+
+// Write appends the column reader to the end of the file.
+// The column reader is not released.
+func (s *SpillFile) Write(cr flux.ColReader) error {
+	w := spillWriter{w: s.w}
+	w.writeBuffer(cr)
+	s.n += w.n
+	if w.err != nil {
+		return errors.Wrap(w.err, codes.Internal, "could not write to spill file")
+	}
+	return nil
+}
+
+// Open returns a reader for the buffers that were written
+// between the start and end offsets.
+// The buffers that are read will use the given group key.
+func (s *SpillFile) Open(key flux.GroupKey, start, end int64) (*SpillReader, error) {
+	if err := s.w.Flush(); err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "could not flush spill file")
+	}
+	return &SpillReader{
+		key: key,
+		r:   bufio.NewReader(io.NewSectionReader(s.f, start, end-start)),
+	}, nil
+}
+
+// Close closes and removes the file.
+func (s *SpillFile) Close() error {
+	name := s.f.Name()
+	err := s.f.Close()
+	if rerr := os.Remove(name); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// SpillReader reads table buffers back from a SpillFile.
+type SpillReader struct {
+	key flux.GroupKey
+	r   *bufio.Reader
+}
+
+// Read reads the next buffer from the spill file and allocates
+// its memory with the given allocator. It returns io.EOF when
+// there are no more buffers.
+func (s *SpillReader) Read(mem memory.Allocator) (*arrow.TableBuffer, error) {
+	if _, err := s.r.Peek(1); err == io.EOF {
+		return nil, io.EOF
+	}
+	r := spillReader{r: s.r}
+	buffer := r.readBuffer(s.key, mem)
+	if r.err != nil {
+		if buffer != nil {
+			buffer.Release()
+		}
+		return nil, errors.Wrap(r.err, codes.Internal, "could not read from spill file")
+	}
+	return buffer, nil
+}
+
+// spillWriter encodes table buffers to the spill file format.
+//
+// Each buffer is written as the number of columns and rows,
+// followed by the label and type of each column and then
+// the values of each column. Each column is a validity bitmap
+// followed by the values. A decimal is written as the high
+// and then the low 64 bits of its 128 bit integer.
+type spillWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (w *spillWriter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *spillWriter) writeUvarint(v uint64) {
+	n := binary.PutUvarint(w.buf[:], v)
+	w.write(w.buf[:n])
+}
+
+func (w *spillWriter) writeUint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.buf[:8], v)
+	w.write(w.buf[:8])
+}
+
+func (w *spillWriter) writeBuffer(cr flux.ColReader) {
+	cols, l := cr.Cols(), cr.Len()
+	w.writeUvarint(uint64(len(cols)))
+	w.writeUvarint(uint64(l))
+	for _, c := range cols {
+		w.writeUvarint(uint64(len(c.Label)))
+		w.write([]byte(c.Label))
+		w.writeUvarint(uint64(c.Type))
+	}
+	for j, c := range cols {
+		arr := Values(cr, j)
+		w.writeBitmap(l, arr.IsValid)
+		switch c.Type {
+		case flux.TInt, flux.TTime, flux.TDuration:
+			vs := arr.(*array.Int)
+			for i := 0; i < l; i++ {
+				w.writeUint64(uint64(vs.Value(i)))
+			}
+		case flux.TUInt:
+			vs := arr.(*array.Uint)
+			for i := 0; i < l; i++ {
+				w.writeUint64(vs.Value(i))
+			}
+		case flux.TFloat:
+			vs := arr.(*array.Float)
+			for i := 0; i < l; i++ {
+				w.writeUint64(math.Float64bits(vs.Value(i)))
+			}
+		case flux.TString:
+			vs := arr.(*array.String)
+			for i := 0; i < l; i++ {
+				v := vs.Value(i)
+				w.writeUvarint(uint64(len(v)))
+				w.write([]byte(v))
+			}
+		case flux.TBool:
+			vs := arr.(*array.Boolean)
+			w.writeBitmap(l, vs.Value)
+		case flux.TDecimal:
+			vs := arr.(*array.Decimal)
+			for i := 0; i < l; i++ {
+				v := vs.Value(i)
+				w.writeUint64(uint64(v.HighBits()))
+				w.writeUint64(v.LowBits())
+			}
+		case flux.TBytes:
+			vs := arr.(*array.Binary)
+			for i := 0; i < l; i++ {
+				v := vs.Value(i)
+				w.writeUvarint(uint64(len(v)))
+				w.write(v)
+			}
+		}
+	}
+}
+
+func (w *spillWriter) writeBitmap(l int, fn func(i int) bool) {
+	var b byte
+	for i := 0; i < l; i++ {
+		if fn(i) {
+			b |= 1 << uint(i%8)
+		}
+		if i%8 == 7 {
+			w.write([]byte{b})
+			b = 0
+		}
+	}
+	if l%8 != 0 {
+		w.write([]byte{b})
+	}
+}
+
+// spillReader decodes table buffers written by spillWriter.
+type spillReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (r *spillReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(r.r, p); err != nil {
+		r.err = err
+		return nil
+	}
+	return p
+}
+
+func (r *spillReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		r.err = err
+	}
+	return v
+}
+
+func (r *spillReader) readUint64() uint64 {
+	p := r.read(8)
+	if p == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(p)
+}
+
+func (r *spillReader) readBitmap(l int) []byte {
+	return r.read((l + 7) / 8)
+}
+
+func (r *spillReader) readBuffer(key flux.GroupKey, mem memory.Allocator) *arrow.TableBuffer {
+	ncols, l := int(r.readUvarint()), int(r.readUvarint())
+	if r.err != nil {
+		return nil
+	}
+	cols := make([]flux.ColMeta, ncols)
+	for j := range cols {
+		label := r.read(int(r.readUvarint()))
+		cols[j] = flux.ColMeta{
+			Label: string(label),
+			Type:  flux.ColType(r.readUvarint()),
+		}
+	}
+	if r.err != nil {
+		return nil
+	}
+
+	buffer := &arrow.TableBuffer{
+		GroupKey: key,
+		Columns:  cols,
+		Values:   make([]array.Interface, 0, ncols),
+	}
+	for _, c := range cols {
+		arr := r.readColumn(c.Type, l, mem)
+		buffer.Values = append(buffer.Values, arr)
+		if r.err != nil {
+			buffer.Release()
+			return nil
+		}
+	}
+	return buffer
+}
+
+func (r *spillReader) readColumn(typ flux.ColType, l int, mem memory.Allocator) array.Interface {
+	valid := r.readBitmap(l)
+	isValid := func(i int) bool {
+		return r.err == nil && valid[i/8]&(1<<uint(i%8)) != 0
+	}
+	b := arrow.NewBuilder(typ, mem)
+	defer b.Release()
+	b.Resize(l)
+	switch typ {
+	case flux.TInt, flux.TTime, flux.TDuration:
+		b := b.(*array.IntBuilder)
+		for i := 0; i < l; i++ {
+			if v := int64(r.readUint64()); isValid(i) {
+				b.Append(v)
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TUInt:
+		b := b.(*array.UintBuilder)
+		for i := 0; i < l; i++ {
+			if v := r.readUint64(); isValid(i) {
+				b.Append(v)
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TFloat:
+		b := b.(*array.FloatBuilder)
+		for i := 0; i < l; i++ {
+			if v := math.Float64frombits(r.readUint64()); isValid(i) {
+				b.Append(v)
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TString:
+		b := b.(*array.StringBuilder)
+		for i := 0; i < l; i++ {
+			if v := r.read(int(r.readUvarint())); isValid(i) {
+				b.Append(string(v))
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TBool:
+		b := b.(*array.BooleanBuilder)
+		values := r.readBitmap(l)
+		for i := 0; i < l; i++ {
+			if isValid(i) {
+				b.Append(values[i/8]&(1<<uint(i%8)) != 0)
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TDecimal:
+		b := b.(*array.DecimalBuilder)
+		for i := 0; i < l; i++ {
+			hi, lo := int64(r.readUint64()), r.readUint64()
+			if isValid(i) {
+				b.Append(decimal128.New(hi, lo))
+			} else {
+				b.AppendNull()
+			}
+		}
+	case flux.TBytes:
+		b := b.(*array.BinaryBuilder)
+		for i := 0; i < l; i++ {
+			if v := r.read(int(r.readUvarint())); isValid(i) {
+				b.Append(v)
+			} else {
+				b.AppendNull()
+			}
+		}
+	default:
+		r.err = errors.Newf(codes.Internal, "unsupported column type in spill file: %s", typ)
+	}
+	return b.NewArray()
+}
+
+// spilledTable is a BufferedTable where some of the buffers
+// were written to a SpillFile. The buffers on disk are read
+// before the buffers that are still in memory.
+type spilledTable struct {
+	BufferedTable
+	file *SpillFile
+	end  int64
+	rows int
+	mem  memory.Allocator
+}
+
+func (t *spilledTable) Do(f func(flux.ColReader) error) error {
+	if t.file == nil {
+		return t.BufferedTable.Do(f)
+	}
+	if err := t.readSpilled(f); err != nil {
+		t.Done()
+		return err
+	}
+	return t.BufferedTable.Do(f)
+}
+
+func (t *spilledTable) readSpilled(f func(flux.ColReader) error) error {
+	defer t.closeFile()
+
+	r, err := t.file.Open(t.GroupKey, 0, t.end)
+	if err != nil {
+		return err
+	}
+	for {
+		buf, err := r.Read(t.mem)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Columns may have been added to the table after
+		// this buffer was written so fill them in with nulls.
+		t.normalize(buf)
+		err = f(buf)
+		buf.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// normalize ensures the buffer has the same columns as the table.
+func (t *spilledTable) normalize(buf *arrow.TableBuffer) {
+	if len(buf.Columns) == len(t.Columns) {
+		return
+	}
+	vs := make([]array.Interface, len(t.Columns))
+	for j, c := range t.Columns {
+		if idx := colIdx(c.Label, buf.Columns); idx >= 0 {
+			vs[j] = buf.Values[idx]
+			continue
+		}
+		vs[j] = arrow.Nulls(c.Type, buf.Len(), t.mem)
+	}
+	buf.Columns, buf.Values = t.Columns, vs
+}
+
+func (t *spilledTable) Done() {
+	t.closeFile()
+	t.BufferedTable.Done()
+}
+
+func (t *spilledTable) Empty() bool {
+	return t.rows == 0 && t.BufferedTable.Empty()
+}
+
+func (t *spilledTable) closeFile() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
@@ -0,0 +1,80 @@
+package table_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	arrowmemory "github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/values"
+)
+
+// newTypedBuffer returns a buffer with the column types
+// that cannot be built with static tables.
+func newTypedBuffer(mem arrowmemory.Allocator) *arrow.TableBuffer {
+	d := array.NewDecimalBuilder(mem)
+	for _, v := range []string{"-1.5", "", "12345678901234567890.000000001"} {
+		if v == "" {
+			d.AppendNull()
+			continue
+		}
+		dec, err := values.ParseDecimal(v)
+		if err != nil {
+			panic(err)
+		}
+		d.Append(dec.Num())
+	}
+	dur := array.NewIntBuilder(mem)
+	dur.AppendValues([]int64{int64(time.Second), 0, -5}, []bool{true, false, true})
+	b := array.NewBinaryBuilder(mem)
+	b.AppendValues([][]byte{{0xff}, {}, nil}, []bool{true, true, false})
+	return &arrow.TableBuffer{
+		GroupKey: execute.NewGroupKey(nil, nil),
+		Columns: []flux.ColMeta{
+			{Label: "d", Type: flux.TDecimal},
+			{Label: "dur", Type: flux.TDuration},
+			{Label: "b", Type: flux.TBytes},
+		},
+		Values: []array.Interface{d.NewArray(), dur.NewArray(), b.NewArray()},
+	}
+}
+
+func TestSpillFile_RoundTripTypes(t *testing.T) {
+	mem := arrowmemory.NewCheckedAllocator(arrowmemory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	f, err := table.NewSpillFile(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	in := newTypedBuffer(mem)
+	defer in.Release()
+	if err := f.Write(in); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := f.Open(in.Key(), 0, f.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Read(mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(mem); err != io.EOF {
+		t.Fatalf("expected the end of the spill file, got %v", err)
+	}
+
+	// The tables release their buffers when they are read.
+	want, got := table.FromBuffer(newTypedBuffer(mem)), table.FromBuffer(out)
+	if diff := table.Diff(table.Iterator{want}, table.Iterator{got}); diff != "" {
+		t.Errorf("unexpected buffer -want/+got:\n%s", diff)
+	}
+}
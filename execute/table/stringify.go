@@ -150,6 +150,21 @@ func valueForRow(cr flux.ColReader, i, j int) values.Value {
 			return values.NewNull(semantic.BasicTime)
 		}
 		return values.NewTime(values.Time(cr.Times(j).Value(i)))
+	case flux.TDecimal:
+		if cr.Decimals(j).IsNull(i) {
+			return values.NewNull(semantic.BasicDecimal)
+		}
+		return values.NewDecimal(values.NewDecimalFromNum(cr.Decimals(j).Value(i)))
+	case flux.TDuration:
+		if cr.Durations(j).IsNull(i) {
+			return values.NewNull(semantic.BasicDuration)
+		}
+		return values.NewDuration(values.ConvertDurationNsecs(time.Duration(cr.Durations(j).Value(i))))
+	case flux.TBytes:
+		if cr.Bytes(j).IsNull(i) {
+			return values.NewNull(semantic.BasicBytes)
+		}
+		return values.NewBytes(cr.Bytes(j).Value(i))
 	default:
 		panic(fmt.Errorf("unknown type %v", t))
 	}
@@ -183,6 +198,12 @@ func stringifyValue(sb *strings.Builder, v values.Value) {
 		} else {
 			sb.WriteString(ts.Format(time.RFC3339))
 		}
+	case semantic.Decimal:
+		sb.WriteString(v.Decimal().String())
+	case semantic.Duration:
+		sb.WriteString(v.Duration().String())
+	case semantic.Bytes:
+		_, _ = fmt.Fprintf(sb, "0x%x", v.Bytes())
 	default:
 		sb.WriteString("!(invalid)")
 	}
@@ -22,6 +22,12 @@ func Values(cr flux.ColReader, j int) array.Interface {
 		return cr.Bools(j)
 	case flux.TTime:
 		return cr.Times(j)
+	case flux.TDecimal:
+		return cr.Decimals(j)
+	case flux.TDuration:
+		return cr.Durations(j)
+	case flux.TBytes:
+		return cr.Bytes(j)
 	default:
 		panic(errors.Newf(codes.Internal, "unimplemented column type: %s", typ))
 	}
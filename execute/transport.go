@@ -6,9 +6,11 @@ import (
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/internal/errors"
@@ -16,6 +18,8 @@ import (
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/plan"
 	"github.com/opentracing/opentracing-go"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -45,6 +49,17 @@ type consecutiveTransport struct {
 	op, label string
 	stack     []interpreter.StackEntry
 
+	// span is the OpenTelemetry span of the transformation.
+	// It ends when the transformation is finished.
+	span    trace.Span
+	metrics *operatorMetrics
+
+	// The state used by the operator profiler, if it is enabled.
+	profiling      bool
+	rowsIn         int64
+	scheduledAt    int64
+	dispatcherWait time.Duration
+
 	finished chan struct{}
 	errMu    sync.Mutex
 	errValue error
@@ -54,17 +69,24 @@ type consecutiveTransport struct {
 }
 
 func newConsecutiveTransport(ctx context.Context, dispatcher Dispatcher, t Transformation, n plan.Node, logger *zap.Logger, mem memory.Allocator) *consecutiveTransport {
+	op, label := OperationType(t), string(n.ID())
+	_, span := flux.Tracer(ctx).Start(ctx, op,
+		trace.WithAttributes(operatorAttributes(op, label)...),
+	)
 	return &consecutiveTransport{
 		ctx:        ctx,
 		dispatcher: dispatcher,
 		logger:     logger,
 		t:          WrapTransformationInTransport(t, mem),
 		// TODO(nathanielc): Have planner specify message queue initial buffer size.
-		messages: newMessageQueue(64),
-		op:       OperationType(t),
-		label:    string(n.ID()),
-		stack:    n.CallStack(),
-		finished: make(chan struct{}),
+		messages:  newMessageQueue(64),
+		op:        op,
+		label:     label,
+		stack:     n.CallStack(),
+		span:      span,
+		metrics:   newOperatorMetrics(ctx, op, label),
+		profiling: operatorProfiler(ctx) != nil,
+		finished:  make(chan struct{}),
 	}
 }
 
@@ -195,6 +217,9 @@ const (
 // schedule indicates that there is work available to schedule.
 func (t *consecutiveTransport) schedule() {
 	if t.tryTransition(idle, running) {
+		if t.profiling {
+			atomic.StoreInt64(&t.scheduledAt, time.Now().UnixNano())
+		}
 		t.dispatcher.Schedule(t.processMessages)
 	}
 }
@@ -210,6 +235,9 @@ func (t *consecutiveTransport) transition(new int32) {
 }
 
 func (t *consecutiveTransport) processMessages(ctx context.Context, throughput int) {
+	if t.profiling {
+		t.dispatcherWait += time.Since(time.Unix(0, atomic.LoadInt64(&t.scheduledAt)))
+	}
 PROCESS:
 	i := 0
 	for m := t.messages.Pop(); m != nil; m = t.messages.Pop() {
@@ -229,6 +257,7 @@ PROCESS:
 					_ = t.t.ProcessMessage(m)
 				}
 				// We are finished
+				t.endSpan()
 				close(t.finished)
 				return
 			}
@@ -258,6 +287,13 @@ PROCESS:
 func (t *consecutiveTransport) processMessage(ctx context.Context, m Message) (finished bool, err error) {
 	if _, span := StartSpanFromContext(ctx, t.op, t.label); span != nil {
 		defer span.Finish()
+		if span, ok := span.(*OperatorProfilingSpan); ok {
+			defer t.profile(span, m)()
+		}
+	}
+	if m, ok := m.(ProcessChunkMsg); ok {
+		chunk := m.TableChunk()
+		t.metrics.record(chunk.Len(), chunk.NCols(), chunk.Values)
 	}
 	if err := t.t.ProcessMessage(m); err != nil {
 		return false, err
@@ -266,6 +302,34 @@ func (t *consecutiveTransport) processMessage(ctx context.Context, m Message) (f
 	return finished, nil
 }
 
+// profile records the tables, chunks and rows of the message and the time
+// spent waiting on the dispatcher in the profiling span. The returned function
+// records the rows that were read while the message was processed.
+func (t *consecutiveTransport) profile(span *OperatorProfilingSpan, m Message) func() {
+	r := &span.Result
+	switch m := m.(type) {
+	case ProcessMsg:
+		r.TableCount++
+	case ProcessChunkMsg:
+		r.ChunkCount++
+		r.RowsIn += int64(m.TableChunk().Len())
+	}
+	r.DispatcherWait, t.dispatcherWait = t.dispatcherWait, 0
+	return func() {
+		r.RowsIn += atomic.SwapInt64(&t.rowsIn, 0)
+	}
+}
+
+// endSpan ends the span of the transformation
+// and records the error that finished it, if any.
+func (t *consecutiveTransport) endSpan() {
+	if err := t.err(); err != nil {
+		t.span.RecordError(err)
+		t.span.SetStatus(otelcodes.Error, err.Error())
+	}
+	t.span.End()
+}
+
 // Message is a message sent from one Dataset to another.
 type Message interface {
 	// Type returns the MessageType for this Message.
@@ -517,6 +581,12 @@ func (t *consecutiveTransportTable) Cols() []flux.ColMeta {
 
 func (t *consecutiveTransportTable) Do(f func(flux.ColReader) error) error {
 	return t.tbl.Do(func(cr flux.ColReader) error {
+		t.transport.metrics.record(cr.Len(), len(cr.Cols()), func(j int) array.Interface {
+			return table.Values(cr, j)
+		})
+		if t.transport.profiling {
+			atomic.AddInt64(&t.transport.rowsIn, int64(cr.Len()))
+		}
 		if err := t.validate(cr); err != nil {
 			fields := []zap.Field{
 				zap.String("source", t.transport.sourceInfo()),
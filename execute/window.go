@@ -12,6 +12,10 @@ type Window struct {
 	Every  Duration
 	Period Duration
 	Offset Duration
+	// Location is the time zone whose wall clock is used
+	// to compute the window boundaries. The boundaries
+	// are computed in UTC when this is nil.
+	Location *time.Location
 }
 
 // NewWindow creates a window with the given parameters,
@@ -68,6 +72,12 @@ func (w Window) IsValid() error {
 // that contains the given time t.  For underlapping windows that
 // do not contain time t, the window directly after time t will be returned.
 func (w Window) GetEarliestBounds(t Time) Bounds {
+	return w.fromLocal(w.getEarliestLocalBounds(t.ToLocal(w.Location)))
+}
+
+// getEarliestLocalBounds computes the earliest bounds in terms
+// of the wall clock of the window's location.
+func (w Window) getEarliestLocalBounds(t Time) Bounds {
 	// translate to not-offset coordinate
 	t = t.Add(w.Offset.Mul(-1))
 
@@ -94,15 +104,32 @@ func (w Window) GetOverlappingBounds(b Bounds) []Bounds {
 	c := (b.Duration().Duration() / w.Every.Duration()) + (w.Period.Duration() / w.Every.Duration())
 	bs := make([]Bounds, 0, c)
 
-	bi := w.GetEarliestBounds(b.Start)
-	for bi.Start < b.Stop {
-		bs = append(bs, bi)
+	bi := w.getEarliestLocalBounds(b.Start.ToLocal(w.Location))
+	for {
+		bounds := w.fromLocal(bi)
+		if bounds.Start >= b.Stop {
+			break
+		}
+		// Bounds in a location may be empty when the wall clock
+		// times they cover are skipped by a daylight saving transition.
+		if w.Location == nil || !bounds.IsEmpty() {
+			bs = append(bs, bounds)
+		}
 		bi.Start = bi.Start.Add(w.Every)
 		bi.Stop = bi.Stop.Add(w.Every)
 	}
 	return bs
 }
 
+// fromLocal converts bounds in terms of the wall clock
+// of the window's location to absolute bounds.
+func (w Window) fromLocal(b Bounds) Bounds {
+	return Bounds{
+		Start: b.Start.FromLocal(w.Location),
+		Stop:  b.Stop.FromLocal(w.Location),
+	}
+}
+
 // truncateByNsecs will truncate the time to the given number
 // of nanoseconds.
 func truncateByNsecs(t Time, d Duration) Time {
@@ -467,6 +467,39 @@ func TestWindow_GetOverlappingBounds(t *testing.T) {
 				{Start: ts("2019-12-01T00:00:00Z"), Stop: ts("2020-03-01T00:00:00Z")},
 			},
 		},
+		{
+			name: "daily in location",
+			b: execute.Bounds{
+				Start: ts("2021-03-27T12:00:00Z"),
+				Stop:  ts("2021-03-29T12:00:00Z"),
+			},
+			w: execute.Window{
+				Every:    ds("1d"),
+				Period:   ds("1d"),
+				Location: mustLoadLocation("Europe/Berlin"),
+			},
+			want: []execute.Bounds{
+				{Start: ts("2021-03-26T23:00:00Z"), Stop: ts("2021-03-27T23:00:00Z")},
+				{Start: ts("2021-03-27T23:00:00Z"), Stop: ts("2021-03-28T22:00:00Z")},
+				{Start: ts("2021-03-28T22:00:00Z"), Stop: ts("2021-03-29T22:00:00Z")},
+			},
+		},
+		{
+			name: "monthly in location",
+			b: execute.Bounds{
+				Start: ts("2021-03-15T00:00:00Z"),
+				Stop:  ts("2021-04-15T00:00:00Z"),
+			},
+			w: execute.Window{
+				Every:    ds("1mo"),
+				Period:   ds("1mo"),
+				Location: mustLoadLocation("America/New_York"),
+			},
+			want: []execute.Bounds{
+				{Start: ts("2021-03-01T05:00:00Z"), Stop: ts("2021-04-01T04:00:00Z")},
+				{Start: ts("2021-04-01T04:00:00Z"), Stop: ts("2021-05-01T04:00:00Z")},
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -504,6 +537,14 @@ func mustParseDuration(s string) execute.Duration {
 	return d
 }
 
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
 func errAsString(err error) (s string) {
 	if err != nil {
 		s = err.Error()
@@ -68,6 +68,9 @@ func (v IntArrayValue) Time() values.Time {
 func (v IntArrayValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (v IntArrayValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (v IntArrayValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -148,6 +151,9 @@ func (v UintArrayValue) Time() values.Time {
 func (v UintArrayValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (v UintArrayValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (v UintArrayValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -230,6 +236,9 @@ func (v FloatArrayValue) Time() values.Time {
 func (v FloatArrayValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (v FloatArrayValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (v FloatArrayValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -316,6 +325,9 @@ func (v BooleanArrayValue) Time() values.Time {
 func (v BooleanArrayValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (v BooleanArrayValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (v BooleanArrayValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -402,6 +414,9 @@ func (v StringArrayValue) Time() values.Time {
 func (v StringArrayValue) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (v StringArrayValue) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (v StringArrayValue) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -7,9 +7,11 @@
 package arrowutil
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/values"
 )
 
 // CompareFunc defines the interface for a comparison function.
@@ -38,6 +40,10 @@ func Compare(x, y array.Interface, i, j int) int {
 	case *array.String:
 		return StringCompare(x, y.(*array.String), i, j)
 
+	case *array.Decimal:
+		return DecimalCompare(x, y.(*array.Decimal), i, j)
+	case *array.Binary:
+		return BinaryCompare(x, y.(*array.Binary), i, j)
 	default:
 		panic(fmt.Errorf("unsupported array data type: %s", x.DataType()))
 	}
@@ -64,6 +70,10 @@ func CompareDesc(x, y array.Interface, i, j int) int {
 	case *array.String:
 		return StringCompareDesc(x, y.(*array.String), i, j)
 
+	case *array.Decimal:
+		return DecimalCompareDesc(x, y.(*array.Decimal), i, j)
+	case *array.Binary:
+		return BinaryCompareDesc(x, y.(*array.Binary), i, j)
 	default:
 		panic(fmt.Errorf("unsupported array data type: %s", x.DataType()))
 	}
@@ -264,3 +274,54 @@ func StringCompareDesc(x, y *array.String, i, j int) int {
 	return 1
 
 }
+
+// The decimal and bytes arrays are not generated from the
+// template data so they are compared by these functions.
+
+func DecimalCompare(x, y *array.Decimal, i, j int) int {
+	if x.IsNull(i) {
+		if y.IsNull(j) {
+			return 0
+		}
+		return -1
+	} else if y.IsNull(j) {
+		return 1
+	}
+	return values.NewDecimalFromNum(x.Value(i)).Cmp(values.NewDecimalFromNum(y.Value(j)))
+}
+
+func DecimalCompareDesc(x, y *array.Decimal, i, j int) int {
+	if x.IsNull(i) {
+		if y.IsNull(j) {
+			return 0
+		}
+		return 1
+	} else if y.IsNull(j) {
+		return -1
+	}
+	return values.NewDecimalFromNum(y.Value(j)).Cmp(values.NewDecimalFromNum(x.Value(i)))
+}
+
+func BinaryCompare(x, y *array.Binary, i, j int) int {
+	if x.IsNull(i) {
+		if y.IsNull(j) {
+			return 0
+		}
+		return -1
+	} else if y.IsNull(j) {
+		return 1
+	}
+	return bytes.Compare(x.Value(i), y.Value(j))
+}
+
+func BinaryCompareDesc(x, y *array.Binary, i, j int) int {
+	if x.IsNull(i) {
+		if y.IsNull(j) {
+			return 0
+		}
+		return 1
+	} else if y.IsNull(j) {
+		return -1
+	}
+	return bytes.Compare(y.Value(j), x.Value(i))
+}
@@ -0,0 +1,56 @@
+package arrowutil_test
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/arrow/decimal128"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/internal/arrowutil"
+	"github.com/influxdata/flux/memory"
+)
+
+func TestCompare_Decimal(t *testing.T) {
+	b := array.NewDecimalBuilder(memory.DefaultAllocator)
+	b.Append(decimal128.FromI64(-5))
+	b.Append(decimal128.FromI64(3))
+	b.AppendNull()
+	arr := b.NewDecimalArray()
+	defer arr.Release()
+
+	testCompare(t, arr)
+}
+
+func TestCompare_Binary(t *testing.T) {
+	b := array.NewBinaryBuilder(memory.DefaultAllocator)
+	b.Append([]byte("a"))
+	b.Append([]byte("ab"))
+	b.AppendNull()
+	arr := b.NewBinaryArray()
+	defer arr.Release()
+
+	testCompare(t, arr)
+}
+
+// testCompare checks the comparisons of an array with a smaller value,
+// a larger value and a null value in that order.
+func testCompare(t *testing.T, arr array.Interface) {
+	t.Helper()
+	for _, tc := range []struct {
+		i, j     int
+		asc, dsc int
+	}{
+		{i: 0, j: 1, asc: -1, dsc: 1},
+		{i: 1, j: 0, asc: 1, dsc: -1},
+		{i: 1, j: 1, asc: 0, dsc: 0},
+		{i: 2, j: 0, asc: -1, dsc: 1},
+		{i: 0, j: 2, asc: 1, dsc: -1},
+		{i: 2, j: 2, asc: 0, dsc: 0},
+	} {
+		if got := arrowutil.Compare(arr, arr, tc.i, tc.j); got != tc.asc {
+			t.Errorf("unexpected ascending comparison of %d and %d: want %d, got %d", tc.i, tc.j, tc.asc, got)
+		}
+		if got := arrowutil.CompareDesc(arr, arr, tc.i, tc.j); got != tc.dsc {
+			t.Errorf("unexpected descending comparison of %d and %d: want %d, got %d", tc.i, tc.j, tc.dsc, got)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package groupkey
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 	"strings"
@@ -102,6 +103,17 @@ func (k *groupKey) String() string {
 	return b.String()
 }
 
+// Hash64 returns a hash of the columns and values of the group key.
+// Equal group keys have the same hash regardless of the order of
+// their columns.
+func Hash64(key flux.GroupKey) uint64 {
+	k, ok := key.(*groupKey)
+	if !ok {
+		k = newGroupKey(key.Cols(), key.Values())
+	}
+	return k.hash64()
+}
+
 func (k *groupKey) hash64() (h uint64) {
 	if h = atomic.LoadUint64(&k.hash); h != 0 {
 		return h
@@ -140,6 +152,23 @@ func (k *groupKey) hash64() (h uint64) {
 			case flux.TTime:
 				arrow.Int64Traits.PutValue(data[:], int64(v.Time()))
 				_, _ = hash.Write(data[:arrow.Int64SizeBytes])
+			case flux.TDecimal:
+				n := v.Decimal().Num()
+				arrow.Int64Traits.PutValue(data[:], n.HighBits())
+				_, _ = hash.Write(data[:arrow.Int64SizeBytes])
+				arrow.Uint64Traits.PutValue(data[:], n.LowBits())
+				_, _ = hash.Write(data[:arrow.Uint64SizeBytes])
+			case flux.TDuration:
+				d := v.Duration()
+				arrow.Int64Traits.PutValue(data[:], d.Months())
+				_, _ = hash.Write(data[:arrow.Int64SizeBytes])
+				arrow.Int64Traits.PutValue(data[:], d.Nanoseconds())
+				_, _ = hash.Write(data[:arrow.Int64SizeBytes])
+				if d.IsNegative() {
+					_, _ = hash.Write([]byte{1})
+				}
+			case flux.TBytes:
+				_, _ = hash.Write(v.Bytes())
 			}
 		} else {
 			// Write an invalid byte if there is a null value
@@ -201,6 +230,18 @@ func groupKeyEqual(a *groupKey, other flux.GroupKey) bool {
 			if a.ValueTime(idx) != b.ValueTime(jdx) {
 				return false
 			}
+		case flux.TDecimal:
+			if a.Value(idx).Decimal() != b.Value(jdx).Decimal() {
+				return false
+			}
+		case flux.TDuration:
+			if !a.Value(idx).Duration().Equal(b.Value(jdx).Duration()) {
+				return false
+			}
+		case flux.TBytes:
+			if !bytes.Equal(a.Value(idx).Bytes(), b.Value(jdx).Bytes()) {
+				return false
+			}
 		}
 	}
 	return true
@@ -271,6 +312,18 @@ func groupKeyLess(a *groupKey, other flux.GroupKey) bool {
 			if av, bv := a.ValueTime(idx), b.ValueTime(jdx); av != bv {
 				return av < bv
 			}
+		case flux.TDecimal:
+			if c := a.Value(idx).Decimal().Cmp(b.Value(jdx).Decimal()); c != 0 {
+				return c < 0
+			}
+		case flux.TDuration:
+			if c := compareDurations(a.Value(idx).Duration(), b.Value(jdx).Duration()); c != 0 {
+				return c < 0
+			}
+		case flux.TBytes:
+			if c := bytes.Compare(a.Value(idx).Bytes(), b.Value(jdx).Bytes()); c != 0 {
+				return c < 0
+			}
 		}
 	}
 
@@ -288,3 +341,28 @@ func colIdx(label string, cols []flux.ColMeta) int {
 	}
 	return -1
 }
+
+// compareDurations orders durations by their signed number
+// of months and then by their signed number of nanoseconds.
+func compareDurations(a, b values.Duration) int {
+	am, an := a.Months(), a.Nanoseconds()
+	if a.IsNegative() {
+		am, an = -am, -an
+	}
+	bm, bn := b.Months(), b.Nanoseconds()
+	if b.IsNegative() {
+		bm, bn = -bm, -bn
+	}
+	switch {
+	case am < bm:
+		return -1
+	case am > bm:
+		return 1
+	case an < bn:
+		return -1
+	case an > bn:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -2,6 +2,7 @@ package groupkey_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
@@ -331,6 +332,46 @@ func TestGroupKey_Less(t *testing.T) {
 			),
 			want: [2]bool{true, false},
 		},
+		{
+			name: "Duration_LessThan",
+			left: execute.NewGroupKey(
+				[]flux.ColMeta{
+					{Label: "a", Type: flux.TDuration},
+				},
+				[]values.Value{
+					values.NewDuration(values.ConvertDurationNsecs(-time.Minute)),
+				},
+			),
+			right: execute.NewGroupKey(
+				[]flux.ColMeta{
+					{Label: "a", Type: flux.TDuration},
+				},
+				[]values.Value{
+					values.NewDuration(values.ConvertDurationNsecs(time.Second)),
+				},
+			),
+			want: [2]bool{true, false},
+		},
+		{
+			name: "Bytes_LessThan",
+			left: execute.NewGroupKey(
+				[]flux.ColMeta{
+					{Label: "a", Type: flux.TBytes},
+				},
+				[]values.Value{
+					values.NewBytes([]byte{0x01, 0x02}),
+				},
+			),
+			right: execute.NewGroupKey(
+				[]flux.ColMeta{
+					{Label: "a", Type: flux.TBytes},
+				},
+				[]values.Value{
+					values.NewBytes([]byte{0x01, 0x03}),
+				},
+			),
+			want: [2]bool{true, false},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			if want, got := tt.want[0], tt.left.Less(tt.right); want != got {
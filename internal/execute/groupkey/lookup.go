@@ -331,11 +331,7 @@ func NewRandomAccessLookup() *RandomAccessLookup {
 }
 
 func (l *RandomAccessLookup) idForKey(key flux.GroupKey) uint64 {
-	k, ok := key.(*groupKey)
-	if !ok {
-		k = newGroupKey(key.Cols(), key.Values())
-	}
-	return k.hash64()
+	return Hash64(key)
 }
 
 // Lookup will retrieve the value associated with the given key if it exists.
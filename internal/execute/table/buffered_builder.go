@@ -4,6 +4,7 @@ import (
 	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute/table"
+	fluxmemory "github.com/influxdata/flux/memory"
 )
 
 const BufferSize = table.BufferSize
@@ -17,3 +18,16 @@ func GetBufferedBuilder(key flux.GroupKey, cache *BuilderCache) (builder *Buffer
 func NewBufferedBuilder(key flux.GroupKey, mem memory.Allocator) *BufferedBuilder {
 	return table.NewBufferedBuilder(key, mem)
 }
+
+func NewSpillableBufferedBuilder(key flux.GroupKey, mem *fluxmemory.Allocator) *BufferedBuilder {
+	return table.NewSpillableBufferedBuilder(key, mem)
+}
+
+type (
+	SpillFile   = table.SpillFile
+	SpillReader = table.SpillReader
+)
+
+func NewSpillFile(dir string) (*SpillFile, error) {
+	return table.NewSpillFile(dir)
+}
@@ -95,15 +95,18 @@ func (m *maskTableView) Cols() []flux.ColMeta {
 	return m.cols
 }
 
-func (m *maskTableView) Len() int                    { return m.reader.Len() }
-func (m *maskTableView) Bools(j int) *array.Boolean  { return m.reader.Bools(j + m.offsets[j]) }
-func (m *maskTableView) Ints(j int) *array.Int       { return m.reader.Ints(j + m.offsets[j]) }
-func (m *maskTableView) UInts(j int) *array.Uint     { return m.reader.UInts(j + m.offsets[j]) }
-func (m *maskTableView) Floats(j int) *array.Float   { return m.reader.Floats(j + m.offsets[j]) }
-func (m *maskTableView) Strings(j int) *array.String { return m.reader.Strings(j + m.offsets[j]) }
-func (m *maskTableView) Times(j int) *array.Int      { return m.reader.Times(j + m.offsets[j]) }
-func (m *maskTableView) Retain()                     { m.reader.Retain() }
-func (m *maskTableView) Release()                    { m.reader.Release() }
+func (m *maskTableView) Len() int                      { return m.reader.Len() }
+func (m *maskTableView) Bools(j int) *array.Boolean    { return m.reader.Bools(j + m.offsets[j]) }
+func (m *maskTableView) Ints(j int) *array.Int         { return m.reader.Ints(j + m.offsets[j]) }
+func (m *maskTableView) UInts(j int) *array.Uint       { return m.reader.UInts(j + m.offsets[j]) }
+func (m *maskTableView) Floats(j int) *array.Float     { return m.reader.Floats(j + m.offsets[j]) }
+func (m *maskTableView) Strings(j int) *array.String   { return m.reader.Strings(j + m.offsets[j]) }
+func (m *maskTableView) Times(j int) *array.Int        { return m.reader.Times(j + m.offsets[j]) }
+func (m *maskTableView) Decimals(j int) *array.Decimal { return m.reader.Decimals(j + m.offsets[j]) }
+func (m *maskTableView) Durations(j int) *array.Int    { return m.reader.Durations(j + m.offsets[j]) }
+func (m *maskTableView) Bytes(j int) *array.Binary     { return m.reader.Bytes(j + m.offsets[j]) }
+func (m *maskTableView) Retain()                       { m.reader.Retain() }
+func (m *maskTableView) Release()                      { m.reader.Release() }
 
 func containsStr(strs []string, str string) bool {
 	for _, s := range strs {
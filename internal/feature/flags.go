@@ -77,6 +77,18 @@ func OptimizeDerivative() BoolFlag {
 	return optimizeDerivative
 }
 
+var queryParallelLanes = feature.MakeIntFlag(
+	"Query Parallel Lanes",
+	"queryParallelLanes",
+	"Jonathan Sternberg",
+	0,
+)
+
+// QueryParallelLanes - Sets the number of parallel lanes the planner splits partitionable transformations into
+func QueryParallelLanes() IntFlag {
+	return queryParallelLanes
+}
+
 // Inject will inject the Flagger into the context.
 func Inject(ctx context.Context, flagger Flagger) context.Context {
 	return feature.Inject(ctx, flagger)
@@ -88,6 +100,7 @@ var all = []Flag{
 	groupTransformationGroup,
 	queryConcurrencyLimit,
 	optimizeDerivative,
+	queryParallelLanes,
 }
 
 var byKey = map[string]Flag{
@@ -96,6 +109,7 @@ var byKey = map[string]Flag{
 	"groupTransformationGroup":         groupTransformationGroup,
 	"queryConcurrencyLimit":            queryConcurrencyLimit,
 	"optimizeDerivative":               optimizeDerivative,
+	"queryParallelLanes":               queryParallelLanes,
 }
 
 // Flags returns all feature flags.
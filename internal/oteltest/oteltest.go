@@ -0,0 +1,166 @@
+// Package oteltest records OpenTelemetry spans and metrics in memory
+// so tests can verify how a query is instrumented.
+package oteltest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/nonrecording"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// NewTracerProvider returns a tracer provider that exports
+// each span to the in-memory exporter as soon as it ends.
+func NewTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exp := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)), exp
+}
+
+// MeterProvider is a metric.MeterProvider that sums
+// the values of its int64 counters in memory.
+// Other instruments do not record anything.
+type MeterProvider struct {
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is the sum of a counter with a set of attributes.
+type series struct {
+	name  string
+	attrs attribute.Set
+	value int64
+}
+
+var _ metric.MeterProvider = (*MeterProvider)(nil)
+
+// NewMeterProvider creates an empty MeterProvider.
+func NewMeterProvider() *MeterProvider {
+	return &MeterProvider{
+		series: make(map[string]*series),
+	}
+}
+
+// Meter returns a meter that records its counters in the provider.
+func (mp *MeterProvider) Meter(instrumentationName string, opts ...metric.MeterOption) metric.Meter {
+	return &meter{
+		Meter: nonrecording.NewNoopMeter(),
+		mp:    mp,
+	}
+}
+
+// Value returns the sum of the counter with exactly the attributes.
+// The order of the attributes does not matter.
+func (mp *MeterProvider) Value(name string, attrs ...attribute.KeyValue) int64 {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	key, _ := seriesKey(name, attrs)
+	if s, ok := mp.series[key]; ok {
+		return s.value
+	}
+	return 0
+}
+
+// Sum returns the sum of the counter over each set of
+// attributes that contains all of the attributes.
+func (mp *MeterProvider) Sum(name string, attrs ...attribute.KeyValue) int64 {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	var sum int64
+SERIES:
+	for _, s := range mp.series {
+		if s.name != name {
+			continue
+		}
+		for _, kv := range attrs {
+			if v, ok := s.attrs.Value(kv.Key); !ok || v != kv.Value {
+				continue SERIES
+			}
+		}
+		sum += s.value
+	}
+	return sum
+}
+
+// Series returns the keys of the counters that have been recorded.
+// A key is the name of the counter followed by its sorted attributes,
+// such as flux.operator.rows{flux.label=filter0,flux.operation=*universe.filterTransformation}.
+func (mp *MeterProvider) Series() []string {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	keys := make([]string, 0, len(mp.series))
+	for k := range mp.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (mp *MeterProvider) add(name string, incr int64, attrs []attribute.KeyValue) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	key, set := seriesKey(name, attrs)
+	s, ok := mp.series[key]
+	if !ok {
+		s = &series{name: name, attrs: set}
+		mp.series[key] = s
+	}
+	s.value += incr
+}
+
+func seriesKey(name string, attrs []attribute.KeyValue) (string, attribute.Set) {
+	// NewSet sorts the attributes in place so it is given a copy.
+	set := attribute.NewSet(append([]attribute.KeyValue(nil), attrs...)...)
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for iter, i := set.Iter(), 0; iter.Next(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		kv := iter.Attribute()
+		b.WriteString(string(kv.Key))
+		b.WriteByte('=')
+		b.WriteString(kv.Value.Emit())
+	}
+	b.WriteByte('}')
+	return b.String(), set
+}
+
+type meter struct {
+	metric.Meter
+	mp *MeterProvider
+}
+
+func (m *meter) SyncInt64() syncint64.InstrumentProvider {
+	return &int64Instruments{
+		InstrumentProvider: m.Meter.SyncInt64(),
+		mp:                 m.mp,
+	}
+}
+
+type int64Instruments struct {
+	syncint64.InstrumentProvider
+	mp *MeterProvider
+}
+
+func (p *int64Instruments) Counter(name string, opts ...instrument.Option) (syncint64.Counter, error) {
+	return &counter{name: name, mp: p.mp}, nil
+}
+
+type counter struct {
+	instrument.Synchronous
+	name string
+	mp   *MeterProvider
+}
+
+func (c *counter) Add(ctx context.Context, incr int64, attrs ...attribute.KeyValue) {
+	c.mp.add(c.name, incr, attrs)
+}
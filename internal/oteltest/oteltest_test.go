@@ -0,0 +1,61 @@
+package oteltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestMeterProvider(t *testing.T) {
+	mp := NewMeterProvider()
+	counter, err := mp.Meter("test").SyncInt64().Counter("rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	a, b := attribute.String("op", "filter"), attribute.String("label", "filter0")
+	counter.Add(ctx, 2, a, b)
+	counter.Add(ctx, 3, b, a)
+	counter.Add(ctx, 4, a, attribute.String("label", "filter1"))
+
+	if want, got := int64(5), mp.Value("rows", a, b); want != got {
+		t.Errorf("unexpected value: want %d, got %d", want, got)
+	}
+	if want, got := int64(0), mp.Value("rows", a); want != got {
+		t.Errorf("unexpected value: want %d, got %d", want, got)
+	}
+	if want, got := int64(9), mp.Sum("rows", a); want != got {
+		t.Errorf("unexpected sum: want %d, got %d", want, got)
+	}
+	if want, got := int64(0), mp.Sum("bytes", a); want != got {
+		t.Errorf("unexpected sum: want %d, got %d", want, got)
+	}
+	want := []string{
+		"rows{label=filter0,op=filter}",
+		"rows{label=filter1,op=filter}",
+	}
+	if got := mp.Series(); !cmp.Equal(want, got) {
+		t.Errorf("unexpected series -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestNewTracerProvider(t *testing.T) {
+	tp, exp := NewTracerProvider()
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	_, child := tp.Tracer("test").Start(ctx, "child")
+	child.End()
+	parent.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if want, got := "child", spans[0].Name; want != got {
+		t.Errorf("unexpected span: want %s, got %s", want, got)
+	}
+	if spans[0].Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Error("expected the child span to have the parent span as its parent")
+	}
+}
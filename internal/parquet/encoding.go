@@ -0,0 +1,254 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"math/bits"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+var errCorruptPage = errors.New(codes.Invalid, "corrupt parquet page")
+
+// bitWidth returns the number of bits required to store max.
+func bitWidth(max int) int {
+	return bits.Len(uint(max))
+}
+
+// decodeHybrid decodes n values that were encoded with the
+// RLE/bit-packing hybrid encoding using the given bit width.
+func decodeHybrid(data []byte, width, n int) ([]int32, error) {
+	out := make([]int32, 0, n)
+	if width == 0 {
+		return out[:n], nil
+	}
+	if width > 32 {
+		return nil, errCorruptPage
+	}
+	byteWidth := (width + 7) / 8
+	for len(out) < n {
+		header, k := binary.Uvarint(data)
+		if k <= 0 {
+			return nil, errCorruptPage
+		}
+		data = data[k:]
+
+		if header&1 == 0 {
+			// A run of the same value repeated count times.
+			count := int(header >> 1)
+			if len(data) < byteWidth {
+				return nil, errCorruptPage
+			}
+			var v uint32
+			for i := 0; i < byteWidth; i++ {
+				v |= uint32(data[i]) << (8 * i)
+			}
+			data = data[byteWidth:]
+			for i := 0; i < count && len(out) < n; i++ {
+				out = append(out, int32(v))
+			}
+			continue
+		}
+
+		// Groups of eight bit packed values.
+		count := int(header>>1) * 8
+		size := int(header>>1) * width
+		if len(data) < size {
+			// The last group may be truncated when the
+			// remaining values are not needed.
+			size = len(data)
+		}
+		packed := data[:size]
+		data = data[size:]
+		var (
+			acc   uint64
+			nbits int
+			mask  = uint64(1)<<uint(width) - 1
+		)
+		for i := 0; i < count && len(out) < n; i++ {
+			for nbits < width {
+				if len(packed) == 0 {
+					return nil, errCorruptPage
+				}
+				acc |= uint64(packed[0]) << uint(nbits)
+				packed = packed[1:]
+				nbits += 8
+			}
+			out = append(out, int32(acc&mask))
+			acc >>= uint(width)
+			nbits -= width
+		}
+	}
+	return out, nil
+}
+
+// encodeHybrid encodes the values as runs of the
+// RLE/bit-packing hybrid encoding using the given bit width.
+func encodeHybrid(buf []byte, values []int32, width int) []byte {
+	byteWidth := (width + 7) / 8
+	var tmp [binary.MaxVarintLen64]byte
+	for i := 0; i < len(values); {
+		j := i + 1
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+		n := binary.PutUvarint(tmp[:], uint64(j-i)<<1)
+		buf = append(buf, tmp[:n]...)
+		for b := 0; b < byteWidth; b++ {
+			buf = append(buf, byte(uint32(values[i])>>(8*b)))
+		}
+		i = j
+	}
+	return buf
+}
+
+// decodeLevels decodes the definition levels at the start of a data page.
+// It returns the levels and the remaining data.
+func decodeLevels(data []byte, enc Encoding, maxLevel, n int) ([]int32, []byte, error) {
+	width := bitWidth(maxLevel)
+	switch enc {
+	case RLE:
+		if len(data) < 4 {
+			return nil, nil, errCorruptPage
+		}
+		size := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if size < 0 || size > len(data) {
+			return nil, nil, errCorruptPage
+		}
+		levels, err := decodeHybrid(data[:size], width, n)
+		return levels, data[size:], err
+	case BitPacked:
+		// The deprecated encoding packs the values from the most significant bit.
+		size := (n*width + 7) / 8
+		if size > len(data) {
+			return nil, nil, errCorruptPage
+		}
+		levels := make([]int32, n)
+		for i := range levels {
+			var v int32
+			for b := 0; b < width; b++ {
+				bit := i*width + b
+				v = v<<1 | int32(data[bit/8]>>(7-uint(bit%8))&1)
+			}
+			levels[i] = v
+		}
+		return levels, data[size:], nil
+	default:
+		return nil, nil, errors.Newf(codes.Unimplemented, "unsupported parquet level encoding %d", enc)
+	}
+}
+
+// decodePlain decodes n plain encoded values of the given physical type.
+// The values are appended to the corresponding slice of vs.
+func decodePlain(vs *ColumnValues, typ Type, typeLength int, data []byte, n int) error {
+	switch typ {
+	case Boolean:
+		if len(data)*8 < n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Bools = append(vs.Bools, data[i/8]>>(uint(i)%8)&1 == 1)
+		}
+	case Int32:
+		if len(data) < 4*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Ints = append(vs.Ints, int64(int32(binary.LittleEndian.Uint32(data[4*i:]))))
+		}
+	case Int64:
+		if len(data) < 8*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Ints = append(vs.Ints, int64(binary.LittleEndian.Uint64(data[8*i:])))
+		}
+	case Int96:
+		if len(data) < 12*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Ints = append(vs.Ints, int96ToNanos(data[12*i:12*i+12]))
+		}
+	case Float:
+		if len(data) < 4*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Floats = append(vs.Floats, float64(math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))))
+		}
+	case Double:
+		if len(data) < 8*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Floats = append(vs.Floats, math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:])))
+		}
+	case ByteArray:
+		for i := 0; i < n; i++ {
+			if len(data) < 4 {
+				return errCorruptPage
+			}
+			size := int(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+			if size < 0 || size > len(data) {
+				return errCorruptPage
+			}
+			vs.Strings = append(vs.Strings, string(data[:size]))
+			data = data[size:]
+		}
+	case FixedLenByteArray:
+		if typeLength <= 0 || len(data) < typeLength*n {
+			return errCorruptPage
+		}
+		for i := 0; i < n; i++ {
+			vs.Strings = append(vs.Strings, string(data[typeLength*i:typeLength*(i+1)]))
+		}
+	default:
+		return errors.Newf(codes.Unimplemented, "unsupported parquet type %v", typ)
+	}
+	return nil
+}
+
+// julianUnixEpoch is the julian day of the unix epoch.
+const julianUnixEpoch = 2440588
+
+// int96ToNanos converts a legacy INT96 timestamp
+// to nanoseconds since the unix epoch.
+func int96ToNanos(b []byte) int64 {
+	nanos := int64(binary.LittleEndian.Uint64(b))
+	days := int64(binary.LittleEndian.Uint32(b[8:]))
+	return (days-julianUnixEpoch)*24*60*60*1e9 + nanos
+}
+
+// decompress decompresses the data of a page.
+func decompress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case Uncompressed:
+		return data, nil
+	case Snappy:
+		out, err := snappy.Decode(nil, data)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "invalid snappy data")
+		}
+		return out, nil
+	case Gzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "invalid gzip data")
+		}
+		out, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "invalid gzip data")
+		}
+		return out, nil
+	default:
+		return nil, errors.Newf(codes.Unimplemented, "unsupported parquet compression codec %v", codec)
+	}
+}
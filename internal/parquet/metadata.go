@@ -0,0 +1,730 @@
+package parquet
+
+// Type is the physical type of a column.
+type Type int32
+
+const (
+	Boolean           Type = 0
+	Int32             Type = 1
+	Int64             Type = 2
+	Int96             Type = 3
+	Float             Type = 4
+	Double            Type = 5
+	ByteArray         Type = 6
+	FixedLenByteArray Type = 7
+)
+
+func (t Type) String() string {
+	switch t {
+	case Boolean:
+		return "BOOLEAN"
+	case Int32:
+		return "INT32"
+	case Int64:
+		return "INT64"
+	case Int96:
+		return "INT96"
+	case Float:
+		return "FLOAT"
+	case Double:
+		return "DOUBLE"
+	case ByteArray:
+		return "BYTE_ARRAY"
+	case FixedLenByteArray:
+		return "FIXED_LEN_BYTE_ARRAY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ConvertedType is the legacy annotation of how a physical type
+// should be interpreted.
+type ConvertedType int32
+
+const (
+	ConvertedNone            ConvertedType = -1
+	ConvertedUTF8            ConvertedType = 0
+	ConvertedTimestampMillis ConvertedType = 9
+	ConvertedTimestampMicros ConvertedType = 10
+	ConvertedUint8           ConvertedType = 11
+	ConvertedUint16          ConvertedType = 12
+	ConvertedUint32          ConvertedType = 13
+	ConvertedUint64          ConvertedType = 14
+)
+
+// TimeUnit is the unit of a timestamp logical type.
+type TimeUnit int
+
+const (
+	Millis TimeUnit = iota + 1
+	Micros
+	Nanos
+)
+
+// LogicalType is the annotation of how a physical type should be interpreted.
+// Only the logical types that influence how values are read are decoded.
+type LogicalType struct {
+	String bool
+
+	Timestamp     bool
+	TimestampUnit TimeUnit
+	AdjustedToUTC bool
+
+	Integer       bool
+	IntegerBits   int8
+	IntegerSigned bool
+}
+
+// Repetition describes whether a field may be null or repeated.
+type Repetition int32
+
+const (
+	Required Repetition = 0
+	Optional Repetition = 1
+	Repeated Repetition = 2
+)
+
+// Encoding is the encoding of the values in a page.
+type Encoding int32
+
+const (
+	Plain                Encoding = 0
+	PlainDictionary      Encoding = 2
+	RLE                  Encoding = 3
+	BitPacked            Encoding = 4
+	DeltaBinaryPacked    Encoding = 5
+	DeltaLengthByteArray Encoding = 6
+	DeltaByteArray       Encoding = 7
+	RLEDictionary        Encoding = 8
+	ByteStreamSplit      Encoding = 9
+)
+
+// Codec is the compression codec of a column chunk.
+type Codec int32
+
+const (
+	Uncompressed Codec = 0
+	Snappy       Codec = 1
+	Gzip         Codec = 2
+)
+
+func (c Codec) String() string {
+	switch c {
+	case Uncompressed:
+		return "UNCOMPRESSED"
+	case Snappy:
+		return "SNAPPY"
+	case Gzip:
+		return "GZIP"
+	case 3:
+		return "LZO"
+	case 4:
+		return "BROTLI"
+	case 5:
+		return "LZ4"
+	case 6:
+		return "ZSTD"
+	case 7:
+		return "LZ4_RAW"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// pageType is the type of a page within a column chunk.
+type pageType int32
+
+const (
+	dataPage       pageType = 0
+	indexPage      pageType = 1
+	dictionaryPage pageType = 2
+	dataPageV2     pageType = 3
+)
+
+// SchemaElement is an element of the flattened schema tree of a file.
+type SchemaElement struct {
+	Type          Type
+	HasType       bool
+	TypeLength    int32
+	Repetition    Repetition
+	Name          string
+	NumChildren   int32
+	ConvertedType ConvertedType
+	LogicalType   *LogicalType
+}
+
+// IsUnsigned reports whether the integer values of a column are unsigned.
+func (se *SchemaElement) IsUnsigned() bool {
+	if lt := se.LogicalType; lt != nil && lt.Integer {
+		return !lt.IntegerSigned
+	}
+	switch se.ConvertedType {
+	case ConvertedUint8, ConvertedUint16, ConvertedUint32, ConvertedUint64:
+		return true
+	}
+	return false
+}
+
+// Statistics are the statistics of a column chunk.
+// The Min and Max values are plain encoded.
+type Statistics struct {
+	Max       []byte
+	Min       []byte
+	NullCount int64
+	HasNulls  bool
+	MaxValue  []byte
+	MinValue  []byte
+}
+
+// ColumnMetaData describes a column chunk.
+type ColumnMetaData struct {
+	Type                  Type
+	Encodings             []Encoding
+	PathInSchema          []string
+	Codec                 Codec
+	NumValues             int64
+	TotalUncompressedSize int64
+	TotalCompressedSize   int64
+	DataPageOffset        int64
+	DictionaryPageOffset  int64
+	HasDictionaryPage     bool
+	Statistics            *Statistics
+}
+
+// ColumnChunk is the location of a column chunk.
+type ColumnChunk struct {
+	FilePath   string
+	FileOffset int64
+	MetaData   *ColumnMetaData
+}
+
+// RowGroup is a horizontal partition of the file.
+type RowGroup struct {
+	Columns       []ColumnChunk
+	TotalByteSize int64
+	NumRows       int64
+}
+
+// KeyValue is an application defined key value pair.
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// FileMetaData is the metadata stored in the footer of a file.
+type FileMetaData struct {
+	Version          int32
+	Schema           []SchemaElement
+	NumRows          int64
+	RowGroups        []RowGroup
+	KeyValueMetadata []KeyValue
+	CreatedBy        string
+}
+
+type dataPageHeader struct {
+	NumValues               int32
+	Encoding                Encoding
+	DefinitionLevelEncoding Encoding
+	RepetitionLevelEncoding Encoding
+}
+
+type dataPageHeaderV2 struct {
+	NumValues                  int32
+	NumNulls                   int32
+	NumRows                    int32
+	Encoding                   Encoding
+	DefinitionLevelsByteLength int32
+	RepetitionLevelsByteLength int32
+	IsCompressed               bool
+}
+
+type dictionaryPageHeader struct {
+	NumValues int32
+	Encoding  Encoding
+}
+
+type pageHeader struct {
+	Type                 pageType
+	UncompressedPageSize int32
+	CompressedPageSize   int32
+	DataPageHeader       *dataPageHeader
+	DictionaryPageHeader *dictionaryPageHeader
+	DataPageHeaderV2     *dataPageHeaderV2
+}
+
+func readFileMetaData(r *thriftReader) (*FileMetaData, error) {
+	m := new(FileMetaData)
+	err := r.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftI32:
+			m.Version, err = r.readI32()
+		case id == 2 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				var se SchemaElement
+				if err := readSchemaElement(r, &se); err != nil {
+					return err
+				}
+				m.Schema = append(m.Schema, se)
+				return nil
+			})
+		case id == 3 && typ == thriftI64:
+			m.NumRows, err = r.readI64()
+		case id == 4 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				var rg RowGroup
+				if err := readRowGroup(r, &rg); err != nil {
+					return err
+				}
+				m.RowGroups = append(m.RowGroups, rg)
+				return nil
+			})
+		case id == 5 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				var kv KeyValue
+				if err := readKeyValue(r, &kv); err != nil {
+					return err
+				}
+				m.KeyValueMetadata = append(m.KeyValueMetadata, kv)
+				return nil
+			})
+		case id == 6 && typ == thriftBinary:
+			m.CreatedBy, err = r.readString()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return m, err
+}
+
+func readSchemaElement(r *thriftReader, se *SchemaElement) error {
+	se.ConvertedType = ConvertedNone
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = r.readI32()
+			se.Type, se.HasType = Type(v), true
+		case id == 2 && typ == thriftI32:
+			se.TypeLength, err = r.readI32()
+		case id == 3 && typ == thriftI32:
+			v, err = r.readI32()
+			se.Repetition = Repetition(v)
+		case id == 4 && typ == thriftBinary:
+			se.Name, err = r.readString()
+		case id == 5 && typ == thriftI32:
+			se.NumChildren, err = r.readI32()
+		case id == 6 && typ == thriftI32:
+			v, err = r.readI32()
+			se.ConvertedType = ConvertedType(v)
+		case id == 10 && typ == thriftStruct:
+			se.LogicalType = new(LogicalType)
+			err = readLogicalType(r, se.LogicalType)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+// readLogicalType reads the LogicalType union.
+func readLogicalType(r *thriftReader, lt *LogicalType) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		if typ != thriftStruct {
+			return false, nil
+		}
+		switch id {
+		case 1:
+			lt.String = true
+		case 8:
+			lt.Timestamp = true
+			return true, r.readStruct(func(id int16, typ byte) (bool, error) {
+				switch {
+				case id == 1 && (typ == thriftBooleanTrue || typ == thriftBooleanFalse):
+					lt.AdjustedToUTC = typ == thriftBooleanTrue
+					return true, nil
+				case id == 2 && typ == thriftStruct:
+					return true, r.readStruct(func(id int16, typ byte) (bool, error) {
+						if typ == thriftStruct && id >= 1 && id <= 3 {
+							lt.TimestampUnit = TimeUnit(id)
+						}
+						return false, nil
+					})
+				}
+				return false, nil
+			})
+		case 10:
+			lt.Integer = true
+			return true, r.readStruct(func(id int16, typ byte) (bool, error) {
+				switch {
+				case id == 1 && typ == thriftByte:
+					b, err := r.readByte()
+					lt.IntegerBits = int8(b)
+					return true, err
+				case id == 2 && (typ == thriftBooleanTrue || typ == thriftBooleanFalse):
+					lt.IntegerSigned = typ == thriftBooleanTrue
+					return true, nil
+				}
+				return false, nil
+			})
+		}
+		return false, nil
+	})
+}
+
+func readKeyValue(r *thriftReader, kv *KeyValue) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			kv.Key, err = r.readString()
+		case id == 2 && typ == thriftBinary:
+			kv.Value, err = r.readString()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readRowGroup(r *thriftReader, rg *RowGroup) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				var cc ColumnChunk
+				if err := readColumnChunk(r, &cc); err != nil {
+					return err
+				}
+				rg.Columns = append(rg.Columns, cc)
+				return nil
+			})
+		case id == 2 && typ == thriftI64:
+			rg.TotalByteSize, err = r.readI64()
+		case id == 3 && typ == thriftI64:
+			rg.NumRows, err = r.readI64()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readColumnChunk(r *thriftReader, cc *ColumnChunk) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			cc.FilePath, err = r.readString()
+		case id == 2 && typ == thriftI64:
+			cc.FileOffset, err = r.readI64()
+		case id == 3 && typ == thriftStruct:
+			cc.MetaData = new(ColumnMetaData)
+			err = readColumnMetaData(r, cc.MetaData)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readColumnMetaData(r *thriftReader, md *ColumnMetaData) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = r.readI32()
+			md.Type = Type(v)
+		case id == 2 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				v, err := r.readI32()
+				md.Encodings = append(md.Encodings, Encoding(v))
+				return err
+			})
+		case id == 3 && typ == thriftList:
+			err = r.readList(func(byte) error {
+				s, err := r.readString()
+				md.PathInSchema = append(md.PathInSchema, s)
+				return err
+			})
+		case id == 4 && typ == thriftI32:
+			v, err = r.readI32()
+			md.Codec = Codec(v)
+		case id == 5 && typ == thriftI64:
+			md.NumValues, err = r.readI64()
+		case id == 6 && typ == thriftI64:
+			md.TotalUncompressedSize, err = r.readI64()
+		case id == 7 && typ == thriftI64:
+			md.TotalCompressedSize, err = r.readI64()
+		case id == 9 && typ == thriftI64:
+			md.DataPageOffset, err = r.readI64()
+		case id == 11 && typ == thriftI64:
+			md.DictionaryPageOffset, err = r.readI64()
+			md.HasDictionaryPage = true
+		case id == 12 && typ == thriftStruct:
+			md.Statistics = new(Statistics)
+			err = readStatistics(r, md.Statistics)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readStatistics(r *thriftReader, s *Statistics) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var err error
+		switch {
+		case id == 1 && typ == thriftBinary:
+			s.Max, err = r.readBinary()
+		case id == 2 && typ == thriftBinary:
+			s.Min, err = r.readBinary()
+		case id == 3 && typ == thriftI64:
+			s.NullCount, err = r.readI64()
+			s.HasNulls = true
+		case id == 5 && typ == thriftBinary:
+			s.MaxValue, err = r.readBinary()
+		case id == 6 && typ == thriftBinary:
+			s.MinValue, err = r.readBinary()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readPageHeader(r *thriftReader) (*pageHeader, error) {
+	h := new(pageHeader)
+	err := r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			v, err = r.readI32()
+			h.Type = pageType(v)
+		case id == 2 && typ == thriftI32:
+			h.UncompressedPageSize, err = r.readI32()
+		case id == 3 && typ == thriftI32:
+			h.CompressedPageSize, err = r.readI32()
+		case id == 5 && typ == thriftStruct:
+			h.DataPageHeader = new(dataPageHeader)
+			err = readDataPageHeader(r, h.DataPageHeader)
+		case id == 7 && typ == thriftStruct:
+			h.DictionaryPageHeader = new(dictionaryPageHeader)
+			err = readDictionaryPageHeader(r, h.DictionaryPageHeader)
+		case id == 8 && typ == thriftStruct:
+			h.DataPageHeaderV2 = &dataPageHeaderV2{IsCompressed: true}
+			err = readDataPageHeaderV2(r, h.DataPageHeaderV2)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return h, err
+}
+
+func readDataPageHeader(r *thriftReader, h *dataPageHeader) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.NumValues, err = r.readI32()
+		case id == 2 && typ == thriftI32:
+			v, err = r.readI32()
+			h.Encoding = Encoding(v)
+		case id == 3 && typ == thriftI32:
+			v, err = r.readI32()
+			h.DefinitionLevelEncoding = Encoding(v)
+		case id == 4 && typ == thriftI32:
+			v, err = r.readI32()
+			h.RepetitionLevelEncoding = Encoding(v)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readDataPageHeaderV2(r *thriftReader, h *dataPageHeaderV2) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.NumValues, err = r.readI32()
+		case id == 2 && typ == thriftI32:
+			h.NumNulls, err = r.readI32()
+		case id == 3 && typ == thriftI32:
+			h.NumRows, err = r.readI32()
+		case id == 4 && typ == thriftI32:
+			v, err = r.readI32()
+			h.Encoding = Encoding(v)
+		case id == 5 && typ == thriftI32:
+			h.DefinitionLevelsByteLength, err = r.readI32()
+		case id == 6 && typ == thriftI32:
+			h.RepetitionLevelsByteLength, err = r.readI32()
+		case id == 7 && (typ == thriftBooleanTrue || typ == thriftBooleanFalse):
+			h.IsCompressed = typ == thriftBooleanTrue
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func readDictionaryPageHeader(r *thriftReader, h *dictionaryPageHeader) error {
+	return r.readStruct(func(id int16, typ byte) (bool, error) {
+		var (
+			v   int32
+			err error
+		)
+		switch {
+		case id == 1 && typ == thriftI32:
+			h.NumValues, err = r.readI32()
+		case id == 2 && typ == thriftI32:
+			v, err = r.readI32()
+			h.Encoding = Encoding(v)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+}
+
+func writeFileMetaData(w *thriftWriter, m *FileMetaData) {
+	w.beginStruct()
+	w.fieldI32(1, m.Version)
+	w.fieldList(2, thriftStruct, len(m.Schema), func(i int) {
+		w.writeStruct(func() { writeSchemaElement(w, &m.Schema[i]) })
+	})
+	w.fieldI64(3, m.NumRows)
+	w.fieldList(4, thriftStruct, len(m.RowGroups), func(i int) {
+		w.writeStruct(func() { writeRowGroup(w, &m.RowGroups[i]) })
+	})
+	if len(m.KeyValueMetadata) > 0 {
+		w.fieldList(5, thriftStruct, len(m.KeyValueMetadata), func(i int) {
+			w.writeStruct(func() {
+				w.fieldString(1, m.KeyValueMetadata[i].Key)
+				w.fieldString(2, m.KeyValueMetadata[i].Value)
+			})
+		})
+	}
+	if m.CreatedBy != "" {
+		w.fieldString(6, m.CreatedBy)
+	}
+	w.endStruct()
+}
+
+func writeSchemaElement(w *thriftWriter, se *SchemaElement) {
+	if se.HasType {
+		w.fieldI32(1, int32(se.Type))
+	}
+	if se.Type == FixedLenByteArray {
+		w.fieldI32(2, se.TypeLength)
+	}
+	if se.HasType {
+		w.fieldI32(3, int32(se.Repetition))
+	}
+	w.fieldString(4, se.Name)
+	if se.NumChildren > 0 {
+		w.fieldI32(5, se.NumChildren)
+	}
+	if se.ConvertedType != ConvertedNone {
+		w.fieldI32(6, int32(se.ConvertedType))
+	}
+	if lt := se.LogicalType; lt != nil {
+		w.fieldStruct(10, func() {
+			switch {
+			case lt.String:
+				w.fieldStruct(1, func() {})
+			case lt.Timestamp:
+				w.fieldStruct(8, func() {
+					w.fieldBool(1, lt.AdjustedToUTC)
+					w.fieldStruct(2, func() {
+						w.fieldStruct(int16(lt.TimestampUnit), func() {})
+					})
+				})
+			case lt.Integer:
+				w.fieldStruct(10, func() {
+					w.writeFieldHeader(1, thriftByte)
+					w.buf = append(w.buf, byte(lt.IntegerBits))
+					w.fieldBool(2, lt.IntegerSigned)
+				})
+			}
+		})
+	}
+}
+
+func writeRowGroup(w *thriftWriter, rg *RowGroup) {
+	w.fieldList(1, thriftStruct, len(rg.Columns), func(i int) {
+		w.writeStruct(func() {
+			cc := &rg.Columns[i]
+			w.fieldI64(2, cc.FileOffset)
+			w.fieldStruct(3, func() { writeColumnMetaData(w, cc.MetaData) })
+		})
+	})
+	w.fieldI64(2, rg.TotalByteSize)
+	w.fieldI64(3, rg.NumRows)
+}
+
+func writeColumnMetaData(w *thriftWriter, md *ColumnMetaData) {
+	w.fieldI32(1, int32(md.Type))
+	w.fieldList(2, thriftI32, len(md.Encodings), func(i int) {
+		w.writeVarint(int64(md.Encodings[i]))
+	})
+	w.fieldList(3, thriftBinary, len(md.PathInSchema), func(i int) {
+		w.writeBinary([]byte(md.PathInSchema[i]))
+	})
+	w.fieldI32(4, int32(md.Codec))
+	w.fieldI64(5, md.NumValues)
+	w.fieldI64(6, md.TotalUncompressedSize)
+	w.fieldI64(7, md.TotalCompressedSize)
+	w.fieldI64(9, md.DataPageOffset)
+	if md.HasDictionaryPage {
+		w.fieldI64(11, md.DictionaryPageOffset)
+	}
+	if s := md.Statistics; s != nil {
+		w.fieldStruct(12, func() {
+			if s.HasNulls {
+				w.fieldI64(3, s.NullCount)
+			}
+			if s.MaxValue != nil {
+				w.fieldBinary(5, s.MaxValue)
+			}
+			if s.MinValue != nil {
+				w.fieldBinary(6, s.MinValue)
+			}
+		})
+	}
+}
+
+func writePageHeader(w *thriftWriter, h *pageHeader) {
+	w.beginStruct()
+	w.fieldI32(1, int32(h.Type))
+	w.fieldI32(2, h.UncompressedPageSize)
+	w.fieldI32(3, h.CompressedPageSize)
+	if dh := h.DataPageHeader; dh != nil {
+		w.fieldStruct(5, func() {
+			w.fieldI32(1, dh.NumValues)
+			w.fieldI32(2, int32(dh.Encoding))
+			w.fieldI32(3, int32(dh.DefinitionLevelEncoding))
+			w.fieldI32(4, int32(dh.RepetitionLevelEncoding))
+		})
+	}
+	w.endStruct()
+}
@@ -0,0 +1,283 @@
+package parquet_test
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/internal/parquet"
+)
+
+func TestWriter_RoundTrip(t *testing.T) {
+	columns := []parquet.SchemaElement{
+		{Name: "b", Type: parquet.Boolean, Repetition: parquet.Required, ConvertedType: parquet.ConvertedNone},
+		{Name: "i", Type: parquet.Int64, Repetition: parquet.Optional, ConvertedType: parquet.ConvertedNone},
+		{Name: "u", Type: parquet.Int64, Repetition: parquet.Optional, ConvertedType: parquet.ConvertedUint64},
+		{Name: "f", Type: parquet.Double, Repetition: parquet.Optional, ConvertedType: parquet.ConvertedNone},
+		{
+			Name:          "s",
+			Type:          parquet.ByteArray,
+			Repetition:    parquet.Optional,
+			ConvertedType: parquet.ConvertedUTF8,
+			LogicalType:   &parquet.LogicalType{String: true},
+		},
+		{
+			Name:          "t",
+			Type:          parquet.Int64,
+			Repetition:    parquet.Required,
+			ConvertedType: parquet.ConvertedNone,
+			LogicalType:   &parquet.LogicalType{Timestamp: true, TimestampUnit: parquet.Nanos, AdjustedToUTC: true},
+		},
+	}
+	rowGroups := [][]*parquet.ColumnValues{
+		{
+			{Bools: []bool{true, false, true}},
+			{Defined: []bool{true, false, true}, Ints: []int64{-4, 7}},
+			{Defined: []bool{true, true, true}, Ints: []int64{1, -1, 2}},
+			{Defined: []bool{true, true, false}, Floats: []float64{1.5, math.NaN()}},
+			{Defined: []bool{false, true, true}, Strings: []string{"b", "a"}},
+			{Ints: []int64{10, 20, 30}},
+		},
+		{
+			{Bools: []bool{false}},
+			{Defined: []bool{false}},
+			{Defined: []bool{false}},
+			{Defined: []bool{false}},
+			{Defined: []bool{true}, Strings: []string{""}},
+			{Ints: []int64{40}},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rg := range rowGroups {
+		if err := w.WriteRowGroup(rg, len(rg[0].Bools)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := parquet.Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.Metadata.NumRows, int64(4); got != want {
+		t.Fatalf("unexpected number of rows: want %d, got %d", want, got)
+	}
+	if got, want := len(f.Metadata.RowGroups), len(rowGroups); got != want {
+		t.Fatalf("unexpected number of row groups: want %d, got %d", want, got)
+	}
+	for j, want := range columns {
+		col := f.Columns[j]
+		if col.Name != want.Name || col.Type != want.Type || col.Repetition != want.Repetition ||
+			col.ConvertedType != want.ConvertedType || !cmp.Equal(col.LogicalType, want.LogicalType) {
+			t.Errorf("unexpected column %d: %+v", j, col)
+		}
+	}
+
+	opts := cmp.Options{
+		cmp.Comparer(func(x, y float64) bool {
+			return x == y || math.IsNaN(x) && math.IsNaN(y)
+		}),
+	}
+	for i, rg := range rowGroups {
+		for j, want := range rg {
+			got, err := f.ReadColumn(i, &f.Columns[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(want, got, opts) {
+				t.Errorf("unexpected values in row group %d, column %q -want/+got:\n%s", i, columns[j].Name, cmp.Diff(want, got, opts))
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		column   int
+		min, max *parquet.ColumnValues
+	}{
+		{column: 1, min: &parquet.ColumnValues{Ints: []int64{-4}}, max: &parquet.ColumnValues{Ints: []int64{7}}},
+		{column: 2, min: &parquet.ColumnValues{Ints: []int64{1}}, max: &parquet.ColumnValues{Ints: []int64{-1}}},
+		{column: 3, min: &parquet.ColumnValues{Floats: []float64{1.5}}, max: &parquet.ColumnValues{Floats: []float64{1.5}}},
+		{column: 4, min: &parquet.ColumnValues{Strings: []string{"a"}}, max: &parquet.ColumnValues{Strings: []string{"b"}}},
+	} {
+		col := &f.Columns[tc.column]
+		stats := f.Metadata.RowGroups[0].Columns[col.Index].MetaData.Statistics
+		if stats == nil {
+			t.Fatalf("column %q has no statistics", col.Name)
+		}
+		min, err := parquet.DecodeStatistic(col, stats.MinValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		max, err := parquet.DecodeStatistic(col, stats.MaxValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.min, min) || !cmp.Equal(tc.max, max) {
+			t.Errorf("unexpected statistics for column %q: min %+v, max %+v", col.Name, min, max)
+		}
+	}
+}
+
+func TestOpen_Invalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"PAR1PAR1",
+		"PAR1\x00\x00\x00\x00\x00\x00\x00\x00PAR1",
+		"PAR1\xff\xff\xff\x7fPAR1abcd",
+		"PAR1\x15\x02\x00\x05\x00\x00\x00PAR1",
+	} {
+		if _, err := parquet.Open(bytes.NewReader([]byte(data)), int64(len(data))); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+// TestOpen_Golden reads files that were not written by this package.
+// See testdata/golden.py for how they are encoded.
+func TestOpen_Golden(t *testing.T) {
+	repeatBool := func(v bool, n int) []bool {
+		vs := make([]bool, n)
+		for i := range vs {
+			vs[i] = v
+		}
+		return vs
+	}
+	for _, tc := range []struct {
+		file    string
+		columns []string
+		// want holds the values of each column within each row group.
+		want [][]*parquet.ColumnValues
+	}{
+		{
+			file:    "golden.parquet",
+			columns: []string{"id", "region", "value", "count", "ok"},
+			want: [][]*parquet.ColumnValues{
+				{
+					{Ints: []int64{1, 2, 3, 4}},
+					{Defined: []bool{true, false, true, true}, Strings: []string{"east", "west", "east"}},
+					{Defined: []bool{true, true, false, true}, Floats: []float64{1.5, 2.5, 1.5}},
+					{Defined: []bool{false, false, false, false}},
+					{Defined: []bool{true, true, false, true}, Bools: []bool{true, false, true}},
+				},
+				{
+					{Ints: []int64{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}},
+					{
+						Defined: append(repeatBool(true, 10), false, true),
+						Strings: []string{"north", "north", "north", "north", "north", "north", "north", "north", "north", "north", "south"},
+					},
+					{
+						Defined: append(repeatBool(true, 9), false, true, true),
+						Floats:  []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, -1, 0.5},
+					},
+					{
+						Defined: append(repeatBool(true, 9), false, false, true),
+						Ints:    []int64{7, 8, 7, 8, 7, 8, 7, 8, 9, 7},
+					},
+					{Defined: append(repeatBool(true, 10), false, true), Bools: append(repeatBool(true, 10), false)},
+				},
+				{
+					{Ints: []int64{17, 18}},
+					{Defined: []bool{false, false}},
+					{Defined: []bool{true, true}, Floats: []float64{3, 3}},
+					{Defined: []bool{true, false}, Ints: []int64{1}},
+					{Defined: []bool{true, true}, Bools: []bool{false, false}},
+				},
+			},
+		},
+		{
+			file:    "golden_v2.parquet",
+			columns: []string{"name", "n"},
+			want: [][]*parquet.ColumnValues{
+				{
+					{Defined: []bool{true, true, false, true, true}, Strings: []string{"a", "b", "a", "c"}},
+					{Defined: []bool{true, false, true, false, true}, Ints: []int64{10, 30, 50}},
+				},
+				{
+					{Defined: []bool{false, true, true}, Strings: []string{"c", "c"}},
+					{Defined: []bool{true, false, true}, Ints: []int64{-1, -3}},
+				},
+			},
+		},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := parquet.Open(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(f.Metadata.RowGroups), len(tc.want); got != want {
+				t.Fatalf("unexpected number of row groups: want %d, got %d", want, got)
+			}
+			var numRows int64
+			for i, rg := range tc.want {
+				numRows += f.Metadata.RowGroups[i].NumRows
+				for j, want := range rg {
+					col, ok := f.Column(tc.columns[j])
+					if !ok {
+						t.Fatalf("column %q not found", tc.columns[j])
+					}
+					got, err := f.ReadColumn(i, col)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !cmp.Equal(want, got) {
+						t.Errorf("unexpected values in row group %d, column %q -want/+got:\n%s", i, col.Name, cmp.Diff(want, got))
+					}
+				}
+			}
+			if numRows != f.Metadata.NumRows {
+				t.Errorf("row groups have %d rows, metadata has %d", numRows, f.Metadata.NumRows)
+			}
+		})
+	}
+
+	// The statistics of the second row group.
+	data, err := os.ReadFile(filepath.Join("testdata", "golden.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parquet.Open(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		column   string
+		min, max *parquet.ColumnValues
+		nulls    int64
+	}{
+		{column: "region", min: &parquet.ColumnValues{Strings: []string{"north"}}, max: &parquet.ColumnValues{Strings: []string{"south"}}, nulls: 1},
+		{column: "value", min: &parquet.ColumnValues{Floats: []float64{-1}}, max: &parquet.ColumnValues{Floats: []float64{0.5}}, nulls: 1},
+		{column: "count", min: &parquet.ColumnValues{Ints: []int64{7}}, max: &parquet.ColumnValues{Ints: []int64{9}}, nulls: 2},
+		{column: "ok", min: &parquet.ColumnValues{Bools: []bool{false}}, max: &parquet.ColumnValues{Bools: []bool{true}}, nulls: 1},
+	} {
+		col, _ := f.Column(tc.column)
+		stats := f.Metadata.RowGroups[1].Columns[col.Index].MetaData.Statistics
+		if stats == nil {
+			t.Fatalf("column %q has no statistics", col.Name)
+		}
+		min, err := parquet.DecodeStatistic(col, stats.MinValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		max, err := parquet.DecodeStatistic(col, stats.MaxValue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.min, min) || !cmp.Equal(tc.max, max) || stats.NullCount != tc.nulls {
+			t.Errorf("unexpected statistics for column %q: min %+v, max %+v, nulls %d", col.Name, min, max, stats.NullCount)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// Package parquet implements reading and writing of files in the
+// Apache Parquet format.
+//
+// Only flat schemas are supported. Nested and repeated fields of a file
+// are not listed in its columns and cannot be read. Pages may use the plain,
+// dictionary and RLE encodings and may be compressed with snappy or gzip.
+// Files are written with plain encoded, uncompressed pages.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+const magic = "PAR1"
+
+// Column is a top level primitive column of a file.
+type Column struct {
+	// Index is the position of the column chunk within each row group.
+	Index int
+	SchemaElement
+}
+
+// ColumnValues holds the decoded values of a column chunk.
+// Only the slice that matches the physical type of the column is used.
+// Ints holds INT32, INT64 and INT96 values where INT96 values are
+// converted to nanoseconds since the unix epoch. Floats holds FLOAT
+// and DOUBLE values and Strings holds byte array values.
+//
+// Null values are not stored. If Defined is not nil,
+// it reports which rows have a value.
+type ColumnValues struct {
+	Defined []bool
+	Bools   []bool
+	Ints    []int64
+	Floats  []float64
+	Strings []string
+}
+
+// Len returns the number of values, excluding nulls.
+func (vs *ColumnValues) Len() int {
+	return len(vs.Bools) + len(vs.Ints) + len(vs.Floats) + len(vs.Strings)
+}
+
+// File is a parquet file opened for reading.
+type File struct {
+	r        io.ReaderAt
+	Metadata *FileMetaData
+	Columns  []Column
+}
+
+// Open reads the metadata from the footer of the file.
+func Open(r io.ReaderAt, size int64) (*File, error) {
+	if size < int64(2*len(magic)+4) {
+		return nil, errors.New(codes.Invalid, "file is too small to be a parquet file")
+	}
+	footer := make([]byte, 4+len(magic))
+	if _, err := r.ReadAt(footer, size-int64(len(footer))); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read parquet footer")
+	}
+	if string(footer[4:]) != magic {
+		return nil, errors.New(codes.Invalid, "not a parquet file")
+	}
+	n := int64(binary.LittleEndian.Uint32(footer))
+	if n <= 0 || n > size-int64(len(footer)+len(magic)) {
+		return nil, errors.New(codes.Invalid, "invalid parquet metadata length")
+	}
+	buf := make([]byte, n)
+	if _, err := r.ReadAt(buf, size-int64(len(footer))-n); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read parquet metadata")
+	}
+	md, err := readFileMetaData(&thriftReader{buf: buf})
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "failed to decode parquet metadata")
+	}
+	if len(md.Schema) == 0 {
+		return nil, errors.New(codes.Invalid, "parquet file has no schema")
+	}
+	f := &File{r: r, Metadata: md}
+	if err := f.readColumns(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// readColumns walks the flattened schema tree and
+// collects the top level primitive columns.
+func (f *File) readColumns() error {
+	schema := f.Metadata.Schema
+	leaf, pos := 0, 1
+	for i := 0; i < int(schema[0].NumChildren); i++ {
+		if pos >= len(schema) {
+			return errors.New(codes.Invalid, "invalid parquet schema")
+		}
+		se := schema[pos]
+		if se.NumChildren == 0 {
+			if se.Repetition != Repeated {
+				f.Columns = append(f.Columns, Column{Index: leaf, SchemaElement: se})
+			}
+			leaf++
+			pos++
+			continue
+		}
+		// Skip over the nested group and count its leaves.
+		remaining := 1
+		for remaining > 0 {
+			if pos >= len(schema) {
+				return errors.New(codes.Invalid, "invalid parquet schema")
+			}
+			remaining += int(schema[pos].NumChildren) - 1
+			if schema[pos].NumChildren == 0 {
+				leaf++
+			}
+			pos++
+		}
+	}
+	for _, rg := range f.Metadata.RowGroups {
+		if len(rg.Columns) != leaf {
+			return errors.New(codes.Invalid, "parquet row group does not match the schema")
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name.
+func (f *File) Column(name string) (*Column, bool) {
+	for i := range f.Columns {
+		if f.Columns[i].Name == name {
+			return &f.Columns[i], true
+		}
+	}
+	return nil, false
+}
+
+// ReadColumn reads and decodes the values of a column within a row group.
+func (f *File) ReadColumn(rowGroup int, col *Column) (*ColumnValues, error) {
+	rg := f.Metadata.RowGroups[rowGroup]
+	cc := rg.Columns[col.Index]
+	md := cc.MetaData
+	if cc.FilePath != "" {
+		return nil, errors.New(codes.Unimplemented, "parquet column chunks in external files are not supported")
+	}
+	if md == nil {
+		return nil, errors.New(codes.Invalid, "parquet column chunk has no metadata")
+	}
+
+	offset := md.DataPageOffset
+	if md.HasDictionaryPage && md.DictionaryPageOffset > 0 && md.DictionaryPageOffset < offset {
+		offset = md.DictionaryPageOffset
+	}
+	if md.TotalCompressedSize < 0 || md.TotalCompressedSize > 1<<31 {
+		return nil, errors.New(codes.Invalid, "invalid parquet column chunk size")
+	}
+	data := make([]byte, md.TotalCompressedSize)
+	if _, err := f.r.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read parquet column chunk")
+	}
+
+	cr := &chunkReader{
+		col:   col,
+		codec: md.Codec,
+		r:     &thriftReader{buf: data},
+		vs:    new(ColumnValues),
+	}
+	if col.Repetition == Optional {
+		cr.vs.Defined = []bool{}
+	}
+	for cr.read < md.NumValues {
+		if err := cr.readPage(); err != nil {
+			return nil, errors.Wrapf(err, codes.Inherit, "failed to read parquet column %q", col.Name)
+		}
+	}
+	return cr.vs, nil
+}
+
+// chunkReader decodes the pages of a column chunk.
+type chunkReader struct {
+	col   *Column
+	codec Codec
+	r     *thriftReader
+	dict  *ColumnValues
+	vs    *ColumnValues
+	read  int64
+}
+
+func (cr *chunkReader) readPage() error {
+	h, err := readPageHeader(cr.r)
+	if err != nil {
+		return err
+	}
+	size := int(h.CompressedPageSize)
+	if size < 0 || size > len(cr.r.buf)-cr.r.pos {
+		return errCorruptPage
+	}
+	data := cr.r.buf[cr.r.pos : cr.r.pos+size]
+	cr.r.pos += size
+
+	switch h.Type {
+	case dictionaryPage:
+		if h.DictionaryPageHeader == nil {
+			return errCorruptPage
+		}
+		if data, err = decompress(cr.codec, data); err != nil {
+			return err
+		}
+		dict := new(ColumnValues)
+		if err := decodePlain(dict, cr.col.Type, int(cr.col.TypeLength), data, int(h.DictionaryPageHeader.NumValues)); err != nil {
+			return err
+		}
+		cr.dict = dict
+		return nil
+	case dataPage:
+		dh := h.DataPageHeader
+		if dh == nil {
+			return errCorruptPage
+		}
+		if data, err = decompress(cr.codec, data); err != nil {
+			return err
+		}
+		n := int(dh.NumValues)
+		defined := n
+		if cr.col.Repetition == Optional {
+			levels, rest, err := decodeLevels(data, dh.DefinitionLevelEncoding, 1, n)
+			if err != nil {
+				return err
+			}
+			data = rest
+			defined = cr.appendDefined(levels)
+		}
+		cr.read += int64(n)
+		return cr.decodeValues(dh.Encoding, data, defined)
+	case dataPageV2:
+		dh := h.DataPageHeaderV2
+		if dh == nil {
+			return errCorruptPage
+		}
+		rlen, dlen := int(dh.RepetitionLevelsByteLength), int(dh.DefinitionLevelsByteLength)
+		if rlen < 0 || dlen < 0 || rlen+dlen > len(data) {
+			return errCorruptPage
+		}
+		levelData := data[rlen : rlen+dlen]
+		data = data[rlen+dlen:]
+		if dh.IsCompressed {
+			if data, err = decompress(cr.codec, data); err != nil {
+				return err
+			}
+		}
+		n := int(dh.NumValues)
+		defined := n
+		if cr.col.Repetition == Optional {
+			levels, err := decodeHybrid(levelData, 1, n)
+			if err != nil {
+				return err
+			}
+			defined = cr.appendDefined(levels)
+		}
+		cr.read += int64(n)
+		return cr.decodeValues(dh.Encoding, data, defined)
+	default:
+		// Index pages and unknown page types do not contain values.
+		return nil
+	}
+}
+
+// appendDefined records which values of the page are defined
+// and returns the number of defined values.
+func (cr *chunkReader) appendDefined(levels []int32) int {
+	n := 0
+	for _, l := range levels {
+		cr.vs.Defined = append(cr.vs.Defined, l == 1)
+		if l == 1 {
+			n++
+		}
+	}
+	return n
+}
+
+func (cr *chunkReader) decodeValues(enc Encoding, data []byte, n int) error {
+	switch enc {
+	case Plain:
+		return decodePlain(cr.vs, cr.col.Type, int(cr.col.TypeLength), data, n)
+	case PlainDictionary, RLEDictionary:
+		if cr.dict == nil {
+			return errors.New(codes.Invalid, "parquet dictionary page is missing")
+		}
+		if n == 0 {
+			return nil
+		}
+		if len(data) == 0 {
+			return errCorruptPage
+		}
+		indices, err := decodeHybrid(data[1:], int(data[0]), n)
+		if err != nil {
+			return err
+		}
+		return cr.appendFromDictionary(indices)
+	case RLE:
+		if cr.col.Type != Boolean || len(data) < 4 {
+			return errCorruptPage
+		}
+		size := int(binary.LittleEndian.Uint32(data))
+		if size < 0 || size > len(data)-4 {
+			return errCorruptPage
+		}
+		values, err := decodeHybrid(data[4:4+size], 1, n)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			cr.vs.Bools = append(cr.vs.Bools, v == 1)
+		}
+		return nil
+	default:
+		return errors.Newf(codes.Unimplemented, "unsupported parquet encoding %d", enc)
+	}
+}
+
+func (cr *chunkReader) appendFromDictionary(indices []int32) error {
+	dict, vs := cr.dict, cr.vs
+	size := dict.Len()
+	for _, i := range indices {
+		if i < 0 || int(i) >= size {
+			return errors.New(codes.Invalid, "parquet dictionary index out of range")
+		}
+		switch cr.col.Type {
+		case Boolean:
+			vs.Bools = append(vs.Bools, dict.Bools[i])
+		case Int32, Int64, Int96:
+			vs.Ints = append(vs.Ints, dict.Ints[i])
+		case Float, Double:
+			vs.Floats = append(vs.Floats, dict.Floats[i])
+		default:
+			vs.Strings = append(vs.Strings, dict.Strings[i])
+		}
+	}
+	return nil
+}
+
+// DecodeStatistic decodes a plain encoded minimum or maximum value of a column.
+// The returned ColumnValues holds a single value.
+func DecodeStatistic(col *Column, b []byte) (*ColumnValues, error) {
+	vs := new(ColumnValues)
+	switch col.Type {
+	case ByteArray, FixedLenByteArray:
+		// Statistics of byte arrays are not length prefixed.
+		vs.Strings = []string{string(b)}
+		return vs, nil
+	case Boolean:
+		if len(b) != 1 {
+			return nil, errCorruptPage
+		}
+	}
+	if err := decodePlain(vs, col.Type, int(col.TypeLength), b, 1); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
+// ReadFile opens a parquet file from a reader that does not support
+// random access by reading all of its contents into memory.
+func ReadFile(r io.Reader) (*File, error) {
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read parquet file")
+	}
+	return Open(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
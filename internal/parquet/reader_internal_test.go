@@ -0,0 +1,304 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeHybrid(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		data  []byte
+		width int
+		n     int
+		want  []int32
+	}{
+		{
+			// The bit packed example from the parquet specification.
+			name:  "bit packed",
+			data:  []byte{0x03, 0x88, 0xc6, 0xfa},
+			width: 3,
+			n:     8,
+			want:  []int32{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:  "rle",
+			data:  []byte{0x06, 0x01, 0x04, 0x00},
+			width: 1,
+			n:     5,
+			want:  []int32{1, 1, 1, 0, 0},
+		},
+		{
+			name:  "truncated bit packed",
+			data:  []byte{0x03, 0x88},
+			width: 3,
+			n:     2,
+			want:  []int32{0, 1},
+		},
+		{
+			name:  "wide rle",
+			data:  []byte{0x04, 0x34, 0x12},
+			width: 13,
+			n:     2,
+			want:  []int32{0x1234, 0x1234},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := decodeHybrid(tc.data, tc.width, tc.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected values -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+
+	levels := []int32{1, 1, 0, 1, 0, 0, 0}
+	got, err := decodeHybrid(encodeHybrid(nil, levels, 1), 1, len(levels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(levels, got) {
+		t.Errorf("unexpected round trip -want/+got:\n%s", cmp.Diff(levels, got))
+	}
+
+	if _, err := decodeHybrid([]byte{0x03}, 3, 8); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+}
+
+func TestDecompress_Snappy(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "literal and copy",
+			// A literal "abc" followed by a copy with
+			// a one byte offset of 3 and a length of 6.
+			data: []byte{0x09, 0x08, 'a', 'b', 'c', 0x09, 0x03},
+			want: "abcabcabc",
+		},
+		{
+			name: "encoded",
+			data: snappy.Encode(nil, []byte("abcabcabcabcabcabc")),
+			want: "abcabcabcabcabcabc",
+		},
+		{
+			name:    "offset out of range",
+			data:    []byte{0x06, 0x04, 'x', 'y', 0x0e, 0x03, 0x00},
+			wantErr: true,
+		},
+		{
+			name:    "length mismatch",
+			data:    []byte{0x04, 0x04, 'x', 'y'},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := decompress(Snappy, tc.data)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("unexpected data: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestReadColumn_Encodings reads pages written the way other
+// writers commonly write them: with a dictionary, with version 2
+// data pages and with compression.
+func TestReadColumn_Encodings(t *testing.T) {
+	var file bytes.Buffer
+	file.WriteString(magic)
+
+	writePage := func(h *pageHeader, page []byte) {
+		h.CompressedPageSize = int32(len(page))
+		var w thriftWriter
+		if h.DataPageHeader != nil {
+			writePageHeader(&w, h)
+		} else {
+			// The writer only writes version 1 data pages
+			// so the other headers are encoded here.
+			w.beginStruct()
+			w.fieldI32(1, int32(h.Type))
+			w.fieldI32(2, h.UncompressedPageSize)
+			w.fieldI32(3, h.CompressedPageSize)
+			if dh := h.DictionaryPageHeader; dh != nil {
+				w.fieldStruct(7, func() {
+					w.fieldI32(1, dh.NumValues)
+					w.fieldI32(2, int32(dh.Encoding))
+				})
+			}
+			if dh := h.DataPageHeaderV2; dh != nil {
+				w.fieldStruct(8, func() {
+					w.fieldI32(1, dh.NumValues)
+					w.fieldI32(2, dh.NumNulls)
+					w.fieldI32(3, dh.NumRows)
+					w.fieldI32(4, int32(dh.Encoding))
+					w.fieldI32(5, dh.DefinitionLevelsByteLength)
+					w.fieldI32(6, dh.RepetitionLevelsByteLength)
+					w.fieldBool(7, dh.IsCompressed)
+				})
+			}
+			w.endStruct()
+		}
+		file.Write(w.buf)
+		file.Write(page)
+	}
+
+	// The first column is a dictionary encoded, snappy compressed string column
+	// with a version 1 data page.
+	dictOffset := int64(file.Len())
+	dict := encodePlain(nil, ByteArray, &ColumnValues{Strings: []string{"east", "west"}})
+	writePage(&pageHeader{
+		Type:                 dictionaryPage,
+		UncompressedPageSize: int32(len(dict)),
+		DictionaryPageHeader: &dictionaryPageHeader{NumValues: 2, Encoding: PlainDictionary},
+	}, snappy.Encode(nil, dict))
+
+	dataOffset := int64(file.Len())
+	levels := encodeHybrid(nil, []int32{1, 0, 1, 1}, 1)
+	var page []byte
+	page = append(page, byte(len(levels)), 0, 0, 0)
+	page = append(page, levels...)
+	// The indices 1, 0, 1 bit packed with a width of one.
+	page = append(page, 0x01, 0x03, 0x05)
+	writePage(&pageHeader{
+		Type:                 dataPage,
+		UncompressedPageSize: int32(len(page)),
+		DataPageHeader: &dataPageHeader{
+			NumValues:               4,
+			Encoding:                RLEDictionary,
+			DefinitionLevelEncoding: RLE,
+			RepetitionLevelEncoding: RLE,
+		},
+	}, snappy.Encode(nil, page))
+	stringsEnd := int64(file.Len())
+
+	// The second column is a boolean column with a gzip compressed version 2
+	// data page. The definition levels are never compressed.
+	boolsOffset := int64(file.Len())
+	levels = encodeHybrid(nil, []int32{1, 1, 0, 1}, 1)
+	values := encodeHybrid(nil, []int32{1, 0, 0}, 1)
+	var data []byte
+	data = append(data, byte(len(values)), 0, 0, 0)
+	data = append(data, values...)
+	writePage(&pageHeader{
+		Type:                 dataPageV2,
+		UncompressedPageSize: int32(len(levels) + len(data)),
+		DataPageHeaderV2: &dataPageHeaderV2{
+			NumValues:                  4,
+			NumNulls:                   1,
+			NumRows:                    4,
+			Encoding:                   RLE,
+			DefinitionLevelsByteLength: int32(len(levels)),
+			IsCompressed:               true,
+		},
+	}, append(levels, gzipData(t, data)...))
+	boolsEnd := int64(file.Len())
+
+	meta := &FileMetaData{
+		Version: 1,
+		Schema: []SchemaElement{
+			{Name: "schema", NumChildren: 2, ConvertedType: ConvertedNone},
+			{Name: "region", Type: ByteArray, HasType: true, Repetition: Optional, ConvertedType: ConvertedUTF8},
+			{Name: "ok", Type: Boolean, HasType: true, Repetition: Optional, ConvertedType: ConvertedNone},
+		},
+		NumRows: 4,
+		RowGroups: []RowGroup{{
+			NumRows: 4,
+			Columns: []ColumnChunk{
+				{
+					FileOffset: dictOffset,
+					MetaData: &ColumnMetaData{
+						Type:                 ByteArray,
+						PathInSchema:         []string{"region"},
+						Codec:                Snappy,
+						NumValues:            4,
+						TotalCompressedSize:  stringsEnd - dictOffset,
+						DataPageOffset:       dataOffset,
+						DictionaryPageOffset: dictOffset,
+						HasDictionaryPage:    true,
+					},
+				},
+				{
+					FileOffset: boolsOffset,
+					MetaData: &ColumnMetaData{
+						Type:                Boolean,
+						PathInSchema:        []string{"ok"},
+						Codec:               Gzip,
+						NumValues:           4,
+						TotalCompressedSize: boolsEnd - boolsOffset,
+						DataPageOffset:      boolsOffset,
+					},
+				},
+			},
+		}},
+	}
+	var w thriftWriter
+	writeFileMetaData(&w, meta)
+	file.Write(w.buf)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(w.buf)))
+	file.Write(size[:])
+	file.WriteString(magic)
+
+	f, err := Open(bytes.NewReader(file.Bytes()), int64(file.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		column string
+		want   *ColumnValues
+	}{
+		{
+			column: "region",
+			want:   &ColumnValues{Defined: []bool{true, false, true, true}, Strings: []string{"west", "east", "west"}},
+		},
+		{
+			column: "ok",
+			want:   &ColumnValues{Defined: []bool{true, true, false, true}, Bools: []bool{true, false, false}},
+		},
+	} {
+		col, ok := f.Column(tc.column)
+		if !ok {
+			t.Fatalf("column %q not found", tc.column)
+		}
+		got, err := f.ReadColumn(0, col)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected values for %q -want/+got:\n%s", tc.column, cmp.Diff(tc.want, got))
+		}
+	}
+}
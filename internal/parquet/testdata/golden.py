@@ -0,0 +1,396 @@
+#!/usr/bin/env python3
+"""Writes the golden parquet files read by TestOpen_Golden.
+
+The files are encoded by this script alone, using only the python
+standard library, so that the reader is tested against an encoder that
+shares no code with the package. The pages are laid out the way
+parquet-cpp (pyarrow) writes them: a plain encoded dictionary page
+followed by RLE_DICTIONARY data pages, RLE encoded definition levels,
+snappy or gzip compression, and several row groups and pages per chunk.
+
+Files written by pyarrow or parquet-mr can be added next to these
+together with their expected values.
+
+Run it from this directory to regenerate the files:
+
+    python3 golden.py
+"""
+
+import struct
+import zlib
+
+# Thrift compact protocol types.
+T_TRUE, T_FALSE, T_I32, T_I64, T_BINARY, T_LIST, T_STRUCT = 1, 2, 5, 6, 8, 9, 12
+
+# Parquet enums.
+BOOLEAN, INT32, INT64, DOUBLE, BYTE_ARRAY = 0, 1, 2, 5, 6
+REQUIRED, OPTIONAL = 0, 1
+PLAIN, RLE, RLE_DICTIONARY = 0, 3, 8
+UNCOMPRESSED, SNAPPY, GZIP = 0, 1, 2
+DATA_PAGE, DICTIONARY_PAGE, DATA_PAGE_V2 = 0, 2, 3
+UTF8 = 0
+
+
+def uvarint(v):
+    out = bytearray()
+    while True:
+        b = v & 0x7F
+        v >>= 7
+        if v:
+            out.append(b | 0x80)
+        else:
+            out.append(b)
+            return bytes(out)
+
+
+def zigzag(v):
+    return (v << 1) ^ (v >> 63)
+
+
+class Struct:
+    """Encodes a struct with the thrift compact protocol."""
+
+    def __init__(self):
+        self.buf = bytearray()
+        self.last = 0
+
+    def header(self, fid, typ):
+        delta = fid - self.last
+        if 0 < delta <= 15:
+            self.buf.append(delta << 4 | typ)
+        else:
+            self.buf.append(typ)
+            self.buf += uvarint(zigzag(fid))
+        self.last = fid
+        return self
+
+    def i32(self, fid, v):
+        self.header(fid, T_I32).buf += uvarint(zigzag(v))
+        return self
+
+    def i64(self, fid, v):
+        self.header(fid, T_I64).buf += uvarint(zigzag(v))
+        return self
+
+    def bool(self, fid, v):
+        return self.header(fid, T_TRUE if v else T_FALSE)
+
+    def binary(self, fid, v):
+        if isinstance(v, str):
+            v = v.encode()
+        self.header(fid, T_BINARY).buf += uvarint(len(v)) + v
+        return self
+
+    def struct(self, fid, s):
+        self.header(fid, T_STRUCT).buf += s.end()
+        return self
+
+    def list(self, fid, typ, items):
+        self.header(fid, T_LIST)
+        if len(items) < 15:
+            self.buf.append(len(items) << 4 | typ)
+        else:
+            self.buf.append(0xF0 | typ)
+            self.buf += uvarint(len(items))
+        for item in items:
+            if typ == T_STRUCT:
+                self.buf += item.end()
+            elif typ == T_I32:
+                self.buf += uvarint(zigzag(item))
+            elif typ == T_BINARY:
+                item = item.encode()
+                self.buf += uvarint(len(item)) + item
+        return self
+
+    def end(self):
+        return bytes(self.buf) + b"\x00"
+
+
+def hybrid(values, width):
+    """Encodes values with the RLE/bit-packing hybrid encoding.
+
+    Runs of eight or more equal values are run length encoded and
+    the other values are bit packed in groups of eight.
+    """
+    out = bytearray()
+    byte_width = (width + 7) // 8
+    pending = []
+
+    def flush():
+        if not pending:
+            return
+        groups = (len(pending) + 7) // 8
+        padded = pending + [0] * (groups * 8 - len(pending))
+        out.extend(uvarint(groups << 1 | 1))
+        bits = 0
+        for i, v in enumerate(padded):
+            bits |= v << (i * width)
+        out.extend(bits.to_bytes(groups * width, "little"))
+        pending.clear()
+
+    i = 0
+    while i < len(values):
+        j = i
+        while j < len(values) and values[j] == values[i]:
+            j += 1
+        # Only start a run at the boundary of a bit packed group.
+        if j - i >= 8 and len(pending) % 8 == 0:
+            flush()
+            out.extend(uvarint((j - i) << 1))
+            out.extend(values[i].to_bytes(byte_width, "little"))
+            i = j
+        else:
+            pending.append(values[i])
+            i += 1
+    flush()
+    return bytes(out)
+
+
+def plain(typ, values):
+    if typ == BOOLEAN:
+        bits = 0
+        for i, v in enumerate(values):
+            bits |= int(v) << i
+        return bits.to_bytes((len(values) + 7) // 8, "little")
+    if typ == INT32:
+        return b"".join(struct.pack("<i", v) for v in values)
+    if typ == INT64:
+        return b"".join(struct.pack("<q", v) for v in values)
+    if typ == DOUBLE:
+        return b"".join(struct.pack("<d", v) for v in values)
+    out = bytearray()
+    for v in values:
+        v = v.encode()
+        out += struct.pack("<I", len(v)) + v
+    return bytes(out)
+
+
+def snappy(data):
+    """Compresses data into a snappy block that only holds literals."""
+    out = bytearray(uvarint(len(data)))
+    for i in range(0, len(data), 256):
+        chunk = data[i:i + 256]
+        if len(chunk) <= 60:
+            out.append((len(chunk) - 1) << 2)
+        else:
+            out.append(60 << 2)
+            out.append(len(chunk) - 1)
+        out += chunk
+    return bytes(out)
+
+
+def compress(codec, data):
+    if codec == SNAPPY:
+        return snappy(data)
+    if codec == GZIP:
+        c = zlib.compressobj(9, zlib.DEFLATED, 31)
+        return c.compress(data) + c.flush()
+    return data
+
+
+class Column:
+    def __init__(self, name, typ, repetition, dictionary=False, utf8=False):
+        self.name = name
+        self.typ = typ
+        self.repetition = repetition
+        self.dictionary = dictionary
+        self.utf8 = utf8
+
+    def schema(self):
+        s = Struct().i32(1, self.typ).i32(3, self.repetition).binary(4, self.name)
+        if self.utf8:
+            s.i32(6, UTF8).struct(10, Struct().struct(1, Struct()))
+        return s
+
+
+class File:
+    def __init__(self, columns, codec, page_version=1):
+        self.columns = columns
+        self.codec = codec
+        self.page_version = page_version
+        self.buf = bytearray(b"PAR1")
+        self.row_groups = []
+        self.num_rows = 0
+
+    def page(self, header, data):
+        self.buf += header.end() + data
+
+    def chunk(self, col, pages):
+        """Writes a column chunk. pages is a list of lists of values
+        where None is a null."""
+        start = len(self.buf)
+        values = [v for p in pages for v in p if v is not None]
+        dict_offset = None
+        encodings = [PLAIN, RLE]
+        dictionary = []
+        if col.dictionary:
+            for v in values:
+                if v not in dictionary:
+                    dictionary.append(v)
+            dict_offset = start
+            data = plain(col.typ, dictionary)
+            self.page(
+                Struct()
+                .i32(1, DICTIONARY_PAGE)
+                .i32(2, len(data))
+                .i32(3, len(compress(self.codec, data)))
+                .struct(7, Struct().i32(1, len(dictionary)).i32(2, PLAIN)),
+                compress(self.codec, data),
+            )
+            encodings = [PLAIN, RLE, RLE_DICTIONARY]
+        data_offset = len(self.buf)
+        for p in pages:
+            defined = [v for v in p if v is not None]
+            if col.dictionary:
+                width = max(len(dictionary) - 1, 0).bit_length()
+                encoding = RLE_DICTIONARY
+                data = bytes([width]) + hybrid([dictionary.index(v) for v in defined], width)
+            else:
+                encoding = PLAIN
+                data = plain(col.typ, defined)
+            levels = b""
+            if col.repetition == OPTIONAL:
+                levels = hybrid([int(v is not None) for v in p], 1)
+            if self.page_version == 1:
+                if levels:
+                    levels = struct.pack("<I", len(levels)) + levels
+                body = compress(self.codec, levels + data)
+                header = (
+                    Struct()
+                    .i32(1, DATA_PAGE)
+                    .i32(2, len(levels) + len(data))
+                    .i32(3, len(body))
+                    .struct(5, Struct().i32(1, len(p)).i32(2, encoding).i32(3, RLE).i32(4, RLE))
+                )
+            else:
+                # The levels of version 2 pages are never compressed.
+                body = levels + compress(self.codec, data)
+                header = (
+                    Struct()
+                    .i32(1, DATA_PAGE_V2)
+                    .i32(2, len(levels) + len(data))
+                    .i32(3, len(body))
+                    .struct(
+                        8,
+                        Struct()
+                        .i32(1, len(p))
+                        .i32(2, len(p) - len(defined))
+                        .i32(3, len(p))
+                        .i32(4, encoding)
+                        .i32(5, len(levels))
+                        .i32(6, 0)
+                        .bool(7, True),
+                    )
+                )
+            self.page(header, body)
+        size = len(self.buf) - start
+        stats = Struct().i64(3, sum(v is None for p in pages for v in p))
+        if values:
+            stats.binary(5, self.stat(col, max(values))).binary(6, self.stat(col, min(values)))
+        md = (
+            Struct()
+            .i32(1, col.typ)
+            .list(2, T_I32, encodings)
+            .list(3, T_BINARY, [col.name])
+            .i32(4, self.codec)
+            .i64(5, sum(len(p) for p in pages))
+            .i64(6, size)
+            .i64(7, size)
+            .i64(9, data_offset)
+        )
+        if dict_offset is not None:
+            md.i64(11, dict_offset)
+        md.struct(12, stats)
+        return Struct().i64(2, start).struct(3, md), size
+
+    @staticmethod
+    def stat(col, v):
+        if col.typ == BYTE_ARRAY:
+            return v.encode()
+        return plain(col.typ, [v])
+
+    def row_group(self, chunks):
+        """Writes a row group. chunks holds the pages of every column."""
+        ccs, total = [], 0
+        for col, pages in zip(self.columns, chunks):
+            cc, size = self.chunk(col, pages)
+            ccs.append(cc)
+            total += size
+        rows = sum(len(p) for p in chunks[0])
+        self.row_groups.append(Struct().list(1, T_STRUCT, ccs).i64(2, total).i64(3, rows))
+        self.num_rows += rows
+
+    def close(self, path):
+        root = Struct().binary(4, "schema").i32(5, len(self.columns))
+        md = (
+            Struct()
+            .i32(1, 1)
+            .list(2, T_STRUCT, [root] + [c.schema() for c in self.columns])
+            .i64(3, self.num_rows)
+            .list(4, T_STRUCT, self.row_groups)
+            .binary(6, "flux internal/parquet/testdata/golden.py")
+            .end()
+        )
+        self.buf += md + struct.pack("<I", len(md)) + b"PAR1"
+        with open(path, "wb") as f:
+            f.write(self.buf)
+
+
+def main():
+    # A snappy compressed file with version 1 data pages, three row
+    # groups and a chunk with two data pages that share a dictionary.
+    f = File(
+        [
+            Column("id", INT64, REQUIRED),
+            Column("region", BYTE_ARRAY, OPTIONAL, dictionary=True, utf8=True),
+            Column("value", DOUBLE, OPTIONAL, dictionary=True),
+            Column("count", INT32, OPTIONAL, dictionary=True),
+            Column("ok", BOOLEAN, OPTIONAL),
+        ],
+        SNAPPY,
+    )
+    f.row_group([
+        [[1, 2, 3, 4]],
+        [["east", None, "west", "east"]],
+        [[1.5, 2.5, None, 1.5]],
+        [[None, None, None, None]],
+        [[True, False, None, True]],
+    ])
+    f.row_group([
+        [[5, 6, 7, 8, 9, 10, 11, 12, 13, 14], [15, 16]],
+        [["north"] * 10, [None, "south"]],
+        [[0.5] * 9 + [None], [-1.0, 0.5]],
+        [[7, 8, 7, 8, 7, 8, 7, 8, 9, None], [None, 7]],
+        [[True] * 10, [None, False]],
+    ])
+    f.row_group([
+        [[17, 18]],
+        [[None, None]],
+        [[3.0, 3.0]],
+        [[1, None]],
+        [[False, False]],
+    ])
+    f.close("golden.parquet")
+
+    # A gzip compressed file with version 2 data pages.
+    f = File(
+        [
+            Column("name", BYTE_ARRAY, OPTIONAL, dictionary=True, utf8=True),
+            Column("n", INT64, OPTIONAL),
+        ],
+        GZIP,
+        page_version=2,
+    )
+    f.row_group([
+        [["a", "b", None, "a", "c"]],
+        [[10, None, 30, None, 50]],
+    ])
+    f.row_group([
+        [[None], ["c", "c"]],
+        [[-1], [None, -3]],
+    ])
+    f.close("golden_v2.parquet")
+
+
+if __name__ == "__main__":
+    main()
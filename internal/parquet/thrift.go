@@ -0,0 +1,369 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// Type identifiers used by the thrift compact protocol.
+const (
+	thriftStop         = 0
+	thriftBooleanTrue  = 1
+	thriftBooleanFalse = 2
+	thriftByte         = 3
+	thriftI16          = 4
+	thriftI32          = 5
+	thriftI64          = 6
+	thriftDouble       = 7
+	thriftBinary       = 8
+	thriftList         = 9
+	thriftSet          = 10
+	thriftMap          = 11
+	thriftStruct       = 12
+)
+
+// maxThriftDepth limits the nesting of skipped structures
+// so a corrupt file cannot exhaust the stack.
+const maxThriftDepth = 64
+
+var errThriftEOF = errors.New(codes.Invalid, "unexpected end of thrift data")
+
+// thriftReader decodes values encoded with the thrift compact protocol.
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errThriftEOF
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		return 0, errThriftEOF
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) readVarint() (int64, error) {
+	v, err := r.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+func (r *thriftReader) readI32() (int32, error) {
+	v, err := r.readVarint()
+	return int32(v), err
+}
+
+func (r *thriftReader) readI64() (int64, error) {
+	return r.readVarint()
+}
+
+func (r *thriftReader) readDouble() (float64, error) {
+	if len(r.buf)-r.pos < 8 {
+		return 0, errThriftEOF
+	}
+	v := binary.LittleEndian.Uint64(r.buf[r.pos:])
+	r.pos += 8
+	return math.Float64frombits(v), nil
+}
+
+func (r *thriftReader) readBinary() ([]byte, error) {
+	n, err := r.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.buf)-r.pos) < n {
+		return nil, errThriftEOF
+	}
+	b := make([]byte, n)
+	copy(b, r.buf[r.pos:])
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *thriftReader) readString() (string, error) {
+	b, err := r.readBinary()
+	return string(b), err
+}
+
+// readFieldHeader reads the header of the next field in a struct.
+// The field id is encoded relative to the id of the previous field.
+// A typ of thriftStop marks the end of the struct.
+func (r *thriftReader) readFieldHeader(lastID int16) (id int16, typ byte, err error) {
+	b, err := r.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	typ = b & 0x0f
+	if typ == thriftStop {
+		return 0, thriftStop, nil
+	}
+	if delta := int16(b >> 4); delta != 0 {
+		return lastID + delta, typ, nil
+	}
+	v, err := r.readVarint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int16(v), typ, nil
+}
+
+// readListHeader reads the header of a list or set
+// and returns the element type and the number of elements.
+func (r *thriftReader) readListHeader() (typ byte, size int, err error) {
+	b, err := r.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	typ = b & 0x0f
+	n := uint64(b >> 4)
+	if n == 15 {
+		if n, err = r.readUvarint(); err != nil {
+			return 0, 0, err
+		}
+	}
+	// Every element takes at least one byte.
+	if n > uint64(len(r.buf)-r.pos) {
+		return 0, 0, errThriftEOF
+	}
+	return typ, int(n), nil
+}
+
+// readBool reads a boolean list element. Boolean fields
+// have their value encoded in the field type instead.
+func (r *thriftReader) readBool() (bool, error) {
+	b, err := r.readByte()
+	return b == thriftBooleanTrue, err
+}
+
+// readStruct reads the fields of a struct and calls fn for each of them.
+// The function must consume the value of the field or return false
+// for the field to be skipped.
+func (r *thriftReader) readStruct(fn func(id int16, typ byte) (bool, error)) error {
+	var lastID int16
+	for {
+		id, typ, err := r.readFieldHeader(lastID)
+		if err != nil {
+			return err
+		}
+		if typ == thriftStop {
+			return nil
+		}
+		lastID = id
+		if ok, err := fn(id, typ); err != nil {
+			return err
+		} else if !ok {
+			if err := r.skip(typ, 0); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readList reads the header of a list and calls fn for each element.
+func (r *thriftReader) readList(fn func(typ byte) error) error {
+	typ, n, err := r.readListHeader()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := fn(typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip discards a value of the given type.
+func (r *thriftReader) skip(typ byte, depth int) error {
+	if depth > maxThriftDepth {
+		return errors.New(codes.Invalid, "thrift data is nested too deeply")
+	}
+	switch typ {
+	case thriftBooleanTrue, thriftBooleanFalse:
+		return nil
+	case thriftByte:
+		_, err := r.readByte()
+		return err
+	case thriftI16, thriftI32, thriftI64:
+		_, err := r.readUvarint()
+		return err
+	case thriftDouble:
+		_, err := r.readDouble()
+		return err
+	case thriftBinary:
+		n, err := r.readUvarint()
+		if err != nil {
+			return err
+		}
+		if uint64(len(r.buf)-r.pos) < n {
+			return errThriftEOF
+		}
+		r.pos += int(n)
+		return nil
+	case thriftList, thriftSet:
+		elem, n, err := r.readListHeader()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			// Booleans take a full byte inside of a list.
+			if elem == thriftBooleanTrue || elem == thriftBooleanFalse {
+				elem = thriftByte
+			}
+			if err := r.skip(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftMap:
+		n, err := r.readUvarint()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return nil
+		}
+		types, err := r.readByte()
+		if err != nil {
+			return err
+		}
+		ktyp, vtyp := types>>4, types&0x0f
+		for i := uint64(0); i < n; i++ {
+			if err := r.skip(ktyp, depth+1); err != nil {
+				return err
+			}
+			if err := r.skip(vtyp, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case thriftStruct:
+		for {
+			_, typ, err := r.readFieldHeader(0)
+			if err != nil {
+				return err
+			}
+			if typ == thriftStop {
+				return nil
+			}
+			if err := r.skip(typ, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Newf(codes.Invalid, "unknown thrift type %d", typ)
+	}
+}
+
+// thriftWriter encodes values with the thrift compact protocol.
+type thriftWriter struct {
+	buf    []byte
+	lastID []int16
+}
+
+func (w *thriftWriter) writeUvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf = append(w.buf, tmp[:n]...)
+}
+
+func (w *thriftWriter) writeVarint(v int64) {
+	w.writeUvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *thriftWriter) writeFieldHeader(id int16, typ byte) {
+	last := w.lastID[len(w.lastID)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.writeVarint(int64(id))
+	}
+	w.lastID[len(w.lastID)-1] = id
+}
+
+func (w *thriftWriter) beginStruct() {
+	w.lastID = append(w.lastID, 0)
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf = append(w.buf, thriftStop)
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+func (w *thriftWriter) fieldBool(id int16, v bool) {
+	if v {
+		w.writeFieldHeader(id, thriftBooleanTrue)
+	} else {
+		w.writeFieldHeader(id, thriftBooleanFalse)
+	}
+}
+
+func (w *thriftWriter) fieldI32(id int16, v int32) {
+	w.writeFieldHeader(id, thriftI32)
+	w.writeVarint(int64(v))
+}
+
+func (w *thriftWriter) fieldI64(id int16, v int64) {
+	w.writeFieldHeader(id, thriftI64)
+	w.writeVarint(v)
+}
+
+func (w *thriftWriter) fieldBinary(id int16, v []byte) {
+	w.writeFieldHeader(id, thriftBinary)
+	w.writeBinary(v)
+}
+
+func (w *thriftWriter) fieldString(id int16, v string) {
+	w.fieldBinary(id, []byte(v))
+}
+
+// fieldStruct writes a struct field whose fields are written by fn.
+func (w *thriftWriter) fieldStruct(id int16, fn func()) {
+	w.writeFieldHeader(id, thriftStruct)
+	w.beginStruct()
+	fn()
+	w.endStruct()
+}
+
+// fieldList writes a list field with n elements of type typ.
+// The elements are written by fn.
+func (w *thriftWriter) fieldList(id int16, typ byte, n int, fn func(i int)) {
+	w.writeFieldHeader(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|typ)
+	} else {
+		w.buf = append(w.buf, 0xf0|typ)
+		w.writeUvarint(uint64(n))
+	}
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+}
+
+func (w *thriftWriter) writeBinary(v []byte) {
+	w.writeUvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// writeStruct writes a struct list element whose fields are written by fn.
+func (w *thriftWriter) writeStruct(fn func()) {
+	w.beginStruct()
+	fn()
+	w.endStruct()
+}
@@ -0,0 +1,299 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+const createdBy = "flux"
+
+// Writer writes a parquet file with a flat schema.
+// Each call to WriteRowGroup writes a row group with
+// a single plain encoded page for each column.
+type Writer struct {
+	w       io.Writer
+	offset  int64
+	columns []SchemaElement
+	meta    FileMetaData
+	err     error
+}
+
+// NewWriter creates a Writer for the given columns.
+// The columns must have a primitive type and cannot be repeated.
+func NewWriter(w io.Writer, columns []SchemaElement) (*Writer, error) {
+	schema := make([]SchemaElement, 0, len(columns)+1)
+	schema = append(schema, SchemaElement{
+		Name:          "schema",
+		NumChildren:   int32(len(columns)),
+		ConvertedType: ConvertedNone,
+	})
+	for _, c := range columns {
+		switch c.Type {
+		case Boolean, Int32, Int64, Float, Double, ByteArray:
+		default:
+			return nil, errors.Newf(codes.Unimplemented, "cannot write parquet column %q of type %v", c.Name, c.Type)
+		}
+		if c.Repetition == Repeated {
+			return nil, errors.Newf(codes.Unimplemented, "cannot write repeated parquet column %q", c.Name)
+		}
+		c.HasType = true
+		schema = append(schema, c)
+	}
+	pw := &Writer{
+		w:       w,
+		columns: columns,
+		meta: FileMetaData{
+			Version:   1,
+			Schema:    schema,
+			CreatedBy: createdBy,
+		},
+	}
+	pw.write([]byte(magic))
+	return pw, pw.err
+}
+
+func (w *Writer) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	w.err = err
+}
+
+// WriteRowGroup writes a row group with one ColumnValues for each column.
+// Optional columns must set Defined for each row.
+func (w *Writer) WriteRowGroup(values []*ColumnValues, numRows int) error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(values) != len(w.columns) {
+		return errors.Newf(codes.Internal, "expected %d parquet columns, got %d", len(w.columns), len(values))
+	}
+
+	rg := RowGroup{NumRows: int64(numRows)}
+	for i := range w.columns {
+		col, vs := &w.columns[i], values[i]
+		if err := validateValues(col, vs, numRows); err != nil {
+			return err
+		}
+
+		var page []byte
+		if col.Repetition == Optional {
+			levels := make([]int32, len(vs.Defined))
+			for j, defined := range vs.Defined {
+				if defined {
+					levels[j] = 1
+				}
+			}
+			encoded := encodeHybrid(nil, levels, 1)
+			var size [4]byte
+			binary.LittleEndian.PutUint32(size[:], uint32(len(encoded)))
+			page = append(page, size[:]...)
+			page = append(page, encoded...)
+		}
+		page = encodePlain(page, col.Type, vs)
+
+		var hw thriftWriter
+		writePageHeader(&hw, &pageHeader{
+			Type:                 dataPage,
+			UncompressedPageSize: int32(len(page)),
+			CompressedPageSize:   int32(len(page)),
+			DataPageHeader: &dataPageHeader{
+				NumValues:               int32(numRows),
+				Encoding:                Plain,
+				DefinitionLevelEncoding: RLE,
+				RepetitionLevelEncoding: RLE,
+			},
+		})
+
+		offset := w.offset
+		w.write(hw.buf)
+		w.write(page)
+		if w.err != nil {
+			return w.err
+		}
+
+		size := int64(len(hw.buf) + len(page))
+		stats := columnStatistics(col, vs, numRows)
+		rg.Columns = append(rg.Columns, ColumnChunk{
+			FileOffset: offset,
+			MetaData: &ColumnMetaData{
+				Type:                  col.Type,
+				Encodings:             []Encoding{Plain, RLE},
+				PathInSchema:          []string{col.Name},
+				Codec:                 Uncompressed,
+				NumValues:             int64(numRows),
+				TotalUncompressedSize: size,
+				TotalCompressedSize:   size,
+				DataPageOffset:        offset,
+				Statistics:            stats,
+			},
+		})
+		rg.TotalByteSize += size
+	}
+	w.meta.RowGroups = append(w.meta.RowGroups, rg)
+	w.meta.NumRows += int64(numRows)
+	return nil
+}
+
+// Close writes the footer of the file. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	var tw thriftWriter
+	writeFileMetaData(&tw, &w.meta)
+	w.write(tw.buf)
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(tw.buf)))
+	w.write(size[:])
+	w.write([]byte(magic))
+	return w.err
+}
+
+func validateValues(col *SchemaElement, vs *ColumnValues, numRows int) error {
+	n := numRows
+	if col.Repetition == Optional {
+		if len(vs.Defined) != numRows {
+			return errors.Newf(codes.Internal, "parquet column %q has %d rows, expected %d", col.Name, len(vs.Defined), numRows)
+		}
+		n = 0
+		for _, defined := range vs.Defined {
+			if defined {
+				n++
+			}
+		}
+	} else if vs.Defined != nil {
+		return errors.Newf(codes.Internal, "required parquet column %q cannot have null values", col.Name)
+	}
+
+	var got int
+	switch col.Type {
+	case Boolean:
+		got = len(vs.Bools)
+	case Int32, Int64:
+		got = len(vs.Ints)
+	case Float, Double:
+		got = len(vs.Floats)
+	case ByteArray:
+		got = len(vs.Strings)
+	}
+	if got != n || vs.Len() != n {
+		return errors.Newf(codes.Internal, "parquet column %q has %d values, expected %d", col.Name, got, n)
+	}
+	return nil
+}
+
+// encodePlain appends the plain encoding of the values to buf.
+func encodePlain(buf []byte, typ Type, vs *ColumnValues) []byte {
+	var tmp [8]byte
+	switch typ {
+	case Boolean:
+		packed := make([]byte, (len(vs.Bools)+7)/8)
+		for i, v := range vs.Bools {
+			if v {
+				packed[i/8] |= 1 << (uint(i) % 8)
+			}
+		}
+		buf = append(buf, packed...)
+	case Int32:
+		for _, v := range vs.Ints {
+			binary.LittleEndian.PutUint32(tmp[:], uint32(v))
+			buf = append(buf, tmp[:4]...)
+		}
+	case Int64:
+		for _, v := range vs.Ints {
+			binary.LittleEndian.PutUint64(tmp[:], uint64(v))
+			buf = append(buf, tmp[:8]...)
+		}
+	case Float:
+		for _, v := range vs.Floats {
+			binary.LittleEndian.PutUint32(tmp[:], math.Float32bits(float32(v)))
+			buf = append(buf, tmp[:4]...)
+		}
+	case Double:
+		for _, v := range vs.Floats {
+			binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(v))
+			buf = append(buf, tmp[:8]...)
+		}
+	case ByteArray:
+		for _, v := range vs.Strings {
+			binary.LittleEndian.PutUint32(tmp[:], uint32(len(v)))
+			buf = append(buf, tmp[:4]...)
+			buf = append(buf, v...)
+		}
+	}
+	return buf
+}
+
+// columnStatistics computes the minimum and maximum of the values
+// using the sort order of the column type.
+func columnStatistics(col *SchemaElement, vs *ColumnValues, numRows int) *Statistics {
+	stats := &Statistics{
+		NullCount: int64(numRows - vs.Len()),
+		HasNulls:  true,
+	}
+	var min, max ColumnValues
+	switch col.Type {
+	case Boolean:
+		for _, v := range vs.Bools {
+			if len(min.Bools) == 0 {
+				min.Bools, max.Bools = []bool{v}, []bool{v}
+			}
+			min.Bools[0] = min.Bools[0] && v
+			max.Bools[0] = max.Bools[0] || v
+		}
+	case Int32, Int64:
+		unsigned := col.IsUnsigned()
+		for _, v := range vs.Ints {
+			if len(min.Ints) == 0 {
+				min.Ints, max.Ints = []int64{v}, []int64{v}
+			}
+			if unsigned {
+				if uint64(v) < uint64(min.Ints[0]) {
+					min.Ints[0] = v
+				}
+				if uint64(v) > uint64(max.Ints[0]) {
+					max.Ints[0] = v
+				}
+			} else {
+				if v < min.Ints[0] {
+					min.Ints[0] = v
+				}
+				if v > max.Ints[0] {
+					max.Ints[0] = v
+				}
+			}
+		}
+	case Float, Double:
+		for _, v := range vs.Floats {
+			if math.IsNaN(v) {
+				continue
+			}
+			if len(min.Floats) == 0 {
+				min.Floats, max.Floats = []float64{v}, []float64{v}
+			}
+			min.Floats[0] = math.Min(min.Floats[0], v)
+			max.Floats[0] = math.Max(max.Floats[0], v)
+		}
+	case ByteArray:
+		// Statistics of byte arrays are stored without a length prefix.
+		for _, v := range vs.Strings {
+			if stats.MinValue == nil || bytes.Compare([]byte(v), stats.MinValue) < 0 {
+				stats.MinValue = []byte(v)
+			}
+			if stats.MaxValue == nil || bytes.Compare([]byte(v), stats.MaxValue) > 0 {
+				stats.MaxValue = []byte(v)
+			}
+		}
+		return stats
+	}
+	if min.Len() > 0 {
+		stats.MinValue = encodePlain(nil, col.Type, &min)
+		stats.MaxValue = encodePlain(nil, col.Type, &max)
+	}
+	return stats
+}
@@ -0,0 +1,330 @@
+// Package payload decodes the payloads of the messages that
+// are read from a message broker into tables.
+//
+// Line protocol payloads are decoded into a table for each series and field
+// like the tables of influxdb.from. JSON payloads are an object or an array
+// of objects that are each decoded into a row of a single table. CSV payloads
+// are annotated CSV and their tables are appended to the tables with the same
+// group key.
+package payload
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	protocol "github.com/influxdata/line-protocol"
+)
+
+// The formats of payloads.
+const (
+	LineProtocol = "lineprotocol"
+	JSON         = "json"
+	CSV          = "csv"
+)
+
+const (
+	fieldColLabel       = "_field"
+	measurementColLabel = "_measurement"
+)
+
+// Message is the payload of a message and the time it was sent.
+type Message struct {
+	Value []byte
+	Time  time.Time
+}
+
+// Decoder decodes the payloads of messages and builds
+// the tables of the decoded messages.
+type Decoder interface {
+	// Decode decodes the payload of a message.
+	Decode(msg Message) error
+	// Do calls f with each table of the decoded messages.
+	Do(f func(flux.Table) error) error
+}
+
+// ValidateFormat returns an error if the format is not a known payload format.
+func ValidateFormat(format string) error {
+	switch format {
+	case LineProtocol, JSON, CSV:
+		return nil
+	default:
+		return errors.Newf(codes.Invalid, "unknown payload format %q, expected one of %q, %q or %q", format, LineProtocol, JSON, CSV)
+	}
+}
+
+// NewDecoder returns a decoder for the payloads of a format.
+func NewDecoder(format string, alloc *memory.Allocator) (Decoder, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	tables := &tables{
+		alloc:  alloc,
+		lookup: execute.NewGroupLookup(),
+	}
+	switch format {
+	case LineProtocol:
+		return &lineProtocolDecoder{tables: tables}, nil
+	case JSON:
+		return &jsonDecoder{alloc: alloc, colIdx: make(map[string]int)}, nil
+	default:
+		return &csvDecoder{tables: tables}, nil
+	}
+}
+
+// tables builds a table for each group key in the order
+// that the group keys are first seen.
+type tables struct {
+	alloc    *memory.Allocator
+	lookup   *execute.GroupLookup
+	builders []*execute.ColListTableBuilder
+}
+
+// builder returns the builder for the key and whether it is new.
+func (t *tables) builder(key flux.GroupKey) (*execute.ColListTableBuilder, bool) {
+	if i, ok := t.lookup.Lookup(key); ok {
+		return t.builders[i.(int)], false
+	}
+	b := execute.NewColListTableBuilder(key, t.alloc)
+	t.lookup.Set(key, len(t.builders))
+	t.builders = append(t.builders, b)
+	return b, true
+}
+
+func (t *tables) Do(f func(flux.Table) error) error {
+	for _, b := range t.builders {
+		tbl, err := b.Table()
+		if err != nil {
+			return err
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type lineProtocolDecoder struct {
+	*tables
+}
+
+func (d *lineProtocolDecoder) Decode(msg Message) error {
+	parser := protocol.NewStreamParser(bytes.NewReader(msg.Value))
+	// Points without a timestamp are at the time of the message.
+	parser.SetTimeFunc(func() time.Time { return msg.Time })
+	for {
+		m, err := parser.Next()
+		if err == protocol.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, codes.Invalid, "failed to decode line protocol")
+		}
+		for _, field := range m.FieldList() {
+			if err := d.decodeField(m, field); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *lineProtocolDecoder) decodeField(m protocol.Metric, field *protocol.Field) error {
+	value := values.New(field.Value)
+	kb := execute.NewGroupKeyBuilder(nil)
+	kb.AddKeyValue(fieldColLabel, values.NewString(field.Key))
+	kb.AddKeyValue(measurementColLabel, values.NewString(m.Name()))
+	for _, tag := range m.TagList() {
+		kb.AddKeyValue(tag.Key, values.NewString(tag.Value))
+	}
+	key, err := kb.Build()
+	if err != nil {
+		return err
+	}
+
+	b, created := d.builder(key)
+	if created {
+		if _, err := b.AddCol(flux.ColMeta{Label: execute.DefaultTimeColLabel, Type: flux.TTime}); err != nil {
+			return err
+		}
+		if _, err := b.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.ColumnType(value.Type())}); err != nil {
+			return err
+		}
+		if err := execute.AddTableKeyCols(key, b); err != nil {
+			return err
+		}
+	} else if typ := flux.ColumnType(value.Type()); b.Cols()[1].Type != typ {
+		return errors.Newf(codes.Invalid, "field %q of series %v has values of type %s and %s", field.Key, key, b.Cols()[1].Type, typ)
+	}
+
+	if err := b.AppendTime(0, values.ConvertTime(m.Time())); err != nil {
+		return err
+	}
+	if err := b.AppendValue(1, value); err != nil {
+		return err
+	}
+	return execute.AppendKeyValues(key, b)
+}
+
+type csvDecoder struct {
+	*tables
+}
+
+func (d *csvDecoder) Decode(msg Message) error {
+	decoder := csv.NewResultDecoder(csv.ResultDecoderConfig{Allocator: d.alloc})
+	result, err := decoder.Decode(bytes.NewReader(msg.Value))
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "failed to decode csv")
+	}
+	return result.Tables().Do(func(tbl flux.Table) error {
+		b, created := d.builder(tbl.Key())
+		if created {
+			if err := execute.AddTableCols(tbl, b); err != nil {
+				return err
+			}
+		}
+		return execute.AppendTable(tbl, b)
+	})
+}
+
+// jsonDecoder decodes each object into a row of a table with a column
+// for each property of the objects. The time of the row is the _time
+// property of the object if it has one or the time of the message.
+type jsonDecoder struct {
+	alloc  *memory.Allocator
+	cols   []flux.ColMeta
+	colIdx map[string]int
+	rows   []map[string]values.Value
+}
+
+func (d *jsonDecoder) Decode(msg Message) error {
+	dec := json.NewDecoder(bytes.NewReader(msg.Value))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return errors.Wrap(err, codes.Invalid, "failed to decode json")
+	}
+
+	var objects []interface{}
+	switch v := v.(type) {
+	case []interface{}:
+		objects = v
+	default:
+		objects = []interface{}{v}
+	}
+	for _, o := range objects {
+		obj, ok := o.(map[string]interface{})
+		if !ok {
+			return errors.New(codes.Invalid, "json payload must be an object or an array of objects")
+		}
+		if err := d.decodeObject(obj, msg.Time); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *jsonDecoder) decodeObject(obj map[string]interface{}, t time.Time) error {
+	row := make(map[string]values.Value, len(obj)+1)
+	row[execute.DefaultTimeColLabel] = values.NewTime(values.ConvertTime(t))
+	for k, v := range obj {
+		value, err := jsonValue(k, v)
+		if err != nil {
+			return err
+		}
+		if k == execute.DefaultTimeColLabel && !value.IsNull() {
+			if value.Type().Nature() != semantic.String {
+				return errors.Newf(codes.Invalid, "json property %q must be an RFC3339 time", k)
+			}
+			ts, err := time.Parse(time.RFC3339Nano, value.Str())
+			if err != nil {
+				return errors.Wrapf(err, codes.Invalid, "json property %q must be an RFC3339 time", k)
+			}
+			value = values.NewTime(values.ConvertTime(ts))
+		}
+		row[k] = value
+	}
+
+	// The columns of new properties are added in the order of their names.
+	keys := make([]string, 0, len(row))
+	for k := range row {
+		if k != execute.DefaultTimeColLabel {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	keys = append([]string{execute.DefaultTimeColLabel}, keys...)
+	for _, k := range keys {
+		v := row[k]
+		if v.IsNull() {
+			continue
+		}
+		typ := flux.ColumnType(v.Type())
+		if j, ok := d.colIdx[k]; !ok {
+			d.colIdx[k] = len(d.cols)
+			d.cols = append(d.cols, flux.ColMeta{Label: k, Type: typ})
+		} else if d.cols[j].Type != typ {
+			return errors.Newf(codes.Invalid, "json property %q has values of type %s and %s", k, d.cols[j].Type, typ)
+		}
+	}
+	d.rows = append(d.rows, row)
+	return nil
+}
+
+// jsonValue converts a decoded JSON value to a value of a column.
+func jsonValue(k string, v interface{}) (values.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return values.Null, nil
+	case bool:
+		return values.NewBool(v), nil
+	case string:
+		return values.NewString(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "json property %q", k)
+		}
+		return values.NewFloat(f), nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "json property %q must be a number, a string, a boolean or null", k)
+	}
+}
+
+func (d *jsonDecoder) Do(f func(flux.Table) error) error {
+	if len(d.rows) == 0 {
+		return nil
+	}
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), d.alloc)
+	for _, col := range d.cols {
+		if _, err := b.AddCol(col); err != nil {
+			return err
+		}
+	}
+	for _, row := range d.rows {
+		for j, col := range d.cols {
+			v, ok := row[col.Label]
+			if !ok || v.IsNull() {
+				if err := b.AppendNil(j); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := b.AppendValue(j, v); err != nil {
+				return err
+			}
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
@@ -0,0 +1,188 @@
+package payload_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/payload"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+func TestDecoder(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := func(d time.Duration) values.Time {
+		return values.ConvertTime(now.Add(d))
+	}
+	for _, tc := range []struct {
+		name     string
+		format   string
+		messages []string
+		want     []*executetest.Table
+		wantErr  string
+	}{
+		{
+			name:   "line protocol",
+			format: payload.LineProtocol,
+			messages: []string{
+				"cpu,host=a usage=1.5,count=2i 1609459201000000000\ncpu,host=b usage=2.5",
+				"cpu,host=a usage=3.5 1609459202000000000",
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_field", "_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TInt},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{ts(time.Second), int64(2), "count", "cpu", "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_field", "_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{ts(time.Second), 1.5, "usage", "cpu", "a"},
+						{ts(2 * time.Second), 3.5, "usage", "cpu", "a"},
+					},
+				},
+				{
+					KeyCols: []string{"_field", "_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{ts(0), 2.5, "usage", "cpu", "b"},
+					},
+				},
+			},
+		},
+		{
+			name:   "line protocol with conflicting field types",
+			format: payload.LineProtocol,
+			messages: []string{
+				"cpu usage=1.5",
+				"cpu usage=1i",
+			},
+			wantErr: `field "usage" of series`,
+		},
+		{
+			name:   "json",
+			format: payload.JSON,
+			messages: []string{
+				`{"host": "a", "usage": 1.5}`,
+				`[{"host": "b", "ok": true, "_time": "2021-01-01T00:00:03Z"}, {"usage": null}]`,
+			},
+			want: []*executetest.Table{
+				{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+						{Label: "usage", Type: flux.TFloat},
+						{Label: "ok", Type: flux.TBool},
+					},
+					Data: [][]interface{}{
+						{ts(0), "a", 1.5, nil},
+						{ts(3 * time.Second), "b", nil, true},
+						{ts(0), nil, nil, nil},
+					},
+				},
+			},
+		},
+		{
+			name:     "json with nested object",
+			format:   payload.JSON,
+			messages: []string{`{"tags": {"host": "a"}}`},
+			wantErr:  `json property "tags" must be a number, a string, a boolean or null`,
+		},
+		{
+			name:   "csv",
+			format: payload.CSV,
+			messages: []string{
+				"#datatype,string,long,string,double\n#group,false,false,true,false\n#default,_result,,,\n,result,table,host,_value\n,,0,a,1.5\n",
+				"#datatype,string,long,string,double\n#group,false,false,true,false\n#default,_result,,,\n,result,table,host,_value\n,,0,a,2.5\n",
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{"a", 1.5},
+						{"a", 2.5},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dec, err := payload.NewDecoder(tc.format, &memory.Allocator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, msg := range tc.messages {
+				err = dec.Decode(payload.Message{Value: []byte(msg), Time: now})
+				if err != nil {
+					break
+				}
+			}
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatal("expected error")
+				} else if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: want %q in %q", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var got []*executetest.Table
+			if err := dec.Do(func(tbl flux.Table) error {
+				t, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				got = append(got, t)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			executetest.NormalizeTables(got)
+			executetest.NormalizeTables(tc.want)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	if err := payload.ValidateFormat(payload.JSON); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := payload.ValidateFormat("xml"); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,203 @@
+// Package pushdown extracts the predicates of the range and filter
+// transformations that follow a source so that the source can use
+// them to read less data.
+package pushdown
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+// ColumnPredicate compares the values of a column with a constant.
+type ColumnPredicate struct {
+	Column string
+	Op     ast.OperatorKind
+	Value  values.Value
+}
+
+func (p ColumnPredicate) String() string {
+	return fmt.Sprintf("%s %s %s", p.Column, p.Op, values.DisplayString(p.Value))
+}
+
+// Equal reports whether both predicates compare
+// the same column with the same value.
+func (p ColumnPredicate) Equal(o ColumnPredicate) bool {
+	return p.Column == o.Column && p.Op == o.Op && p.Value.Equal(o.Value)
+}
+
+// FindSource walks the chain of nodes of the given kinds that precede the
+// node and returns the source of the source kind at its start, along with
+// the nodes of the chain. Each node of the chain must have a single successor
+// so that pushing predicates into the source does not change the rows seen
+// by other branches of the plan.
+func FindSource(node plan.Node, source plan.ProcedureKind, kinds ...plan.ProcedureKind) (plan.Node, []plan.Node) {
+	var chain []plan.Node
+	for {
+		if len(node.Predecessors()) != 1 {
+			return nil, nil
+		}
+		pred := node.Predecessors()[0]
+		if len(pred.Successors()) != 1 {
+			return nil, nil
+		}
+		if pred.Kind() == source {
+			return pred, chain
+		}
+		if !containsKind(kinds, pred.Kind()) {
+			return nil, nil
+		}
+		chain = append(chain, pred)
+		node = pred
+	}
+}
+
+func containsKind(kinds []plan.ProcedureKind, kind plan.ProcedureKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// RangePredicates returns the predicates that select the rows within the bounds of a range.
+func RangePredicates(spec *universe.RangeProcedureSpec) []ColumnPredicate {
+	bounds := spec.TimeBounds(nil)
+	return []ColumnPredicate{
+		{Column: spec.TimeColumn, Op: ast.GreaterThanEqualOperator, Value: values.NewTime(bounds.Start)},
+		{Column: spec.TimeColumn, Op: ast.LessThanOperator, Value: values.NewTime(bounds.Stop)},
+	}
+}
+
+// FilterPredicates returns the comparisons between a column and a literal
+// that are joined with and in the predicate of a filter. It reports whether
+// the predicates are equivalent to the whole predicate of the filter.
+func FilterPredicates(spec *universe.FilterProcedureSpec) ([]ColumnPredicate, bool) {
+	fn := spec.Fn.Fn
+	if fn == nil || fn.Parameters == nil || len(fn.Parameters.List) != 1 {
+		return nil, false
+	}
+	body, ok := fn.GetFunctionBodyExpression()
+	if !ok {
+		return nil, false
+	}
+	return filterPredicates(fn.Parameters.List[0].Key.Name, body, nil)
+}
+
+// NewPredicates returns the predicates that are not in existing.
+func NewPredicates(existing, preds []ColumnPredicate) []ColumnPredicate {
+	var added []ColumnPredicate
+	for _, p := range preds {
+		if !Contains(existing, p) && !Contains(added, p) {
+			added = append(added, p)
+		}
+	}
+	return added
+}
+
+// Contains reports whether the predicate is in preds.
+func Contains(preds []ColumnPredicate, p ColumnPredicate) bool {
+	for _, q := range preds {
+		if q.Equal(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterPredicates appends the predicates of the conjunctions in
+// the expression that compare a property of the parameter with a literal.
+// It reports whether every conjunction could be converted to a predicate.
+func filterPredicates(param string, expr semantic.Expression, preds []ColumnPredicate) ([]ColumnPredicate, bool) {
+	switch e := expr.(type) {
+	case *semantic.LogicalExpression:
+		if e.Operator != ast.AndOperator {
+			return preds, false
+		}
+		preds, lok := filterPredicates(param, e.Left, preds)
+		preds, rok := filterPredicates(param, e.Right, preds)
+		return preds, lok && rok
+	case *semantic.BinaryExpression:
+		op := e.Operator
+		switch op {
+		case ast.EqualOperator, ast.NotEqualOperator,
+			ast.LessThanOperator, ast.LessThanEqualOperator,
+			ast.GreaterThanOperator, ast.GreaterThanEqualOperator:
+		default:
+			return preds, false
+		}
+		if col, ok := columnReference(param, e.Left); ok {
+			if v, ok := literalValue(e.Right); ok {
+				return append(preds, ColumnPredicate{Column: col, Op: op, Value: v}), true
+			}
+		}
+		if col, ok := columnReference(param, e.Right); ok {
+			if v, ok := literalValue(e.Left); ok {
+				return append(preds, ColumnPredicate{Column: col, Op: swapOperator(op), Value: v}), true
+			}
+		}
+	}
+	return preds, false
+}
+
+// swapOperator returns the operator that gives the same
+// result when the operands of a comparison are swapped.
+func swapOperator(op ast.OperatorKind) ast.OperatorKind {
+	switch op {
+	case ast.LessThanOperator:
+		return ast.GreaterThanOperator
+	case ast.LessThanEqualOperator:
+		return ast.GreaterThanEqualOperator
+	case ast.GreaterThanOperator:
+		return ast.LessThanOperator
+	case ast.GreaterThanEqualOperator:
+		return ast.LessThanEqualOperator
+	default:
+		return op
+	}
+}
+
+func columnReference(param string, expr semantic.Expression) (string, bool) {
+	m, ok := expr.(*semantic.MemberExpression)
+	if !ok {
+		return "", false
+	}
+	id, ok := m.Object.(*semantic.IdentifierExpression)
+	if !ok || id.Name != param {
+		return "", false
+	}
+	return m.Property, true
+}
+
+func literalValue(expr semantic.Expression) (values.Value, bool) {
+	switch e := expr.(type) {
+	case *semantic.IntegerLiteral:
+		return values.NewInt(e.Value), true
+	case *semantic.UnsignedIntegerLiteral:
+		return values.NewUInt(e.Value), true
+	case *semantic.FloatLiteral:
+		return values.NewFloat(e.Value), true
+	case *semantic.StringLiteral:
+		return values.NewString(e.Value), true
+	case *semantic.BooleanLiteral:
+		return values.NewBool(e.Value), true
+	case *semantic.DateTimeLiteral:
+		return values.NewTime(values.ConvertTime(e.Value)), true
+	case *semantic.UnaryExpression:
+		if e.Operator != ast.SubtractionOperator {
+			return nil, false
+		}
+		switch a := e.Argument.(type) {
+		case *semantic.IntegerLiteral:
+			return values.NewInt(-a.Value), true
+		case *semantic.FloatLiteral:
+			return values.NewFloat(-a.Value), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,65 @@
+package pushdown_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/pushdown"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+func TestFilterPredicates(t *testing.T) {
+	testCases := []struct {
+		name  string
+		fn    string
+		want  []pushdown.ColumnPredicate
+		whole bool
+	}{
+		{
+			name: "conjunction",
+			fn:   `(r) => r.host == "a" and 10 < r.count and r._value != -1.5`,
+			want: []pushdown.ColumnPredicate{
+				{Column: "host", Op: ast.EqualOperator, Value: values.NewString("a")},
+				{Column: "count", Op: ast.GreaterThanOperator, Value: values.NewInt(10)},
+				{Column: "_value", Op: ast.NotEqualOperator, Value: values.NewFloat(-1.5)},
+			},
+			whole: true,
+		},
+		{
+			name: "partial conjunction",
+			fn:   `(r) => r.host == "a" and (r.region == "b" or r.region == "c")`,
+			want: []pushdown.ColumnPredicate{
+				{Column: "host", Op: ast.EqualOperator, Value: values.NewString("a")},
+			},
+		},
+		{
+			name: "another record",
+			fn:   `(r) => r.host == r.region`,
+		},
+		{
+			name: "regular expression",
+			fn:   `(r) => r.host =~ /^a/`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn: executetest.FunctionExpression(t, tc.fn),
+				},
+			}
+			got, whole := pushdown.FilterPredicates(spec)
+			if !cmp.Equal(tc.want, got, cmp.Comparer(func(x, y pushdown.ColumnPredicate) bool { return x.Equal(y) })) {
+				t.Errorf("unexpected predicates -want/+got:\n\t- %v\n\t+ %v", tc.want, got)
+			}
+			if whole != tc.whole {
+				t.Errorf("unexpected whole filter: want %v, got %v", tc.whole, whole)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package interpreter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+)
+
+// CoverageKind is the kind of source code counted by a coverage block.
+type CoverageKind int
+
+const (
+	// StatementCoverage counts the evaluations of a statement.
+	StatementCoverage CoverageKind = iota
+	// BranchCoverage counts the evaluations of the consequent
+	// or the alternate of a conditional expression.
+	BranchCoverage
+)
+
+func (k CoverageKind) String() string {
+	switch k {
+	case StatementCoverage:
+		return "stmt"
+	case BranchCoverage:
+		return "branch"
+	default:
+		return "unknown"
+	}
+}
+
+// CoverageBlock counts how many times the interpreter
+// evaluated a statement or a branch.
+type CoverageBlock struct {
+	File  string
+	Start ast.Position
+	End   ast.Position
+	Kind  CoverageKind
+	Count int64
+}
+
+type coverageKey struct {
+	file       string
+	start, end ast.Position
+}
+
+// Coverage records how many times the interpreter evaluates the
+// statements and the branches of the registered packages.
+// A Coverage is safe for concurrent use.
+type Coverage struct {
+	mu     sync.Mutex
+	blocks map[coverageKey]*CoverageBlock
+}
+
+// NewCoverage creates a Coverage without any registered packages.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		blocks: make(map[coverageKey]*CoverageBlock),
+	}
+}
+
+// Register adds the statements within the function bodies of the
+// package, and the branches of the conditional expressions within them,
+// to the coverage. Statements outside of a function are evaluated
+// once when the package is imported so they are not counted.
+// The file names of the blocks are prefixed with the package path.
+func (c *Coverage) Register(pkgpath string, pkg *semantic.Package) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	semantic.Walk(&coverageRegistrar{c: c, pkgpath: pkgpath}, pkg)
+}
+
+func (c *Coverage) add(pkgpath string, loc ast.SourceLocation, kind CoverageKind) {
+	if !loc.IsValid() {
+		return
+	}
+	key := coverageKey{file: loc.File, start: loc.Start, end: loc.End}
+	if _, ok := c.blocks[key]; ok {
+		return
+	}
+	c.blocks[key] = &CoverageBlock{
+		File:  path.Join(pkgpath, loc.File),
+		Start: loc.Start,
+		End:   loc.End,
+		Kind:  kind,
+	}
+}
+
+// hit counts an evaluation of the block at the location
+// if the location belongs to a registered package.
+func (c *Coverage) hit(loc ast.SourceLocation) {
+	key := coverageKey{file: loc.File, start: loc.Start, end: loc.End}
+	c.mu.Lock()
+	if b, ok := c.blocks[key]; ok {
+		b.Count++
+	}
+	c.mu.Unlock()
+}
+
+// Blocks returns a copy of the coverage blocks sorted by file and position.
+func (c *Coverage) Blocks() []CoverageBlock {
+	c.mu.Lock()
+	blocks := make([]CoverageBlock, 0, len(c.blocks))
+	for _, b := range c.blocks {
+		blocks = append(blocks, *b)
+	}
+	c.mu.Unlock()
+	sortCoverageBlocks(blocks)
+	return blocks
+}
+
+func sortCoverageBlocks(blocks []CoverageBlock) {
+	sort.Slice(blocks, func(i, j int) bool {
+		bi, bj := blocks[i], blocks[j]
+		if bi.File != bj.File {
+			return bi.File < bj.File
+		}
+		if bi.Start != bj.Start {
+			return bi.Start.Less(bj.Start)
+		}
+		if bi.End != bj.End {
+			return bi.End.Less(bj.End)
+		}
+		return bi.Kind < bj.Kind
+	})
+}
+
+// coverageRegistrar walks a package and registers
+// the blocks that are evaluated by function calls.
+type coverageRegistrar struct {
+	c          *Coverage
+	pkgpath    string
+	inFunction bool
+}
+
+func (v *coverageRegistrar) Visit(node semantic.Node) semantic.Visitor {
+	switch n := node.(type) {
+	case *semantic.FunctionExpression:
+		if n.Block != nil {
+			for _, stmt := range n.Block.Body {
+				v.c.add(v.pkgpath, stmt.Location(), StatementCoverage)
+			}
+		}
+		if !v.inFunction {
+			return &coverageRegistrar{c: v.c, pkgpath: v.pkgpath, inFunction: true}
+		}
+	case *semantic.ConditionalExpression:
+		if v.inFunction {
+			v.c.add(v.pkgpath, n.Consequent.Location(), BranchCoverage)
+			v.c.add(v.pkgpath, n.Alternate.Location(), BranchCoverage)
+		}
+	}
+	return v
+}
+
+func (v *coverageRegistrar) Done(node semantic.Node) {}
+
+// WithCoverage returns a context that records the evaluations of the
+// interpreter in the coverage.
+func WithCoverage(ctx context.Context, c *Coverage) context.Context {
+	return context.WithValue(ctx, coverageContextKey, c)
+}
+
+// GetCoverage returns the coverage that records the evaluations of
+// the interpreter for the context, or nil if there is none.
+func GetCoverage(ctx context.Context) *Coverage {
+	c, _ := ctx.Value(coverageContextKey).(*Coverage)
+	return c
+}
+
+func recordCoverage(ctx context.Context, loc ast.SourceLocation) {
+	if c := GetCoverage(ctx); c != nil {
+		c.hit(loc)
+	}
+}
+
+// coverProfileMode is the header of a coverage profile.
+const coverProfileMode = "mode: count"
+
+// WriteCoverProfile writes the blocks as a coverage profile.
+// After a header line, each line of the profile describes a block as
+//
+//	file:startLine.startColumn,endLine.endColumn kind count
+//
+// where kind is either stmt or branch.
+func WriteCoverProfile(w io.Writer, blocks []CoverageBlock) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintln(bw, coverProfileMode); err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		if _, err := fmt.Fprintf(bw, "%s:%d.%d,%d.%d %s %d\n",
+			b.File, b.Start.Line, b.Start.Column, b.End.Line, b.End.Column, b.Kind, b.Count,
+		); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadCoverProfile reads the blocks of a coverage profile
+// that was written by WriteCoverProfile.
+func ReadCoverProfile(r io.Reader) ([]CoverageBlock, error) {
+	s := bufio.NewScanner(r)
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(codes.Invalid, "coverage profile is empty")
+	}
+	if s.Text() != coverProfileMode {
+		return nil, errors.Newf(codes.Invalid, "unexpected coverage profile header %q", s.Text())
+	}
+
+	var blocks []CoverageBlock
+	for lineno := 2; s.Scan(); lineno++ {
+		line := s.Text()
+		if line == "" {
+			continue
+		}
+		b, err := parseCoverProfileLine(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "line %d of coverage profile", lineno)
+		}
+		blocks = append(blocks, b)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	sortCoverageBlocks(blocks)
+	return blocks, nil
+}
+
+func parseCoverProfileLine(line string) (CoverageBlock, error) {
+	var b CoverageBlock
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return b, errors.Newf(codes.Invalid, "expected 3 fields, found %d", len(fields))
+	}
+
+	i := strings.LastIndex(fields[0], ":")
+	if i < 0 {
+		return b, errors.Newf(codes.Invalid, "missing location in %q", fields[0])
+	}
+	b.File = fields[0][:i]
+	positions := strings.Split(fields[0][i+1:], ",")
+	if len(positions) != 2 {
+		return b, errors.Newf(codes.Invalid, "invalid location %q", fields[0][i+1:])
+	}
+	var err error
+	if b.Start, err = parseCoverPosition(positions[0]); err != nil {
+		return b, err
+	}
+	if b.End, err = parseCoverPosition(positions[1]); err != nil {
+		return b, err
+	}
+
+	switch fields[1] {
+	case StatementCoverage.String():
+		b.Kind = StatementCoverage
+	case BranchCoverage.String():
+		b.Kind = BranchCoverage
+	default:
+		return b, errors.Newf(codes.Invalid, "unknown block kind %q", fields[1])
+	}
+
+	if b.Count, err = strconv.ParseInt(fields[2], 10, 64); err != nil {
+		return b, errors.Newf(codes.Invalid, "invalid count %q", fields[2])
+	}
+	return b, nil
+}
+
+func parseCoverPosition(s string) (ast.Position, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return ast.Position{}, errors.Newf(codes.Invalid, "invalid position %q", s)
+	}
+	line, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ast.Position{}, errors.Newf(codes.Invalid, "invalid position %q", s)
+	}
+	column, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return ast.Position{}, errors.Newf(codes.Invalid, "invalid position %q", s)
+	}
+	return ast.Position{Line: line, Column: column}, nil
+}
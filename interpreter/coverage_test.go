@@ -0,0 +1,114 @@
+package interpreter_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/runtime"
+)
+
+func TestCoverage(t *testing.T) {
+	src := `f = (x) => {
+    y = x + 1
+    return if y > 2 then "big" else "small"
+}
+f(x: 1)
+f(x: 5)
+f(x: 10)
+`
+	pkg, err := runtime.AnalyzeSource(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage := interpreter.NewCoverage()
+	coverage.Register("main", pkg)
+
+	ctx := dependenciestest.Default().Inject(context.Background())
+	ctx = interpreter.WithCoverage(ctx, coverage)
+	if _, _, err := runtime.Eval(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the statements and branches within the function are counted.
+	want := []interpreter.CoverageBlock{
+		{
+			File:  "main",
+			Start: ast.Position{Line: 2, Column: 5},
+			End:   ast.Position{Line: 2, Column: 14},
+			Kind:  interpreter.StatementCoverage,
+			Count: 3,
+		},
+		{
+			File:  "main",
+			Start: ast.Position{Line: 3, Column: 5},
+			End:   ast.Position{Line: 3, Column: 44},
+			Kind:  interpreter.StatementCoverage,
+			Count: 3,
+		},
+		{
+			File:  "main",
+			Start: ast.Position{Line: 3, Column: 26},
+			End:   ast.Position{Line: 3, Column: 31},
+			Kind:  interpreter.BranchCoverage,
+			Count: 2,
+		},
+		{
+			File:  "main",
+			Start: ast.Position{Line: 3, Column: 37},
+			End:   ast.Position{Line: 3, Column: 44},
+			Kind:  interpreter.BranchCoverage,
+			Count: 1,
+		},
+	}
+	got := coverage.Blocks()
+	if !cmp.Equal(want, got) {
+		t.Fatalf("unexpected coverage -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	var buf bytes.Buffer
+	if err := interpreter.WriteCoverProfile(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	wantProfile := `mode: count
+main:2.5,2.14 stmt 3
+main:3.5,3.44 stmt 3
+main:3.26,3.31 branch 2
+main:3.37,3.44 branch 1
+`
+	if diff := cmp.Diff(wantProfile, buf.String()); diff != "" {
+		t.Fatalf("unexpected coverage profile -want/+got:\n%s", diff)
+	}
+
+	blocks, err := interpreter.ReadCoverProfile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, blocks) {
+		t.Fatalf("unexpected blocks read from profile -want/+got:\n%s", cmp.Diff(want, blocks))
+	}
+}
+
+func TestReadCoverProfile_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		profile string
+	}{
+		{name: "empty", profile: ""},
+		{name: "missing header", profile: "main:1.1,1.5 stmt 1\n"},
+		{name: "missing count", profile: "mode: count\nmain:1.1,1.5 stmt\n"},
+		{name: "invalid location", profile: "mode: count\nmain:1.1 stmt 1\n"},
+		{name: "unknown kind", profile: "mode: count\nmain:1.1,1.5 expr 1\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := interpreter.ReadCoverProfile(strings.NewReader(tc.profile)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ const (
 	PackageMain = "main"
 	NowPkg      = "universe"
 	NowOption   = "now"
+
+	LocationOption = "location"
 )
 
 // This interface is used by the interpreter to set options that are relevant
@@ -31,6 +33,7 @@ const (
 type ExecOptsConfig interface {
 	ConfigureProfiler(ctx context.Context, profilerNames []string)
 	ConfigureNow(ctx context.Context, now time.Time)
+	ConfigureLocation(ctx context.Context, location values.Object)
 }
 
 // A default execution options implementation that discards the settings.
@@ -38,6 +41,7 @@ type defExecOptsConfig struct{}
 
 func (es *defExecOptsConfig) ConfigureProfiler(ctx context.Context, profilerNames []string) {}
 func (es *defExecOptsConfig) ConfigureNow(ctx context.Context, now time.Time)               {}
+func (es *defExecOptsConfig) ConfigureLocation(ctx context.Context, location values.Object) {}
 
 type Interpreter struct {
 	sideEffects    []SideEffect // a list of the side effects occurred during the last call to `Eval`.
@@ -155,6 +159,7 @@ func (itrp *Interpreter) doImport(dec *semantic.ImportDeclaration, scope values.
 // doStatement returns the resolved value of a top-level statement
 func (itrp *Interpreter) doStatement(ctx context.Context, stmt semantic.Statement, scope values.Scope) (values.Value, error) {
 	scope.SetReturn(values.InvalidValue)
+	recordCoverage(ctx, stmt.Location())
 	switch s := stmt.(type) {
 	case *semantic.OptionStatement:
 		return itrp.doOptionStatement(ctx, s, scope)
@@ -203,6 +208,15 @@ func (irtp *Interpreter) evaluateNowOption(ctx context.Context, name string, ini
 	irtp.execOptsConfig.ConfigureNow(ctx, now)
 }
 
+// If the option is "location", store it in the execution dependencies
+// so that window can use it as its default location.
+func (irtp *Interpreter) evaluateLocationOption(ctx context.Context, name string, init values.Value) {
+	if name != LocationOption || init.Type().Nature() != semantic.Object {
+		return
+	}
+	irtp.execOptsConfig.ConfigureLocation(ctx, init.Object())
+}
+
 func convert(rules values.Array) ([]string, error) {
 	noRules := rules.Len()
 	rs := make([]string, noRules)
@@ -238,6 +252,7 @@ func (itrp *Interpreter) doOptionStatement(ctx context.Context, s *semantic.Opti
 		// (eg tableFind). For those cases we immediately evaluate and store it
 		// in the execution deps.
 		itrp.evaluateNowOption(ctx, a.Identifier.Name, init)
+		itrp.evaluateLocationOption(ctx, a.Identifier.Name, init)
 
 		// Retrieve an option with the name from the scope.
 		// If it exists and is an option, then set the option
@@ -384,6 +399,8 @@ func (itrp *Interpreter) doExpression(ctx context.Context, expr semantic.Express
 				return values.NewFloat(-v.Float()), nil
 			case semantic.Duration:
 				return values.NewDuration(v.Duration().Mul(-1)), nil
+			case semantic.Decimal:
+				return values.NewDecimal(v.Decimal().Neg()), nil
 			default:
 				return nil, errors.Newf(codes.Invalid, "operand to unary expression is not a number value, got %v", v.Type())
 			}
@@ -456,8 +473,10 @@ func (itrp *Interpreter) doExpression(ctx context.Context, expr semantic.Express
 			return nil, errors.New(codes.Invalid, "conditional test expression is not a boolean value")
 		}
 		if t.Bool() {
+			recordCoverage(ctx, e.Consequent.Location())
 			return itrp.doExpression(ctx, e.Consequent, scope)
 		}
+		recordCoverage(ctx, e.Alternate.Location())
 		return itrp.doExpression(ctx, e.Alternate, scope)
 	case *semantic.FunctionExpression:
 		// In the case of builtin functions this function value is shared across all query requests
@@ -803,6 +822,9 @@ func (f function) Time() values.Time {
 func (f function) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Duration))
 }
+func (f function) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 func (f function) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Function, semantic.Regexp))
 }
@@ -1613,6 +1635,7 @@ type contextKey int
 
 const (
 	callStackKey contextKey = iota
+	coverageContextKey
 )
 
 // StackEntry describes a single entry in the call stack.
@@ -110,6 +110,9 @@ func (p *Package) Time() values.Time {
 func (p *Package) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Object, semantic.Duration))
 }
+func (p *Package) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Object, semantic.Decimal))
+}
 func (p *Package) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Object, semantic.Regexp))
 }
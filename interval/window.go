@@ -1,6 +1,8 @@
 package interval
 
 import (
+	"time"
+
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/values"
@@ -34,6 +36,11 @@ type Window struct {
 	period     values.Duration
 	zero       values.Time
 	zeroMonths int64
+	// loc is the location whose wall clock is used to compute
+	// the boundaries. The zero and every values are in terms of the
+	// wall clock time which is then converted back to an absolute time.
+	// When nil, the boundaries are computed in UTC.
+	loc *time.Location
 }
 
 // NewWindow creates a window which can be used to determine the boundaries for a given point.
@@ -55,6 +62,22 @@ func NewWindow(every, period, offset values.Duration) (Window, error) {
 	return w, nil
 }
 
+// NewWindowInLocation creates a window like NewWindow, but the boundaries
+// are computed using the wall clock of the given location rather than UTC.
+// Boundaries are aligned to the local midnight and calendar of the location
+// so a window that is one day long will be 23 or 25 hours long on the days
+// of a daylight saving transition.
+func NewWindowInLocation(every, period, offset values.Duration, loc *time.Location) (Window, error) {
+	w, err := NewWindow(every, period, offset)
+	if err != nil {
+		return Window{}, err
+	}
+	if loc != time.UTC {
+		w.loc = loc
+	}
+	return w, nil
+}
+
 // IsZero checks if the window's every duration is zero
 func (w Window) IsZero() bool {
 	return w.every.IsZero()
@@ -89,16 +112,9 @@ func (w Window) GetLatestBounds(t values.Time) Bounds {
 	// Get the latest index that should contain the time t
 	index := w.lastIndex(t)
 	// Construct the bounds from the index
-	start := w.zero.Add(w.every.Mul(index))
-	b := Bounds{
-		start: start,
-		stop:  start.Add(w.period),
-		index: index,
-	}
+	b := w.boundsAt(index)
 	// If the period is negative its possible future bounds can still contain this point
 	if w.period.IsNegative() {
-		// swap start and stop since the period was negative
-		b.start, b.stop = b.stop, b.start
 		// If period is NOT mixed we can do a direct calculation
 		// to determine how far into the future a bounds may be found.
 		if !w.period.IsMixed() {
@@ -144,7 +160,9 @@ func (w Window) GetOverlappingBounds(start, stop values.Time) []Bounds {
 
 	curr := w.GetLatestBounds(stop)
 	for curr.stop > start {
-		if curr.Overlaps(bounds) {
+		// Bounds in a location may be empty when the wall clock
+		// times they cover are skipped by a daylight saving transition.
+		if curr.Overlaps(bounds) && (w.loc == nil || !curr.IsEmpty()) {
 			bs = append(bs, curr)
 		}
 		curr = w.PrevBounds(curr)
@@ -155,36 +173,32 @@ func (w Window) GetOverlappingBounds(start, stop values.Time) []Bounds {
 
 // NextBounds returns the next boundary in sequence from the given boundary.
 func (w Window) NextBounds(b Bounds) Bounds {
-	index := b.index + 1
-	start := w.zero.Add(w.every.Mul(index))
-	stop := start.Add(w.period)
-	if w.period.IsNegative() {
-		start, stop = stop, start
-	}
-	return Bounds{
-		start: start,
-		stop:  stop,
-		index: index,
-	}
+	return w.boundsAt(b.index + 1)
 }
 
 // PrevBounds returns the previous boundary in sequence from the given boundary.
 func (w Window) PrevBounds(b Bounds) Bounds {
-	index := b.index - 1
+	return w.boundsAt(b.index - 1)
+}
+
+// boundsAt constructs the bounds for the given index.
+func (w Window) boundsAt(index int) Bounds {
 	start := w.zero.Add(w.every.Mul(index))
 	stop := start.Add(w.period)
 	if w.period.IsNegative() {
 		start, stop = stop, start
 	}
 	return Bounds{
-		start: start,
-		stop:  stop,
+		start: start.FromLocal(w.loc),
+		stop:  stop.FromLocal(w.loc),
 		index: index,
 	}
 }
 
 // lastIndex will compute the index of the last bounds to contain t
 func (w Window) lastIndex(t values.Time) int {
+	// The zero is in terms of the wall clock so do the same to the target.
+	t = t.ToLocal(w.loc)
 	// We treat both nanoseconds and months as the space of whole numbers (aka integers).
 	// This keeps the math the same once we transform into the correct space.
 	//    For months, we operate in the number of months since the epoch.
@@ -1007,6 +1007,144 @@ func TestWindow_PrevBounds(t *testing.T) {
 	}
 }
 
+func TestWindow_InLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testcases := []struct {
+		name string
+		w    interval.Window
+		b    testBounds
+		want []testBounds
+	}{
+		{
+			name: "daily across start of daylight saving time",
+			w: mustWindowInLocation(
+				mustDuration("1d"),
+				mustDuration("1d"),
+				mustDuration("0s"),
+				berlin,
+			),
+			b: testBounds{
+				Start: mustTime("2021-03-27T12:00:00Z"),
+				Stop:  mustTime("2021-03-29T12:00:00Z"),
+			},
+			want: []testBounds{
+				{
+					Start: mustTime("2021-03-28T22:00:00Z"),
+					Stop:  mustTime("2021-03-29T22:00:00Z"),
+				},
+				{
+					Start: mustTime("2021-03-27T23:00:00Z"),
+					Stop:  mustTime("2021-03-28T22:00:00Z"),
+				},
+				{
+					Start: mustTime("2021-03-26T23:00:00Z"),
+					Stop:  mustTime("2021-03-27T23:00:00Z"),
+				},
+			},
+		},
+		{
+			name: "daily across end of daylight saving time",
+			w: mustWindowInLocation(
+				mustDuration("1d"),
+				mustDuration("1d"),
+				mustDuration("0s"),
+				berlin,
+			),
+			b: testBounds{
+				Start: mustTime("2021-10-31T12:00:00Z"),
+				Stop:  mustTime("2021-10-31T13:00:00Z"),
+			},
+			want: []testBounds{
+				{
+					Start: mustTime("2021-10-30T22:00:00Z"),
+					Stop:  mustTime("2021-10-31T23:00:00Z"),
+				},
+			},
+		},
+		{
+			name: "hourly across start of daylight saving time",
+			w: mustWindowInLocation(
+				mustDuration("1h"),
+				mustDuration("1h"),
+				mustDuration("0s"),
+				berlin,
+			),
+			b: testBounds{
+				Start: mustTime("2021-03-28T00:00:00Z"),
+				Stop:  mustTime("2021-03-28T02:00:00Z"),
+			},
+			want: []testBounds{
+				{
+					Start: mustTime("2021-03-28T01:00:00Z"),
+					Stop:  mustTime("2021-03-28T02:00:00Z"),
+				},
+				{
+					Start: mustTime("2021-03-28T00:00:00Z"),
+					Stop:  mustTime("2021-03-28T01:00:00Z"),
+				},
+			},
+		},
+		{
+			name: "monthly with offset",
+			w: mustWindowInLocation(
+				mustDuration("1mo"),
+				mustDuration("1mo"),
+				mustDuration("6h"),
+				berlin,
+			),
+			b: testBounds{
+				Start: mustTime("2021-03-15T00:00:00Z"),
+				Stop:  mustTime("2021-04-15T00:00:00Z"),
+			},
+			want: []testBounds{
+				{
+					Start: mustTime("2021-04-01T04:00:00Z"),
+					Stop:  mustTime("2021-05-01T04:00:00Z"),
+				},
+				{
+					Start: mustTime("2021-03-01T05:00:00Z"),
+					Stop:  mustTime("2021-04-01T04:00:00Z"),
+				},
+			},
+		},
+		{
+			name: "fixed offset",
+			w: mustWindowInLocation(
+				mustDuration("1d"),
+				mustDuration("1d"),
+				mustDuration("0s"),
+				time.FixedZone("", -8*60*60),
+			),
+			b: testBounds{
+				Start: mustTime("2021-01-01T00:00:00Z"),
+				Stop:  mustTime("2021-01-01T12:00:00Z"),
+			},
+			want: []testBounds{
+				{
+					Start: mustTime("2021-01-01T08:00:00Z"),
+					Stop:  mustTime("2021-01-02T08:00:00Z"),
+				},
+				{
+					Start: mustTime("2020-12-31T08:00:00Z"),
+					Stop:  mustTime("2021-01-01T08:00:00Z"),
+				},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := transformBounds(tc.w.GetOverlappingBounds(tc.b.Start, tc.b.Stop))
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("got unexpected bounds; -want/+got:\n%v\n", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
 func mustWindow(every, period, offset values.Duration) interval.Window {
 	w, err := interval.NewWindow(every, period, offset)
 	if err != nil {
@@ -1015,6 +1153,14 @@ func mustWindow(every, period, offset values.Duration) interval.Window {
 	return w
 }
 
+func mustWindowInLocation(every, period, offset values.Duration, loc *time.Location) interval.Window {
+	w, err := interval.NewWindowInLocation(every, period, offset, loc)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
 func mustTime(s string) values.Time {
 	t, err := time.Parse(time.RFC3339Nano, s)
 	if err != nil {
@@ -0,0 +1,35 @@
+package ipc
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const DialectType = "arrow"
+
+// AddDialectMappings adds the arrow specific dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return &Dialect{}
+	})
+}
+
+// Dialect describes the output format of queries as Arrow IPC streams.
+type Dialect struct{}
+
+func (d Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/vnd.apache.arrow.stream")
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+func (d Dialect) Encoder() flux.MultiResultEncoder {
+	return NewMultiResultEncoder(nil)
+}
+func (d Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
+
+func DefaultDialect() *Dialect {
+	return &Dialect{}
+}
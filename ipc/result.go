@@ -0,0 +1,674 @@
+// Package ipc contains the Arrow IPC result encoders and decoders.
+//
+// Each table is written as its own Arrow IPC stream: a schema message,
+// followed by one record batch for each buffer in the table and
+// an end-of-stream marker. The streams for all of the tables in all
+// of the results are written one after the other.
+//
+// The schema of each stream holds the following metadata:
+//
+//	flux.result     The name of the result the table belongs to.
+//	flux.group_key  The group key of the table as a JSON list of
+//	                {"column": label, "value": value} objects.
+//	                The values are formatted the same way they are
+//	                in annotated CSV and are null for null values.
+//	flux.error      Set instead of the other keys when an error occurred
+//	                while producing the results. The stream has no fields.
+//
+// Flux column types map to the arrow types int64, uint64, float64, utf8,
+// bool, timestamp with nanosecond precision in UTC, decimal128 with
+// a precision of 38 and a scale of 9, duration with nanosecond precision
+// and binary.
+package ipc
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	arrowlib "github.com/apache/arrow/go/arrow"
+	arrowarray "github.com/apache/arrow/go/arrow/array"
+	arrowipc "github.com/apache/arrow/go/arrow/ipc"
+	arrowmemory "github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	resultMetadataKey   = "flux.result"
+	groupKeyMetadataKey = "flux.group_key"
+	errorMetadataKey    = "flux.error"
+)
+
+var (
+	timeType = &arrowlib.TimestampType{
+		Unit:     arrowlib.Nanosecond,
+		TimeZone: "UTC",
+	}
+	durationType = &arrowlib.DurationType{Unit: arrowlib.Nanosecond}
+)
+
+// ResultEncoder encodes a result as a series of Arrow IPC streams,
+// one for each table.
+type ResultEncoder struct {
+	mem arrowmemory.Allocator
+}
+
+// NewResultEncoder creates a new ResultEncoder.
+// The allocator is used for the buffers that need to be built
+// when the table data cannot be written directly.
+// If it is nil, an unlimited allocator is used.
+func NewResultEncoder(mem arrowmemory.Allocator) *ResultEncoder {
+	if mem == nil {
+		mem = arrow.NewAllocator(new(memory.Allocator))
+	}
+	return &ResultEncoder{mem: mem}
+}
+
+type ipcEncoderError struct {
+	err error
+}
+
+func (e *ipcEncoderError) Error() string {
+	return fmt.Sprintf("arrow ipc encoder error: %s", e.err.Error())
+}
+
+func (e *ipcEncoderError) IsEncoderError() bool {
+	return true
+}
+
+func (e *ipcEncoderError) Unwrap() error {
+	return e.err
+}
+
+func wrapEncodingError(err error) error {
+	if err == nil {
+		return err
+	}
+	return &ipcEncoderError{err: err}
+}
+
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	name := result.Name()
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		return e.encodeTable(wc, name, tbl)
+	})
+	return wc.Count(), err
+}
+
+func (e *ResultEncoder) encodeTable(w io.Writer, name string, tbl flux.Table) error {
+	schema, err := newSchema(name, tbl.Key(), tbl.Cols())
+	if err != nil {
+		tbl.Done()
+		return wrapEncodingError(err)
+	}
+
+	writer := arrowipc.NewWriter(w, arrowipc.WithSchema(schema), arrowipc.WithAllocator(e.mem))
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		rec := e.newRecord(schema, cr)
+		defer rec.Release()
+		return wrapEncodingError(writer.Write(rec))
+	}); err != nil {
+		return err
+	}
+	return wrapEncodingError(writer.Close())
+}
+
+// newRecord creates an arrow record from the columns of a buffer.
+func (e *ResultEncoder) newRecord(schema *arrowlib.Schema, cr flux.ColReader) arrowarray.Record {
+	cols := make([]arrowarray.Interface, len(cr.Cols()))
+	for j, c := range cr.Cols() {
+		switch c.Type {
+		case flux.TInt:
+			cols[j] = cr.Ints(j)
+			cols[j].Retain()
+		case flux.TUInt:
+			cols[j] = cr.UInts(j)
+			cols[j].Retain()
+		case flux.TFloat:
+			cols[j] = cr.Floats(j)
+			cols[j].Retain()
+		case flux.TBool:
+			cols[j] = cr.Bools(j)
+			cols[j].Retain()
+		case flux.TString:
+			data := cr.Strings(j).Data(e.mem)
+			cols[j] = arrowarray.MakeFromData(data)
+			data.Release()
+		case flux.TTime:
+			cols[j] = withType(cr.Times(j).Data(), timeType)
+		case flux.TDecimal:
+			cols[j] = cr.Decimals(j)
+			cols[j].Retain()
+		case flux.TDuration:
+			cols[j] = withType(cr.Durations(j).Data(), durationType)
+		case flux.TBytes:
+			cols[j] = cr.Bytes(j)
+			cols[j].Retain()
+		}
+	}
+	rec := arrowarray.NewRecord(schema, cols, int64(cr.Len()))
+	for _, col := range cols {
+		col.Release()
+	}
+	return rec
+}
+
+// EncodeError writes a stream that holds the error in its schema metadata.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	md := arrowlib.NewMetadata([]string{errorMetadataKey}, []string{err.Error()})
+	writer := arrowipc.NewWriter(w, arrowipc.WithSchema(arrowlib.NewSchema(nil, &md)), arrowipc.WithAllocator(e.mem))
+	return writer.Close()
+}
+
+// NewMultiResultEncoder creates a MultiResultEncoder
+// that writes the tables of each result one after the other.
+func NewMultiResultEncoder(mem arrowmemory.Allocator) flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Encoder: NewResultEncoder(mem),
+	}
+}
+
+type groupKeyColumn struct {
+	Column string  `json:"column"`
+	Value  *string `json:"value"`
+}
+
+func newSchema(name string, key flux.GroupKey, cols []flux.ColMeta) (*arrowlib.Schema, error) {
+	fields := make([]arrowlib.Field, len(cols))
+	for j, c := range cols {
+		typ, err := toArrowType(c.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[j] = arrowlib.Field{
+			Name:     c.Label,
+			Type:     typ,
+			Nullable: true,
+		}
+	}
+
+	keyCols := make([]groupKeyColumn, len(key.Cols()))
+	for j, c := range key.Cols() {
+		keyCols[j].Column = c.Label
+		if v := key.Value(j); !v.IsNull() {
+			s, err := encodeValue(v)
+			if err != nil {
+				return nil, err
+			}
+			keyCols[j].Value = &s
+		}
+	}
+	keyJSON, err := json.Marshal(keyCols)
+	if err != nil {
+		return nil, err
+	}
+
+	md := arrowlib.NewMetadata(
+		[]string{resultMetadataKey, groupKeyMetadataKey},
+		[]string{name, string(keyJSON)},
+	)
+	return arrowlib.NewSchema(fields, &md), nil
+}
+
+func toArrowType(typ flux.ColType) (arrowlib.DataType, error) {
+	switch typ {
+	case flux.TInt:
+		return arrowlib.PrimitiveTypes.Int64, nil
+	case flux.TUInt:
+		return arrowlib.PrimitiveTypes.Uint64, nil
+	case flux.TFloat:
+		return arrowlib.PrimitiveTypes.Float64, nil
+	case flux.TString:
+		return arrowlib.BinaryTypes.String, nil
+	case flux.TBool:
+		return arrowlib.FixedWidthTypes.Boolean, nil
+	case flux.TTime:
+		return timeType, nil
+	case flux.TDecimal:
+		return array.DecimalType, nil
+	case flux.TDuration:
+		return durationType, nil
+	case flux.TBytes:
+		return array.BinaryType, nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unsupported column type: %s", typ)
+	}
+}
+
+func fromArrowType(typ arrowlib.DataType) (flux.ColType, error) {
+	switch typ.ID() {
+	case arrowlib.INT64:
+		return flux.TInt, nil
+	case arrowlib.UINT64:
+		return flux.TUInt, nil
+	case arrowlib.FLOAT64:
+		return flux.TFloat, nil
+	case arrowlib.STRING:
+		return flux.TString, nil
+	case arrowlib.BOOL:
+		return flux.TBool, nil
+	case arrowlib.TIMESTAMP:
+		if typ.(*arrowlib.TimestampType).Unit == arrowlib.Nanosecond {
+			return flux.TTime, nil
+		}
+	case arrowlib.DECIMAL:
+		// The values are only the same decimals with the same scale.
+		if typ.(*arrowlib.Decimal128Type).Scale == array.DecimalType.Scale {
+			return flux.TDecimal, nil
+		}
+	case arrowlib.DURATION:
+		if typ.(*arrowlib.DurationType).Unit == arrowlib.Nanosecond {
+			return flux.TDuration, nil
+		}
+	case arrowlib.BINARY:
+		return flux.TBytes, nil
+	}
+	return flux.TInvalid, errors.Newf(codes.Invalid, "unsupported arrow type: %s", typ)
+}
+
+// withType creates an array with the given data type
+// that shares the buffers of the data.
+func withType(data *arrowarray.Data, typ arrowlib.DataType) arrowarray.Interface {
+	data = arrowarray.NewData(typ, data.Len(), data.Buffers(), nil, data.NullN(), data.Offset())
+	defer data.Release()
+	return arrowarray.MakeFromData(data)
+}
+
+func encodeValue(v values.Value) (string, error) {
+	switch v.Type().Nature() {
+	case semantic.Int:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case semantic.UInt:
+		return strconv.FormatUint(v.UInt(), 10), nil
+	case semantic.Float:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case semantic.Time:
+		return v.Time().Time().Format(time.RFC3339Nano), nil
+	case semantic.Decimal:
+		return v.Decimal().String(), nil
+	case semantic.Duration:
+		nsecs, err := v.Duration().FixedNanoseconds()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(nsecs, 10), nil
+	case semantic.Bytes:
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+	default:
+		return "", errors.Newf(codes.Internal, "unsupported group key value type: %v", v.Type())
+	}
+}
+
+func decodeValue(s string, typ flux.ColType) (values.Value, error) {
+	switch typ {
+	case flux.TInt:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewInt(v), nil
+	case flux.TUInt:
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewUInt(v), nil
+	case flux.TFloat:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewFloat(v), nil
+	case flux.TString:
+		return values.NewString(s), nil
+	case flux.TBool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewBool(v), nil
+	case flux.TTime:
+		v, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewTime(values.ConvertTime(v)), nil
+	case flux.TDecimal:
+		v, err := values.ParseDecimal(s)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewDecimal(v), nil
+	case flux.TDuration:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewDuration(values.ConvertDurationNsecs(time.Duration(v))), nil
+	case flux.TBytes:
+		v, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return values.NewBytes(v), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unsupported group key value type: %s", typ)
+	}
+}
+
+// ResultDecoderConfig are options that can be specified on the decoders.
+type ResultDecoderConfig struct {
+	// Allocator is the memory allocator that will be used during decoding.
+	// The default is to use an unlimited allocator when this is not set.
+	Allocator arrowmemory.Allocator
+}
+
+func (c ResultDecoderConfig) allocator() arrowmemory.Allocator {
+	if c.Allocator == nil {
+		return arrow.NewAllocator(new(memory.Allocator))
+	}
+	return c.Allocator
+}
+
+// ResultDecoder decodes a single result from a series of Arrow IPC streams.
+type ResultDecoder struct {
+	c ResultDecoderConfig
+}
+
+// NewResultDecoder creates a new ResultDecoder.
+func NewResultDecoder(c ResultDecoderConfig) *ResultDecoder {
+	return &ResultDecoder{c: c}
+}
+
+// Decode decodes the tables of the first result that was written to r.
+func (d *ResultDecoder) Decode(r io.Reader) (flux.Result, error) {
+	s := &streamReader{
+		r:   bufio.NewReader(r),
+		mem: d.c.allocator(),
+	}
+	res, err := s.nextResult()
+	if err == io.EOF {
+		return nil, errors.New(codes.Invalid, "no result found in arrow stream")
+	}
+	return res, err
+}
+
+// MultiResultDecoder decodes all of the results from a series of Arrow IPC streams.
+// The tables for a result are the consecutive streams with the same result name.
+type MultiResultDecoder struct {
+	c ResultDecoderConfig
+}
+
+// NewMultiResultDecoder creates a new MultiResultDecoder.
+func NewMultiResultDecoder(c ResultDecoderConfig) *MultiResultDecoder {
+	return &MultiResultDecoder{c: c}
+}
+
+func (d *MultiResultDecoder) Decode(r io.ReadCloser) (flux.ResultIterator, error) {
+	return &resultIterator{
+		r: r,
+		s: &streamReader{
+			r:   bufio.NewReader(r),
+			mem: d.c.allocator(),
+		},
+	}, nil
+}
+
+// resultIterator iterates through the results encoded in r.
+type resultIterator struct {
+	r    io.ReadCloser
+	s    *streamReader
+	next *resultDecoder
+	err  error
+
+	released bool
+}
+
+func (r *resultIterator) More() bool {
+	if r.err == nil {
+		// Skip over any tables that were not read from the last result.
+		if r.next != nil {
+			r.err = r.next.Do(func(tbl flux.Table) error {
+				tbl.Done()
+				return nil
+			})
+		}
+		if r.err == nil {
+			r.next, r.err = r.s.nextResult()
+			if r.err == nil {
+				return true
+			}
+			if r.err == io.EOF {
+				// Do not report EOF errors
+				r.err = nil
+			}
+		}
+	}
+
+	// Release the resources for this query.
+	r.Release()
+	return false
+}
+
+func (r *resultIterator) Next() flux.Result {
+	return r.next
+}
+
+func (r *resultIterator) Release() {
+	if r.released {
+		return
+	}
+
+	if err := r.r.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	r.released = true
+}
+
+func (r *resultIterator) Err() error {
+	return r.err
+}
+
+func (r *resultIterator) Statistics() flux.Statistics {
+	return flux.Statistics{}
+}
+
+// streamReader reads the Arrow IPC streams one after the other.
+type streamReader struct {
+	r   *bufio.Reader
+	mem arrowmemory.Allocator
+
+	// next is the stream for the first table of the next result.
+	next *arrowipc.Reader
+}
+
+// nextResult returns the next result in the stream.
+// It returns io.EOF when there are no more results.
+func (s *streamReader) nextResult() (*resultDecoder, error) {
+	next := s.next
+	if next == nil {
+		var err error
+		if next, err = s.open(); err != nil {
+			return nil, err
+		}
+	}
+	s.next = nil
+
+	name, _ := metadataValue(next.Schema(), resultMetadataKey)
+	return &resultDecoder{
+		name:  name,
+		s:     s,
+		first: next,
+	}, nil
+}
+
+// open reads the schema for the next stream.
+// It returns io.EOF when there are no more streams.
+func (s *streamReader) open() (*arrowipc.Reader, error) {
+	if _, err := s.r.Peek(1); err != nil {
+		return nil, err
+	}
+	rd, err := arrowipc.NewReader(s.r, arrowipc.WithAllocator(s.mem))
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read arrow stream")
+	}
+	if msg, ok := metadataValue(rd.Schema(), errorMetadataKey); ok {
+		rd.Release()
+		return nil, errors.New(codes.Unknown, msg)
+	}
+	return rd, nil
+}
+
+type resultDecoder struct {
+	name  string
+	s     *streamReader
+	first *arrowipc.Reader
+	done  bool
+}
+
+func (r *resultDecoder) Name() string {
+	return r.name
+}
+
+func (r *resultDecoder) Tables() flux.TableIterator {
+	return r
+}
+
+func (r *resultDecoder) Do(f func(flux.Table) error) error {
+	if r.done {
+		return nil
+	}
+	r.done = true
+
+	rd := r.first
+	r.first = nil
+	for {
+		tbl, err := r.s.readTable(rd)
+		if err != nil {
+			return err
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+
+		rd, err = r.s.open()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if name, _ := metadataValue(rd.Schema(), resultMetadataKey); name != r.name {
+			r.s.next = rd
+			return nil
+		}
+	}
+}
+
+func (r *resultDecoder) Statistics() flux.Statistics {
+	return flux.Statistics{}
+}
+
+// readTable reads all of the record batches of a stream into a table.
+func (s *streamReader) readTable(rd *arrowipc.Reader) (flux.Table, error) {
+	defer rd.Release()
+
+	schema := rd.Schema()
+	cols := make([]flux.ColMeta, len(schema.Fields()))
+	for j, field := range schema.Fields() {
+		typ, err := fromArrowType(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		cols[j] = flux.ColMeta{Label: field.Name, Type: typ}
+	}
+	key, err := decodeGroupKey(schema, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl := &table.BufferedTable{
+		GroupKey: key,
+		Columns:  cols,
+	}
+	for rd.Next() {
+		rec := rd.Record()
+		buf := &arrow.TableBuffer{
+			GroupKey: key,
+			Columns:  cols,
+			Values:   make([]array.Interface, len(cols)),
+		}
+		for j, c := range cols {
+			data := rec.Column(j).Data()
+			switch c.Type {
+			case flux.TString:
+				buf.Values[j] = array.NewStringData(data)
+			case flux.TTime, flux.TDuration:
+				buf.Values[j] = withType(data, arrowlib.PrimitiveTypes.Int64)
+			default:
+				buf.Values[j] = arrowarray.MakeFromData(data)
+			}
+		}
+		tbl.Buffers = append(tbl.Buffers, buf)
+	}
+	if err := rd.Err(); err != nil {
+		tbl.Done()
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read arrow record batch")
+	}
+	return tbl, nil
+}
+
+func metadataValue(schema *arrowlib.Schema, key string) (string, bool) {
+	md := schema.Metadata()
+	if i := md.FindKey(key); i >= 0 {
+		return md.Values()[i], true
+	}
+	return "", false
+}
+
+func decodeGroupKey(schema *arrowlib.Schema, cols []flux.ColMeta) (flux.GroupKey, error) {
+	var keyCols []groupKeyColumn
+	if s, ok := metadataValue(schema, groupKeyMetadataKey); ok {
+		if err := json.Unmarshal([]byte(s), &keyCols); err != nil {
+			return nil, errors.Wrap(err, codes.Invalid, "failed to decode group key")
+		}
+	}
+
+	kcols := make([]flux.ColMeta, len(keyCols))
+	kvals := make([]values.Value, len(keyCols))
+	for j, kc := range keyCols {
+		idx := execute.ColIdx(kc.Column, cols)
+		if idx < 0 {
+			return nil, errors.Newf(codes.Invalid, "group key column %q is not in the schema", kc.Column)
+		}
+		kcols[j] = cols[idx]
+		if kc.Value == nil {
+			kvals[j] = values.NewNull(flux.SemanticType(cols[idx].Type))
+			continue
+		}
+		v, err := decodeValue(*kc.Value, cols[idx].Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid value for group key column %q", kc.Column)
+		}
+		kvals[j] = v
+	}
+	return execute.NewGroupKey(kcols, kvals), nil
+}
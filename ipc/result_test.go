@@ -0,0 +1,253 @@
+package ipc_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	arrowipc "github.com/apache/arrow/go/arrow/ipc"
+	arrowmemory "github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/execute/table/static"
+	"github.com/influxdata/flux/ipc"
+	"github.com/influxdata/flux/values"
+)
+
+type result struct {
+	name   string
+	tables flux.TableIterator
+}
+
+func (r *result) Name() string               { return r.name }
+func (r *result) Tables() flux.TableIterator { return r.tables }
+
+// errorIterator produces the results and then reports err.
+type errorIterator struct {
+	flux.ResultIterator
+	err error
+}
+
+func (ri *errorIterator) Err() error { return ri.err }
+
+func newTables() static.TableGroup {
+	return static.TableGroup{
+		static.StringKey("_measurement", "cpu"),
+		static.TimeKey("_start", "2021-01-01T00:00:00Z"),
+		static.TableList{
+			static.Table{
+				static.StringKey("host", "a"),
+				static.Times("_time", 0, 10, 20),
+				static.Floats("_value", 1.5, nil, 3.5),
+				static.Ints("count", 1, 2, nil),
+				static.Uints("ucount", nil, 2, 3),
+				static.Strings("name", "x", nil, "z"),
+				static.Booleans("ok", true, false, nil),
+			},
+			static.Table{
+				static.StringKey("host", "b"),
+				static.Times("_time"),
+				static.Floats("_value"),
+				static.Ints("count"),
+				static.Uints("ucount"),
+				static.Strings("name"),
+				static.Booleans("ok"),
+			},
+		},
+	}
+}
+
+func newOtherTables() static.TableGroup {
+	return static.TableGroup{
+		static.IntKey("id", 7),
+		static.Table{
+			static.Times("_time", 0, 10),
+			static.Strings("_value", "a", "b"),
+		},
+	}
+}
+
+func TestMultiResultEncoder_RoundTrip(t *testing.T) {
+	mem := arrowmemory.NewCheckedAllocator(arrowmemory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&result{name: "_result", tables: newTables()},
+		&result{name: "other", tables: newOtherTables()},
+	})
+
+	var buf bytes.Buffer
+	enc := ipc.NewMultiResultEncoder(mem)
+	if _, err := enc.Encode(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := ipc.NewMultiResultDecoder(ipc.ResultDecoderConfig{Allocator: mem})
+	ri, err := dec.Decode(ioutil.NopCloser(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*result{
+		{name: "_result", tables: newTables()},
+		{name: "other", tables: newOtherTables()},
+	}
+	i := 0
+	for ri.More() {
+		if i >= len(want) {
+			t.Fatalf("unexpected result %q", ri.Next().Name())
+		}
+		res := ri.Next()
+		if got, want := res.Name(), want[i].name; got != want {
+			t.Errorf("unexpected result name -want/+got:\n\t- %s\n\t+ %s", want, got)
+		}
+		if diff := table.Diff(want[i].tables, res.Tables()); diff != "" {
+			t.Errorf("unexpected tables in result %q -want/+got:\n%s", want[i].name, diff)
+		}
+		i++
+	}
+	ri.Release()
+	if err := ri.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if i != len(want) {
+		t.Fatalf("unexpected number of results -want/+got:\n\t- %d\n\t+ %d", len(want), i)
+	}
+}
+
+func TestMultiResultEncoder_Schema(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&result{name: "_result", tables: newOtherTables()},
+	})
+
+	var buf bytes.Buffer
+	if _, err := ipc.NewMultiResultEncoder(nil).Encode(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each table must be readable as a plain Arrow IPC stream.
+	rd, err := arrowipc.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rd.Release()
+
+	schema := rd.Schema()
+	if got, want := schema.String(), strings.Join([]string{
+		"schema:",
+		"  fields: 3",
+		"    - id: type=int64, nullable",
+		"    - _time: type=timestamp[ns, tz=UTC], nullable",
+		"    - _value: type=utf8, nullable",
+		"  metadata: [\"flux.result\": \"_result\", \"flux.group_key\": \"[{\\\"column\\\":\\\"id\\\",\\\"value\\\":\\\"7\\\"}]\"]",
+	}, "\n"); got != want {
+		t.Errorf("unexpected schema -want/+got:\n\t- %s\n\t+ %s", want, got)
+	}
+
+	n := 0
+	for rd.Next() {
+		n += int(rd.Record().NumRows())
+	}
+	if got, want := n, 2; got != want {
+		t.Errorf("unexpected number of rows -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+}
+
+func TestMultiResultEncoder_Error(t *testing.T) {
+	results := &errorIterator{
+		ResultIterator: flux.NewSliceResultIterator([]flux.Result{
+			&result{name: "_result", tables: newOtherTables()},
+		}),
+		err: errors.New("expected error"),
+	}
+
+	var buf bytes.Buffer
+	if _, err := ipc.NewMultiResultEncoder(nil).Encode(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	dec := ipc.NewMultiResultDecoder(ipc.ResultDecoderConfig{})
+	ri, err := dec.Decode(ioutil.NopCloser(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for ri.More() {
+		n++
+	}
+	ri.Release()
+	if got, want := n, 1; got != want {
+		t.Errorf("unexpected number of results -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+	if err := ri.Err(); err == nil || !strings.Contains(err.Error(), "expected error") {
+		t.Errorf("expected error to be decoded, got %v", err)
+	}
+}
+
+// newTypedTables returns a table with the column types
+// that cannot be built with static tables.
+func newTypedTables() flux.TableIterator {
+	mem := arrowmemory.NewGoAllocator()
+	key := execute.NewGroupKey(
+		[]flux.ColMeta{{Label: "k", Type: flux.TDecimal}},
+		[]values.Value{values.NewDecimal(mustParseDecimal("-1.5"))},
+	)
+
+	k := array.NewDecimalBuilder(mem)
+	d := array.NewDecimalBuilder(mem)
+	for _, v := range []string{"0.000000001", "", "12345678901234567890.5"} {
+		k.Append(mustParseDecimal("-1.5").Num())
+		if v == "" {
+			d.AppendNull()
+			continue
+		}
+		d.Append(mustParseDecimal(v).Num())
+	}
+	dur := array.NewIntBuilder(mem)
+	dur.AppendValues([]int64{int64(time.Second), 0, -5}, []bool{true, false, true})
+	b := array.NewBinaryBuilder(mem)
+	b.AppendValues([][]byte{{0xff}, {}, nil}, []bool{true, true, false})
+	return table.Iterator{table.FromBuffer(&arrow.TableBuffer{
+		GroupKey: key,
+		Columns: []flux.ColMeta{
+			{Label: "k", Type: flux.TDecimal},
+			{Label: "d", Type: flux.TDecimal},
+			{Label: "dur", Type: flux.TDuration},
+			{Label: "b", Type: flux.TBytes},
+		},
+		Values: []array.Interface{k.NewArray(), d.NewArray(), dur.NewArray(), b.NewArray()},
+	})}
+}
+
+func mustParseDecimal(s string) values.Decimal {
+	v, err := values.ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestResultEncoder_RoundTripTypes(t *testing.T) {
+	mem := arrowmemory.NewCheckedAllocator(arrowmemory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+
+	var buf bytes.Buffer
+	if _, err := ipc.NewResultEncoder(mem).Encode(&buf, &result{name: "_result", tables: newTypedTables()}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := ipc.NewResultDecoder(ipc.ResultDecoderConfig{Allocator: mem}).Decode(ioutil.NopCloser(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := table.Diff(newTypedTables(), res.Tables()); diff != "" {
+		t.Errorf("unexpected tables -want/+got:\n%s", diff)
+	}
+}
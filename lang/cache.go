@@ -0,0 +1,225 @@
+package lang
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// SemanticRuntime is a runtime that can analyze a package once
+// and evaluate the analyzed package many times.
+// Programs only use a ProgramCache when their runtime implements it.
+type SemanticRuntime interface {
+	flux.Runtime
+
+	// Analyze analyzes the AST into a semantic graph.
+	Analyze(astPkg flux.ASTHandle) (*semantic.Package, error)
+
+	// EvalSemantic evaluates a semantic graph that was produced by Analyze.
+	EvalSemantic(ctx context.Context, semPkg *semantic.Package, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error)
+
+	// StdlibVersion identifies the standard library
+	// that the semantic graphs are analyzed against.
+	StdlibVersion() string
+}
+
+// ProgramCacheKey identifies a cached program.
+type ProgramCacheKey struct {
+	// Hash is the hash of the script of the program,
+	// its extern and the types of its params.
+	Hash string
+	// StdlibVersion is the version of the standard
+	// library that the program was analyzed against.
+	StdlibVersion string
+}
+
+type programCacheEntry struct {
+	key ProgramCacheKey
+	pkg *semantic.Package
+}
+
+// ProgramCache caches the semantic graphs of compiled programs so a script
+// that is executed many times with a different now and different params
+// is parsed and analyzed only once.
+//
+// The programs are keyed by the hash of the script and the version of the
+// standard library, so upgrading the standard library never reuses a stale
+// semantic graph.
+//
+// The scope of the cache is the semantic graph; logical plans are not
+// cached. A logical plan is built from the side effects of evaluating the
+// graph, and the procedure specs may hold values that evaluation computed
+// from the now time and the params, for example a range that starts at
+// date.truncate(t: now(), unit: 1d) or a filter on params.host. A script
+// may even produce a different set of operations for other param values.
+// Reusing a plan would require the specs to keep their now and param
+// dependent values unresolved until execution, so every execution still
+// evaluates the cached graph and plans the result.
+//
+// A ProgramCache is safe for concurrent use.
+type ProgramCache struct {
+	mu           sync.Mutex
+	size         int
+	entries      map[ProgramCacheKey]*list.Element
+	lru          *list.List
+	onInvalidate []func(key ProgramCacheKey)
+
+	hits, misses int64
+}
+
+// NewProgramCache creates a cache that holds at most size programs.
+// The least recently used program is evicted when the cache is full.
+func NewProgramCache(size int) *ProgramCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &ProgramCache{
+		size:    size,
+		entries: make(map[ProgramCacheKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Len returns the number of cached programs.
+func (c *ProgramCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Hits returns the number of compilations that reused a cached program.
+func (c *ProgramCache) Hits() int64 {
+	return atomic.LoadInt64(&c.hits)
+}
+
+// Misses returns the number of compilations that did not find a cached program.
+func (c *ProgramCache) Misses() int64 {
+	return atomic.LoadInt64(&c.misses)
+}
+
+// OnInvalidate registers a function that is called with the key
+// of each program that is invalidated or evicted from the cache.
+// The function is called while the cache is locked so it must not use the cache.
+func (c *ProgramCache) OnInvalidate(fn func(key ProgramCacheKey)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onInvalidate = append(c.onInvalidate, fn)
+}
+
+// Invalidate removes the program with the key from the cache.
+func (c *ProgramCache) Invalidate(key ProgramCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+}
+
+// InvalidateIf removes each program whose key matches the predicate.
+// Pass a predicate that compares the StdlibVersion to drop the programs
+// that were analyzed against another version of the standard library.
+func (c *ProgramCache) InvalidateIf(pred func(key ProgramCacheKey) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if pred(key) {
+			c.remove(e)
+		}
+	}
+}
+
+// InvalidateAll removes every program from the cache.
+func (c *ProgramCache) InvalidateAll() {
+	c.InvalidateIf(func(ProgramCacheKey) bool { return true })
+}
+
+func (c *ProgramCache) get(key ProgramCacheKey) (*semantic.Package, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddInt64(&c.hits, 1)
+	c.lru.MoveToFront(e)
+	return e.Value.(*programCacheEntry).pkg, true
+}
+
+func (c *ProgramCache) add(key ProgramCacheKey, pkg *semantic.Package) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*programCacheEntry).pkg = pkg
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&programCacheEntry{key: key, pkg: pkg})
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *ProgramCache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*programCacheEntry)
+	delete(c.entries, entry.key)
+	for _, fn := range c.onInvalidate {
+		fn(entry.key)
+	}
+}
+
+type cacheContextKey struct{}
+
+// ContextWithCache returns a context that makes the Flux and AST
+// compilers cache the programs they compile in the program cache.
+func ContextWithCache(ctx context.Context, c *ProgramCache) context.Context {
+	return context.WithValue(ctx, cacheContextKey{}, c)
+}
+
+// CacheFromContext returns the program cache of the context, or nil if there is none.
+func CacheFromContext(ctx context.Context) *ProgramCache {
+	c, _ := ctx.Value(cacheContextKey{}).(*ProgramCache)
+	return c
+}
+
+// programCacheKey computes the key of a program from its source, which is
+// either the script or the JSON of its AST, and the options it is compiled with.
+func programCacheKey(r SemanticRuntime, kind string, src []byte, o *compileOptions) (ProgramCacheKey, error) {
+	h := sha256.New()
+	_, _ = h.Write([]byte(kind))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(src)
+	if o.extern != nil {
+		extern, err := o.extern.Format()
+		if err != nil {
+			return ProgramCacheKey{}, err
+		}
+		_, _ = h.Write([]byte("\x00extern\x00"))
+		_, _ = h.Write([]byte(extern))
+	}
+	if o.params != nil {
+		file, err := ParamsFile(o.params)
+		if err != nil {
+			return ProgramCacheKey{}, err
+		}
+		bs, err := json.Marshal(file)
+		if err != nil {
+			return ProgramCacheKey{}, err
+		}
+		_, _ = h.Write([]byte("\x00params\x00"))
+		_, _ = h.Write(bs)
+	}
+	return ProgramCacheKey{
+		Hash:          hex.EncodeToString(h.Sum(nil)),
+		StdlibVersion: r.StdlibVersion(),
+	}, nil
+}
@@ -0,0 +1,193 @@
+package lang
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+type cacheTestHandle struct {
+	src string
+}
+
+func (h *cacheTestHandle) ASTHandle()              {}
+func (h *cacheTestHandle) Format() (string, error) { return h.src, nil }
+func (h *cacheTestHandle) GetError() error         { return nil }
+
+// cacheTestRuntime counts the scripts it parses and analyzes.
+type cacheTestRuntime struct {
+	flux.Runtime
+	version  string
+	parsed   int
+	analyzed int
+}
+
+func (r *cacheTestRuntime) Parse(src string) (flux.ASTHandle, error) {
+	r.parsed++
+	return &cacheTestHandle{src: src}, nil
+}
+
+func (r *cacheTestRuntime) JSONToHandle(json []byte) (flux.ASTHandle, error) {
+	return &cacheTestHandle{src: string(json)}, nil
+}
+
+func (r *cacheTestRuntime) MergePackages(dst, src flux.ASTHandle) error {
+	dst.(*cacheTestHandle).src += "\n" + src.(*cacheTestHandle).src
+	return nil
+}
+
+func (r *cacheTestRuntime) Analyze(astPkg flux.ASTHandle) (*semantic.Package, error) {
+	r.analyzed++
+	return &semantic.Package{Package: astPkg.(*cacheTestHandle).src}, nil
+}
+
+func (r *cacheTestRuntime) EvalSemantic(ctx context.Context, semPkg *semantic.Package, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
+	scope := values.NewScope()
+	for _, opt := range opts {
+		opt(r, scope)
+	}
+	return []interpreter.SideEffect{{Node: semPkg}}, scope, nil
+}
+
+func (r *cacheTestRuntime) StdlibVersion() string {
+	return r.version
+}
+
+func TestProgramCache(t *testing.T) {
+	r := &cacheTestRuntime{version: "v1"}
+	cache := NewProgramCache(10)
+	compileAndEval := func(q string, params map[string]values.Value) (*AstProgram, values.Scope) {
+		t.Helper()
+		opts := []CompileOption{WithCache(cache)}
+		if params != nil {
+			opts = append(opts, WithParams(params))
+		}
+		p, err := Compile(q, r, time.Unix(0, 0), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, scope, err := p.eval(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p, scope
+	}
+
+	p, _ := compileAndEval(`from(bucket: "a")`, nil)
+	if p.compileCacheMisses != 1 || p.compileCacheHits != 0 {
+		t.Errorf("expected a cache miss, got %d hits and %d misses", p.compileCacheHits, p.compileCacheMisses)
+	}
+	first := p.semPkg
+
+	p, _ = compileAndEval(`from(bucket: "a")`, nil)
+	if p.compileCacheMisses != 0 || p.compileCacheHits != 1 {
+		t.Errorf("expected a cache hit, got %d hits and %d misses", p.compileCacheHits, p.compileCacheMisses)
+	}
+	if p.semPkg != first {
+		t.Error("expected the cached semantic graph to be reused")
+	}
+	if want, got := 1, r.parsed; want != got {
+		t.Errorf("unexpected number of parsed scripts: want %d, got %d", want, got)
+	}
+	if want, got := 1, r.analyzed; want != got {
+		t.Errorf("unexpected number of analyzed scripts: want %d, got %d", want, got)
+	}
+
+	// A cached program only parses its source if the AST is requested.
+	astPkg, err := p.GetAst()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := `from(bucket: "a")`, astPkg.(*cacheTestHandle).src; want != got {
+		t.Errorf("unexpected AST of cached program: want %q, got %q", want, got)
+	}
+	if want, got := 2, r.parsed; want != got {
+		t.Errorf("unexpected number of parsed scripts: want %d, got %d", want, got)
+	}
+
+	// Params with the same types reuse the program
+	// while their values are bound on each evaluation.
+	q := `from(bucket: params.bucket)`
+	compileAndEval(q, map[string]values.Value{"bucket": values.NewString("a")})
+	p, scope := compileAndEval(q, map[string]values.Value{"bucket": values.NewString("b")})
+	if p.compileCacheHits != 1 {
+		t.Error("expected params with the same types to reuse the program")
+	}
+	params, ok := scope.Lookup(ParamsIdentifier)
+	if !ok {
+		t.Fatal("expected params in the scope")
+	}
+	if bucket, _ := params.Object().Get("bucket"); bucket.Str() != "b" {
+		t.Errorf("unexpected bucket param: want b, got %v", bucket)
+	}
+	p, _ = compileAndEval(q, map[string]values.Value{"bucket": values.NewInt(1)})
+	if p.compileCacheMisses != 1 {
+		t.Error("expected params with different types to miss the cache")
+	}
+
+	// A new version of the standard library does not reuse the programs.
+	r.version = "v2"
+	p, _ = compileAndEval(`from(bucket: "a")`, nil)
+	if p.compileCacheMisses != 1 {
+		t.Error("expected a new stdlib version to miss the cache")
+	}
+
+	if want, got := int64(2), cache.Hits(); want != got {
+		t.Errorf("unexpected cache hits: want %d, got %d", want, got)
+	}
+	if want, got := int64(4), cache.Misses(); want != got {
+		t.Errorf("unexpected cache misses: want %d, got %d", want, got)
+	}
+
+	var invalidated []string
+	cache.OnInvalidate(func(key ProgramCacheKey) {
+		invalidated = append(invalidated, key.StdlibVersion)
+	})
+	cache.InvalidateIf(func(key ProgramCacheKey) bool {
+		return key.StdlibVersion != "v2"
+	})
+	if want := []string{"v1", "v1", "v1"}; !cmp.Equal(want, invalidated) {
+		t.Errorf("unexpected invalidated programs -want/+got:\n%s", cmp.Diff(want, invalidated))
+	}
+	if want, got := 1, cache.Len(); want != got {
+		t.Errorf("unexpected number of cached programs: want %d, got %d", want, got)
+	}
+	cache.InvalidateAll()
+	if want, got := 0, cache.Len(); want != got {
+		t.Errorf("unexpected number of cached programs: want %d, got %d", want, got)
+	}
+}
+
+func TestProgramCache_Evict(t *testing.T) {
+	cache := NewProgramCache(2)
+	var evicted []string
+	cache.OnInvalidate(func(key ProgramCacheKey) {
+		evicted = append(evicted, key.Hash)
+	})
+
+	pkg := &semantic.Package{}
+	cache.add(ProgramCacheKey{Hash: "a"}, pkg)
+	cache.add(ProgramCacheKey{Hash: "b"}, pkg)
+	// Using a makes b the least recently used program.
+	if _, ok := cache.get(ProgramCacheKey{Hash: "a"}); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	cache.add(ProgramCacheKey{Hash: "c"}, pkg)
+
+	if want := []string{"b"}; !cmp.Equal(want, evicted) {
+		t.Errorf("unexpected evicted programs -want/+got:\n%s", cmp.Diff(want, evicted))
+	}
+	if _, ok := cache.get(ProgramCacheKey{Hash: "b"}); ok {
+		t.Error("expected b to be evicted")
+	}
+	cache.Invalidate(ProgramCacheKey{Hash: "a"})
+	if want, got := 1, cache.Len(); want != got {
+		t.Errorf("unexpected number of cached programs: want %d, got %d", want, got)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 	"github.com/opentracing/opentracing-go"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -52,6 +54,8 @@ type compileOptions struct {
 	verbose bool
 
 	extern flux.ASTHandle
+	params map[string]values.Value
+	cache  *ProgramCache
 
 	planOptions struct {
 		logical  []plan.LogicalOption
@@ -75,6 +79,16 @@ func WithExtern(extern flux.ASTHandle) CompileOption {
 	}
 }
 
+// WithCache reuses the semantic graph of a program from the cache
+// when the same script was compiled before, and adds it otherwise.
+// The logical plan is not cached; see ProgramCache.
+// The cache is only used when the runtime implements SemanticRuntime.
+func WithCache(c *ProgramCache) CompileOption {
+	return func(o *compileOptions) {
+		o.cache = c
+	}
+}
+
 func defaultOptions() *compileOptions {
 	o := new(compileOptions)
 	return o
@@ -100,26 +114,61 @@ func Verbose(v bool) CompileOption {
 // Compile evaluates a Flux script producing a flux.Program.
 // now parameter must be non-zero, that is the default now time should be set before compiling.
 func Compile(q string, runtime flux.Runtime, now time.Time, opts ...CompileOption) (*AstProgram, error) {
-	astPkg, err := runtime.Parse(q)
-	if err != nil {
-		return nil, err
-	}
-	return CompileAST(astPkg, runtime, now, opts...), nil
+	return compile(runtime, now, applyOptions(opts...), FluxCompilerType, []byte(q), func() (flux.ASTHandle, error) {
+		return runtime.Parse(q)
+	})
 }
 
 // CompileAST evaluates a Flux handle to an AST and produces a flux.Program.
 // now parameter must be non-zero, that is the default now time should be set before compiling.
 func CompileAST(astPkg flux.ASTHandle, runtime flux.Runtime, now time.Time, opts ...CompileOption) *AstProgram {
+	return newAstProgram(astPkg, runtime, now, applyOptions(opts...))
+}
+
+func newAstProgram(astPkg flux.ASTHandle, runtime flux.Runtime, now time.Time, o *compileOptions) *AstProgram {
 	return &AstProgram{
 		Program: &Program{
 			Runtime: runtime,
-			opts:    applyOptions(opts...),
+			opts:    o,
 		},
-		Ast: astPkg,
-		Now: now,
+		Ast:    astPkg,
+		Now:    now,
+		params: o.params,
 	}
 }
 
+// compile produces a flux.Program from the source that the parse function parses.
+// When the program cache holds the semantic graph of the source,
+// the source is only parsed if the AST of the program is requested.
+func compile(runtime flux.Runtime, now time.Time, o *compileOptions, kind flux.CompilerType, src []byte, parse func() (flux.ASTHandle, error)) (*AstProgram, error) {
+	var key *ProgramCacheKey
+	if sr, ok := runtime.(SemanticRuntime); ok && o.cache != nil {
+		k, err := programCacheKey(sr, string(kind), src, o)
+		if err != nil {
+			return nil, err
+		}
+		if semPkg, ok := o.cache.get(k); ok {
+			p := newAstProgram(nil, runtime, now, o)
+			p.semPkg = semPkg
+			p.parse = parse
+			p.compileCacheHits = 1
+			return p, nil
+		}
+		key = &k
+	}
+
+	astPkg, err := parse()
+	if err != nil {
+		return nil, err
+	}
+	p := newAstProgram(astPkg, runtime, now, o)
+	if key != nil {
+		p.cacheKey = key
+		p.compileCacheMisses = 1
+	}
+	return p, nil
+}
+
 // CompileTableObject evaluates a TableObject and produces a flux.Program.
 // now parameter must be non-zero, that is the default now time should be set before compiling.
 func CompileTableObject(ctx context.Context, to *flux.TableObject, now time.Time, opts ...CompileOption) (*Program, error) {
@@ -141,9 +190,11 @@ func CompileTableObject(ctx context.Context, to *flux.TableObject, now time.Time
 	}, nil
 }
 
-func buildPlan(ctx context.Context, spec *flux.Spec, opts *compileOptions) (*plan.Spec, error) {
+func buildPlan(ctx context.Context, spec *flux.Spec, opts *compileOptions) (ps *plan.Spec, err error) {
 	s, _ := opentracing.StartSpanFromContext(ctx, "plan")
 	defer s.Finish()
+	ctx, span := flux.Tracer(ctx).Start(ctx, "plan")
+	defer func() { endSpan(span, err) }()
 	pb := plan.PlannerBuilder{}
 
 	planOptions := opts.planOptions
@@ -154,17 +205,23 @@ func buildPlan(ctx context.Context, spec *flux.Spec, opts *compileOptions) (*pla
 	pb.AddLogicalOptions(lopts...)
 	pb.AddPhysicalOptions(popts...)
 
-	ps, err := pb.Build().Plan(ctx, spec)
+	return pb.Build().Plan(ctx, spec)
+}
+
+// endSpan records the error, if any, on the OpenTelemetry span and ends it.
+func endSpan(span trace.Span, err error) {
 	if err != nil {
-		return nil, err
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
 	}
-	return ps, nil
+	span.End()
 }
 
 // FluxCompiler compiles a Flux script into a spec.
 type FluxCompiler struct {
 	Now    time.Time
 	Extern json.RawMessage `json:"extern,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
 	Query  string          `json:"query"`
 }
 
@@ -187,15 +244,27 @@ func IsNonNullJSON(bs json.RawMessage) bool {
 func (c FluxCompiler) Compile(ctx context.Context, runtime flux.Runtime) (flux.Program, error) {
 	query := c.Query
 
-	// Ignore context, it will be provided upon Program Start.
+	// The context is only used to find the program cache,
+	// the context of the query will be provided upon Program Start.
+	var opts []CompileOption
+	if cache := CacheFromContext(ctx); cache != nil {
+		opts = append(opts, WithCache(cache))
+	}
 	if IsNonNullJSON(c.Extern) {
 		hdl, err := runtime.JSONToHandle(wrapFileJSONInPkg(c.Extern))
 		if err != nil {
 			return nil, errors.Wrap(err, codes.Inherit, "extern json parse error")
 		}
-		return Compile(query, runtime, c.Now, WithExtern(hdl))
+		opts = append(opts, WithExtern(hdl))
+	}
+	if IsNonNullJSON(c.Params) {
+		params, err := ParseParams(c.Params)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithParams(params))
 	}
-	return Compile(query, runtime, c.Now)
+	return Compile(query, runtime, c.Now, opts...)
 }
 
 func (c FluxCompiler) CompilerType() flux.CompilerType {
@@ -205,6 +274,7 @@ func (c FluxCompiler) CompilerType() flux.CompilerType {
 // ASTCompiler implements Compiler by producing a Program from an AST.
 type ASTCompiler struct {
 	Extern json.RawMessage `json:"extern,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
 	AST    json.RawMessage `json:"ast"`
 	Now    time.Time
 }
@@ -214,23 +284,37 @@ func (c ASTCompiler) Compile(ctx context.Context, runtime flux.Runtime) (flux.Pr
 	if now.IsZero() {
 		now = time.Now()
 	}
-	hdl, err := runtime.JSONToHandle(c.AST)
-	if err != nil {
-		return nil, err
-	}
-	if err := hdl.GetError(); err != nil {
-		return nil, err
-	}
 
-	// Ignore context, it will be provided upon Program Start.
+	// The context is only used to find the program cache,
+	// the context of the query will be provided upon Program Start.
+	var opts []CompileOption
+	if cache := CacheFromContext(ctx); cache != nil {
+		opts = append(opts, WithCache(cache))
+	}
 	if IsNonNullJSON(c.Extern) {
 		extHdl, err := runtime.JSONToHandle(wrapFileJSONInPkg(c.Extern))
 		if err != nil {
 			return nil, err
 		}
-		return CompileAST(hdl, runtime, now, WithExtern(extHdl)), nil
+		opts = append(opts, WithExtern(extHdl))
+	}
+	if IsNonNullJSON(c.Params) {
+		params, err := ParseParams(c.Params)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithParams(params))
 	}
-	return CompileAST(hdl, runtime, now), nil
+	return compile(runtime, now, applyOptions(opts...), ASTCompilerType, c.AST, func() (flux.ASTHandle, error) {
+		hdl, err := runtime.JSONToHandle(c.AST)
+		if err != nil {
+			return nil, err
+		}
+		if err := hdl.GetError(); err != nil {
+			return nil, err
+		}
+		return hdl, nil
+	})
 }
 
 func (ASTCompiler) CompilerType() flux.CompilerType {
@@ -266,6 +350,11 @@ type Program struct {
 	Runtime  flux.Runtime
 
 	opts *compileOptions
+
+	// compileCacheHits and compileCacheMisses
+	// are reported in the statistics of the query.
+	compileCacheHits   int64
+	compileCacheMisses int64
 }
 
 func (p *Program) SetLogger(logger *zap.Logger) {
@@ -275,23 +364,28 @@ func (p *Program) SetLogger(logger *zap.Logger) {
 func (p *Program) Start(ctx context.Context, alloc *memory.Allocator) (flux.Query, error) {
 	ctx, cancel := context.WithCancel(ctx)
 
-	// This span gets closed by the query when it is done.
+	// These spans get closed by the query when it is done.
 	s, cctx := opentracing.StartSpanFromContext(ctx, "execute")
+	cctx, otelSpan := flux.Tracer(cctx).Start(cctx, "execute")
 	results := make(chan flux.Result)
 	q := &query{
-		ctx:     cctx,
-		results: results,
-		alloc:   alloc,
-		span:    s,
-		cancel:  cancel,
+		ctx:      cctx,
+		results:  results,
+		alloc:    alloc,
+		span:     s,
+		otelSpan: otelSpan,
+		cancel:   cancel,
 		stats: flux.Statistics{
-			Metadata: make(metadata.Metadata),
+			CompileCacheHits:   p.compileCacheHits,
+			CompileCacheMisses: p.compileCacheMisses,
+			Metadata:           make(metadata.Metadata),
 		},
 	}
 
 	if execute.HaveExecutionDependencies(ctx) {
 		deps := execute.GetExecutionDependencies(ctx)
 		q.stats.Metadata.AddAll(deps.Metadata)
+		q.operatorProfiler = deps.ExecutionOptions.OperatorProfiler
 	}
 
 	if traceID, sampled, found := jaeger.InfoFromSpan(s); found {
@@ -306,6 +400,7 @@ func (p *Program) Start(ctx context.Context, alloc *memory.Allocator) (flux.Quer
 	resultMap, md, err := e.Execute(cctx, p.PlanSpec, q.alloc)
 	if err != nil {
 		s.Finish()
+		endSpan(otelSpan, err)
 		return nil, err
 	}
 
@@ -346,6 +441,8 @@ func (p *Program) readMetadata(q *query, metaCh <-chan metadata.Metadata) {
 type AstProgram struct {
 	*Program
 
+	// Ast is nil when the program evaluates a semantic graph
+	// from a program cache until GetAst parses the source.
 	Ast flux.ASTHandle
 	Now time.Time
 	// A list of profilers that are profiling this query
@@ -353,6 +450,17 @@ type AstProgram struct {
 	// The operator profiler that is profiling this query, if any.
 	// Note this operator profiler is also cached in the Profilers array.
 	tfProfiler *execute.OperatorProfiler
+
+	params map[string]values.Value
+	// semPkg is the semantic graph of the program
+	// once it has been analyzed or found in the cache.
+	semPkg *semantic.Package
+	// cacheKey is the key that adds the semantic graph
+	// to the program cache after it has been analyzed.
+	cacheKey *ProgramCacheKey
+	// parse parses the source of a program that was
+	// found in the cache when its AST is requested.
+	parse func() (flux.ASTHandle, error)
 }
 
 // Prepare the Ast for semantic analysis
@@ -363,6 +471,24 @@ func (p *AstProgram) GetAst() (flux.ASTHandle, error) {
 	if p.opts == nil {
 		p.opts = defaultOptions()
 	}
+	if p.Ast == nil && p.parse != nil {
+		astPkg, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		p.Ast, p.parse = astPkg, nil
+	}
+	if p.opts.params != nil {
+		params, err := paramsHandle(p.Runtime, p.opts.params)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.Runtime.MergePackages(params, p.Ast); err != nil {
+			return nil, err
+		}
+		p.Ast = params
+		p.opts.params = nil
+	}
 	if p.opts.extern != nil {
 		extern := p.opts.extern
 		if err := p.Runtime.MergePackages(extern, p.Ast); err != nil {
@@ -427,12 +553,57 @@ func (eoc *ExecOptsConfig) ConfigureNow(ctx context.Context, now time.Time) {
 	deps.Inject(ctx)
 }
 
-func (p *AstProgram) getSpec(ctx context.Context, alloc *memory.Allocator) (*flux.Spec, values.Scope, error) {
-	ast, astErr := p.GetAst()
-	if astErr != nil {
-		return nil, nil, astErr
+func (eoc *ExecOptsConfig) ConfigureLocation(ctx context.Context, location values.Object) {
+	loc, err := plan.LocationFromObject(location)
+	if err != nil {
+		return
+	}
+	if execute.HaveExecutionDependencies(ctx) {
+		deps := execute.GetExecutionDependencies(ctx)
+		deps.ExecutionOptions.Location = loc
+		deps.Inject(ctx)
+	}
+}
+
+// eval evaluates the program. It reuses the semantic graph from the
+// program cache, or adds the semantic graph to the cache after analyzing it,
+// when the program was compiled with a cache.
+func (p *AstProgram) eval(ctx context.Context, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
+	if p.params != nil {
+		opts = append(opts, withParamsScope(p.params))
+	}
+	sr, ok := p.Runtime.(SemanticRuntime)
+	if !ok || (p.semPkg == nil && p.cacheKey == nil) {
+		ast, err := p.GetAst()
+		if err != nil {
+			return nil, nil, err
+		}
+		return p.Runtime.Eval(ctx, ast, &ExecOptsConfig{}, opts...)
 	}
 
+	if p.semPkg == nil {
+		ast, err := p.GetAst()
+		if err != nil {
+			return nil, nil, err
+		}
+		semPkg, err := sr.Analyze(ast)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.opts.cache.add(*p.cacheKey, semPkg)
+		p.semPkg = semPkg
+	}
+	return sr.EvalSemantic(ctx, p.semPkg, &ExecOptsConfig{}, opts...)
+}
+
+func (p *AstProgram) getSpec(ctx context.Context, alloc *memory.Allocator) (_ *flux.Spec, _ values.Scope, err error) {
+	if p.Now.IsZero() {
+		p.Now = time.Now()
+	}
+
+	ctx, span := flux.Tracer(ctx).Start(ctx, "compile")
+	defer func() { endSpan(span, err) }()
+
 	s, cctx := opentracing.StartSpanFromContext(ctx, "eval")
 
 	// Set the now option to our own default and capture the option itself
@@ -444,7 +615,7 @@ func (p *AstProgram) getSpec(ctx context.Context, alloc *memory.Allocator) (*flu
 	// the runtime and flux code in so many places. We should evaluate how
 	// now is used and see if we can improve how now interacts with the system.
 	var nowOpt values.Value
-	sideEffects, scope, err := p.Runtime.Eval(cctx, ast, &ExecOptsConfig{},
+	sideEffects, scope, err := p.eval(cctx,
 		flux.SetNowOption(p.Now),
 		func(r flux.Runtime, scope values.Scope) {
 			nowOpt, _ = scope.Lookup(interpreter.NowOption)
@@ -510,6 +681,30 @@ func (p *AstProgram) Start(ctx context.Context, alloc *memory.Allocator) (flux.Q
 	return p.Program.Start(cctx, alloc)
 }
 
+// Explain evaluates the program and plans the resulting query
+// without executing it. It returns a description of each step
+// taken by the planners.
+func (p *AstProgram) Explain(ctx context.Context, alloc *memory.Allocator) (*plan.Explanation, error) {
+	deps := execute.NewExecutionDependencies(alloc, &p.Now, p.Logger)
+	ctx = deps.Inject(ctx)
+	nextPlanNodeID := new(int)
+	ctx = context.WithValue(ctx, plan.NextPlanNodeIDKey, nextPlanNodeID)
+
+	sp, scope, err := p.getSpec(ctx, alloc)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.updateOpts(scope); err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "error in reading options while explaining program")
+	}
+
+	e := &plan.Explanation{}
+	if _, err := buildPlan(plan.ContextWithExplanation(ctx, e), sp, p.opts); err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "error in building plan while explaining program")
+	}
+	return e, nil
+}
+
 func (p *AstProgram) updateProfilers(ctx context.Context, scope values.Scope) error {
 	if execute.HaveExecutionDependencies(ctx) {
 		deps := execute.GetExecutionDependencies(ctx)
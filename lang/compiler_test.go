@@ -19,6 +19,7 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/internal/oteltest"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/mock"
@@ -31,6 +32,7 @@ import (
 	"github.com/influxdata/flux/stdlib/universe"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func init() {
@@ -51,6 +53,7 @@ func TestFluxCompiler(t *testing.T) {
 		now          time.Time
 		extern       *ast.File
 		externRaw    json.RawMessage
+		params       json.RawMessage
 		q            string
 		jsonCompiler []byte
 		compilerErr  string
@@ -123,6 +126,48 @@ func TestFluxCompiler(t *testing.T) {
 			now:  time.Unix(1000, 0),
 			q:    `from(bucket: "foo") |> range(start: -5m)`,
 		},
+		{
+			name:   "params",
+			params: []byte(`{"bucket": "foo", "start": "-5m", "hosts": ["a", "b"]}`),
+			q: `from(bucket: params.bucket)
+				|> range(start: duration(v: params.start))
+				|> filter(fn: (r) => contains(value: r.host, set: params.hosts))`,
+		},
+		{
+			name: "params with extern",
+			extern: &ast.File{
+				Body: []ast.Statement{
+					&ast.OptionStatement{
+						Assignment: &ast.VariableAssignment{
+							ID:   &ast.Identifier{Name: "twentySix"},
+							Init: &ast.IntegerLiteral{Value: 26},
+						},
+					},
+				},
+			},
+			params: []byte(`{"n": 1}`),
+			q: `twentySeven = twentySix + params.n
+				twentySeven
+				from(bucket: "foo") |> range(start: -5m)`,
+		},
+		{
+			name:     "params with wrong type",
+			params:   []byte(`{"bucket": 1}`),
+			q:        `from(bucket: params.bucket) |> range(start: -5m)`,
+			startErr: "type error",
+		},
+		{
+			name:     "missing param",
+			params:   []byte(`{"start": "-5m"}`),
+			q:        `from(bucket: params.bucket) |> range(start: duration(v: params.start))`,
+			startErr: "type error",
+		},
+		{
+			name:        "params invalid json",
+			params:      []byte(`["foo"]`),
+			q:           `from(bucket: "foo") |> range(start: -5m)`,
+			compilerErr: "params must be a JSON object",
+		},
 		{
 			name: "extern that uses null keyword",
 			now:  parser.MustParseTime("2020-03-24T14:24:46.15933241Z").Value,
@@ -149,6 +194,7 @@ func TestFluxCompiler(t *testing.T) {
 					c = lang.FluxCompiler{
 						Now:    tc.now,
 						Extern: tc.externRaw,
+						Params: tc.params,
 						Query:  tc.q,
 					}
 				} else if len(tc.jsonCompiler) > 0 {
@@ -927,6 +973,77 @@ func TestQueryTracing(t *testing.T) {
 	}
 }
 
+func TestQueryOpenTelemetry(t *testing.T) {
+	tp, exp := oteltest.NewTracerProvider()
+	mp := oteltest.NewMeterProvider()
+	ctx := flux.WithTracerProvider(context.Background(), tp)
+	ctx = flux.WithMeterProvider(ctx, mp)
+
+	c := lang.FluxCompiler{
+		Query: `
+			import "array"
+			array.from(rows: [{key: 1, value: 2}, {key: 3, value: 4}])
+			  |> filter(fn: (r) => r.value == 2)
+			  |> map(fn: (r) => ({r with foo: "hi"}))`,
+	}
+	prog, err := c.Compile(ctx, runtime.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := prog.Start(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(flux.Table) error {
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exp.GetSpans() {
+		spans[span.Name] = span
+	}
+	for _, name := range []string{
+		"compile",
+		"plan",
+		"execute",
+		"*array.tableSource",
+		"*universe.filterTransformation",
+		"*universe.mapTransformation",
+	} {
+		if _, ok := spans[name]; !ok {
+			t.Errorf("expected to find span %q but it wasn't there", name)
+		}
+	}
+	// The sources and transformations are children of the execute span.
+	executeID := spans["execute"].SpanContext.SpanID()
+	for _, name := range []string{"*array.tableSource", "*universe.mapTransformation"} {
+		if got := spans[name].Parent.SpanID(); got != executeID {
+			t.Errorf("expected span %q to be a child of the execute span", name)
+		}
+	}
+
+	// Filter receives both rows of the source and map receives the one row that passes the filter.
+	filter := execute.OperationAttribute.String("*universe.filterTransformation")
+	if want, got := int64(2), mp.Sum(execute.OperatorRowsMetric, filter); want != got {
+		t.Errorf("unexpected rows for filter: want %d, got %d (recorded %v)", want, got, mp.Series())
+	}
+	mapOp := execute.OperationAttribute.String("*universe.mapTransformation")
+	if want, got := int64(1), mp.Sum(execute.OperatorRowsMetric, mapOp); want != got {
+		t.Errorf("unexpected rows for map: want %d, got %d (recorded %v)", want, got, mp.Series())
+	}
+	if got := mp.Sum(execute.OperatorBytesMetric, filter); got <= 0 {
+		t.Errorf("expected bytes for filter, got %d", got)
+	}
+}
+
 func getRootErr(err error) error {
 	if err == nil {
 		return err
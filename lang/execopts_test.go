@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/values"
 )
@@ -15,8 +16,10 @@ import (
 func TestExecutionOptions(t *testing.T) {
 	src := `
 		import "profiler"
+		import "timezone"
 		option profiler.enabledProfilers = [ "query", "operator" ]
 		option now = () => ( 2020-10-15T00:00:00Z )
+		option location = timezone.location(name: "Europe/Berlin")
 	`
 
 	h, err := parser.ParseToHandle([]byte(src))
@@ -84,4 +87,9 @@ func TestExecutionOptions(t *testing.T) {
 			t.Errorf("now was set with the expected value, expected: %v got: %v", expectedTime, *deps.Now)
 		}
 	}
+
+	// Verify that the location was set.
+	if want, got := (plan.Location{Name: "Europe/Berlin"}), deps.ExecutionOptions.Location; want != got {
+		t.Errorf("location was not configured, expected: %v got: %v", want, got)
+	}
 }
@@ -0,0 +1,215 @@
+package lang
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+	"unicode"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// ParamsIdentifier is the name of the record that holds
+// the parameters of a query.
+const ParamsIdentifier = "params"
+
+// WithParams binds the parameters to the params record of the query.
+// The type of the record is declared in a file that is added to the package
+// of the query so the params are checked against their use in the query.
+func WithParams(params map[string]values.Value) CompileOption {
+	return func(o *compileOptions) {
+		o.params = params
+	}
+}
+
+// ParseParams decodes a JSON object into query parameters.
+// Booleans, numbers, strings, arrays and objects become
+// booleans, integers or floats, strings, arrays and records.
+// Numbers without a fraction or an exponent become integers.
+// Strings are converted with ParseParamString so durations
+// and RFC3339 times become duration and time values.
+func ParseParams(data json.RawMessage) (map[string]values.Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "params must be a JSON object")
+	}
+	params := make(map[string]values.Value, len(obj))
+	for k, v := range obj {
+		value, err := paramFromJSON(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "param %q", k)
+		}
+		params[k] = value
+	}
+	return params, nil
+}
+
+func paramFromJSON(v interface{}) (values.Value, error) {
+	switch v := v.(type) {
+	case bool:
+		return values.NewBool(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return values.NewInt(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid number %s", v)
+		}
+		return values.NewFloat(f), nil
+	case string:
+		return ParseParamString(v), nil
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, errors.New(codes.Invalid, "cannot infer the element type of an empty array")
+		}
+		elements := make([]values.Value, len(v))
+		for i, e := range v {
+			value, err := paramFromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && !value.Type().Equal(elements[0].Type()) {
+				return nil, errors.Newf(codes.Invalid, "array elements must have the same type, found %v and %v", elements[0].Type(), value.Type())
+			}
+			elements[i] = value
+		}
+		return values.NewArrayWithBacking(semantic.NewArrayType(elements[0].Type()), elements), nil
+	case map[string]interface{}:
+		properties := make(map[string]values.Value, len(v))
+		for k, e := range v {
+			value, err := paramFromJSON(e)
+			if err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "property %q", k)
+			}
+			properties[k] = value
+		}
+		return values.NewObjectWithValues(properties), nil
+	case nil:
+		return nil, errors.New(codes.Invalid, "null is not a valid param")
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported param %v", v)
+	}
+}
+
+// ParseParamString returns the value of a param that is given as a string.
+// A duration literal becomes a duration and an RFC3339 time becomes a time.
+// Any other string is kept as a string.
+func ParseParamString(s string) values.Value {
+	if d, err := values.ParseDuration(s); err == nil {
+		return values.NewDuration(d)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return values.NewTime(values.ConvertTime(t))
+	}
+	return values.NewString(s)
+}
+
+// ParamsFile returns a file that declares the type of the params record.
+// The record itself is added to the scope of the query when it is evaluated.
+func ParamsFile(params map[string]values.Value) (*ast.File, error) {
+	ty, err := paramsType(values.NewObjectWithValues(params).Type())
+	if err != nil {
+		return nil, err
+	}
+	return &ast.File{
+		Body: []ast.Statement{
+			&ast.BuiltinStatement{
+				ID: &ast.Identifier{Name: ParamsIdentifier},
+				Ty: ast.TypeExpression{
+					Ty:          ty,
+					Constraints: []*ast.TypeConstraint{},
+				},
+			},
+		},
+	}, nil
+}
+
+// paramsType converts the type of a param into a type expression.
+func paramsType(t semantic.MonoType) (ast.MonoType, error) {
+	switch n := t.Nature(); n {
+	case semantic.Int, semantic.UInt, semantic.Float, semantic.String,
+		semantic.Bool, semantic.Time, semantic.Duration, semantic.Regexp:
+		return &ast.NamedType{ID: &ast.Identifier{Name: n.String()}}, nil
+	case semantic.Array:
+		elem, err := t.ElemType()
+		if err != nil {
+			return nil, err
+		}
+		ty, err := paramsType(elem)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.ArrayType{ElementType: ty}, nil
+	case semantic.Object:
+		props, err := t.SortedProperties()
+		if err != nil {
+			return nil, err
+		}
+		rec := &ast.RecordType{
+			Properties: make([]*ast.PropertyType, 0, len(props)),
+		}
+		for _, p := range props {
+			if !isIdentifier(p.Name()) {
+				return nil, errors.Newf(codes.Invalid, "param %q is not a valid identifier", p.Name())
+			}
+			pt, err := p.TypeOf()
+			if err != nil {
+				return nil, err
+			}
+			ty, err := paramsType(pt)
+			if err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "param %q", p.Name())
+			}
+			rec.Properties = append(rec.Properties, &ast.PropertyType{
+				Name: &ast.Identifier{Name: p.Name()},
+				Ty:   ty,
+			})
+		}
+		return rec, nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported param type %v", t)
+	}
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// withParamsScope returns a scope mutator that
+// adds the params record to the scope of the query.
+func withParamsScope(params map[string]values.Value) flux.ScopeMutator {
+	obj := values.NewObjectWithValues(params)
+	return func(r flux.Runtime, scope values.Scope) {
+		scope.Set(ParamsIdentifier, obj)
+	}
+}
+
+// paramsHandle returns a handle to a package that contains the params file.
+func paramsHandle(runtime flux.Runtime, params map[string]values.Value) (flux.ASTHandle, error) {
+	file, err := ParamsFile(params)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.JSONToHandle(wrapFileJSONInPkg(bs))
+}
@@ -0,0 +1,134 @@
+package lang_test
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/ast/asttest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+func TestParseParams(t *testing.T) {
+	params, err := lang.ParseParams([]byte(`{
+	"s": "foo",
+	"i": 5,
+	"f": 1.5,
+	"b": true,
+	"a": ["x", "y"],
+	"r": {"n": 1},
+	"t": "2020-01-01T00:00:00Z",
+	"d": "1h30m"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]values.Value{
+		"s": values.NewString("foo"),
+		"i": values.NewInt(5),
+		"f": values.NewFloat(1.5),
+		"b": values.NewBool(true),
+		"a": values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), []values.Value{
+			values.NewString("x"),
+			values.NewString("y"),
+		}),
+		"r": values.NewObjectWithValues(map[string]values.Value{
+			"n": values.NewInt(1),
+		}),
+		"t": values.NewTime(values.ConvertTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+		"d": values.NewDuration(values.ConvertDurationNsecs(90 * time.Minute)),
+	}
+	if len(want) != len(params) {
+		t.Fatalf("unexpected number of params: want %d, got %d", len(want), len(params))
+	}
+	for k, v := range want {
+		if got, ok := params[k]; !ok || !v.Equal(got) {
+			t.Errorf("unexpected value for param %q: want %v, got %v", k, v, got)
+		}
+	}
+}
+
+func TestParseParams_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{name: "not an object", data: `["foo"]`},
+		{name: "null", data: `{"a": null}`},
+		{name: "empty array", data: `{"a": []}`},
+		{name: "mixed array", data: `{"a": [1, "b"]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := lang.ParseParams(json.RawMessage(tc.data)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestParamsFile(t *testing.T) {
+	file, err := lang.ParamsFile(map[string]values.Value{
+		"start": values.NewTime(values.ConvertTime(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))),
+		"every": values.NewDuration(values.ConvertDurationNsecs(time.Minute)),
+		"re":    values.NewRegexp(regexp.MustCompile(`^a`)),
+		"n":     values.NewUInt(3),
+		"tags": values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), []values.Value{
+			values.NewString("a"),
+		}),
+		"r": values.NewObjectWithValues(map[string]values.Value{
+			"f": values.NewFloat(math.NaN()),
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	named := func(name string) ast.MonoType {
+		return &ast.NamedType{ID: &ast.Identifier{Name: name}}
+	}
+	want := &ast.File{
+		Body: []ast.Statement{
+			&ast.BuiltinStatement{
+				ID: &ast.Identifier{Name: "params"},
+				Ty: ast.TypeExpression{
+					Ty: &ast.RecordType{
+						Properties: []*ast.PropertyType{
+							{Name: &ast.Identifier{Name: "every"}, Ty: named("duration")},
+							{Name: &ast.Identifier{Name: "n"}, Ty: named("uint")},
+							{
+								Name: &ast.Identifier{Name: "r"},
+								Ty: &ast.RecordType{
+									Properties: []*ast.PropertyType{
+										{Name: &ast.Identifier{Name: "f"}, Ty: named("float")},
+									},
+								},
+							},
+							{Name: &ast.Identifier{Name: "re"}, Ty: named("regexp")},
+							{Name: &ast.Identifier{Name: "start"}, Ty: named("time")},
+							{Name: &ast.Identifier{Name: "tags"}, Ty: &ast.ArrayType{ElementType: named("string")}},
+						},
+					},
+					Constraints: []*ast.TypeConstraint{},
+				},
+			},
+		},
+	}
+	if !cmp.Equal(want, file, asttest.CompareOptions...) {
+		t.Fatalf("unexpected params file -want/+got:\n%s", cmp.Diff(want, file, asttest.CompareOptions...))
+	}
+}
+
+func TestParamsFile_InvalidName(t *testing.T) {
+	if _, err := lang.ParamsFile(map[string]values.Value{
+		"my-bucket": values.NewString("telegraf"),
+	}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -6,20 +6,27 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/dependencies/testing"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/memory"
 	"github.com/opentracing/opentracing-go"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // query implements the flux.Query interface.
 type query struct {
-	ctx     context.Context
-	results chan flux.Result
-	stats   flux.Statistics
-	alloc   *memory.Allocator
-	span    opentracing.Span
-	cancel  func()
-	err     error
-	wg      sync.WaitGroup
+	ctx      context.Context
+	results  chan flux.Result
+	stats    flux.Statistics
+	alloc    *memory.Allocator
+	span     opentracing.Span
+	otelSpan trace.Span
+	cancel   func()
+	err      error
+	wg       sync.WaitGroup
+
+	// operatorProfiler adds the profile of each operator
+	// to the statistics when the query is done.
+	operatorProfiler *execute.OperatorProfiler
 }
 
 func (q *query) Results() <-chan flux.Result {
@@ -31,6 +38,11 @@ func (q *query) Done() {
 	q.wg.Wait()
 	q.stats.MaxAllocated = q.alloc.MaxAllocated()
 	q.stats.TotalAllocated = q.alloc.TotalAllocated()
+	q.stats.TotalSpilled = q.alloc.TotalSpilled()
+	q.stats.SpillCount = q.alloc.SpillCount()
+	if q.operatorProfiler != nil {
+		q.stats.Metadata.AddAll(q.operatorProfiler.Metadata())
+	}
 	if q.span != nil {
 		q.span.Finish()
 		q.span = nil
@@ -43,6 +55,10 @@ func (q *query) Done() {
 		// If the testing framework was configured, verify all expectations.
 		q.err = testing.Check(q.ctx)
 	}
+	if q.otelSpan != nil {
+		endSpan(q.otelSpan, q.err)
+		q.otelSpan = nil
+	}
 }
 
 func (q *query) Cancel() {
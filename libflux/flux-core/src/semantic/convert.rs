@@ -177,6 +177,7 @@ fn convert_monotype(
             "time" => Ok(MonoType::Time),
             "regexp" => Ok(MonoType::Regexp),
             "bytes" => Ok(MonoType::Bytes),
+            "decimal" => Ok(MonoType::Decimal),
             _ => Err(format!("invalid named type {}", basic.name.name)),
         },
         ast::MonoType::Array(arr) => Ok(MonoType::Arr(Box::new(types::Array(convert_monotype(
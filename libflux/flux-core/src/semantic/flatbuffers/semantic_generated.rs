@@ -143,13 +143,13 @@ pub mod fbsemantic {
         since = "2.0.0",
         note = "Use associated constants instead. This will no longer be generated in 2021."
     )]
-    pub const ENUM_MAX_TYPE: u8 = 8;
+    pub const ENUM_MAX_TYPE: u8 = 9;
     #[deprecated(
         since = "2.0.0",
         note = "Use associated constants instead. This will no longer be generated in 2021."
     )]
     #[allow(non_camel_case_types)]
-    pub const ENUM_VALUES_TYPE: [Type; 9] = [
+    pub const ENUM_VALUES_TYPE: [Type; 10] = [
         Type::Bool,
         Type::Int,
         Type::Uint,
@@ -159,6 +159,7 @@ pub mod fbsemantic {
         Type::Time,
         Type::Regexp,
         Type::Bytes,
+        Type::Decimal,
     ];
 
     #[derive(Clone, Copy, PartialEq, Eq, PartialOrd, Ord, Hash, Default)]
@@ -175,9 +176,10 @@ pub mod fbsemantic {
         pub const Time: Self = Self(6);
         pub const Regexp: Self = Self(7);
         pub const Bytes: Self = Self(8);
+        pub const Decimal: Self = Self(9);
 
         pub const ENUM_MIN: u8 = 0;
-        pub const ENUM_MAX: u8 = 8;
+        pub const ENUM_MAX: u8 = 9;
         pub const ENUM_VALUES: &'static [Self] = &[
             Self::Bool,
             Self::Int,
@@ -188,6 +190,7 @@ pub mod fbsemantic {
             Self::Time,
             Self::Regexp,
             Self::Bytes,
+            Self::Decimal,
         ];
         /// Returns the variant's name or "" if unknown.
         pub fn variant_name(self) -> Option<&'static str> {
@@ -201,6 +204,7 @@ pub mod fbsemantic {
                 Self::Time => Some("Time"),
                 Self::Regexp => Some("Regexp"),
                 Self::Bytes => Some("Bytes"),
+                Self::Decimal => Some("Decimal"),
                 _ => None,
             }
         }
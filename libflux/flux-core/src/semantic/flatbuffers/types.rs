@@ -154,6 +154,7 @@ impl From<fb::Basic<'_>> for MonoType {
             fb::Type::Time => MonoType::Time,
             fb::Type::Regexp => MonoType::Regexp,
             fb::Type::Bytes => MonoType::Bytes,
+            fb::Type::Decimal => MonoType::Decimal,
             _ => unreachable!("Unknown fb::Type"),
         }
     }
@@ -423,6 +424,13 @@ pub fn build_type(
             let v = fb::Basic::create(builder, &a);
             (v.as_union_value(), fb::MonoType::Basic)
         }
+        MonoType::Decimal => {
+            let a = fb::BasicArgs {
+                t: fb::Type::Decimal,
+            };
+            let v = fb::Basic::create(builder, &a);
+            (v.as_union_value(), fb::MonoType::Basic)
+        }
         MonoType::Var(tvr) => {
             let offset = build_var(builder, tvr);
             (offset.as_union_value(), fb::MonoType::Var)
@@ -674,6 +682,7 @@ mod tests {
         test_serde("time");
         test_serde("regexp");
         test_serde("bytes");
+        test_serde("decimal");
     }
     #[test]
     fn serde_array_type() {
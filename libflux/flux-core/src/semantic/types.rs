@@ -284,6 +284,8 @@ pub enum MonoType {
     Regexp,
     #[display(fmt = "bytes")]
     Bytes,
+    #[display(fmt = "decimal")]
+    Decimal,
     #[display(fmt = "{}", _0)]
     Var(Tvar),
     #[display(fmt = "{}", _0)]
@@ -314,7 +316,8 @@ impl Substitutable for MonoType {
             | MonoType::Duration
             | MonoType::Time
             | MonoType::Regexp
-            | MonoType::Bytes => self,
+            | MonoType::Bytes
+            | MonoType::Decimal => self,
             MonoType::Var(tvr) => sub.apply(tvr),
             MonoType::Arr(arr) => MonoType::Arr(Box::new(arr.apply(sub))),
             MonoType::Dict(dict) => MonoType::Dict(Box::new(dict.apply(sub))),
@@ -332,7 +335,8 @@ impl Substitutable for MonoType {
             | MonoType::Duration
             | MonoType::Time
             | MonoType::Regexp
-            | MonoType::Bytes => Vec::new(),
+            | MonoType::Bytes
+            | MonoType::Decimal => Vec::new(),
             MonoType::Var(tvr) => vec![*tvr],
             MonoType::Arr(arr) => arr.free_vars(),
             MonoType::Dict(dict) => dict.free_vars(),
@@ -353,7 +357,8 @@ impl MaxTvar for MonoType {
             | MonoType::Duration
             | MonoType::Time
             | MonoType::Regexp
-            | MonoType::Bytes => Tvar(0),
+            | MonoType::Bytes
+            | MonoType::Decimal => Tvar(0),
             MonoType::Var(tvr) => tvr.max_tvar(),
             MonoType::Arr(arr) => arr.max_tvar(),
             MonoType::Dict(dict) => dict.max_tvar(),
@@ -389,7 +394,8 @@ impl MonoType {
             | (MonoType::Duration, MonoType::Duration)
             | (MonoType::Time, MonoType::Time)
             | (MonoType::Regexp, MonoType::Regexp)
-            | (MonoType::Bytes, MonoType::Bytes) => Ok(Substitution::empty()),
+            | (MonoType::Bytes, MonoType::Bytes)
+            | (MonoType::Decimal, MonoType::Decimal) => Ok(Substitution::empty()),
             (MonoType::Var(tv), t) => tv.unify(t, cons),
             (t, MonoType::Var(tv)) => tv.unify(t, cons),
             (MonoType::Arr(t), MonoType::Arr(s)) => t.unify(*s, cons, f),
@@ -466,6 +472,21 @@ impl MonoType {
                     exp: with,
                 }),
             },
+            MonoType::Decimal => match with {
+                Kind::Addable
+                | Kind::Subtractable
+                | Kind::Divisible
+                | Kind::Numeric
+                | Kind::Comparable
+                | Kind::Equatable
+                | Kind::Nullable
+                | Kind::Stringable
+                | Kind::Negatable => Ok(Substitution::empty()),
+                _ => Err(Error::CannotConstrain {
+                    act: self,
+                    exp: with,
+                }),
+            },
             MonoType::Duration => match with {
                 Kind::Comparable
                 | Kind::Equatable
@@ -521,7 +542,8 @@ impl MonoType {
             | MonoType::Duration
             | MonoType::Time
             | MonoType::Regexp
-            | MonoType::Bytes => false,
+            | MonoType::Bytes
+            | MonoType::Decimal => false,
             MonoType::Var(tvr) => tv == *tvr,
             MonoType::Arr(arr) => arr.contains(tv),
             MonoType::Dict(dict) => dict.contains(tv),
@@ -1456,6 +1478,7 @@ mod tests {
     #[test]
     fn display_type_bytes() {
         assert_eq!("bytes", MonoType::Bytes.to_string());
+        assert_eq!("decimal", MonoType::Decimal.to_string());
     }
     #[test]
     fn display_type_tvar() {
@@ -0,0 +1,36 @@
+package libflux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+)
+
+var (
+	versionOnce sync.Once
+	version     string
+)
+
+// Version returns a hash of the sources that the library
+// and its embedded standard library were built from.
+// Two builds with the same version analyze Flux in the same way.
+func Version() string {
+	versionOnce.Do(func() {
+		paths := make([]string, 0, len(sourceHashes))
+		for path := range sourceHashes {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		h := sha256.New()
+		for _, path := range paths {
+			_, _ = h.Write([]byte(path))
+			_, _ = h.Write([]byte{0})
+			_, _ = h.Write([]byte(sourceHashes[path]))
+			_, _ = h.Write([]byte{0})
+		}
+		version = hex.EncodeToString(h.Sum(nil))
+	})
+	return version
+}
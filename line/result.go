@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
@@ -17,7 +18,9 @@ import (
 // The `_value` column contains tokens.
 // The `_time` column contains the timestamps for when each `_value` has been read.
 // Strings in `_value` are obtained from the io.Reader passed to the Decode function.
-// ResultDecoder outputs one table once the reader reaches EOF.
+// ResultDecoder outputs one table once the reader reaches EOF unless the config
+// sets a chunk size or a flush interval, in which case it outputs a table for
+// each chunk of rows so that a reader that never ends still produces tables.
 type ResultDecoder struct {
 	reader *bufio.Reader
 	config *ResultDecoderConfig
@@ -37,48 +40,163 @@ type TimeProvider interface {
 type ResultDecoderConfig struct {
 	Separator    byte
 	TimeProvider TimeProvider
+	// ChunkSize is the maximum number of rows in each table.
+	// Zero means that there is no maximum.
+	ChunkSize int
+	// FlushInterval is how often the rows that have been read
+	// are output as a table. Zero means that the rows are only
+	// output once there are ChunkSize rows or at EOF.
+	FlushInterval time.Duration
 }
 
-func (rd *ResultDecoder) Do(f func(flux.Table) error) error {
+// chunkBuilder builds the tables for the chunks of rows.
+type chunkBuilder struct {
+	builder  *execute.ColListTableBuilder
+	timeIdx  int
+	valueIdx int
+	tables   int
+}
+
+func newChunkBuilder() (*chunkBuilder, error) {
 	timeCol := flux.ColMeta{Label: "_time", Type: flux.TTime}
 	valueCol := flux.ColMeta{Label: "_value", Type: flux.TString}
 	key := execute.NewGroupKey(nil, nil)
 	builder := execute.NewColListTableBuilder(key, &memory.Allocator{})
 	timeIdx, err := builder.AddCol(timeCol)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	valueIdx, err := builder.AddCol(valueCol)
+	if err != nil {
+		return nil, err
+	}
+	return &chunkBuilder{
+		builder:  builder,
+		timeIdx:  timeIdx,
+		valueIdx: valueIdx,
+	}, nil
+}
+
+func (b *chunkBuilder) append(ts values.Time, v string) error {
+	if err := b.builder.AppendTime(b.timeIdx, ts); err != nil {
+		return err
+	}
+	return b.builder.AppendString(b.valueIdx, v)
+}
+
+func (b *chunkBuilder) len() int {
+	return b.builder.NRows()
+}
+
+// flush outputs the rows that have been appended as a table.
+func (b *chunkBuilder) flush(f func(flux.Table) error) error {
+	tbl, err := b.builder.Table()
+	if err != nil {
+		return err
+	}
+	b.builder.ClearData()
+	b.tables++
+	return f(tbl)
+}
+
+func (rd *ResultDecoder) Do(f func(flux.Table) error) error {
+	b, err := newChunkBuilder()
+	if err != nil {
+		return err
+	}
+	if rd.config.FlushInterval > 0 {
+		err = rd.doInterval(b, f)
+	} else {
+		err = rd.doChunks(b, f)
+	}
 	if err != nil {
 		return err
 	}
 
-	var eof bool
-	for !eof {
+	// The remaining rows are output at EOF. When no table has been
+	// output, an empty table is output so there is always a table.
+	if b.len() > 0 || b.tables == 0 {
+		return b.flush(f)
+	}
+	return nil
+}
+
+// doChunks reads the tokens until EOF and outputs
+// a table each time that the chunk is full.
+func (rd *ResultDecoder) doChunks(b *chunkBuilder, f func(flux.Table) error) error {
+	for {
 		s, err := rd.reader.ReadString(rd.config.Separator)
-		if err != nil && err == io.EOF {
-			break
+		if err == io.EOF {
+			return nil
 		} else if err != nil {
 			return err
 		}
 
-		v := strings.Trim(s, string(rd.config.Separator))
-		ts := rd.config.TimeProvider.CurrentTime()
-		err = builder.AppendTime(timeIdx, ts)
-		if err != nil {
+		if err := rd.appendToken(b, s, f); err != nil {
 			return err
 		}
-		err = builder.AppendString(valueIdx, v)
-		if err != nil {
-			return err
+	}
+}
+
+// token is a token read from the reader or the error that stopped the reader.
+type token struct {
+	s   string
+	err error
+}
+
+// doInterval reads the tokens until EOF and outputs a table each time that
+// the chunk is full and each flush interval if there are rows to output.
+func (rd *ResultDecoder) doInterval(b *chunkBuilder, f func(flux.Table) error) error {
+	tokens := make(chan token)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			s, err := rd.reader.ReadString(rd.config.Separator)
+			select {
+			case tokens <- token{s: s, err: err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(rd.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case tok := <-tokens:
+			if tok.err == io.EOF {
+				return nil
+			} else if tok.err != nil {
+				return tok.err
+			}
+			if err := rd.appendToken(b, tok.s, f); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if b.len() > 0 {
+				if err := b.flush(f); err != nil {
+					return err
+				}
+			}
 		}
 	}
+}
 
-	tbl, err := builder.Table()
-	if err != nil {
+func (rd *ResultDecoder) appendToken(b *chunkBuilder, s string, f func(flux.Table) error) error {
+	v := strings.Trim(s, string(rd.config.Separator))
+	ts := rd.config.TimeProvider.CurrentTime()
+	if err := b.append(ts, v); err != nil {
 		return err
 	}
-	return f(tbl)
+	if rd.config.ChunkSize > 0 && b.len() >= rd.config.ChunkSize {
+		return b.flush(f)
+	}
+	return nil
 }
 
 func (*ResultDecoder) Name() string {
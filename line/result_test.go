@@ -2,7 +2,9 @@ package line_test
 
 import (
 	"bytes"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
@@ -121,3 +123,101 @@ empty line above
 		})
 	}
 }
+
+func TestResultDecoder_ChunkSize(t *testing.T) {
+	decoder := line.NewResultDecoder(&line.ResultDecoderConfig{
+		Separator:    '\n',
+		TimeProvider: &mock.AscendingTimeProvider{},
+		ChunkSize:    3,
+	})
+
+	r, err := decoder.Decode(bytes.NewReader([]byte("a\nb\nc\nd\ne\nf\ng\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*executetest.Table
+	if err := r.Tables().Do(func(table flux.Table) error {
+		ct, err := executetest.ConvertTable(table)
+		if err != nil {
+			return err
+		}
+		got = append(got, ct)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TString},
+	}
+	want := []*executetest.Table{
+		{
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), "a"},
+				{execute.Time(1), "b"},
+				{execute.Time(2), "c"},
+			},
+		},
+		{
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(3), "d"},
+				{execute.Time(4), "e"},
+				{execute.Time(5), "f"},
+			},
+		},
+		{
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(6), "g"},
+			},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestResultDecoder_FlushInterval(t *testing.T) {
+	decoder := line.NewResultDecoder(&line.ResultDecoderConfig{
+		Separator:     '\n',
+		TimeProvider:  &mock.AscendingTimeProvider{},
+		FlushInterval: 10 * time.Millisecond,
+	})
+
+	// The writer is only closed once the rows have been output,
+	// so the rows must be output by the flush interval.
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("a\nb\n"))
+	}()
+
+	r, err := decoder.Decode(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	if err := r.Tables().Do(func(table flux.Table) error {
+		ct, err := executetest.ConvertTable(table)
+		if err != nil {
+			return err
+		}
+		for _, row := range ct.Data {
+			got = append(got, row[1].(string))
+		}
+		if len(got) == 2 {
+			_ = pw.Close()
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"a", "b"}; !cmp.Equal(want, got) {
+		t.Errorf("unexpected values -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,147 @@
+package lsp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+// analysisErrorPattern matches the location of each error in
+// the message returned by the semantic analysis in libflux,
+// such as "type error @1:11-1:16: expected float but found string".
+var analysisErrorPattern = regexp.MustCompile(`(?:type )?error (?:at )?@(\d+):(\d+)-(\d+):(\d+): `)
+
+// diagnostics reports the syntax errors of the document or, when the
+// document parses, the errors found by semantic analysis.
+func diagnostics(doc *document) []Diagnostic {
+	diags := []Diagnostic{}
+	pkg := parser.ParseSource(doc.text)
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		for _, err := range n.Errs() {
+			diags = append(diags, Diagnostic{
+				Range:    doc.lspRange(n.Location()),
+				Severity: SeverityError,
+				Source:   "flux",
+				Message:  err.Msg,
+			})
+		}
+	}), pkg)
+	if len(diags) > 0 {
+		return diags
+	}
+
+	if _, err := runtime.AnalyzeSource(doc.text); err != nil {
+		diags = append(diags, analysisDiagnostics(doc, err.Error())...)
+	}
+	return diags
+}
+
+// analysisDiagnostics splits an analysis error message into
+// a diagnostic for each of the errors that it contains.
+func analysisDiagnostics(doc *document, msg string) []Diagnostic {
+	matches := analysisErrorPattern.FindAllStringSubmatchIndex(msg, -1)
+	if len(matches) == 0 {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Source:   "flux",
+			Message:  msg,
+		}}
+	}
+
+	diags := make([]Diagnostic, 0, len(matches))
+	for i, m := range matches {
+		end := len(msg)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		var pos [4]int
+		for j := range pos {
+			pos[j], _ = strconv.Atoi(msg[m[2+2*j]:m[3+2*j]])
+		}
+		diags = append(diags, Diagnostic{
+			Range: doc.lspRange(ast.SourceLocation{
+				Start: ast.Position{Line: pos[0], Column: pos[1]},
+				End:   ast.Position{Line: pos[2], Column: pos[3]},
+			}),
+			Severity: SeverityError,
+			Source:   "flux",
+			Message:  strings.TrimSpace(msg[m[1]:end]),
+		})
+	}
+	return diags
+}
+
+// hover describes the type of the identifier at the position.
+// The document must pass semantic analysis for a type to be found.
+func hover(doc *document, pos Position) *Hover {
+	pkg, err := runtime.AnalyzeSource(doc.text)
+	if err != nil {
+		return nil
+	}
+
+	p := doc.astPosition(pos)
+	var (
+		name, typ string
+		loc       ast.SourceLocation
+	)
+	semantic.Walk(semantic.CreateVisitor(func(n semantic.Node) {
+		// The walk is depth first so the innermost
+		// node that contains the position wins.
+		switch n := n.(type) {
+		case *semantic.NativeVariableAssignment:
+			if l := n.Identifier.Location(); contains(l, p) {
+				name, typ, loc = n.Identifier.Name, n.Typ.CanonicalString(), l
+			}
+		case *semantic.IdentifierExpression:
+			if l := n.Location(); contains(l, p) {
+				name, typ, loc = n.Name, n.TypeOf().CanonicalString(), l
+			}
+		case *semantic.MemberExpression:
+			if l := n.Location(); contains(l, p) {
+				name, typ, loc = n.Property, n.TypeOf().CanonicalString(), l
+			}
+		}
+	}), pkg)
+	if name == "" {
+		return nil
+	}
+
+	r := doc.lspRange(loc)
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: fmt.Sprintf("```flux\n%s: %s\n```", name, typ),
+		},
+		Range: &r,
+	}
+}
+
+// format formats the document in the same way as flux fmt.
+func format(doc *document) ([]TextEdit, error) {
+	src := strings.TrimSpace(doc.text)
+	astPkg := libflux.ParseString(src)
+	defer astPkg.Free()
+	if err := astPkg.GetError(); err != nil {
+		return nil, errorf(codeRequestFailed, "parse error: %s", err)
+	}
+	formatted, err := astPkg.Format()
+	if err != nil {
+		return nil, errorf(codeRequestFailed, "failed to format the query: %s", err)
+	}
+
+	formatted += "\n"
+	if formatted == doc.text {
+		return []TextEdit{}, nil
+	}
+	return []TextEdit{{
+		Range:   doc.fullRange(),
+		NewText: formatted,
+	}}, nil
+}
@@ -0,0 +1,325 @@
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/complete"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// argumentKeyPattern matches the named arguments
+// that have already been passed to a call.
+var argumentKeyPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*:`)
+
+// completion suggests the names that can be inserted at the position.
+//
+// The script is usually incomplete while it is being edited so the
+// context of the position is found from the text in front of it.
+// Package members are suggested after the name of an import, the
+// parameters of the called function are suggested where a named
+// argument is expected, and the names in scope are suggested otherwise.
+func (s *Server) completion(doc *document, pos Position) *CompletionList {
+	text := doc.text[:doc.offset(pos)]
+	start := identStart(text, len(text))
+	prefix := text[start:]
+
+	file := parser.ParseSource(doc.text).Files[0]
+	imports := s.imports(file)
+
+	var items []CompletionItem
+	if start > 0 && text[start-1] == '.' {
+		obj := text[identStart(text, start-1) : start-1]
+		if pkgpath, ok := imports[obj]; ok {
+			items = s.memberCompletions(pkgpath)
+		}
+	} else if callee, args, ok := enclosingCall(text[:start]); ok && isArgumentKey(text[:start]) {
+		items = s.parameterCompletions(file, imports, callee, args)
+	} else {
+		items = s.scopeCompletions(file, imports, doc.astPosition(pos))
+	}
+
+	list := &CompletionList{Items: []CompletionItem{}}
+	for _, item := range items {
+		if strings.HasPrefix(item.Label, prefix) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list
+}
+
+// imports returns the import path for each package name
+// that is declared by the imports of the file.
+func (s *Server) imports(file *ast.File) map[string]string {
+	imports := make(map[string]string, len(file.Imports))
+	for _, imp := range file.Imports {
+		if imp.Path == nil {
+			continue
+		}
+		if imp.As != nil {
+			imports[imp.As.Name] = imp.Path.Value
+		} else {
+			imports[s.importName(imp.Path.Value)] = imp.Path.Value
+		}
+	}
+	return imports
+}
+
+// memberCompletions suggests the members of an imported package.
+func (s *Server) memberCompletions(pkgpath string) []CompletionItem {
+	pkg, err := s.importer.ImportPackageObject(pkgpath)
+	if err != nil {
+		return nil
+	}
+	var items []CompletionItem
+	pkg.Range(func(name string, v values.Value) {
+		if strings.HasPrefix(name, "_") {
+			return
+		}
+		items = append(items, valueCompletion(name, v))
+	})
+	sortCompletions(items)
+	return items
+}
+
+// parameterCompletions suggests the parameters of the called
+// function that have not been passed as an argument yet.
+func (s *Server) parameterCompletions(file *ast.File, imports map[string]string, callee, args string) []CompletionItem {
+	passed := make(map[string]bool)
+	for _, m := range argumentKeyPattern.FindAllStringSubmatch(args, -1) {
+		passed[m[1]] = true
+	}
+
+	var items []CompletionItem
+	add := func(name, detail string) {
+		if passed[name] {
+			return
+		}
+		items = append(items, CompletionItem{
+			Label:      name,
+			Kind:       CompletionKindProperty,
+			Detail:     detail,
+			InsertText: name + ": ",
+		})
+	}
+
+	if fn := localFunction(file, callee); fn != nil {
+		for _, p := range fn.Params {
+			if p.Key != nil {
+				add(p.Key.Key(), "")
+			}
+		}
+		return items
+	}
+
+	typ, ok := s.calleeType(imports, callee)
+	if !ok || typ.Nature() != semantic.Function {
+		return nil
+	}
+	fnArgs, err := typ.SortedArguments()
+	if err != nil {
+		return nil
+	}
+	for _, arg := range fnArgs {
+		if arg.Pipe() {
+			continue
+		}
+		var detail string
+		if t, err := arg.TypeOf(); err == nil {
+			detail = t.String()
+		}
+		add(string(arg.Name()), detail)
+	}
+	return items
+}
+
+// calleeType returns the type of a function from an imported package or the prelude.
+func (s *Server) calleeType(imports map[string]string, callee string) (semantic.MonoType, bool) {
+	if i := strings.IndexByte(callee, '.'); i >= 0 {
+		pkgpath, ok := imports[callee[:i]]
+		if !ok {
+			return semantic.MonoType{}, false
+		}
+		pkg, err := s.importer.ImportPackageObject(pkgpath)
+		if err != nil {
+			return semantic.MonoType{}, false
+		}
+		v, ok := pkg.Get(callee[i+1:])
+		if !ok {
+			return semantic.MonoType{}, false
+		}
+		return v.Type(), true
+	}
+	v, err := complete.NewCompleter(s.prelude).Value(callee)
+	if err != nil {
+		return semantic.MonoType{}, false
+	}
+	return v.Type(), true
+}
+
+// localFunction returns the function that is assigned
+// to name at the top level of the file.
+func localFunction(file *ast.File, name string) *ast.FunctionExpression {
+	var fn *ast.FunctionExpression
+	for _, stmt := range file.Body {
+		if a, ok := stmt.(*ast.VariableAssignment); ok && a.ID.Name == name {
+			fn, _ = a.Init.(*ast.FunctionExpression)
+		}
+	}
+	return fn
+}
+
+// scopeCompletions suggests the names from the prelude, the
+// imported packages and the declarations that are visible
+// at the position.
+func (s *Server) scopeCompletions(file *ast.File, imports map[string]string, p ast.Position) []CompletionItem {
+	byName := make(map[string]CompletionItem)
+	c := complete.NewCompleter(s.prelude)
+	for _, name := range c.Names() {
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		v, err := c.Value(name)
+		if err != nil {
+			continue
+		}
+		byName[name] = valueCompletion(name, v)
+	}
+	for name, pkgpath := range imports {
+		byName[name] = CompletionItem{
+			Label:  name,
+			Kind:   CompletionKindModule,
+			Detail: pkgpath,
+		}
+	}
+
+	declare := func(stmts []ast.Statement) {
+		for _, stmt := range stmts {
+			var a *ast.VariableAssignment
+			switch stmt := stmt.(type) {
+			case *ast.VariableAssignment:
+				a = stmt
+			case *ast.OptionStatement:
+				a, _ = stmt.Assignment.(*ast.VariableAssignment)
+			}
+			if a == nil || !a.Location().End.Less(p) {
+				continue
+			}
+			kind := CompletionKindVariable
+			if _, ok := a.Init.(*ast.FunctionExpression); ok {
+				kind = CompletionKindFunction
+			}
+			byName[a.ID.Name] = CompletionItem{Label: a.ID.Name, Kind: kind}
+		}
+	}
+	declare(file.Body)
+	// The walk is depth first so the parameters and variables
+	// of inner functions shadow those of outer functions.
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		fn, ok := n.(*ast.FunctionExpression)
+		if !ok || !contains(fn.Location(), p) {
+			return
+		}
+		for _, param := range fn.Params {
+			if param.Key != nil {
+				name := param.Key.Key()
+				byName[name] = CompletionItem{Label: name, Kind: CompletionKindVariable}
+			}
+		}
+		if body, ok := fn.Body.(*ast.Block); ok {
+			declare(body.Body)
+		}
+	}), file)
+
+	items := make([]CompletionItem, 0, len(byName))
+	for _, item := range byName {
+		items = append(items, item)
+	}
+	sortCompletions(items)
+	return items
+}
+
+func valueCompletion(name string, v values.Value) CompletionItem {
+	kind := CompletionKindVariable
+	if v.Type().Nature() == semantic.Function {
+		kind = CompletionKindFunction
+	}
+	return CompletionItem{
+		Label:  name,
+		Kind:   kind,
+		Detail: v.Type().String(),
+	}
+}
+
+func sortCompletions(items []CompletionItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Label < items[j].Label
+	})
+}
+
+// enclosingCall finds the call whose argument list contains the end of
+// text. It returns the name of the called function, which may be a member
+// of a package, and the text of the arguments in front of the end.
+func enclosingCall(text string) (callee, args string, ok bool) {
+	depth := 0
+	for i := len(text) - 1; i >= 0; i-- {
+		switch text[i] {
+		case ')', ']', '}':
+			depth++
+		case '[', '{':
+			if depth == 0 {
+				return "", "", false
+			}
+			depth--
+		case '(':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			end := i
+			for end > 0 && isSpace(text[end-1]) {
+				end--
+			}
+			start := identStart(text, end)
+			if start > 0 && text[start-1] == '.' {
+				start = identStart(text, start-1)
+			}
+			if start == end {
+				return "", "", false
+			}
+			return text[start:end], text[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// isArgumentKey reports whether the end of text is
+// where the name of an argument is expected.
+func isArgumentKey(text string) bool {
+	text = strings.TrimRightFunc(text, func(r rune) bool {
+		return r < 0x80 && isSpace(byte(r))
+	})
+	return strings.HasSuffix(text, "(") || strings.HasSuffix(text, ",")
+}
+
+// identStart returns the offset of the start of the
+// identifier that ends at the end offset within text.
+func identStart(text string, end int) int {
+	start := end
+	for start > 0 && isIdentByte(text[start-1]) {
+		start--
+	}
+	return start
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,260 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+// declaration is the place where a name is introduced in a script.
+type declaration struct {
+	loc ast.SourceLocation
+	// path is the import path when the name refers to an imported package.
+	path string
+}
+
+// resolver walks the AST of a script to find the identifier at a
+// position and the declaration that the identifier refers to.
+//
+// The resolver only uses the AST so that definitions can be found
+// in scripts that do not pass semantic analysis.
+type resolver struct {
+	pos        ast.Position
+	importName func(path string) string
+
+	scopes  []map[string]declaration
+	parents []ast.Node
+
+	// found is set once the identifier at the position is visited.
+	found bool
+	// decl is the declaration of the identifier or, when member is set,
+	// the import that declares the package that contains the member.
+	decl   *declaration
+	member string
+	// global is set when the identifier is not declared
+	// in the script and may refer to the prelude.
+	global string
+}
+
+func (r *resolver) Visit(n ast.Node) ast.Visitor {
+	if r.found {
+		return nil
+	}
+
+	switch n := n.(type) {
+	case *ast.File:
+		r.scopes = append(r.scopes, make(map[string]declaration))
+		for _, imp := range n.Imports {
+			if imp.Path == nil {
+				continue
+			}
+			name := ""
+			if imp.As != nil {
+				name = imp.As.Name
+			} else {
+				name = r.importName(imp.Path.Value)
+			}
+			r.declare(name, declaration{
+				loc:  imp.Location(),
+				path: imp.Path.Value,
+			})
+		}
+	case *ast.FunctionExpression:
+		r.scopes = append(r.scopes, make(map[string]declaration))
+		for _, p := range n.Params {
+			if id, ok := p.Key.(*ast.Identifier); ok {
+				r.declare(id.Name, declaration{loc: id.Location()})
+			}
+		}
+	case *ast.MemberExpression:
+		if contains(n.Property.Location(), r.pos) {
+			r.found = true
+			if obj, ok := n.Object.(*ast.Identifier); ok {
+				if d, ok := r.lookup(obj.Name); ok && d.path != "" {
+					r.decl, r.member = &d, n.Property.Key()
+				}
+			}
+			return nil
+		}
+	case *ast.Identifier:
+		if contains(n.Location(), r.pos) {
+			r.found = true
+			r.resolve(n)
+			return nil
+		}
+	}
+	r.parents = append(r.parents, n)
+	return r
+}
+
+func (r *resolver) Done(n ast.Node) {
+	if r.found {
+		return
+	}
+
+	r.parents = r.parents[:len(r.parents)-1]
+	switch n := n.(type) {
+	case *ast.File, *ast.FunctionExpression:
+		r.scopes = r.scopes[:len(r.scopes)-1]
+	case *ast.VariableAssignment:
+		// Variables are declared once their assignment has been
+		// visited because the initializer cannot refer to them.
+		r.declare(n.ID.Name, declaration{loc: n.ID.Location()})
+	}
+}
+
+// resolve finds the declaration for the identifier at the position.
+func (r *resolver) resolve(id *ast.Identifier) {
+	self := &declaration{loc: id.Location()}
+	if len(r.parents) > 0 {
+		switch parent := r.parents[len(r.parents)-1].(type) {
+		case *ast.Property:
+			if parent.Key == id {
+				// A property key declares a parameter in a
+				// function and is not a reference anywhere else.
+				if len(r.parents) > 1 {
+					if _, ok := r.parents[len(r.parents)-2].(*ast.FunctionExpression); ok {
+						r.decl = self
+					}
+				}
+				return
+			}
+		case *ast.VariableAssignment:
+			if parent.ID == id {
+				r.decl = self
+				return
+			}
+		case *ast.BuiltinStatement:
+			if parent.ID == id {
+				r.decl = self
+				return
+			}
+		case *ast.ImportDeclaration:
+			r.decl = &declaration{loc: parent.Location(), path: parent.Path.Value}
+			return
+		}
+	}
+
+	if d, ok := r.lookup(id.Name); ok {
+		r.decl = &d
+		return
+	}
+	r.global = id.Name
+}
+
+func (r *resolver) declare(name string, d declaration) {
+	if len(r.scopes) > 0 {
+		r.scopes[len(r.scopes)-1][name] = d
+	}
+}
+
+func (r *resolver) lookup(name string) (declaration, bool) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if d, ok := r.scopes[i][name]; ok {
+			return d, true
+		}
+	}
+	return declaration{}, false
+}
+
+// importName returns the name that an import
+// of the package declares in a script.
+func (s *Server) importName(pkgpath string) string {
+	pkg, err := s.importer.ImportPackageObject(pkgpath)
+	if err != nil || pkg.Name() == "" {
+		return path.Base(pkgpath)
+	}
+	return pkg.Name()
+}
+
+// definition finds the location where the identifier at
+// the position is declared. Members of imported packages
+// and names from the prelude are found in the sources of
+// the standard library.
+func (s *Server) definition(doc *document, pos Position) []Location {
+	r := &resolver{
+		pos:        doc.astPosition(pos),
+		importName: s.importName,
+	}
+	ast.Walk(r, parser.ParseSource(doc.text))
+
+	switch {
+	case r.member != "":
+		return s.stdlibDefinition(r.decl.path, r.member)
+	case r.decl != nil:
+		return []Location{{
+			URI:   doc.uri,
+			Range: doc.lspRange(r.decl.loc),
+		}}
+	case r.global != "":
+		for _, pkgpath := range runtime.PreludeList {
+			if locs := s.stdlibDefinition(pkgpath, r.global); locs != nil {
+				return locs
+			}
+		}
+	}
+	return nil
+}
+
+// stdlibDefinition finds the declaration of name in a
+// package of the standard library.
+func (s *Server) stdlibDefinition(pkgpath, name string) []Location {
+	if s.opts.StdlibDir == "" {
+		return nil
+	}
+	pkg, ok := runtime.LookupPackage(pkgpath)
+	if !ok {
+		return nil
+	}
+
+	for _, file := range pkg.Files {
+		for _, stmt := range file.Body {
+			var id *semantic.Identifier
+			switch stmt := stmt.(type) {
+			case *semantic.NativeVariableAssignment:
+				id = stmt.Identifier
+			case *semantic.BuiltinStatement:
+				id = stmt.ID
+			case *semantic.OptionStatement:
+				if a, ok := stmt.Assignment.(*semantic.NativeVariableAssignment); ok {
+					id = a.Identifier
+				}
+			}
+			if id == nil || id.Name != name {
+				continue
+			}
+
+			fpath := filepath.Join(s.opts.StdlibDir, filepath.FromSlash(pkgpath), file.Loc.File)
+			src, err := ioutil.ReadFile(fpath)
+			if err != nil {
+				return nil
+			}
+			fdoc := newDocument(fileURI(fpath), string(src))
+			return []Location{{
+				URI:   fdoc.uri,
+				Range: fdoc.lspRange(id.Location()),
+			}}
+		}
+	}
+	return nil
+}
+
+// fileURI returns the file URI for the path.
+func fileURI(fpath string) string {
+	if abs, err := filepath.Abs(fpath); err == nil {
+		fpath = abs
+	}
+	p := filepath.ToSlash(fpath)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	u := url.URL{Scheme: "file", Path: p}
+	return u.String()
+}
@@ -0,0 +1,158 @@
+package lsp
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux/ast"
+)
+
+// document is the text of a Flux script opened by the client.
+//
+// The client addresses the text by zero based lines and UTF-16
+// code units while the flux parser reports one based lines and
+// byte columns. The document converts between the two.
+type document struct {
+	uri  string
+	text string
+
+	// lines holds the byte offset of the start of each line.
+	lines []int
+}
+
+func newDocument(uri, text string) *document {
+	lines := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return &document{
+		uri:   uri,
+		text:  text,
+		lines: lines,
+	}
+}
+
+// line returns the text of the zero based line n without its line ending.
+func (d *document) line(n int) string {
+	if n < 0 || n >= len(d.lines) {
+		return ""
+	}
+	end := len(d.text)
+	if n+1 < len(d.lines) {
+		end = d.lines[n+1] - 1
+	}
+	return d.text[d.lines[n]:end]
+}
+
+// offset returns the byte offset of the position within the text.
+func (d *document) offset(p Position) int {
+	if p.Line < 0 {
+		return 0
+	} else if p.Line >= len(d.lines) {
+		return len(d.text)
+	}
+	return d.lines[p.Line] + byteOffset(d.line(p.Line), p.Character)
+}
+
+// position returns the position of the byte offset within the text.
+func (d *document) position(offset int) Position {
+	if offset > len(d.text) {
+		offset = len(d.text)
+	}
+	n := sort.Search(len(d.lines), func(i int) bool {
+		return d.lines[i] > offset
+	}) - 1
+	if n < 0 {
+		return Position{}
+	}
+	line := d.line(n)
+	col := offset - d.lines[n]
+	if col > len(line) {
+		col = len(line)
+	}
+	return Position{
+		Line:      n,
+		Character: utf16Len(line[:col]),
+	}
+}
+
+// astPosition converts a position into the position used by the flux parser.
+func (d *document) astPosition(p Position) ast.Position {
+	return ast.Position{
+		Line:   p.Line + 1,
+		Column: byteOffset(d.line(p.Line), p.Character) + 1,
+	}
+}
+
+// lspPosition converts a position reported by the flux parser.
+func (d *document) lspPosition(p ast.Position) Position {
+	if p.Line <= 0 {
+		return Position{}
+	}
+	line := d.line(p.Line - 1)
+	col := p.Column - 1
+	if col < 0 {
+		col = 0
+	} else if col > len(line) {
+		col = len(line)
+	}
+	return Position{
+		Line:      p.Line - 1,
+		Character: utf16Len(line[:col]),
+	}
+}
+
+// lspRange converts a source location reported by the flux parser.
+func (d *document) lspRange(loc ast.SourceLocation) Range {
+	return Range{
+		Start: d.lspPosition(loc.Start),
+		End:   d.lspPosition(loc.End),
+	}
+}
+
+// fullRange returns the range that spans the entire document.
+func (d *document) fullRange() Range {
+	return Range{
+		End: d.position(len(d.text)),
+	}
+}
+
+// byteOffset returns the byte offset within line of
+// the character offset counted in UTF-16 code units.
+func byteOffset(line string, character int) int {
+	n := 0
+	for i, r := range line {
+		if n >= character {
+			return i
+		}
+		n += utf16RuneLen(r)
+	}
+	return len(line)
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+// contains reports whether the source location contains the position.
+// The end of the location is included so that a position directly
+// after an identifier still refers to it.
+func contains(loc ast.SourceLocation, p ast.Position) bool {
+	if loc.Start.Line == 0 {
+		return false
+	}
+	return !p.Less(loc.Start) && !loc.End.Less(p)
+}
@@ -0,0 +1,115 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Error codes defined by JSON-RPC and the Language Server Protocol.
+const (
+	codeParseError           = -32700
+	codeInvalidRequest       = -32600
+	codeMethodNotFound       = -32601
+	codeInvalidParams        = -32602
+	codeInternalError        = -32603
+	codeServerNotInitialized = -32002
+	codeRequestFailed        = -32803
+)
+
+// request is a JSON-RPC request or notification sent by the client.
+// Notifications do not have an ID.
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+func (r *request) isNotification() bool {
+	return r.ID == nil
+}
+
+// response is the reply to a request that succeeded.
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+}
+
+// errorResponse is the reply to a request that failed.
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Error   *ResponseError   `json:"error"`
+}
+
+// notification is a message sent by the server
+// that does not expect a reply.
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// ResponseError is an error returned to the client in reply to a request.
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+func errorf(code int, format string, a ...interface{}) *ResponseError {
+	return &ResponseError{
+		Code:    code,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// readMessage reads the content of the next message from r.
+// Each message is preceded by a set of headers terminated
+// by an empty line and the Content-Length header is required.
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	tr := textproto.NewReader(r)
+	header, err := tr.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	v := header.Get("Content-Length")
+	if v == "" {
+		return nil, errorf(codeParseError, "missing Content-Length header")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil || n < 0 {
+		return nil, errorf(codeParseError, "invalid Content-Length header %q", v)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// writeMessage writes v to w as a JSON message
+// preceded by its Content-Length header.
+func writeMessage(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
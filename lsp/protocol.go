@@ -0,0 +1,174 @@
+package lsp
+
+// The types in this file are the subset of the Language Server Protocol
+// that the flux language server uses. The names and fields follow the
+// specification at https://microsoft.github.io/language-server-protocol/.
+
+// Position is a zero based line and character offset within a document.
+// The character offset is counted in UTF-16 code units.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is the span between two positions within a document.
+// The end position is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range within the document referenced by URI.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// DiagnosticSeverity is the severity of a diagnostic.
+type DiagnosticSeverity int
+
+const (
+	SeverityError       DiagnosticSeverity = 1
+	SeverityWarning     DiagnosticSeverity = 2
+	SeverityInformation DiagnosticSeverity = 3
+	SeverityHint        DiagnosticSeverity = 4
+)
+
+// Diagnostic is an error or warning reported for a document.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams is sent by the server to report
+// the diagnostics of a document.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// TextDocumentSyncKind determines how the client sends document changes.
+type TextDocumentSyncKind int
+
+const (
+	SyncNone        TextDocumentSyncKind = 0
+	SyncFull        TextDocumentSyncKind = 1
+	SyncIncremental TextDocumentSyncKind = 2
+)
+
+// CompletionOptions describe the completion support of the server.
+type CompletionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+}
+
+// ServerCapabilities are the language features the server supports.
+type ServerCapabilities struct {
+	TextDocumentSync           TextDocumentSyncKind `json:"textDocumentSync"`
+	HoverProvider              bool                 `json:"hoverProvider"`
+	CompletionProvider         *CompletionOptions   `json:"completionProvider,omitempty"`
+	DefinitionProvider         bool                 `json:"definitionProvider"`
+	DocumentFormattingProvider bool                 `json:"documentFormattingProvider"`
+}
+
+// ServerInfo identifies the server to the client.
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// InitializeResult is the response to the initialize request.
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   *ServerInfo        `json:"serverInfo,omitempty"`
+}
+
+// TextDocumentIdentifier references a document by its URI.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// TextDocumentItem is a document that was opened by the client.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// DidOpenTextDocumentParams are the parameters of textDocument/didOpen.
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// TextDocumentContentChangeEvent is a change to a document. The server
+// uses full document synchronization so Text is the entire document.
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+// DidChangeTextDocumentParams are the parameters of textDocument/didChange.
+type DidChangeTextDocumentParams struct {
+	TextDocument   TextDocumentIdentifier           `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+// DidCloseTextDocumentParams are the parameters of textDocument/didClose.
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// TextDocumentPositionParams reference a position within a document.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// MarkupContent is formatted text displayed by the client.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// Hover is the response to textDocument/hover.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// CompletionItemKind is the kind of a completion item.
+type CompletionItemKind int
+
+const (
+	CompletionKindFunction CompletionItemKind = 3
+	CompletionKindField    CompletionItemKind = 5
+	CompletionKindVariable CompletionItemKind = 6
+	CompletionKindModule   CompletionItemKind = 9
+	CompletionKindProperty CompletionItemKind = 10
+)
+
+// CompletionItem is a single completion suggestion.
+type CompletionItem struct {
+	Label      string             `json:"label"`
+	Kind       CompletionItemKind `json:"kind,omitempty"`
+	Detail     string             `json:"detail,omitempty"`
+	InsertText string             `json:"insertText,omitempty"`
+}
+
+// CompletionList is the response to textDocument/completion.
+type CompletionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+// DocumentFormattingParams are the parameters of textDocument/formatting.
+type DocumentFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// TextEdit replaces the text within a range of a document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
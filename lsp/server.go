@@ -0,0 +1,237 @@
+// Package lsp implements a Language Server Protocol server for Flux.
+//
+// The server communicates with a single client over a pair of streams,
+// usually the standard input and output of the flux process, and
+// provides diagnostics, hover, completion, go to definition and
+// formatting for Flux scripts.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/values"
+)
+
+// Options configure the language server.
+type Options struct {
+	// StdlibDir is the directory that contains the sources of the
+	// Flux standard library. It is used to resolve the definitions
+	// of imported package members. Definitions within imported
+	// packages are not resolved when it is empty.
+	StdlibDir string
+}
+
+// Server is a language server for Flux scripts.
+type Server struct {
+	opts     Options
+	prelude  values.Scope
+	importer interpreter.Importer
+
+	docs        map[string]*document
+	initialized bool
+	shutdown    bool
+
+	w io.Writer
+}
+
+// NewServer creates a language server. The flux runtime
+// must be finalized before the server is created.
+func NewServer(opts Options) *Server {
+	return &Server{
+		opts:     opts,
+		prelude:  runtime.Prelude(),
+		importer: runtime.StdLib(),
+		docs:     make(map[string]*document),
+	}
+}
+
+// Serve reads messages from r and writes the replies to w until the
+// client sends the exit notification or r is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+	br := bufio.NewReader(r)
+	for {
+		data, err := readMessage(br)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(data, &req); err != nil {
+			if err := s.reply(nil, nil, errorf(codeParseError, "invalid message: %s", err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(&req)
+		if req.isNotification() {
+			// Notifications cannot be replied to so errors
+			// that occur while handling them are dropped.
+			continue
+		}
+		if err := s.reply(req.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req *request) (interface{}, error) {
+	if !s.initialized && req.Method != "initialize" {
+		return nil, errorf(codeServerNotInitialized, "server is not initialized")
+	}
+	if s.shutdown {
+		return nil, errorf(codeInvalidRequest, "server is shutting down")
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		return &InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync: SyncFull,
+				HoverProvider:    true,
+				CompletionProvider: &CompletionOptions{
+					TriggerCharacters: []string{".", "(", ","},
+				},
+				DefinitionProvider:         true,
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: &ServerInfo{Name: "flux"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		doc := newDocument(params.TextDocument.URI, params.TextDocument.Text)
+		s.docs[doc.uri] = doc
+		return nil, s.publishDiagnostics(doc)
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// The server uses full synchronization so the
+		// last change contains the entire document.
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		doc := newDocument(params.TextDocument.URI, text)
+		s.docs[doc.uri] = doc
+		return nil, s.publishDiagnostics(doc)
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		doc, err := s.documentParams(req, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return hover(doc, params.Position), nil
+	case "textDocument/completion":
+		var params TextDocumentPositionParams
+		doc, err := s.documentParams(req, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return s.completion(doc, params.Position), nil
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		doc, err := s.documentParams(req, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return s.definition(doc, params.Position), nil
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		doc, err := s.documentParams(req, &params, &params.TextDocument)
+		if err != nil {
+			return nil, err
+		}
+		return format(doc)
+	default:
+		return nil, errorf(codeMethodNotFound, "method %q is not supported", req.Method)
+	}
+}
+
+func unmarshalParams(req *request, params interface{}) error {
+	if err := json.Unmarshal(req.Params, params); err != nil {
+		return errorf(codeInvalidParams, "invalid params for %s: %s", req.Method, err)
+	}
+	return nil
+}
+
+// documentParams decodes the request parameters and returns
+// the open document that they reference.
+func (s *Server) documentParams(req *request, params interface{}, id *TextDocumentIdentifier) (*document, error) {
+	if err := unmarshalParams(req, params); err != nil {
+		return nil, err
+	}
+	doc, ok := s.docs[id.URI]
+	if !ok {
+		return nil, errorf(codeRequestFailed, "document %s is not open", id.URI)
+	}
+	return doc, nil
+}
+
+func (s *Server) publishDiagnostics(doc *document) error {
+	return s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: diagnostics(doc),
+	})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return writeMessage(s.w, &notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, err error) error {
+	if err != nil {
+		rerr, ok := err.(*ResponseError)
+		if !ok {
+			rerr = &ResponseError{
+				Code:    codeInternalError,
+				Message: err.Error(),
+			}
+		}
+		return writeMessage(s.w, &errorResponse{
+			JSONRPC: "2.0",
+			ID:      id,
+			Error:   rerr,
+		})
+	}
+	return writeMessage(s.w, &response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	})
+}
@@ -0,0 +1,434 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/lsp"
+)
+
+// session runs the language server over a sequence of
+// client messages and returns the messages that it wrote.
+func session(t *testing.T, opts lsp.Options, msgs ...interface{}) []map[string]json.RawMessage {
+	t.Helper()
+
+	var in bytes.Buffer
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(data), data)
+	}
+
+	var out bytes.Buffer
+	if err := lsp.NewServer(opts).Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var replies []map[string]json.RawMessage
+	r := bufio.NewReader(&out)
+	for {
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err == io.EOF {
+			return replies
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			t.Fatal(err)
+		}
+		var reply map[string]json.RawMessage
+		if err := json.Unmarshal(data, &reply); err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, reply)
+	}
+}
+
+type message map[string]interface{}
+
+func initialize() message {
+	return message{"jsonrpc": "2.0", "id": 0, "method": "initialize", "params": message{}}
+}
+
+func didOpen(text string) message {
+	return message{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/didOpen",
+		"params": message{
+			"textDocument": message{"uri": "file:///query.flux", "languageId": "flux", "version": 1, "text": text},
+		},
+	}
+}
+
+func positionRequest(id int, method string, line, character int) message {
+	return message{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params": message{
+			"textDocument": message{"uri": "file:///query.flux"},
+			"position":     message{"line": line, "character": character},
+		},
+	}
+}
+
+// result finds the reply to the request with the given id
+// and decodes its result into v.
+func result(t *testing.T, replies []map[string]json.RawMessage, id int, v interface{}) {
+	t.Helper()
+	for _, reply := range replies {
+		if string(reply["id"]) != strconv.Itoa(id) {
+			continue
+		}
+		if e, ok := reply["error"]; ok {
+			t.Fatalf("unexpected error in reply to request %d: %s", id, e)
+		}
+		if err := json.Unmarshal(reply["result"], v); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	t.Fatalf("no reply to request %d", id)
+}
+
+func TestServer_Initialize(t *testing.T) {
+	replies := session(t, lsp.Options{}, initialize())
+
+	var got lsp.InitializeResult
+	result(t, replies, 0, &got)
+	want := lsp.ServerCapabilities{
+		TextDocumentSync: lsp.SyncFull,
+		HoverProvider:    true,
+		CompletionProvider: &lsp.CompletionOptions{
+			TriggerCharacters: []string{".", "(", ","},
+		},
+		DefinitionProvider:         true,
+		DocumentFormattingProvider: true,
+	}
+	if !cmp.Equal(want, got.Capabilities) {
+		t.Errorf("unexpected capabilities -want/+got:\n%s", cmp.Diff(want, got.Capabilities))
+	}
+}
+
+func TestServer_NotInitialized(t *testing.T) {
+	replies := session(t, lsp.Options{}, positionRequest(1, "textDocument/hover", 0, 0))
+	if len(replies) != 1 {
+		t.Fatalf("expected one reply, got %d", len(replies))
+	}
+	var got lsp.ResponseError
+	if err := json.Unmarshal(replies[0]["error"], &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := (lsp.ResponseError{Code: -32002, Message: "server is not initialized"}); got != want {
+		t.Errorf("unexpected error -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		text string
+		want []lsp.Diagnostic
+	}{
+		{
+			name: "valid",
+			text: "x = 1\ny = x + 2\n",
+			want: []lsp.Diagnostic{},
+		},
+		{
+			name: "type error",
+			text: "x = 1\ny = x + \"a\"\n",
+			want: []lsp.Diagnostic{{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 8},
+					End:   lsp.Position{Line: 1, Character: 11},
+				},
+				Severity: lsp.SeverityError,
+				Source:   "flux",
+				Message:  "expected int but found string",
+			}},
+		},
+		{
+			name: "undefined identifier",
+			text: "x = y\n",
+			want: []lsp.Diagnostic{{
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Character: 4},
+					End:   lsp.Position{Line: 0, Character: 5},
+				},
+				Severity: lsp.SeverityError,
+				Source:   "flux",
+				Message:  "undefined identifier y",
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := session(t, lsp.Options{}, initialize(), didOpen(tc.text))
+			if len(replies) != 2 {
+				t.Fatalf("expected two messages, got %d", len(replies))
+			}
+			if got, want := string(replies[1]["method"]), `"textDocument/publishDiagnostics"`; got != want {
+				t.Fatalf("unexpected method: want %s, got %s", want, got)
+			}
+			var got lsp.PublishDiagnosticsParams
+			if err := json.Unmarshal(replies[1]["params"], &got); err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, got.Diagnostics) {
+				t.Errorf("unexpected diagnostics -want/+got:\n%s", cmp.Diff(tc.want, got.Diagnostics))
+			}
+		})
+	}
+}
+
+func TestServer_SyntaxError(t *testing.T) {
+	replies := session(t, lsp.Options{}, initialize(), didOpen("x = (\n"))
+	var got lsp.PublishDiagnosticsParams
+	if err := json.Unmarshal(replies[1]["params"], &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Diagnostics) == 0 {
+		t.Fatal("expected a diagnostic for the syntax error")
+	}
+}
+
+func TestServer_Hover(t *testing.T) {
+	text := "import \"strings\"\nx = 1\ny = strings.toUpper(v: \"a\")\n"
+	for _, tc := range []struct {
+		name      string
+		line, col int
+		want      string
+	}{
+		{name: "variable declaration", line: 1, col: 0, want: "x: int"},
+		{name: "package member", line: 2, col: 14, want: "toUpper: (v: string) => string"},
+		{name: "nothing", line: 1, col: 4},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := session(t, lsp.Options{},
+				initialize(),
+				didOpen(text),
+				positionRequest(1, "textDocument/hover", tc.line, tc.col),
+			)
+			var got *lsp.Hover
+			result(t, replies, 1, &got)
+			if tc.want == "" {
+				if got != nil {
+					t.Fatalf("unexpected hover: %v", got.Contents.Value)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a hover")
+			}
+			if want := "```flux\n" + tc.want + "\n```"; got.Contents.Value != want {
+				t.Errorf("unexpected hover -want/+got:\n%s", cmp.Diff(want, got.Contents.Value))
+			}
+		})
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		text      string
+		line, col int
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:    "package members",
+			text:    "import \"strings\"\nstrings.toU",
+			line:    1,
+			col:     11,
+			want:    []string{"toUpper"},
+			notWant: []string{"toLower"},
+		},
+		{
+			name:    "named parameters",
+			text:    "import \"strings\"\nstrings.replace(v: \"a\", ",
+			line:    1,
+			col:     24,
+			want:    []string{"i", "t", "u"},
+			notWant: []string{"v"},
+		},
+		{
+			name:    "prelude parameters",
+			text:    "from(bucket: \"a\") |> range(",
+			line:    0,
+			col:     27,
+			want:    []string{"start", "stop"},
+			notWant: []string{"tables"},
+		},
+		{
+			name:    "local function parameters",
+			text:    "f = (a, b) => a + b\nf(",
+			line:    1,
+			col:     2,
+			want:    []string{"a", "b"},
+			notWant: []string{"f"},
+		},
+		{
+			name:    "names in scope",
+			text:    "import \"strings\"\nmyVar = 1\nf = (myParam) => my",
+			line:    2,
+			col:     19,
+			want:    []string{"myParam", "myVar"},
+			notWant: []string{"strings"},
+		},
+		{
+			name: "prelude",
+			text: "fil",
+			line: 0,
+			col:  3,
+			want: []string{"fill", "filter"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := session(t, lsp.Options{},
+				initialize(),
+				didOpen(tc.text),
+				positionRequest(1, "textDocument/completion", tc.line, tc.col),
+			)
+			var got lsp.CompletionList
+			result(t, replies, 1, &got)
+			labels := make(map[string]bool)
+			for _, item := range got.Items {
+				labels[item.Label] = true
+			}
+			for _, label := range tc.want {
+				if !labels[label] {
+					t.Errorf("expected completion %q", label)
+				}
+			}
+			for _, label := range tc.notWant {
+				if labels[label] {
+					t.Errorf("unexpected completion %q", label)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_Definition(t *testing.T) {
+	text := "x = 1\nf = (x) => x + 1\ny = x + f(x: 2)\n"
+	for _, tc := range []struct {
+		name      string
+		line, col int
+		want      []lsp.Location
+	}{
+		{
+			name: "variable",
+			line: 2,
+			col:  4,
+			want: []lsp.Location{{
+				URI: "file:///query.flux",
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 0, Character: 0},
+					End:   lsp.Position{Line: 0, Character: 1},
+				},
+			}},
+		},
+		{
+			name: "parameter",
+			line: 1,
+			col:  11,
+			want: []lsp.Location{{
+				URI: "file:///query.flux",
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 5},
+					End:   lsp.Position{Line: 1, Character: 6},
+				},
+			}},
+		},
+		{
+			name: "function",
+			line: 2,
+			col:  8,
+			want: []lsp.Location{{
+				URI: "file:///query.flux",
+				Range: lsp.Range{
+					Start: lsp.Position{Line: 1, Character: 0},
+					End:   lsp.Position{Line: 1, Character: 1},
+				},
+			}},
+		},
+		{
+			name: "argument key",
+			line: 2,
+			col:  10,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			replies := session(t, lsp.Options{},
+				initialize(),
+				didOpen(text),
+				positionRequest(1, "textDocument/definition", tc.line, tc.col),
+			)
+			var got []lsp.Location
+			result(t, replies, 1, &got)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected definition -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestServer_DefinitionInPackage(t *testing.T) {
+	text := "import \"strings\"\nstrings.toUpper(v: \"a\")\n"
+	replies := session(t, lsp.Options{StdlibDir: "../stdlib"},
+		initialize(),
+		didOpen(text),
+		positionRequest(1, "textDocument/definition", 1, 10),
+	)
+	var got []lsp.Location
+	result(t, replies, 1, &got)
+	if len(got) != 1 {
+		t.Fatalf("expected one location, got %v", got)
+	}
+	if want := "/stdlib/strings/strings.flux"; !strings.HasSuffix(got[0].URI, want) {
+		t.Errorf("unexpected definition: want a location in %s, got %s", want, got[0].URI)
+	}
+}
+
+func TestServer_Formatting(t *testing.T) {
+	replies := session(t, lsp.Options{},
+		initialize(),
+		didOpen("x=1\ny=x+  2"),
+		message{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "textDocument/formatting",
+			"params": message{
+				"textDocument": message{"uri": "file:///query.flux"},
+				"options":      message{"tabSize": 4, "insertSpaces": true},
+			},
+		},
+	)
+	var got []lsp.TextEdit
+	result(t, replies, 1, &got)
+	want := []lsp.TextEdit{{
+		Range: lsp.Range{
+			End: lsp.Position{Line: 1, Character: 7},
+		},
+		NewText: "x = 1\ny = x + 2\n",
+	}}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected edits -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
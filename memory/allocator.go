@@ -26,7 +26,14 @@ type Allocator struct {
 	bytesAllocated  int64
 	maxAllocated    int64
 	totalAllocated  int64
+	totalSpilled    int64
+	spillCount      int64
 	mu              sync.Mutex
+	spillers        []Spiller
+
+	// parent is the allocator that the memory
+	// of a child allocator is accounted to.
+	parent *Allocator
 
 	// Limit is the limit on the amount of memory that this allocator
 	// can assign. If this is null, there is no limit.
@@ -42,6 +49,27 @@ type Allocator struct {
 	// allocate and free memory.
 	// If this is unset, the DefaultAllocator is used.
 	Allocator memory.Allocator
+
+	// SpillDir is the directory where registered Spillers may write
+	// their buffered data when the limit would be exceeded and the
+	// Manager refuses to give out more memory.
+	// If this is empty, spilling is disabled.
+	SpillDir string
+}
+
+// Child returns an allocator that accounts for the memory it allocates in
+// its own counters as well as in this allocator. The limit, manager and
+// spillers of this allocator apply to the memory of the child, so the child
+// can be used in place of this allocator to measure the memory used by one
+// part of a query.
+func (a *Allocator) Child() *Allocator {
+	if a == nil {
+		return &Allocator{}
+	}
+	return &Allocator{
+		parent:   a,
+		SpillDir: a.SpillDir,
+	}
 }
 
 // Allocate will ensure that the requested memory is available and
@@ -130,10 +158,18 @@ func (a *Allocator) Free(b []byte) {
 	alloc.Free(b)
 
 	// Release the memory in our accounting.
-	atomic.AddInt64(&a.bytesAllocated, int64(-size))
+	for ; a != nil; a = a.parent {
+		atomic.AddInt64(&a.bytesAllocated, int64(-size))
+	}
 }
 
 func (a *Allocator) count(size int) error {
+	if a.parent != nil {
+		if err := a.parent.count(size); err != nil {
+			return err
+		}
+	}
+
 	var c int64
 	if a.Limit != nil {
 		// We need to load the current bytes allocated, add to it, and
@@ -201,6 +237,14 @@ func (a *Allocator) requestMemory(allocated, want int64) error {
 		// Ignore the error. We use our own custom one so we just
 		// needed to know it failed.
 	}
+
+	// As a last resort, ask the spillers to move their data to disk.
+	// If any memory was released, try again.
+	if ok, err := a.spill(allocated); ok {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, codes.ResourceExhausted, "memory allocation limit reached and spilling to disk failed")
+	}
 	return errors.Wrap(LimitExceededError{
 		Limit:     *a.Limit,
 		Allocated: allocated,
@@ -210,6 +254,9 @@ func (a *Allocator) requestMemory(allocated, want int64) error {
 
 // allocator returns the underlying memory.Allocator that should be used.
 func (a *Allocator) allocator() memory.Allocator {
+	if a.parent != nil {
+		return a.parent.allocator()
+	}
 	if a.Allocator == nil {
 		return DefaultAllocator
 	}
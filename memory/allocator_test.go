@@ -341,3 +341,133 @@ func TestAllocator_RequestMemory_Concurrently(t *testing.T) {
 		t.Fatalf("unexpected memory left in the manager -want/+got\n\t- %d\n\t+ %d", want, got)
 	}
 }
+
+type mockSpiller struct {
+	allocator *memory.Allocator
+	held      []byte
+}
+
+func (m *mockSpiller) Spill() (int64, error) {
+	if m.held == nil {
+		return 0, nil
+	}
+	n := int64(len(m.held))
+	m.allocator.Free(m.held)
+	m.held = nil
+	return n, nil
+}
+
+func TestAllocator_Spill(t *testing.T) {
+	maxLimit := int64(64)
+	allocator := &memory.Allocator{
+		Limit:    &maxLimit,
+		SpillDir: t.TempDir(),
+	}
+	spiller := &mockSpiller{allocator: allocator}
+	if !allocator.RegisterSpiller(spiller) {
+		t.Fatal("expected spiller to be registered")
+	}
+	spiller.held = allocator.Allocate(64)
+
+	// This allocation exceeds the limit so the spiller
+	// should release its memory to make room.
+	b := allocator.Allocate(32)
+
+	if want, got := int64(32), allocator.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(64), allocator.TotalSpilled(); want != got {
+		t.Fatalf("unexpected total spilled -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(1), allocator.SpillCount(); want != got {
+		t.Fatalf("unexpected spill count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+
+	// There is nothing left to spill so this should fail.
+	if err := allocator.Account(64); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// The spiller should not be used after it is unregistered.
+	allocator.UnregisterSpiller(spiller)
+	allocator.Free(b)
+	spiller.held = allocator.Allocate(64)
+	if err := allocator.Account(1); err == nil {
+		t.Fatal("expected error")
+	}
+	allocator.Free(spiller.held)
+}
+
+func TestAllocator_SpillDisabled(t *testing.T) {
+	maxLimit := int64(64)
+	allocator := &memory.Allocator{Limit: &maxLimit}
+	spiller := &mockSpiller{allocator: allocator}
+	if allocator.RegisterSpiller(spiller) {
+		t.Fatal("expected spiller to not be registered")
+	}
+	spiller.held = allocator.Allocate(64)
+	if err := allocator.Account(1); err == nil {
+		t.Fatal("expected error")
+	}
+	if want, got := int64(0), allocator.TotalSpilled(); want != got {
+		t.Fatalf("unexpected total spilled -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
+
+func TestAllocator_Child(t *testing.T) {
+	mem := arrowmemory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	maxLimit := int64(128)
+	parent := &memory.Allocator{
+		Limit:     &maxLimit,
+		Allocator: mem,
+		SpillDir:  t.TempDir(),
+	}
+	a, b := parent.Child(), parent.Child()
+
+	// The memory of each child is also accounted to the parent.
+	ba := a.Allocate(64)
+	bb := b.Allocate(32)
+	mem.AssertSize(t, 96)
+	if want, got := int64(64), a.TotalAllocated(); want != got {
+		t.Fatalf("unexpected total allocated -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(32), b.TotalAllocated(); want != got {
+		t.Fatalf("unexpected total allocated -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(96), parent.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+
+	// The limit of the parent applies to the children.
+	if err := b.Account(64); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// A spiller of a child is used when the parent reaches its limit.
+	spiller := &mockSpiller{allocator: a, held: ba}
+	if !a.RegisterSpiller(spiller) {
+		t.Fatal("expected spiller to be registered")
+	}
+	bc := b.Allocate(64)
+	if want, got := int64(0), a.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(96), parent.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(64), parent.TotalSpilled(); want != got {
+		t.Fatalf("unexpected total spilled -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	a.UnregisterSpiller(spiller)
+
+	b.Free(bb)
+	b.Free(bc)
+	if want, got := int64(0), parent.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(96), b.TotalAllocated(); want != got {
+		t.Fatalf("unexpected total allocated -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
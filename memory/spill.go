@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sync/atomic"
+)
+
+// Spiller is implemented by buffered data that can be moved out
+// of memory when the Allocator reaches its limit.
+//
+// A Spiller is registered with an Allocator using RegisterSpiller.
+// When an allocation would exceed the limit and the Manager cannot
+// provide any more memory, the Allocator will ask each registered
+// Spiller to write its data to disk and release the memory
+// it was holding.
+//
+// Spill may be invoked from any goroutine that allocates memory
+// with the Allocator so implementations must be safe to call
+// concurrently with their other methods. Spill is called while
+// the Allocator holds an internal lock so it must not allocate
+// memory from the same Allocator. If the Spiller is busy and
+// cannot spill without blocking, it should return immediately.
+type Spiller interface {
+	// Spill writes buffered data to disk and releases the memory
+	// associated with it. It returns the number of bytes that
+	// were written to disk.
+	Spill() (n int64, err error)
+}
+
+// RegisterSpiller registers a Spiller that can be used to release
+// memory when the allocation limit is reached.
+// If spilling is not enabled for this Allocator, this does nothing
+// and returns false. The spillers of a child allocator are
+// registered with the allocator that enforces the limit.
+func (a *Allocator) RegisterSpiller(s Spiller) bool {
+	if !a.SpillEnabled() {
+		return false
+	}
+	if a.parent != nil {
+		return a.parent.RegisterSpiller(s)
+	}
+	a.mu.Lock()
+	a.spillers = append(a.spillers, s)
+	a.mu.Unlock()
+	return true
+}
+
+// UnregisterSpiller removes a Spiller that was registered with
+// RegisterSpiller. Once this returns, the Spiller will not be
+// invoked by the Allocator again.
+func (a *Allocator) UnregisterSpiller(s Spiller) {
+	if a == nil {
+		return
+	}
+	if a.parent != nil {
+		a.parent.UnregisterSpiller(s)
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, other := range a.spillers {
+		if other == s {
+			a.spillers = append(a.spillers[:i], a.spillers[i+1:]...)
+			return
+		}
+	}
+}
+
+// SpillEnabled reports whether this Allocator allows registered
+// Spillers to write data to disk.
+func (a *Allocator) SpillEnabled() bool {
+	return a != nil && a.SpillDir != ""
+}
+
+// TotalSpilled reports the total number of bytes written to disk
+// by spillers to make room for new allocations.
+func (a *Allocator) TotalSpilled() int64 {
+	return atomic.LoadInt64(&a.totalSpilled)
+}
+
+// SpillCount reports the number of times buffered data was
+// written to disk by a spiller.
+func (a *Allocator) SpillCount() int64 {
+	return atomic.LoadInt64(&a.spillCount)
+}
+
+// spill asks the registered spillers to release memory.
+// It reports whether the amount of allocated memory fell below
+// the amount that was allocated when the request was made.
+// This must be called with the lock held.
+func (a *Allocator) spill(allocated int64) (bool, error) {
+	var firstErr error
+	for _, s := range a.spillers {
+		n, err := s.Spill()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if n > 0 {
+			atomic.AddInt64(&a.totalSpilled, n)
+			atomic.AddInt64(&a.spillCount, 1)
+		}
+		if atomic.LoadInt64(&a.bytesAllocated) < allocated {
+			return true, nil
+		}
+	}
+	return false, firstErr
+}
@@ -1,7 +1,11 @@
 package plan
 
+// Statistics holds estimates of the data produced by a plan node.
+// A zero value means the estimate is unknown.
 type Statistics struct {
-	Cardinality      int64
+	// Cardinality is the estimated number of rows.
+	Cardinality int64
+	// GroupCardinality is the estimated number of tables.
 	GroupCardinality int64
 }
 
@@ -25,9 +29,73 @@ func Add(a Cost, b Cost) Cost {
 	}
 }
 
+// Total returns the sum of all of the dimensions of the cost.
+// It is used to compare alternative plans with each other.
+func (c Cost) Total() int64 {
+	return c.Disk + c.CPU + c.GPU + c.MEM + c.NET
+}
+
 type DefaultCost struct {
 }
 
 func (c DefaultCost) Cost(inStats []Statistics) (Cost, Statistics) {
 	return Cost{}, Statistics{}
 }
+
+// EstimateCost returns the estimated cost of the plan rooted at node
+// including the cost of all of its predecessors, and the statistics
+// of the data produced by the node.
+func EstimateCost(node Node) (Cost, Statistics) {
+	est := newCostEstimator().estimate(node)
+	return est.total, est.stats
+}
+
+// costEstimate is the estimated cost of a single plan node.
+type costEstimate struct {
+	// self is the cost of the node alone and total
+	// includes the cost of its predecessors.
+	self, total Cost
+	stats       Statistics
+}
+
+// costEstimator estimates the cost of plan nodes and remembers
+// the estimates so each node is only visited once.
+type costEstimator struct {
+	estimates map[Node]costEstimate
+}
+
+func newCostEstimator() *costEstimator {
+	return &costEstimator{
+		estimates: make(map[Node]costEstimate),
+	}
+}
+
+func (ce *costEstimator) estimate(node Node) costEstimate {
+	if est, ok := ce.estimates[node]; ok {
+		return est
+	}
+
+	var (
+		inStats []Statistics
+		est     costEstimate
+	)
+	for _, pred := range node.Predecessors() {
+		predEst := ce.estimate(pred)
+		inStats = append(inStats, predEst.stats)
+		est.total = Add(est.total, predEst.total)
+	}
+
+	if ppn, ok := node.(*PhysicalPlanNode); ok {
+		est.self, est.stats = ppn.Cost(inStats)
+	} else {
+		// Logical nodes do not have a cost so pass
+		// the statistics of the inputs through.
+		for _, stats := range inStats {
+			est.stats.Cardinality += stats.Cardinality
+			est.stats.GroupCardinality += stats.GroupCardinality
+		}
+	}
+	est.total = Add(est.total, est.self)
+	ce.estimates[node] = est
+	return est
+}
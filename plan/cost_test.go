@@ -0,0 +1,171 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+// costSpec is a procedure spec with a fixed cost. If rows is
+// set, it is reported as the output cardinality. Otherwise,
+// the cardinality of the input is passed through.
+type costSpec struct {
+	kind plan.ProcedureKind
+	cost plan.Cost
+	rows int64
+}
+
+func (s *costSpec) Kind() plan.ProcedureKind {
+	return s.kind
+}
+
+func (s *costSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func (s *costSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	var stats plan.Statistics
+	for _, in := range inStats {
+		stats.Cardinality += in.Cardinality
+	}
+	if s.rows > 0 {
+		stats.Cardinality = s.rows
+	}
+	return s.cost, stats
+}
+
+// replaceRule replaces nodes of a kind with a node using spec.
+type replaceRule struct {
+	name string
+	kind plan.ProcedureKind
+	spec *costSpec
+}
+
+func (r replaceRule) Name() string {
+	return r.name
+}
+
+func (r replaceRule) Pattern() plan.Pattern {
+	return plan.PhysPat(r.kind, plan.Any())
+}
+
+func (r replaceRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	newNode := plan.CreatePhysicalNode(node.ID(), r.spec.Copy().(*costSpec))
+	plan.ReplaceNode(node, newNode)
+	return newNode, true, nil
+}
+
+func TestEstimateCost(t *testing.T) {
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("0", &costSpec{kind: "source", cost: plan.Cost{Disk: 10}, rows: 100}),
+			plan.CreatePhysicalNode("1", &costSpec{kind: "source", cost: plan.Cost{NET: 5}, rows: 20}),
+			plan.CreatePhysicalNode("2", &costSpec{kind: "union", cost: plan.Cost{CPU: 3}}),
+			plan.CreatePhysicalNode("3", &costSpec{kind: "limit", cost: plan.Cost{MEM: 1}, rows: 7}),
+		},
+		Edges: [][2]int{
+			{0, 2},
+			{1, 2},
+			{2, 3},
+		},
+	})
+
+	var root plan.Node
+	for n := range spec.Roots {
+		root = n
+	}
+
+	cost, stats := plan.EstimateCost(root)
+	if want := (plan.Cost{Disk: 10, NET: 5, CPU: 3, MEM: 1}); !cmp.Equal(want, cost) {
+		t.Errorf("unexpected cost -want/+got:\n%s", cmp.Diff(want, cost))
+	}
+	if got, want := cost.Total(), int64(19); got != want {
+		t.Errorf("unexpected total cost -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+	if want := (plan.Statistics{Cardinality: 7}); !cmp.Equal(want, stats) {
+		t.Errorf("unexpected statistics -want/+got:\n%s", cmp.Diff(want, stats))
+	}
+
+	// The union passes the cardinality of both inputs through.
+	union := root.Predecessors()[0]
+	if _, stats := plan.EstimateCost(union); stats.Cardinality != 120 {
+		t.Errorf("unexpected union cardinality -want/+got:\n\t- %d\n\t+ %d", 120, stats.Cardinality)
+	}
+}
+
+func TestCostBasedRules(t *testing.T) {
+	var (
+		cheap = &costSpec{kind: "cheap", cost: plan.Cost{CPU: 1}}
+		free  = &costSpec{kind: "free"}
+		dear  = &costSpec{kind: "dear", cost: plan.Cost{CPU: 100}}
+	)
+	testCases := []struct {
+		name  string
+		rules []plan.Rule
+		want  plan.ProcedureKind
+	}{
+		{
+			name: "cheaper",
+			rules: []plan.Rule{
+				plan.CostBased(replaceRule{name: "cheap", kind: "op", spec: cheap}),
+			},
+			want: "cheap",
+		},
+		{
+			name: "more expensive",
+			rules: []plan.Rule{
+				plan.CostBased(replaceRule{name: "dear", kind: "op", spec: dear}),
+			},
+			want: "op",
+		},
+		{
+			name: "cheapest alternative",
+			rules: []plan.Rule{
+				plan.CostBased(replaceRule{name: "cheap", kind: "op", spec: cheap}),
+				plan.CostBased(replaceRule{name: "dear", kind: "op", spec: dear}),
+				plan.CostBased(replaceRule{name: "free", kind: "op", spec: free}),
+			},
+			want: "free",
+		},
+		{
+			name: "same cost",
+			rules: []plan.Rule{
+				plan.CostBased(replaceRule{name: "same", kind: "op", spec: &costSpec{kind: "same", cost: plan.Cost{MEM: 10}}}),
+			},
+			want: "op",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("0", &costSpec{kind: "source", rows: 100}),
+					plan.CreatePhysicalNode("1", &costSpec{kind: "op", cost: plan.Cost{CPU: 10}}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			})
+
+			pp := plan.NewPhysicalPlanner(plan.OnlyPhysicalRules(tc.rules...), plan.DisableValidation())
+			got, err := pp.Plan(context.Background(), spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for root := range got.Roots {
+				if root.Kind() != tc.want {
+					t.Errorf("unexpected plan node kind -want/+got:\n\t- %s\n\t+ %s", tc.want, root.Kind())
+				}
+				if len(root.Predecessors()) != 1 || root.Predecessors()[0].ID() != "0" {
+					t.Errorf("unexpected predecessors for %s", root.ID())
+				}
+			}
+		})
+	}
+}
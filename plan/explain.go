@@ -0,0 +1,296 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type explanationKey int
+
+const explainKey explanationKey = iota
+
+// ContextWithExplanation returns a context that causes the planners
+// to record how a plan was produced into e.
+func ContextWithExplanation(ctx context.Context, e *Explanation) context.Context {
+	return context.WithValue(ctx, explainKey, e)
+}
+
+// explanationFromContext returns the Explanation stored in the
+// context or nil if the plan is not being explained.
+func explanationFromContext(ctx context.Context) *Explanation {
+	e, _ := ctx.Value(explainKey).(*Explanation)
+	return e
+}
+
+// Explanation records each step the planners took when planning a query.
+// It is populated when a context created by ContextWithExplanation
+// is passed to the planners.
+type Explanation struct {
+	// Phases holds the logical and physical planning phases
+	// in the order they were run.
+	Phases []*PlanPhase `json:"phases"`
+
+	// Plan is the final physical plan with its triggers,
+	// bounds and cost estimates.
+	Plan *PlanSnapshot `json:"plan"`
+}
+
+// PlanPhase is one run of the heuristic planner.
+type PlanPhase struct {
+	// Name is either "logical" or "physical".
+	Name string `json:"name"`
+
+	// Initial is the plan before any rule was applied.
+	Initial *PlanSnapshot `json:"initial"`
+
+	// Passes holds each traversal of the plan. The planner stops
+	// after the first pass that does not fire any rule.
+	Passes []*PlanPass `json:"passes"`
+}
+
+// PlanPass is a single traversal of the plan by the heuristic planner.
+type PlanPass struct {
+	// Rules holds the rules that rewrote a node in this pass.
+	Rules []RuleApplication `json:"rules"`
+
+	// Plan is the plan as it was at the end of the pass.
+	Plan *PlanSnapshot `json:"plan"`
+}
+
+// RuleApplication records a rule that rewrote a plan node.
+type RuleApplication struct {
+	Rule   string `json:"rule"`
+	Node   NodeID `json:"node"`
+	Result NodeID `json:"result"`
+
+	// Rejected is set when a cost based rule was not applied
+	// because its rewrite was estimated to be more expensive.
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// PlanSnapshot is a copy of the state of a plan at some point during planning.
+type PlanSnapshot struct {
+	Nodes []*NodeSnapshot `json:"nodes"`
+
+	// ConcurrencyQuota and MemoryBytesQuota are the resources
+	// allocated to the query. They are only set by the physical planner.
+	ConcurrencyQuota int   `json:"concurrencyQuota,omitempty"`
+	MemoryBytesQuota int64 `json:"memoryBytesQuota,omitempty"`
+}
+
+// NodeSnapshot is a copy of the state of a plan node.
+type NodeSnapshot struct {
+	ID           NodeID        `json:"id"`
+	Kind         ProcedureKind `json:"kind"`
+	Physical     bool          `json:"physical"`
+	Predecessors []NodeID      `json:"predecessors,omitempty"`
+	Details      string        `json:"details,omitempty"`
+	Trigger      string        `json:"trigger,omitempty"`
+	Bounds       string        `json:"bounds,omitempty"`
+
+	// Cost is the estimated cost of this node alone and
+	// TotalCost includes the cost of all of its predecessors.
+	// Both are only computed for physical nodes.
+	Cost      *Cost       `json:"cost,omitempty"`
+	TotalCost *Cost       `json:"totalCost,omitempty"`
+	Stats     *Statistics `json:"stats,omitempty"`
+}
+
+func (e *Explanation) beginPhase(name string, p *Spec) {
+	e.Phases = append(e.Phases, &PlanPhase{
+		Name:    name,
+		Initial: snapshotPlan(p),
+	})
+}
+
+func (e *Explanation) currentPhase() *PlanPhase {
+	if len(e.Phases) == 0 {
+		// The heuristic planner was invoked directly.
+		e.beginPhase("", nil)
+	}
+	return e.Phases[len(e.Phases)-1]
+}
+
+func (e *Explanation) beginPass() {
+	phase := e.currentPhase()
+	phase.Passes = append(phase.Passes, &PlanPass{})
+}
+
+func (e *Explanation) currentPass() *PlanPass {
+	phase := e.currentPhase()
+	if len(phase.Passes) == 0 {
+		e.beginPass()
+	}
+	return phase.Passes[len(phase.Passes)-1]
+}
+
+func (e *Explanation) markRule(rule string, node, result NodeID) {
+	pass := e.currentPass()
+	pass.Rules = append(pass.Rules, RuleApplication{
+		Rule:   rule,
+		Node:   node,
+		Result: result,
+	})
+}
+
+func (e *Explanation) rejectRule(rule string, node NodeID) {
+	pass := e.currentPass()
+	pass.Rules = append(pass.Rules, RuleApplication{
+		Rule:     rule,
+		Node:     node,
+		Result:   node,
+		Rejected: true,
+	})
+}
+
+func (e *Explanation) endPass(p *Spec) {
+	e.currentPass().Plan = snapshotPlan(p)
+}
+
+func (e *Explanation) finish(p *Spec) {
+	e.Plan = snapshotPlan(p)
+}
+
+// snapshotPlan copies the state of each node in the plan
+// so that later rewrites do not modify it.
+func snapshotPlan(p *Spec) *PlanSnapshot {
+	if p == nil {
+		return nil
+	}
+	snapshot := &PlanSnapshot{
+		ConcurrencyQuota: p.Resources.ConcurrencyQuota,
+		MemoryBytesQuota: p.Resources.MemoryBytesQuota,
+	}
+
+	ce := newCostEstimator()
+	_ = p.BottomUpWalk(func(pn Node) error {
+		ns := &NodeSnapshot{
+			ID:   pn.ID(),
+			Kind: pn.Kind(),
+		}
+		for _, pred := range pn.Predecessors() {
+			ns.Predecessors = append(ns.Predecessors, pred.ID())
+		}
+		if d, ok := pn.ProcedureSpec().(Detailer); ok {
+			ns.Details = strings.TrimSpace(d.PlanDetails())
+		}
+		if b := pn.Bounds(); b != nil {
+			ns.Bounds = fmt.Sprintf("[%v, %v)", b.Start, b.Stop)
+		}
+		if ppn, ok := pn.(*PhysicalPlanNode); ok {
+			ns.Physical = true
+			if ppn.TriggerSpec != nil {
+				ns.Trigger = formatTrigger(ppn.TriggerSpec)
+			}
+
+			est := ce.estimate(pn)
+			ns.Cost, ns.TotalCost, ns.Stats = &est.self, &est.total, &est.stats
+		}
+		snapshot.Nodes = append(snapshot.Nodes, ns)
+		return nil
+	})
+	return snapshot
+}
+
+func formatTrigger(t TriggerSpec) string {
+	switch t := t.(type) {
+	case NarrowTransformationTriggerSpec:
+		return "narrow"
+	case AfterWatermarkTriggerSpec:
+		return fmt.Sprintf("afterWatermark(allowedLateness: %v)", t.AllowedLateness)
+	case RepeatedTriggerSpec:
+		return fmt.Sprintf("repeated(%s)", formatTrigger(t.Trigger))
+	case AfterProcessingTimeTriggerSpec:
+		return fmt.Sprintf("afterProcessingTime(duration: %v)", t.Duration)
+	case AfterAtLeastCountTriggerSpec:
+		return fmt.Sprintf("afterAtLeastCount(count: %d)", t.Count)
+	case OrFinallyTriggerSpec:
+		return fmt.Sprintf("orFinally(main: %s, finally: %s)", formatTrigger(t.Main), formatTrigger(t.Finally))
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
+
+// WriteText writes a human readable description of the explanation to w.
+func (e *Explanation) WriteText(w io.Writer) error {
+	tw := &textWriter{w: w}
+	for _, phase := range e.Phases {
+		if phase.Initial != nil {
+			tw.printf("initial %s plan:\n", phase.Name)
+			phase.Initial.writeText(tw, "  ")
+		}
+		for i, pass := range phase.Passes {
+			tw.printf("%s pass %d:\n", phase.Name, i+1)
+			if len(pass.Rules) == 0 {
+				tw.printf("  no rules applied\n")
+				continue
+			}
+			for _, r := range pass.Rules {
+				if r.Rejected {
+					tw.printf("  %s rejected for %s\n", r.Rule, r.Node)
+				} else if r.Node == r.Result {
+					tw.printf("  %s rewrote %s\n", r.Rule, r.Node)
+				} else {
+					tw.printf("  %s rewrote %s as %s\n", r.Rule, r.Node, r.Result)
+				}
+			}
+			if pass.Plan != nil {
+				pass.Plan.writeText(tw, "    ")
+			}
+		}
+	}
+	if e.Plan != nil {
+		tw.printf("physical plan:\n")
+		tw.printf("  concurrency quota: %d\n", e.Plan.ConcurrencyQuota)
+		tw.printf("  memory bytes quota: %d\n", e.Plan.MemoryBytesQuota)
+		e.Plan.writeText(tw, "  ")
+	}
+	return tw.err
+}
+
+// textWriter holds on to the first error
+// encountered while writing.
+type textWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (tw *textWriter) printf(format string, args ...interface{}) {
+	if tw.err == nil {
+		_, tw.err = fmt.Fprintf(tw.w, format, args...)
+	}
+}
+
+func (s *PlanSnapshot) writeText(tw *textWriter, indent string) {
+	for _, n := range s.Nodes {
+		tw.printf("%s%s (%s)", indent, n.ID, n.Kind)
+		if len(n.Predecessors) > 0 {
+			preds := make([]string, len(n.Predecessors))
+			for i, pred := range n.Predecessors {
+				preds[i] = string(pred)
+			}
+			tw.printf(" <- %s", strings.Join(preds, ", "))
+		}
+		tw.printf("\n")
+		if n.Trigger != "" {
+			tw.printf("%s  trigger: %s\n", indent, n.Trigger)
+		}
+		if n.Bounds != "" {
+			tw.printf("%s  bounds: %s\n", indent, n.Bounds)
+		}
+		if n.Cost != nil {
+			tw.printf("%s  cost: %s total: %s\n", indent, formatCost(*n.Cost), formatCost(*n.TotalCost))
+		}
+		if n.Details != "" {
+			for _, line := range strings.Split(n.Details, "\n") {
+				tw.printf("%s  // %s\n", indent, line)
+			}
+		}
+	}
+}
+
+func formatCost(c Cost) string {
+	return fmt.Sprintf("{disk: %d, cpu: %d, gpu: %d, mem: %d, net: %d}", c.Disk, c.CPU, c.GPU, c.MEM, c.NET)
+}
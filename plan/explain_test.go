@@ -0,0 +1,98 @@
+package plan_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+func TestExplanation(t *testing.T) {
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plantest.CreateLogicalMockNode("0"),
+			plantest.CreateLogicalMockNode("1"),
+		},
+		Edges: [][2]int{
+			{0, 1},
+		},
+	})
+
+	// Rewrite node 1 once so the logical planner
+	// records a rule and needs two passes.
+	rewritten := false
+	rule := &plantest.FunctionRule{
+		RewriteFn: func(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+			if node.ID() != "1" || rewritten {
+				return node, false, nil
+			}
+			rewritten = true
+			return node, true, nil
+		},
+	}
+
+	e := &plan.Explanation{}
+	ctx := plan.ContextWithExplanation(context.Background(), e)
+
+	lp := plan.NewLogicalPlanner(plan.OnlyLogicalRules(rule))
+	spec, err := lp.Plan(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp := plan.NewPhysicalPlanner(plan.OnlyPhysicalRules(), plan.WithDefaultMemoryLimit(1024))
+	if _, err := pp.Plan(ctx, spec); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := len(e.Phases), 2; got != want {
+		t.Fatalf("unexpected number of phases -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+
+	var sb strings.Builder
+	if err := e.WriteText(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `initial logical plan:
+  0 (mock)
+  1 (mock) <- 0
+logical pass 1:
+  function rewrote 1
+    0 (mock)
+    1 (mock) <- 0
+logical pass 2:
+  no rules applied
+initial physical plan:
+  0 (mock)
+  1 (mock) <- 0
+physical pass 1:
+  physicalConverterRule rewrote 1
+  physicalConverterRule rewrote 0
+    0 (mock)
+      cost: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0} total: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0}
+    1 (mock) <- 0
+      cost: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0} total: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0}
+physical pass 2:
+  no rules applied
+physical plan:
+  concurrency quota: 1
+  memory bytes quota: 1024
+  0 (mock)
+    trigger: afterWatermark(allowedLateness: 0ns)
+    cost: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0} total: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0}
+  1 (mock) <- 0
+    trigger: afterWatermark(allowedLateness: 0ns)
+    cost: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0} total: {disk: 0, cpu: 0, gpu: 0, mem: 0, net: 0}
+`
+	if got := sb.String(); got != want {
+		t.Errorf("unexpected explanation -want/+got:\n%s", diff.LineDiff(want, got))
+	}
+
+	// The explanation must also be serializable as json.
+	if _, err := json.Marshal(e); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -46,38 +46,129 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 	anyChanged := false
 
 	for _, rule := range p.rules[AnyKind] {
-		if p.disabledRules[rule.Name()] {
+		if p.disabledRules[rule.Name()] || isCostBased(rule) {
 			continue
 		}
 		if rule.Pattern().Match(node) {
-			newNode, changed, err := rule.Rewrite(ctx, node)
+			newNode, changed, err := p.rewrite(ctx, rule, node)
 			if err != nil {
 				return nil, false, err
-			} else if changed {
-				testing.MarkInvokedPlannerRule(ctx, rule.Name())
-				anyChanged = true
 			}
+			anyChanged = anyChanged || changed
 			node = newNode
 		}
 	}
 
 	for _, rule := range p.rules[node.Kind()] {
-		if p.disabledRules[rule.Name()] {
+		if p.disabledRules[rule.Name()] || isCostBased(rule) {
 			continue
 		}
 		if rule.Pattern().Match(node) {
-			newNode, changed, err := rule.Rewrite(ctx, node)
+			newNode, changed, err := p.rewrite(ctx, rule, node)
 			if err != nil {
 				return nil, false, err
-			} else if changed {
-				testing.MarkInvokedPlannerRule(ctx, rule.Name())
-				anyChanged = true
 			}
+			anyChanged = anyChanged || changed
 			node = newNode
 		}
 	}
 
-	return node, anyChanged, nil
+	// Cost based rules are considered last so they
+	// compete against the result of the other rules.
+	newNode, changed, err := p.matchCostBasedRules(ctx, node)
+	if err != nil {
+		return nil, false, err
+	}
+	return newNode, anyChanged || changed, nil
+}
+
+// rewrite applies the rule to the node and records the rule if it changed the node.
+func (p *heuristicPlanner) rewrite(ctx context.Context, rule Rule, node Node) (Node, bool, error) {
+	newNode, changed, err := rule.Rewrite(ctx, node)
+	if err != nil {
+		return nil, false, err
+	} else if changed {
+		testing.MarkInvokedPlannerRule(ctx, rule.Name())
+		if e := explanationFromContext(ctx); e != nil {
+			e.markRule(rule.Name(), node.ID(), newNode.ID())
+		}
+	}
+	return newNode, changed, nil
+}
+
+func isCostBased(rule Rule) bool {
+	_, ok := rule.(costBasedRule)
+	return ok
+}
+
+// matchCostBasedRules rewrites a copy of the node with each matching
+// cost based rule and applies the rule that produces the cheapest plan.
+// The node is left unchanged unless an alternative is strictly cheaper.
+func (p *heuristicPlanner) matchCostBasedRules(ctx context.Context, node Node) (Node, bool, error) {
+	var (
+		best     Rule
+		bestCost int64
+		rejected []Rule
+	)
+	for _, rules := range [][]Rule{p.rules[AnyKind], p.rules[node.Kind()]} {
+		for _, rule := range rules {
+			if p.disabledRules[rule.Name()] || !isCostBased(rule) || !rule.Pattern().Match(node) {
+				continue
+			}
+			alt, changed, err := rule.Rewrite(ctx, copySubtree(node, make(map[Node]Node)))
+			if err != nil {
+				return nil, false, err
+			} else if !changed {
+				continue
+			}
+
+			cost, _ := EstimateCost(alt)
+			if best == nil || cost.Total() < bestCost {
+				if best != nil {
+					rejected = append(rejected, best)
+				}
+				best, bestCost = rule, cost.Total()
+			} else {
+				rejected = append(rejected, rule)
+			}
+		}
+	}
+	if best == nil {
+		return node, false, nil
+	}
+
+	if cost, _ := EstimateCost(node); bestCost >= cost.Total() {
+		rejected = append(rejected, best)
+		best = nil
+	}
+	if e := explanationFromContext(ctx); e != nil {
+		for _, rule := range rejected {
+			e.rejectRule(rule.Name(), node.ID())
+		}
+	}
+	if best == nil {
+		return node, false, nil
+	}
+	return p.rewrite(ctx, best, node)
+}
+
+// copySubtree copies the node and all of its predecessors so
+// that a rule can rewrite them without modifying the plan.
+func copySubtree(node Node, copies map[Node]Node) Node {
+	if c, ok := copies[node]; ok {
+		return c
+	}
+	c := node.ShallowCopy()
+	c.ClearPredecessors()
+	c.ClearSuccessors()
+	c.SetBounds(node.Bounds())
+	for _, pred := range node.Predecessors() {
+		predCopy := copySubtree(pred, copies)
+		c.AddPredecessors(predCopy)
+		predCopy.AddSuccessors(c)
+	}
+	copies[node] = c
+	return c
 }
 
 // Plan is a fixed-point query planning algorithm.
@@ -87,7 +178,11 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 // Plan may change its argument and/or return a new instance of Spec, so the correct way to call Plan is:
 //     plan, err = plan.Plan(plan)
 func (p *heuristicPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, error) {
+	e := explanationFromContext(ctx)
 	for anyChanged := true; anyChanged; {
+		if e != nil {
+			e.beginPass()
+		}
 		visited := make(map[Node]struct{})
 
 		nodeStack := make([]Node, 0, len(inputPlan.Roots))
@@ -129,6 +224,10 @@ func (p *heuristicPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, er
 				visited[newNode] = struct{}{}
 			}
 		}
+
+		if e != nil {
+			e.endPass(inputPlan)
+		}
 	}
 
 	return inputPlan, nil
@@ -99,6 +99,9 @@ func (l *logicalPlanner) CreateInitialPlan(spec *flux.Spec) (*Spec, error) {
 
 // Plan transforms the given naive plan by applying rules.
 func (l *logicalPlanner) Plan(ctx context.Context, logicalPlan *Spec) (*Spec, error) {
+	if e := explanationFromContext(ctx); e != nil {
+		e.beginPhase("logical", logicalPlan)
+	}
 	newLogicalPlan, err := l.heuristicPlanner.Plan(ctx, logicalPlan)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,233 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// PartitionLanesKind is the kind of the node that splits
+	// the tables it receives between parallel lanes.
+	PartitionLanesKind = "partitionLanes"
+
+	// MergeLanesKind is the kind of the node that merges
+	// the tables produced by parallel lanes.
+	MergeLanesKind = "mergeLanes"
+)
+
+// PartitionableProcedureSpec is implemented by procedures that process each
+// table independently of all of the other tables. A chain of partitionable
+// procedures may be split into parallel lanes that each receive
+// a distinct subset of the tables.
+type PartitionableProcedureSpec interface {
+	PhysicalProcedureSpec
+
+	// ModifiedGroupKeyColumns returns the group key columns that the procedure
+	// may add, remove or change the value of. Tables are assigned to lanes
+	// using the remaining group key columns so that tables that
+	// may share a group key once processed end up in the same lane.
+	// If the columns cannot be determined, ok is false and the procedure
+	// is not split into lanes.
+	ModifiedGroupKeyColumns() (columns []string, ok bool)
+}
+
+// PartitionLanesProcedureSpec assigns each table to one of its successors
+// using a hash of the group key. The columns in Exclude are not part of the hash.
+type PartitionLanesProcedureSpec struct {
+	Lanes   int
+	Exclude []string
+}
+
+func (s *PartitionLanesProcedureSpec) Kind() ProcedureKind {
+	return PartitionLanesKind
+}
+
+func (s *PartitionLanesProcedureSpec) Copy() ProcedureSpec {
+	ns := new(PartitionLanesProcedureSpec)
+	ns.Lanes = s.Lanes
+	if s.Exclude != nil {
+		ns.Exclude = make([]string, len(s.Exclude))
+		copy(ns.Exclude, s.Exclude)
+	}
+	return ns
+}
+
+// Cost splits the input evenly between the lanes.
+func (s *PartitionLanesProcedureSpec) Cost(inStats []Statistics) (Cost, Statistics) {
+	stats := sumStatistics(inStats)
+	if s.Lanes > 1 {
+		stats.Cardinality /= int64(s.Lanes)
+		stats.GroupCardinality /= int64(s.Lanes)
+	}
+	return Cost{}, stats
+}
+
+func (s *PartitionLanesProcedureSpec) PlanDetails() string {
+	if len(s.Exclude) == 0 {
+		return fmt.Sprintf("lanes = %d", s.Lanes)
+	}
+	return fmt.Sprintf("lanes = %d, exclude = [%s]", s.Lanes, strings.Join(s.Exclude, ", "))
+}
+
+// MergeLanesProcedureSpec passes through the tables of all of its predecessors.
+type MergeLanesProcedureSpec struct{}
+
+func (s *MergeLanesProcedureSpec) Kind() ProcedureKind {
+	return MergeLanesKind
+}
+
+func (s *MergeLanesProcedureSpec) Copy() ProcedureSpec {
+	return &MergeLanesProcedureSpec{}
+}
+
+func (s *MergeLanesProcedureSpec) Cost(inStats []Statistics) (Cost, Statistics) {
+	return Cost{}, sumStatistics(inStats)
+}
+
+func sumStatistics(inStats []Statistics) Statistics {
+	var stats Statistics
+	for _, s := range inStats {
+		stats.Cardinality += s.Cardinality
+		stats.GroupCardinality += s.GroupCardinality
+	}
+	return stats
+}
+
+// splitParallelLanes finds each chain of partitionable nodes in the plan
+// and replaces it with the given number of copies of the chain.
+// A partition node in front of the copies assigns the tables to the lanes
+// and a merge node after them combines their output.
+// It reports whether any chain was split.
+func splitParallelLanes(spec *Spec, lanes int) bool {
+	if lanes < 2 {
+		return false
+	}
+
+	var chains [][]*PhysicalPlanNode
+	inChain := make(map[Node]bool)
+	_ = spec.BottomUpWalk(func(node Node) error {
+		if inChain[node] || !isPartitionable(node) {
+			return nil
+		}
+		var chain []*PhysicalPlanNode
+		for cur := node; ; {
+			inChain[cur] = true
+			chain = append(chain, cur.(*PhysicalPlanNode))
+			if len(cur.Successors()) != 1 {
+				break
+			}
+			next := cur.Successors()[0]
+			if !isPartitionable(next) || len(next.Predecessors()) != 1 {
+				break
+			}
+			cur = next
+		}
+		// The merge node needs somewhere to send its tables.
+		if len(chain[len(chain)-1].Successors()) > 0 {
+			chains = append(chains, chain)
+		}
+		return nil
+	})
+
+	for _, chain := range chains {
+		splitChain(chain, lanes)
+	}
+	return len(chains) > 0
+}
+
+func isPartitionable(node Node) bool {
+	ppn, ok := node.(*PhysicalPlanNode)
+	if !ok || len(ppn.Predecessors()) != 1 {
+		return false
+	}
+	spec, ok := ppn.Spec.(PartitionableProcedureSpec)
+	if !ok {
+		return false
+	}
+	_, ok = spec.ModifiedGroupKeyColumns()
+	return ok
+}
+
+func splitChain(chain []*PhysicalPlanNode, lanes int) {
+	head, tail := chain[0], chain[len(chain)-1]
+
+	var exclude []string
+	seen := make(map[string]bool)
+	for _, node := range chain {
+		columns, _ := node.Spec.(PartitionableProcedureSpec).ModifiedGroupKeyColumns()
+		for _, c := range columns {
+			if !seen[c] {
+				seen[c] = true
+				exclude = append(exclude, c)
+			}
+		}
+	}
+	sort.Strings(exclude)
+
+	pred := head.Predecessors()[0]
+	partition := &PhysicalPlanNode{
+		id:          head.id + "_partition",
+		Spec:        &PartitionLanesProcedureSpec{Lanes: lanes, Exclude: exclude},
+		Source:      head.Source,
+		TriggerSpec: NarrowTransformationTriggerSpec{},
+	}
+	partition.SetBounds(pred.Bounds())
+	replaceEdge(pred.Successors(), head, partition)
+	partition.AddPredecessors(pred)
+	head.ClearPredecessors()
+
+	merge := &PhysicalPlanNode{
+		id:          tail.id + "_merge",
+		Spec:        &MergeLanesProcedureSpec{},
+		Source:      tail.Source,
+		TriggerSpec: NarrowTransformationTriggerSpec{},
+	}
+	merge.SetBounds(tail.Bounds())
+	for _, succ := range tail.Successors() {
+		replaceEdge(succ.Predecessors(), tail, merge)
+		merge.AddSuccessors(succ)
+	}
+	tail.ClearSuccessors()
+
+	// The first lane reuses the original nodes.
+	partition.AddSuccessors(head)
+	head.AddPredecessors(partition)
+	tail.AddSuccessors(merge)
+	merge.AddPredecessors(tail)
+
+	for lane := 1; lane < lanes; lane++ {
+		var prev Node = partition
+		for _, node := range chain {
+			nn := copyLaneNode(node, lane)
+			prev.AddSuccessors(nn)
+			nn.AddPredecessors(prev)
+			prev = nn
+		}
+		prev.AddSuccessors(merge)
+		merge.AddPredecessors(prev)
+	}
+}
+
+func copyLaneNode(node *PhysicalPlanNode, lane int) *PhysicalPlanNode {
+	nn := &PhysicalPlanNode{
+		id:            NodeID(fmt.Sprintf("%s_lane%d", node.id, lane)),
+		Spec:          node.Spec.Copy().(PhysicalProcedureSpec),
+		Source:        node.Source,
+		TriggerSpec:   node.TriggerSpec,
+		RequiredAttrs: node.RequiredAttrs,
+		OutputAttrs:   node.OutputAttrs,
+	}
+	nn.SetBounds(node.Bounds())
+	return nn
+}
+
+// replaceEdge replaces old with new in a list of predecessors or successors
+// so that the position of the edge is preserved.
+func replaceEdge(nodes []Node, old, new Node) {
+	for i, n := range nodes {
+		if n == old {
+			nodes[i] = new
+		}
+	}
+}
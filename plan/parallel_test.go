@@ -0,0 +1,228 @@
+package plan_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+// laneSpec is a procedure spec that may be split into parallel lanes
+// when ok is set.
+type laneSpec struct {
+	plan.DefaultCost
+	modified []string
+	ok       bool
+}
+
+func (s *laneSpec) Kind() plan.ProcedureKind {
+	return "lane"
+}
+
+func (s *laneSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func (s *laneSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return s.modified, s.ok
+}
+
+// formatEdges lists each node of the plan with its predecessors.
+func formatEdges(p *plan.Spec) []string {
+	var lines []string
+	_ = p.BottomUpWalk(func(node plan.Node) error {
+		preds := make([]string, len(node.Predecessors()))
+		for i, pred := range node.Predecessors() {
+			preds[i] = string(pred.ID())
+		}
+		lines = append(lines, fmt.Sprintf("%s <- [%s]", node.ID(), strings.Join(preds, ", ")))
+		return nil
+	})
+	return lines
+}
+
+func TestParallelLanes(t *testing.T) {
+	testcases := []struct {
+		name      string
+		spec      *plantest.PlanSpec
+		lanes     int
+		wantEdges []string
+		wantQuota int
+		exclude   []string
+	}{
+		{
+			name: "chain",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("1", &laneSpec{modified: []string{"_stop", "_start"}, ok: true}),
+					plan.CreatePhysicalNode("2", &laneSpec{modified: []string{"_start"}, ok: true}),
+					plantest.CreatePhysicalMockNode("3"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			lanes: 3,
+			wantEdges: []string{
+				"0 <- []",
+				"1_partition <- [0]",
+				"1 <- [1_partition]",
+				"2 <- [1]",
+				"1_lane1 <- [1_partition]",
+				"2_lane1 <- [1_lane1]",
+				"1_lane2 <- [1_partition]",
+				"2_lane2 <- [1_lane2]",
+				"2_merge <- [2, 2_lane1, 2_lane2]",
+				"3 <- [2_merge]",
+			},
+			wantQuota: 3,
+			exclude:   []string{"_start", "_stop"},
+		},
+		{
+			name: "limited by concurrency quota",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("1", &laneSpec{ok: true}),
+					plantest.CreatePhysicalMockNode("2"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 2},
+			},
+			lanes: 4,
+			wantEdges: []string{
+				"0 <- []",
+				"1_partition <- [0]",
+				"1 <- [1_partition]",
+				"1_lane1 <- [1_partition]",
+				"1_merge <- [1, 1_lane1]",
+				"2 <- [1_merge]",
+			},
+			wantQuota: 2,
+		},
+		{
+			name: "not partitionable",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("1", &laneSpec{ok: true}),
+					plan.CreatePhysicalNode("2", &laneSpec{ok: false}),
+					plantest.CreatePhysicalMockNode("3"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			lanes: 2,
+			wantEdges: []string{
+				"0 <- []",
+				"1_partition <- [0]",
+				"1 <- [1_partition]",
+				"1_lane1 <- [1_partition]",
+				"1_merge <- [1, 1_lane1]",
+				"2 <- [1_merge]",
+				"3 <- [2]",
+			},
+			wantQuota: 2,
+		},
+		{
+			name: "multiple successors",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("1", &laneSpec{ok: true}),
+					plan.CreatePhysicalNode("2", &laneSpec{ok: true}),
+					plantest.CreatePhysicalMockNode("3"),
+					plantest.CreatePhysicalMockNode("4"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+					{2, 4},
+				},
+			},
+			lanes: 2,
+			wantEdges: []string{
+				"0 <- []",
+				"1_partition <- [0]",
+				"1 <- [1_partition]",
+				"1_lane1 <- [1_partition]",
+				"1_merge <- [1, 1_lane1]",
+				"3 <- [1_merge]",
+				"2_partition <- [1_merge]",
+				"2 <- [2_partition]",
+				"2_lane1 <- [2_partition]",
+				"2_merge <- [2, 2_lane1]",
+				"4 <- [2_merge]",
+			},
+			wantQuota: 2,
+		},
+		{
+			name: "disabled",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plantest.CreatePhysicalMockNode("0"),
+					plan.CreatePhysicalNode("1", &laneSpec{ok: true}),
+					plantest.CreatePhysicalMockNode("2"),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			wantEdges: []string{
+				"0 <- []",
+				"1 <- [0]",
+				"2 <- [1]",
+			},
+			wantQuota: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			planner := plan.NewPhysicalPlanner(
+				plan.OnlyPhysicalRules(),
+				plan.WithParallelLanes(tc.lanes),
+			)
+			p, err := planner.Plan(context.Background(), plantest.CreatePlanSpec(tc.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := formatEdges(p); !cmp.Equal(tc.wantEdges, got) {
+				t.Errorf("unexpected plan -want/+got:\n%s", cmp.Diff(tc.wantEdges, got))
+			}
+			if got := p.Resources.ConcurrencyQuota; got != tc.wantQuota {
+				t.Errorf("unexpected concurrency quota: want %d, got %d", tc.wantQuota, got)
+			}
+
+			_ = p.BottomUpWalk(func(node plan.Node) error {
+				spec, ok := node.ProcedureSpec().(*plan.PartitionLanesProcedureSpec)
+				if ok && spec.Lanes < 2 {
+					t.Errorf("%s has %d lanes", node.ID(), spec.Lanes)
+				}
+				if ok && node.ID() == "1_partition" && !cmp.Equal(tc.exclude, spec.Exclude) {
+					t.Errorf("unexpected excluded columns -want/+got:\n%s", cmp.Diff(tc.exclude, spec.Exclude))
+				}
+				return nil
+			})
+		})
+	}
+}
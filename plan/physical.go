@@ -44,6 +44,10 @@ func NewPhysicalPlanner(options ...PhysicalOption) PhysicalPlanner {
 }
 
 func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error) {
+	e := explanationFromContext(ctx)
+	if e != nil {
+		e.beginPhase("physical", spec)
+	}
 	transformedSpec, err := pp.heuristicPlanner.Plan(ctx, spec)
 	if err != nil {
 		return nil, err
@@ -59,6 +63,12 @@ func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error)
 		return nil, err
 	}
 
+	// Split partitionable transformations into parallel lanes
+	lanes := pp.parallelLanes(ctx, transformedSpec)
+	if !splitParallelLanes(transformedSpec, lanes) {
+		lanes = 0
+	}
+
 	// Ensure that the plan is valid
 	if !pp.disableValidation {
 		err := transformedSpec.CheckIntegrity()
@@ -103,11 +113,38 @@ func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error)
 			}
 			transformedSpec.Resources.ConcurrencyQuota = concurrencyQuota
 		}
+
+		// Each lane needs a worker to run in parallel with the others.
+		if transformedSpec.Resources.ConcurrencyQuota < lanes {
+			transformedSpec.Resources.ConcurrencyQuota = lanes
+		}
 	}
 
+	if e != nil {
+		e.finish(transformedSpec)
+	}
 	return transformedSpec, nil
 }
 
+// parallelLanes returns the number of lanes that partitionable
+// transformations should be split into. The number of lanes
+// is limited by the concurrency quota.
+func (pp *physicalPlanner) parallelLanes(ctx context.Context, spec *Spec) int {
+	lanes := pp.lanes
+	if lanes == 0 {
+		lanes = int(feature.QueryParallelLanes().Int(ctx))
+	}
+
+	limit := spec.Resources.ConcurrencyQuota
+	if limit == 0 {
+		limit = int(feature.QueryConcurrencyLimit().Int(ctx))
+	}
+	if limit > 0 && lanes > limit {
+		lanes = limit
+	}
+	return lanes
+}
+
 func validatePhysicalPlan(plan *Spec) error {
 	err := plan.BottomUpWalk(func(pn Node) error {
 		if validator, ok := pn.ProcedureSpec().(PostPhysicalValidator); ok {
@@ -129,6 +166,7 @@ type physicalPlanner struct {
 	*heuristicPlanner
 	defaultMemoryLimit int64
 	disableValidation  bool
+	lanes              int
 }
 
 // PhysicalOption is an option to configure the behavior of the physical plan.
@@ -150,6 +188,17 @@ func WithDefaultMemoryLimit(memBytes int64) PhysicalOption {
 	})
 }
 
+// WithParallelLanes splits chains of transformations that process each table
+// independently into n parallel lanes. Tables are assigned to a lane by their group key
+// and the output of the lanes is merged before it is consumed by the rest of the plan.
+// The number of lanes is limited by the concurrency quota of the query.
+// When not set, the number of lanes is read from the queryParallelLanes feature flag.
+func WithParallelLanes(n int) PhysicalOption {
+	return physicalOption(func(p *physicalPlanner) {
+		p.lanes = n
+	})
+}
+
 // OnlyPhysicalRules produces a physical plan option that forces only a particular set of rules to be applied.
 func OnlyPhysicalRules(rules ...Rule) PhysicalOption {
 	return physicalOption(func(pp *physicalPlanner) {
@@ -15,3 +15,21 @@ type Rule interface {
 	// The boolean return value should be true if anything changed during the rewrite.
 	Rewrite(context.Context, Node) (Node, bool, error)
 }
+
+// CostBased wraps a rule so that the planner only keeps its rewrite
+// when it lowers the estimated cost of the plan.
+//
+// When several cost based rules match the same node, each of them
+// rewrites a copy of the node and its predecessors and the planner
+// applies the rule whose alternative has the lowest estimated cost.
+// The rule is then invoked again on the plan itself, so its Rewrite
+// method must be deterministic. Ties are resolved in favor of the
+// current plan, so the rule is not applied when no node reports
+// an estimate.
+func CostBased(rule Rule) Rule {
+	return costBasedRule{Rule: rule}
+}
+
+type costBasedRule struct {
+	Rule
+}
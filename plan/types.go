@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 )
 
 type Planner interface {
@@ -340,4 +345,73 @@ type WindowSpec struct {
 	Every  flux.Duration
 	Period flux.Duration
 	Offset flux.Duration
+	// Location is the time zone used to compute the window boundaries.
+	// The zero value is the location option of the query,
+	// which defaults to UTC.
+	Location Location
+}
+
+// Location is a time zone. It is the Go representation of
+// the location records that are created by the timezone package.
+type Location struct {
+	// Name is the name of a zone in the IANA time zone database.
+	// An empty name is the same as UTC.
+	Name string `json:"zone"`
+	// Offset is a fixed offset from UTC.
+	// It may only be used when the zone is UTC.
+	Offset flux.Duration `json:"offset"`
+}
+
+// LocationFromObject reads a Location from a location record.
+func LocationFromObject(obj values.Object) (Location, error) {
+	zone, ok := obj.Get("zone")
+	if !ok || zone.Type().Nature() != semantic.String {
+		return Location{}, errors.New(codes.Invalid, "location must have a string zone")
+	}
+	loc := Location{Name: zone.Str()}
+	if offset, ok := obj.Get("offset"); ok {
+		if offset.Type().Nature() != semantic.Duration {
+			return Location{}, errors.New(codes.Invalid, "location offset must be a duration")
+		}
+		loc.Offset = offset.Duration()
+	}
+	return loc, nil
+}
+
+// IsUTC reports whether the location is UTC without an offset.
+func (l Location) IsUTC() bool {
+	return (l.Name == "" || l.Name == "UTC") && l.Offset.IsZero()
+}
+
+// locations caches the zones that have been loaded from
+// the time zone database.
+var locations sync.Map
+
+// Load returns the time.Location for this location.
+func (l Location) Load() (*time.Location, error) {
+	if l.IsUTC() {
+		return time.UTC, nil
+	}
+	if l.Name != "" && l.Name != "UTC" {
+		if !l.Offset.IsZero() {
+			return nil, errors.Newf(codes.Invalid, "location %q cannot be used with an offset", l.Name)
+		}
+		if loc, ok := locations.Load(l.Name); ok {
+			return loc.(*time.Location), nil
+		}
+		loc, err := time.LoadLocation(l.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "unknown location %q", l.Name)
+		}
+		locations.Store(l.Name, loc)
+		return loc, nil
+	}
+	if !l.Offset.NanoOnly() {
+		return nil, errors.New(codes.Invalid, "location offset cannot contain months")
+	}
+	name := l.Offset.String()
+	if !l.Offset.IsNegative() {
+		name = "+" + name
+	}
+	return time.FixedZone("UTC"+name, int(l.Offset.Duration()/time.Second)), nil
 }
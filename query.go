@@ -55,6 +55,18 @@ type Statistics struct {
 	// TotalAllocated is the total number of bytes allocated.
 	// The number includes memory that was freed and then used again.
 	TotalAllocated int64 `json:"total_allocated"`
+	// TotalSpilled is the total number of bytes written to disk
+	// to free memory when the memory limit was reached.
+	TotalSpilled int64 `json:"total_spilled"`
+	// SpillCount is the number of times buffered data was written to disk.
+	SpillCount int64 `json:"spill_count"`
+
+	// CompileCacheHits is the number of compilations that reused
+	// the semantic graph of a program from a program cache.
+	CompileCacheHits int64 `json:"compile_cache_hits"`
+	// CompileCacheMisses is the number of compilations that analyzed
+	// a program and added its semantic graph to a program cache.
+	CompileCacheMisses int64 `json:"compile_cache_misses"`
 
 	// RuntimeErrors contains error messages that happened during the execution of the query.
 	RuntimeErrors []string `json:"runtime_errors"`
@@ -81,7 +93,13 @@ func (s Statistics) Add(other Statistics) Statistics {
 		Concurrency:     s.Concurrency + other.Concurrency,
 		MaxAllocated:    s.MaxAllocated + other.MaxAllocated,
 		TotalAllocated:  s.TotalAllocated + other.TotalAllocated,
-		RuntimeErrors:   errs,
-		Metadata:        md,
+		TotalSpilled:    s.TotalSpilled + other.TotalSpilled,
+		SpillCount:      s.SpillCount + other.SpillCount,
+
+		CompileCacheHits:   s.CompileCacheHits + other.CompileCacheHits,
+		CompileCacheMisses: s.CompileCacheMisses + other.CompileCacheMisses,
+
+		RuntimeErrors: errs,
+		Metadata:      md,
 	}
 }
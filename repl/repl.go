@@ -15,6 +15,7 @@ import (
 
 	"github.com/c-bata/go-prompt"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast/astutil"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/spec"
 	"github.com/influxdata/flux/interpreter"
@@ -59,6 +60,24 @@ func New(ctx context.Context, deps flux.Dependencies) *REPL {
 	}
 }
 
+// SetParams assigns the parameters to the params record
+// so the following input can refer to them.
+func (r *REPL) SetParams(params map[string]values.Value) error {
+	file, err := lang.ParamsFile(params)
+	if err != nil {
+		return err
+	}
+	src, err := astutil.Format(file)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Eval(src); err != nil {
+		return err
+	}
+	r.scope.Set(lang.ParamsIdentifier, values.NewObjectWithValues(params))
+	return nil
+}
+
 func (r *REPL) Run() {
 	p := prompt.New(
 		r.input,
@@ -88,6 +88,9 @@ const (
 	TFloat
 	TString
 	TTime
+	TDecimal
+	TDuration
+	TBytes
 )
 
 // ColumnType returns the column type when given a semantic.Type.
@@ -106,6 +109,12 @@ func ColumnType(typ semantic.MonoType) ColType {
 		return TString
 	case semantic.Time:
 		return TTime
+	case semantic.Decimal:
+		return TDecimal
+	case semantic.Duration:
+		return TDuration
+	case semantic.Bytes:
+		return TBytes
 	default:
 		return TInvalid
 	}
@@ -125,6 +134,12 @@ func SemanticType(typ ColType) semantic.MonoType {
 		return semantic.BasicString
 	case TTime:
 		return semantic.BasicTime
+	case TDecimal:
+		return semantic.BasicDecimal
+	case TDuration:
+		return semantic.BasicDuration
+	case TBytes:
+		return semantic.BasicBytes
 	default:
 		return semantic.MonoType{}
 	}
@@ -147,6 +162,12 @@ func (t ColType) String() string {
 		return "string"
 	case TTime:
 		return "time"
+	case TDecimal:
+		return "decimal"
+	case TDuration:
+		return "duration"
+	case TBytes:
+		return "bytes"
 	default:
 		return "unknown"
 	}
@@ -170,6 +191,11 @@ type ColReader interface {
 	Floats(j int) *array.Float
 	Strings(j int) *array.String
 	Times(j int) *array.Int
+	Decimals(j int) *array.Decimal
+	// Durations returns the durations of a column
+	// as a signed number of nanoseconds.
+	Durations(j int) *array.Int
+	Bytes(j int) *array.Binary
 
 	// Retain will retain this buffer to avoid having the
 	// memory consumed by it freed.
@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/flux/ast"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 )
 
@@ -37,6 +38,11 @@ func StdLib() interpreter.Importer {
 	return Default.Stdlib()
 }
 
+// LookupPackage returns the semantic graph for a Flux standard library package.
+func LookupPackage(path string) (*semantic.Package, bool) {
+	return Default.LookupPackage(path)
+}
+
 // Prelude returns a scope object representing the Flux universe block
 func Prelude() values.Scope {
 	return Default.Prelude()
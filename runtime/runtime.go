@@ -118,11 +118,22 @@ func (r *runtime) Prelude() values.Scope {
 }
 
 func (r *runtime) Eval(ctx context.Context, astPkg flux.ASTHandle, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
-	semPkg, err := AnalyzePackage(astPkg)
+	semPkg, err := r.Analyze(astPkg)
 	if err != nil {
 		return nil, nil, err
 	}
+	return r.EvalSemantic(ctx, semPkg, es, opts...)
+}
+
+// Analyze analyzes the AST into a semantic graph.
+// The handle cannot be used after it has been analyzed.
+func (r *runtime) Analyze(astPkg flux.ASTHandle) (*semantic.Package, error) {
+	return AnalyzePackage(astPkg)
+}
 
+// EvalSemantic evaluates a semantic graph that was produced by Analyze.
+// The semantic graph is not modified so it may be evaluated many times.
+func (r *runtime) EvalSemantic(ctx context.Context, semPkg *semantic.Package, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
 	// Construct the initial scope for this package.
 	importer := &importer{r: r}
 	scope, err := r.newScopeFor("main", importer)
@@ -144,6 +155,12 @@ func (r *runtime) Eval(ctx context.Context, astPkg flux.ASTHandle, es interprete
 	return sideEffects, scope, nil
 }
 
+// StdlibVersion returns a hash of the sources of libflux
+// and of the standard library that is embedded within it.
+func (r *runtime) StdlibVersion() string {
+	return libflux.Version()
+}
+
 // newScopeFor constructs a new scope for the given package using the
 // passed in importer.
 func (r *runtime) newScopeFor(pkgpath string, imp interpreter.Importer) (values.Scope, error) {
@@ -173,6 +190,16 @@ func (r *runtime) newScopeFor(pkgpath string, imp interpreter.Importer) (values.
 	return scope, nil
 }
 
+// LookupPackage returns the semantic graph of the builtin
+// package with the given import path.
+func (r *runtime) LookupPackage(path string) (*semantic.Package, bool) {
+	if !r.finalized {
+		panic("builtins not finalized")
+	}
+	pkg, ok := r.pkgs[path]
+	return pkg, ok
+}
+
 func (r *runtime) Stdlib() interpreter.Importer {
 	if !r.finalized {
 		panic("builtins not finalized")
@@ -81,6 +81,8 @@ func nature(tbl flatbuffers.Table, t fbsemantic.MonoType) Nature {
 			return Regexp
 		case fbsemantic.TypeBytes:
 			return Bytes
+		case fbsemantic.TypeDecimal:
+			return Decimal
 		default:
 			return Invalid
 		}
@@ -128,6 +130,7 @@ var (
 	BasicTime     = newBasicType(fbsemantic.TypeTime)
 	BasicRegexp   = newBasicType(fbsemantic.TypeRegexp)
 	BasicBytes    = newBasicType(fbsemantic.TypeBytes)
+	BasicDecimal  = newBasicType(fbsemantic.TypeDecimal)
 )
 
 func getBasic(tbl fbTabler) (*fbsemantic.Basic, error) {
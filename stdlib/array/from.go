@@ -55,7 +55,6 @@ func (s *FromOpSpec) Kind() flux.OperationKind {
 }
 
 type FromProcedureSpec struct {
-	plan.DefaultCost
 	Rows values.Array
 }
 
@@ -80,6 +79,15 @@ func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Cost reports that the rows are produced as a single table.
+func (s *FromProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	n := int64(s.Rows.Len())
+	return plan.Cost{CPU: n}, plan.Statistics{
+		Cardinality:      n,
+		GroupCardinality: 1,
+	}
+}
+
 func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := ps.(*FromProcedureSpec)
 	return &tableSource{
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
@@ -19,8 +20,8 @@ var SpecialFns map[string]values.Function
 func init() {
 	SpecialFns = map[string]values.Function{
 		"second": values.NewFunction(
-			"second",
-			runtime.MustLookupBuiltinType("date", "second"),
+			"_second",
+			runtime.MustLookupBuiltinType("date", "_second"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -31,15 +32,20 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Second())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Second())), nil
 				}
 
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					second := nowTime.Add(v1.Duration().Duration()).Second()
+					second := nowTime.Add(v1.Duration().Duration()).In(loc).Second()
 					return values.NewInt(int64(second)), nil
 				}
 
@@ -47,8 +53,8 @@ func init() {
 			}, false,
 		),
 		"minute": values.NewFunction(
-			"minute",
-			runtime.MustLookupBuiltinType("date", "minute"),
+			"_minute",
+			runtime.MustLookupBuiltinType("date", "_minute"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -59,22 +65,27 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Minute())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Minute())), nil
 				}
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					minute := nowTime.Add(v1.Duration().Duration()).Minute()
+					minute := nowTime.Add(v1.Duration().Duration()).In(loc).Minute()
 					return values.NewInt(int64(minute)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"hour": values.NewFunction(
-			"hour",
-			runtime.MustLookupBuiltinType("date", "hour"),
+			"_hour",
+			runtime.MustLookupBuiltinType("date", "_hour"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -85,23 +96,28 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Hour())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Hour())), nil
 				}
 
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					hour := nowTime.Add(v1.Duration().Duration()).Hour()
+					hour := nowTime.Add(v1.Duration().Duration()).In(loc).Hour()
 					return values.NewInt(int64(hour)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"weekDay": values.NewFunction(
-			"weekDay",
-			runtime.MustLookupBuiltinType("date", "weekDay"),
+			"_weekDay",
+			runtime.MustLookupBuiltinType("date", "_weekDay"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -112,22 +128,27 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Weekday())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Weekday())), nil
 				}
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					weekDay := nowTime.Add(v1.Duration().Duration()).Weekday()
+					weekDay := nowTime.Add(v1.Duration().Duration()).In(loc).Weekday()
 					return values.NewInt(int64(weekDay)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"monthDay": values.NewFunction(
-			"monthDay",
-			runtime.MustLookupBuiltinType("date", "monthDay"),
+			"_monthDay",
+			runtime.MustLookupBuiltinType("date", "_monthDay"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -138,22 +159,27 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Day())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Day())), nil
 				}
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					day := nowTime.Add(v1.Duration().Duration()).Day()
+					day := nowTime.Add(v1.Duration().Duration()).In(loc).Day()
 					return values.NewInt(int64(day)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"yearDay": values.NewFunction(
-			"yearDay",
-			runtime.MustLookupBuiltinType("date", "yearDay"),
+			"_yearDay",
+			runtime.MustLookupBuiltinType("date", "_yearDay"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -164,23 +190,28 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().YearDay())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).YearDay())), nil
 				}
 
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					yearDay := nowTime.Add(v1.Duration().Duration()).YearDay()
+					yearDay := nowTime.Add(v1.Duration().Duration()).In(loc).YearDay()
 					return values.NewInt(int64(yearDay)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"month": values.NewFunction(
-			"month",
-			runtime.MustLookupBuiltinType("date", "month"),
+			"_month",
+			runtime.MustLookupBuiltinType("date", "_month"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -191,23 +222,28 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Month())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Month())), nil
 				}
 
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					month := nowTime.Add(v1.Duration().Duration()).Month()
+					month := nowTime.Add(v1.Duration().Duration()).In(loc).Month()
 					return values.NewInt(int64(month)), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
 			}, false,
 		),
 		"year": values.NewFunction(
-			"year",
-			runtime.MustLookupBuiltinType("date", "year"),
+			"_year",
+			runtime.MustLookupBuiltinType("date", "_year"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -218,15 +254,20 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					return values.NewInt(int64(v1.Time().Time().Year())), nil
+					return values.NewInt(int64(v1.Time().Time().In(loc).Year())), nil
 				}
 
 				if v1.Type().Nature() == semantic.Duration {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					year := nowTime.Add(v1.Duration().Duration()).Year()
+					year := nowTime.Add(v1.Duration().Duration()).In(loc).Year()
 					return values.NewInt(int64(year)), nil
 				}
 
@@ -234,8 +275,8 @@ func init() {
 			}, false,
 		),
 		"week": values.NewFunction(
-			"week",
-			runtime.MustLookupBuiltinType("date", "week"),
+			"_week",
+			runtime.MustLookupBuiltinType("date", "_week"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -246,8 +287,13 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					_, week := v1.Time().Time().ISOWeek()
+					_, week := v1.Time().Time().In(loc).ISOWeek()
 					return values.NewInt(int64(week)), nil
 				}
 
@@ -255,7 +301,7 @@ func init() {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					_, week := nowTime.Add(v1.Duration().Duration()).ISOWeek()
+					_, week := nowTime.Add(v1.Duration().Duration()).In(loc).ISOWeek()
 					return values.NewInt(int64(week)), nil
 				}
 
@@ -263,8 +309,8 @@ func init() {
 			}, false,
 		),
 		"quarter": values.NewFunction(
-			"quarter",
-			runtime.MustLookupBuiltinType("date", "quarter"),
+			"_quarter",
+			runtime.MustLookupBuiltinType("date", "_quarter"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v1, ok := args.Get("t")
 				if !ok {
@@ -275,8 +321,13 @@ func init() {
 					return nil, errors.New(codes.FailedPrecondition, "argument t was nil")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if v1.Type().Nature() == semantic.Time {
-					month := v1.Time().Time().Month()
+					month := v1.Time().Time().In(loc).Month()
 					return values.NewInt(int64(math.Ceil(float64(month) / 3.0))), nil
 				}
 
@@ -284,7 +335,7 @@ func init() {
 					deps := execute.GetExecutionDependencies(ctx)
 					nowTime := *deps.Now
 
-					month := nowTime.Add(v1.Duration().Duration()).Month()
+					month := nowTime.Add(v1.Duration().Duration()).In(loc).Month()
 					return values.NewInt(int64(math.Ceil(float64(month) / 3.0))), nil
 				}
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot convert argument t of type %v to time", v1.Type().Nature()))
@@ -373,8 +424,8 @@ func init() {
 			}, false,
 		),
 		"truncate": values.NewFunction(
-			"truncate",
-			runtime.MustLookupBuiltinType("date", "truncate"),
+			"_truncate",
+			runtime.MustLookupBuiltinType("date", "_truncate"),
 			func(ctx context.Context, args values.Object) (values.Value, error) {
 				v, ok := args.Get("t")
 				if !ok {
@@ -390,12 +441,18 @@ func init() {
 					return nil, errors.New(codes.Invalid, "missing argument unit")
 				}
 
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+
 				if values.IsTimeable(v) && u.Type().Nature() == semantic.Duration {
 					if v.Type().Nature() == semantic.Time {
 						w, err := execute.NewWindow(u.Duration(), u.Duration(), execute.Duration{})
 						if err != nil {
 							return nil, err
 						}
+						w.Location = loc
 						b := w.GetEarliestBounds(v.Time())
 						return values.NewTime(b.Start), nil
 					}
@@ -406,6 +463,7 @@ func init() {
 						if err != nil {
 							return nil, err
 						}
+						w.Location = loc
 
 						deps := execute.GetExecutionDependencies(ctx)
 						nowTime := *deps.Now
@@ -417,20 +475,210 @@ func init() {
 				return nil, errors.New(codes.FailedPrecondition, fmt.Sprintf("cannot truncate argument t of type %v to unit %v", v.Type().Nature(), u))
 			}, false,
 		),
+		"add": values.NewFunction(
+			"_add",
+			runtime.MustLookupBuiltinType("date", "_add"),
+			func(ctx context.Context, args values.Object) (values.Value, error) {
+				d, err := getDuration(args, "d")
+				if err != nil {
+					return nil, err
+				}
+				t, err := getTime(ctx, args, "to")
+				if err != nil {
+					return nil, err
+				}
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+				return values.NewTime(t.AddInLocation(d, loc)), nil
+			}, false,
+		),
+		"sub": values.NewFunction(
+			"_sub",
+			runtime.MustLookupBuiltinType("date", "_sub"),
+			func(ctx context.Context, args values.Object) (values.Value, error) {
+				d, err := getDuration(args, "d")
+				if err != nil {
+					return nil, err
+				}
+				t, err := getTime(ctx, args, "from")
+				if err != nil {
+					return nil, err
+				}
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+				return values.NewTime(t.AddInLocation(d.Mul(-1), loc)), nil
+			}, false,
+		),
+		"diff": values.NewFunction(
+			"_diff",
+			runtime.MustLookupBuiltinType("date", "_diff"),
+			func(ctx context.Context, args values.Object) (values.Value, error) {
+				start, err := getTime(ctx, args, "start")
+				if err != nil {
+					return nil, err
+				}
+				stop, err := getTime(ctx, args, "stop")
+				if err != nil {
+					return nil, err
+				}
+				unit, err := getDuration(args, "unit")
+				if err != nil {
+					return nil, err
+				}
+				if !unit.IsPositive() {
+					return nil, errors.Newf(codes.Invalid, "unit must be positive, got %v", unit)
+				}
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+				return values.NewInt(diff(start, stop, unit, loc)), nil
+			}, false,
+		),
+		"startOfWeek": values.NewFunction(
+			"_startOfWeek",
+			runtime.MustLookupBuiltinType("date", "_startOfWeek"),
+			func(ctx context.Context, args values.Object) (values.Value, error) {
+				t, err := getTime(ctx, args, "t")
+				if err != nil {
+					return nil, err
+				}
+				v, ok := args.Get("weekStart")
+				if !ok {
+					return nil, errors.New(codes.Invalid, "missing argument weekStart")
+				}
+				if v.Type().Nature() != semantic.Int {
+					return nil, errors.Newf(codes.Invalid, "weekStart must be an int, got %v", v.Type().Nature())
+				}
+				weekStart := v.Int()
+				if weekStart < 0 || weekStart > 6 {
+					return nil, errors.Newf(codes.Invalid, "weekStart must be a day of the week between 0 and 6, got %d", weekStart)
+				}
+				loc, err := getLocation(args)
+				if err != nil {
+					return nil, err
+				}
+				return values.NewTime(startOfWeek(t, time.Weekday(weekStart), loc)), nil
+			}, false,
+		),
 	}
 
-	runtime.RegisterPackageValue("date", "second", SpecialFns["second"])
-	runtime.RegisterPackageValue("date", "minute", SpecialFns["minute"])
-	runtime.RegisterPackageValue("date", "hour", SpecialFns["hour"])
-	runtime.RegisterPackageValue("date", "weekDay", SpecialFns["weekDay"])
-	runtime.RegisterPackageValue("date", "monthDay", SpecialFns["monthDay"])
-	runtime.RegisterPackageValue("date", "yearDay", SpecialFns["yearDay"])
-	runtime.RegisterPackageValue("date", "month", SpecialFns["month"])
-	runtime.RegisterPackageValue("date", "year", SpecialFns["year"])
-	runtime.RegisterPackageValue("date", "week", SpecialFns["week"])
-	runtime.RegisterPackageValue("date", "quarter", SpecialFns["quarter"])
+	runtime.RegisterPackageValue("date", "_second", SpecialFns["second"])
+	runtime.RegisterPackageValue("date", "_minute", SpecialFns["minute"])
+	runtime.RegisterPackageValue("date", "_hour", SpecialFns["hour"])
+	runtime.RegisterPackageValue("date", "_weekDay", SpecialFns["weekDay"])
+	runtime.RegisterPackageValue("date", "_monthDay", SpecialFns["monthDay"])
+	runtime.RegisterPackageValue("date", "_yearDay", SpecialFns["yearDay"])
+	runtime.RegisterPackageValue("date", "_month", SpecialFns["month"])
+	runtime.RegisterPackageValue("date", "_year", SpecialFns["year"])
+	runtime.RegisterPackageValue("date", "_week", SpecialFns["week"])
+	runtime.RegisterPackageValue("date", "_quarter", SpecialFns["quarter"])
 	runtime.RegisterPackageValue("date", "millisecond", SpecialFns["millisecond"])
 	runtime.RegisterPackageValue("date", "microsecond", SpecialFns["microsecond"])
 	runtime.RegisterPackageValue("date", "nanosecond", SpecialFns["nanosecond"])
-	runtime.RegisterPackageValue("date", "truncate", SpecialFns["truncate"])
+	runtime.RegisterPackageValue("date", "_truncate", SpecialFns["truncate"])
+	runtime.RegisterPackageValue("date", "_add", SpecialFns["add"])
+	runtime.RegisterPackageValue("date", "_sub", SpecialFns["sub"])
+	runtime.RegisterPackageValue("date", "_diff", SpecialFns["diff"])
+	runtime.RegisterPackageValue("date", "_startOfWeek", SpecialFns["startOfWeek"])
+}
+
+// getLocation returns the time zone for the location argument.
+// The location is UTC when the argument is not present.
+func getLocation(args values.Object) (*time.Location, error) {
+	v, ok := args.Get("location")
+	if !ok {
+		return time.UTC, nil
+	}
+	if v.Type().Nature() != semantic.Object {
+		return nil, errors.Newf(codes.Invalid, "location must be a record, got %v", v.Type().Nature())
+	}
+	loc, err := plan.LocationFromObject(v.Object())
+	if err != nil {
+		return nil, err
+	}
+	return loc.Load()
+}
+
+// getTime returns the time for a timeable argument.
+// A duration is relative to the now time of the query.
+func getTime(ctx context.Context, args values.Object, name string) (values.Time, error) {
+	v, ok := args.Get(name)
+	if !ok {
+		return 0, errors.Newf(codes.Invalid, "missing argument %s", name)
+	}
+	if v == nil {
+		return 0, errors.Newf(codes.FailedPrecondition, "argument %s was nil", name)
+	}
+	switch v.Type().Nature() {
+	case semantic.Time:
+		return v.Time(), nil
+	case semantic.Duration:
+		deps := execute.GetExecutionDependencies(ctx)
+		return values.ConvertTime(*deps.Now).Add(v.Duration()), nil
+	default:
+		return 0, errors.Newf(codes.FailedPrecondition, "cannot convert argument %s of type %v to time", name, v.Type().Nature())
+	}
+}
+
+func getDuration(args values.Object, name string) (values.Duration, error) {
+	v, ok := args.Get(name)
+	if !ok {
+		return values.Duration{}, errors.Newf(codes.Invalid, "missing argument %s", name)
+	}
+	if v == nil {
+		return values.Duration{}, errors.Newf(codes.FailedPrecondition, "argument %s was nil", name)
+	}
+	if v.Type().Nature() != semantic.Duration {
+		return values.Duration{}, errors.Newf(codes.Invalid, "argument %s must be a duration, got %v", name, v.Type().Nature())
+	}
+	return v.Duration(), nil
+}
+
+// diff returns the number of whole units between start and stop.
+// The result is negative when stop is before start.
+func diff(start, stop values.Time, unit values.Duration, loc *time.Location) int64 {
+	at := func(n int64) values.Time {
+		return start.AddInLocation(unit.Mul(int(n)), loc)
+	}
+
+	// Estimate the number of units using the average length
+	// of a unit and adjust it using calendar arithmetic.
+	n := int64(stop-start) / int64(unit.Duration())
+	if stop >= start {
+		if n < 0 {
+			n = 0
+		}
+		for n > 0 && at(n) > stop {
+			n--
+		}
+		for at(n+1) <= stop {
+			n++
+		}
+		return n
+	}
+	if n > 0 {
+		n = 0
+	}
+	for n < 0 && at(n) < stop {
+		n++
+	}
+	for at(n-1) >= stop {
+		n--
+	}
+	return n
+}
+
+// startOfWeek returns the start of the day that begins
+// the week of t in the given location.
+func startOfWeek(t values.Time, weekStart time.Weekday, loc *time.Location) values.Time {
+	local := t.ToLocal(loc).Time()
+	days := (int(local.Weekday()) - int(weekStart) + 7) % 7
+	year, month, day := local.Date()
+	start := time.Date(year, month, day-days, 0, 0, 0, 0, time.UTC)
+	return values.ConvertTime(start).FromLocal(loc)
 }
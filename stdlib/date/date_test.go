@@ -2,7 +2,9 @@ package date
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/flux/dependencies/dependenciestest"
 	"github.com/influxdata/flux/values"
@@ -221,3 +223,250 @@ func TestTruncateNilErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestLocation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fn       string
+		time     string
+		unit     string
+		location values.Object
+		want     values.Value
+		wantErr  string
+	}{
+		{
+			name: "hour in zone",
+			fn:   "hour",
+			time: "2021-03-28T21:30:00.000000000Z",
+			location: values.NewObjectWithValues(map[string]values.Value{
+				"zone":   values.NewString("Europe/Berlin"),
+				"offset": values.NewDuration(values.ConvertDurationNsecs(0)),
+			}),
+			want: values.NewInt(23),
+		},
+		{
+			name: "weekDay with fixed offset",
+			fn:   "weekDay",
+			time: "2021-03-29T01:30:00.000000000Z",
+			location: values.NewObjectWithValues(map[string]values.Value{
+				"zone":   values.NewString("UTC"),
+				"offset": values.NewDuration(values.ConvertDurationNsecs(-2 * time.Hour)),
+			}),
+			want: values.NewInt(0),
+		},
+		{
+			name: "truncate in zone",
+			fn:   "truncate",
+			time: "2021-03-28T21:30:00.000000000Z",
+			unit: "1d",
+			location: values.NewObjectWithValues(map[string]values.Value{
+				"zone":   values.NewString("Europe/Berlin"),
+				"offset": values.NewDuration(values.ConvertDurationNsecs(0)),
+			}),
+			want: values.NewTime(values.ConvertTime(time.Date(2021, time.March, 27, 23, 0, 0, 0, time.UTC))),
+		},
+		{
+			name: "unknown zone",
+			fn:   "hour",
+			time: "2021-03-28T21:30:00.000000000Z",
+			location: values.NewObjectWithValues(map[string]values.Value{
+				"zone":   values.NewString("Mars/Olympus_Mons"),
+				"offset": values.NewDuration(values.ConvertDurationNsecs(0)),
+			}),
+			wantErr: `unknown location "Mars/Olympus_Mons"`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fluxFn := SpecialFns[tc.fn]
+			ts, err := values.ParseTime(tc.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fluxArg := values.NewObjectWithValues(map[string]values.Value{
+				"t":        values.NewTime(ts),
+				"location": tc.location,
+			})
+			if tc.unit != "" {
+				unit, err := values.ParseDuration(tc.unit)
+				if err != nil {
+					t.Fatal(err)
+				}
+				fluxArg.Set("unit", values.NewDuration(unit))
+			}
+			got, err := fluxFn.Call(dependenciestest.Default().Inject(context.Background()), fluxArg)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatal("expected error")
+				} else if !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: want %q, got %q", tc.wantErr, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !tc.want.Equal(got) {
+				t.Errorf("input %v: expected %v, got %v", ts, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCalendarFns(t *testing.T) {
+	mustTime := func(s string) values.Value {
+		ts, err := values.ParseTime(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return values.NewTime(ts)
+	}
+	mustDuration := func(s string) values.Value {
+		d, err := values.ParseDuration(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return values.NewDuration(d)
+	}
+	chicago := values.NewObjectWithValues(map[string]values.Value{
+		"zone":   values.NewString("America/Chicago"),
+		"offset": values.NewDuration(values.ConvertDurationNsecs(0)),
+	})
+
+	testCases := []struct {
+		name    string
+		fn      string
+		args    map[string]values.Value
+		want    values.Value
+		wantErr string
+	}{
+		{
+			name: "add month",
+			fn:   "add",
+			args: map[string]values.Value{
+				"d":  mustDuration("1mo"),
+				"to": mustTime("2021-01-31T12:00:00.000000000Z"),
+			},
+			want: mustTime("2021-02-28T12:00:00.000000000Z"),
+		},
+		{
+			name: "add day in zone",
+			fn:   "add",
+			args: map[string]values.Value{
+				"d":        mustDuration("1d"),
+				"to":       mustTime("2021-03-13T18:00:00.000000000Z"),
+				"location": chicago,
+			},
+			want: mustTime("2021-03-14T17:00:00.000000000Z"),
+		},
+		{
+			name: "sub month in zone",
+			fn:   "sub",
+			args: map[string]values.Value{
+				"d":        mustDuration("1mo"),
+				"from":     mustTime("2021-03-31T05:00:00.000000000Z"),
+				"location": chicago,
+			},
+			want: mustTime("2021-02-28T06:00:00.000000000Z"),
+		},
+		{
+			name: "diff months",
+			fn:   "diff",
+			args: map[string]values.Value{
+				"start": mustTime("2021-01-15T00:00:00.000000000Z"),
+				"stop":  mustTime("2021-04-14T00:00:00.000000000Z"),
+				"unit":  mustDuration("1mo"),
+			},
+			want: values.NewInt(2),
+		},
+		{
+			name: "diff negative",
+			fn:   "diff",
+			args: map[string]values.Value{
+				"start": mustTime("2021-04-14T00:00:00.000000000Z"),
+				"stop":  mustTime("2021-01-15T00:00:00.000000000Z"),
+				"unit":  mustDuration("1mo"),
+			},
+			want: values.NewInt(-2),
+		},
+		{
+			name: "diff days in zone",
+			fn:   "diff",
+			args: map[string]values.Value{
+				"start":    mustTime("2021-03-13T18:00:00.000000000Z"),
+				"stop":     mustTime("2021-03-14T17:00:00.000000000Z"),
+				"unit":     mustDuration("1d"),
+				"location": chicago,
+			},
+			want: values.NewInt(1),
+		},
+		{
+			name: "diff hours",
+			fn:   "diff",
+			args: map[string]values.Value{
+				"start": mustTime("2021-03-13T18:00:00.000000000Z"),
+				"stop":  mustTime("2021-03-14T17:59:59.000000000Z"),
+				"unit":  mustDuration("2h"),
+			},
+			want: values.NewInt(11),
+		},
+		{
+			name: "diff zero unit",
+			fn:   "diff",
+			args: map[string]values.Value{
+				"start": mustTime("2021-03-13T18:00:00.000000000Z"),
+				"stop":  mustTime("2021-03-14T17:59:59.000000000Z"),
+				"unit":  mustDuration("0s"),
+			},
+			wantErr: "unit must be positive",
+		},
+		{
+			name: "start of week",
+			fn:   "startOfWeek",
+			args: map[string]values.Value{
+				"t":         mustTime("2021-03-17T12:00:00.000000000Z"),
+				"weekStart": values.NewInt(1),
+			},
+			want: mustTime("2021-03-15T00:00:00.000000000Z"),
+		},
+		{
+			name: "start of week on sunday in zone",
+			fn:   "startOfWeek",
+			args: map[string]values.Value{
+				"t":         mustTime("2021-03-17T03:00:00.000000000Z"),
+				"weekStart": values.NewInt(0),
+				"location":  chicago,
+			},
+			want: mustTime("2021-03-14T06:00:00.000000000Z"),
+		},
+		{
+			name: "invalid week start",
+			fn:   "startOfWeek",
+			args: map[string]values.Value{
+				"t":         mustTime("2021-03-17T12:00:00.000000000Z"),
+				"weekStart": values.NewInt(7),
+			},
+			wantErr: "weekStart must be a day of the week",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fluxFn := SpecialFns[tc.fn]
+			got, err := fluxFn.Call(dependenciestest.Default().Inject(context.Background()), values.NewObjectWithValues(tc.args))
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatal("expected error")
+				} else if !strings.HasPrefix(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error: want %q, got %q", tc.wantErr, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !tc.want.Equal(got) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -29,9 +29,10 @@ func init() {
 }
 
 type JoinOpSpec struct {
-	Left  flux.OperationID             `json:"left"`
-	Right flux.OperationID             `json:"right"`
-	Fn    interpreter.ResolvedFunction `json:"fn"`
+	Left   flux.OperationID             `json:"left"`
+	Right  flux.OperationID             `json:"right"`
+	Fn     interpreter.ResolvedFunction `json:"fn"`
+	Method string                       `json:"method"`
 
 	l, r *flux.TableObject
 }
@@ -81,10 +82,23 @@ func createJoinOpSpec(args flux.Arguments, p *flux.Administration) (flux.Operati
 		return nil, err
 	}
 
+	method, ok, err := args.GetString("method")
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		method = "inner"
+	}
+	switch method {
+	case "inner", "left", "right", "full":
+	default:
+		return nil, errors.Newf(codes.Invalid, "%s is not a valid join type", method)
+	}
+
 	return &JoinOpSpec{
-		Fn: fn,
-		l:  left,
-		r:  right,
+		Fn:     fn,
+		Method: method,
+		l:      left,
+		r:      right,
 	}, nil
 }
 
@@ -99,7 +113,8 @@ func (s *JoinOpSpec) Kind() flux.OperationKind {
 type MergeJoinProcedureSpec struct {
 	plan.DefaultCost
 
-	Fn interpreter.ResolvedFunction `json:"fn"`
+	Fn     interpreter.ResolvedFunction `json:"fn"`
+	Method string                       `json:"method"`
 }
 
 func newMergeJoinProcedure(spec flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -107,14 +122,14 @@ func newMergeJoinProcedure(spec flux.OperationSpec, pa plan.Administration) (pla
 	if !ok {
 		return nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
-	return &MergeJoinProcedureSpec{Fn: s.Fn}, nil
+	return &MergeJoinProcedureSpec{Fn: s.Fn, Method: s.Method}, nil
 }
 
 func (s *MergeJoinProcedureSpec) Kind() plan.ProcedureKind {
 	return joinKind
 }
 func (s *MergeJoinProcedureSpec) Copy() plan.ProcedureSpec {
-	return &MergeJoinProcedureSpec{Fn: s.Fn.Copy()}
+	return &MergeJoinProcedureSpec{Fn: s.Fn.Copy(), Method: s.Method}
 }
 
 func createMergeJoinTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
@@ -125,6 +140,7 @@ func createMergeJoinTransformation(id execute.DatasetID, mode execute.Accumulati
 	parents := a.Parents()
 
 	c := NewMergeJoinCache(a.Context(), a.Allocator(), s.Fn, parents[0], parents[1])
+	c.method = s.Method
 	d := execute.NewDataset(id, mode, c)
 	t := NewMergeJoinTransformation(d, c)
 	return t, d, nil
@@ -192,6 +208,7 @@ func (t *mergeJoinTransformation) Finish(id execute.DatasetID, err error) {
 	defer t.mu.Unlock()
 
 	if err != nil || t.done {
+		t.cache.finished = true
 		t.d.Finish(err)
 		t.cache.clean()
 	}
@@ -201,21 +218,33 @@ func (t *mergeJoinTransformation) Finish(id execute.DatasetID, err error) {
 
 func NewMergeJoinCache(ctx context.Context, alloc *memory.Allocator, fn interpreter.ResolvedFunction, left, right execute.DatasetID) *mergeJoinCache {
 	return &mergeJoinCache{
-		left:  left,
-		right: right,
-		fn:    newRowJoinFn(fn.Fn, compiler.ToScope(fn.Scope)),
-		data:  execute.NewGroupLookup(),
-		ctx:   ctx,
-		alloc: alloc,
-	}
-}
-
+		left:    left,
+		right:   right,
+		fn:      newRowJoinFn(fn.Fn, compiler.ToScope(fn.Scope)),
+		data:    execute.NewGroupLookup(),
+		columns: make(map[execute.DatasetID][]flux.ColMeta, 2),
+		ctx:     ctx,
+		alloc:   alloc,
+	}
+}
+
+// mergeJoinCache holds the tables from both input streams
+// until the tables with the same group key can be joined.
+//
+// For the left, right and full outer join methods, rows that do not
+// match any row from the other stream are passed to fn with a record
+// where the columns of the other stream are null. A table that has no
+// table with the same group key in the other stream is only joined
+// once both input streams have finished.
 type mergeJoinCache struct {
 	left, right execute.DatasetID
 	fn          *rowJoinFn
+	method      string
+	finished    bool
 
-	data *execute.GroupLookup
-	spec plan.TriggerSpec
+	data    *execute.GroupLookup
+	spec    plan.TriggerSpec
+	columns map[execute.DatasetID][]flux.ColMeta
 
 	ctx   context.Context
 	alloc *memory.Allocator
@@ -225,11 +254,37 @@ type cacheEntry struct {
 	l, r *RowIterator
 }
 
-func (e *cacheEntry) ready() bool {
-	return e.l != nil && e.r != nil && e.l.len != 0 && e.r.len != 0
+// preserves reports whether the join method keeps the rows from the
+// stream with the given id that do not match any row from the other stream.
+func (c *mergeJoinCache) preserves(id execute.DatasetID) bool {
+	switch c.method {
+	case "left":
+		return id == c.left
+	case "right":
+		return id == c.right
+	case "full":
+		return true
+	}
+	return false
+}
+
+// ready reports whether the entry can be joined.
+func (c *mergeJoinCache) ready(e *cacheEntry) bool {
+	l := e.l != nil && e.l.len != 0
+	r := e.r != nil && e.r.len != 0
+	if l && r {
+		return true
+	}
+	if !c.finished {
+		return false
+	}
+	return l && c.preserves(c.left) || r && c.preserves(c.right)
 }
 
 func (c *mergeJoinCache) insert(id execute.DatasetID, key flux.GroupKey, iter *RowIterator) {
+	if _, ok := c.columns[id]; !ok {
+		c.columns[id] = iter.columns
+	}
 	if entry, ok := c.data.Lookup(key); ok {
 		switch id {
 		case c.left:
@@ -279,15 +334,22 @@ func (c *mergeJoinCache) Table(key flux.GroupKey) (flux.Table, error) {
 		return nil, errors.Newf(codes.Internal, "no entry for group key %v in cache", key)
 	}
 	t := entry.(*cacheEntry)
-	if t.l == nil || t.r == nil {
+	if !c.ready(t) {
 		return nil, errors.Newf(codes.Internal, "no entry for group key %v in cache", key)
 	}
-	return c.join(key, t.l, t.r)
+	l, r := t.l, t.r
+	if l == nil {
+		l = NewRowIterator(c.columns[c.left], nil, -1)
+	}
+	if r == nil {
+		r = NewRowIterator(c.columns[c.right], nil, -1)
+	}
+	return c.join(key, l, r)
 }
 
 func (c *mergeJoinCache) ForEach(f func(flux.GroupKey)) {
 	c.data.Range(func(key flux.GroupKey, value interface{}) {
-		if value.(*cacheEntry).ready() {
+		if c.ready(value.(*cacheEntry)) {
 			f(key)
 		}
 	})
@@ -295,7 +357,7 @@ func (c *mergeJoinCache) ForEach(f func(flux.GroupKey)) {
 
 func (c *mergeJoinCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigger, execute.TableContext)) {
 	c.data.Range(func(key flux.GroupKey, value interface{}) {
-		if value.(*cacheEntry).ready() {
+		if c.ready(value.(*cacheEntry)) {
 			f(key, execute.NewTriggerFromSpec(c.spec), execute.TableContext{
 				Key: key,
 			})
@@ -325,6 +387,42 @@ func (c *mergeJoinCache) join(key flux.GroupKey, a, b *RowIterator) (flux.Table,
 	builder := execute.NewColListTableBuilder(key, c.alloc)
 
 	firstRow := true
+	appendRow := func(left, right map[string]values.Value) error {
+		// Evaluate fn over both input rows
+		obj, err := c.fn.Eval(c.ctx, left, right)
+		if err != nil {
+			return err
+		}
+
+		// Build schema if this is the first row being joined
+		if firstRow {
+			if err := buildSchema(builder, obj); err != nil {
+				return err
+			}
+			firstRow = false
+		}
+
+		// Check fn does not update the group key values.
+		// TODO(josh): This should be caught during planning.
+		// Remove this when the planner is made schema aware.
+		if ok := objContainsKey(obj, key); !ok {
+			return errors.New(codes.Invalid, "argument 'fn' may not modify group key")
+		}
+
+		// The record obtained from calling fn may be added to output
+		return appendRowToBuilder(builder, obj)
+	}
+
+	// Rows that are not matched by an outer join
+	// are joined with a record of null values.
+	preserveLeft, preserveRight := c.preserves(c.left), c.preserves(c.right)
+	nullLeft, nullRight := nullRecord(key, a.columns), nullRecord(key, b.columns)
+
+	// The time of the last rows that were joined. The rows of b
+	// with this time must not be joined again with a null record.
+	var lastMatched int64
+	matched := false
+
 	i, j := 0, 0
 
 NEXT:
@@ -336,11 +434,21 @@ NEXT:
 	}
 
 	if ta < tb {
+		if preserveLeft {
+			if err := appendRow(a.record(i), nullRight); err != nil {
+				return nil, err
+			}
+		}
 		i++
 		goto NEXT
 	}
 
 	if ta > tb {
+		if preserveRight && !(matched && tb == lastMatched) {
+			if err := appendRow(nullLeft, b.record(j)); err != nil {
+				return nil, err
+			}
+		}
 		j++
 		goto NEXT
 	}
@@ -354,37 +462,46 @@ NEXT:
 	// incremented by one.
 	//
 	for k := j; ta == b.time(k); k++ {
-
-		// Evaluate fn over both input rows
-		obj, err := c.fn.Eval(c.ctx, a.record(i), b.record(k))
-		if err != nil {
+		if err := appendRow(a.record(i), b.record(k)); err != nil {
 			return nil, err
 		}
-
-		// Build schema if this is the first row being joined
-		if firstRow {
-			if err := buildSchema(builder, obj); err != nil {
+	}
+	lastMatched, matched = ta, true
+	i++
+	goto NEXT
+DONE:
+	if preserveLeft {
+		for ; a.time(i) != -1; i++ {
+			if err := appendRow(a.record(i), nullRight); err != nil {
 				return nil, err
 			}
-			firstRow = false
 		}
-
-		// Check fn does not update the group key values.
-		// TODO(josh): This should be caught during planning.
-		// Remove this when the planner is made schema aware.
-		if ok := objContainsKey(obj, key); !ok {
-			return nil, errors.New(codes.Invalid, "argument 'fn' may not modify group key")
+	}
+	if preserveRight {
+		for ; b.time(j) != -1; j++ {
+			if matched && b.time(j) == lastMatched {
+				continue
+			}
+			if err := appendRow(nullLeft, b.record(j)); err != nil {
+				return nil, err
+			}
 		}
+	}
+	return builder.Table()
+}
 
-		// The record obtained from calling fn may be added to output
-		if err := appendRowToBuilder(builder, obj); err != nil {
-			return nil, err
+// nullRecord returns a record with a null value for each column
+// that is not part of the group key.
+func nullRecord(key flux.GroupKey, columns []flux.ColMeta) map[string]values.Value {
+	record := make(map[string]values.Value, len(columns))
+	for _, col := range columns {
+		if v := key.LabelValue(col.Label); v != nil {
+			record[col.Label] = v
+			continue
 		}
+		record[col.Label] = values.NewNull(flux.SemanticType(col.Type))
 	}
-	i++
-	goto NEXT
-DONE:
-	return builder.Table()
+	return record
 }
 
 // objContainsKey checks if an object contains a specific group key
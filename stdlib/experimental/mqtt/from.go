@@ -0,0 +1,273 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/payload"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	FromMQTTKind = "fromMQTT"
+	// DefaultFromMQTTDuration is how long mqtt.from collects messages by default.
+	DefaultFromMQTTDuration = 10 * time.Second
+)
+
+func init() {
+	fromMQTTSignature := runtime.MustLookupBuiltinType("experimental/mqtt", "from")
+
+	runtime.RegisterPackageValue("experimental/mqtt", "from", flux.MustValue(flux.FunctionValue(FromMQTTKind, createFromMQTTOpSpec, fromMQTTSignature)))
+	flux.RegisterOpSpec(FromMQTTKind, func() flux.OperationSpec { return &FromMQTTOpSpec{} })
+	plan.RegisterProcedureSpec(FromMQTTKind, newFromMQTTProcedure, FromMQTTKind)
+	execute.RegisterSource(FromMQTTKind, createFromMQTTSource)
+}
+
+// DefaultMQTTClientFactory makes the clients of mqtt.from and is injectable for testing
+var DefaultMQTTClientFactory = func(opts *MQTT.ClientOptions) MQTT.Client {
+	return MQTT.NewClient(opts)
+}
+
+type FromMQTTOpSpec struct {
+	Broker      string        `json:"broker"`
+	Topic       string        `json:"topic"`
+	QoS         int           `json:"qos"`
+	Duration    time.Duration `json:"duration"`
+	MaxMessages int           `json:"maxMessages"`
+	Format      string        `json:"format"`
+	ClientID    string        `json:"clientid"`
+	Username    string        `json:"username"`
+	Password    string        `json:"password"`
+	Timeout     time.Duration `json:"timeout"`
+}
+
+// ReadArgs loads a flux.Arguments into FromMQTTOpSpec. It sets several default values.
+// If the duration isn't set, it defaults to DefaultFromMQTTDuration.
+// If the format isn't set, it defaults to line protocol.
+func (o *FromMQTTOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	o.Broker, err = args.GetRequiredString("broker")
+	if err != nil {
+		return err
+	}
+	if err := validateBroker(o.Broker); err != nil {
+		return err
+	}
+
+	o.Topic, err = args.GetRequiredString("topic")
+	if err != nil {
+		return err
+	}
+	if o.Topic == "" {
+		return fmt.Errorf("topic must not be empty")
+	}
+
+	q, _, err := args.GetInt("qos")
+	if err != nil {
+		return err
+	}
+	if q < 0 || q > 2 {
+		return fmt.Errorf("qos must be 0, 1 or 2 but was %d", q)
+	}
+	o.QoS = int(q)
+
+	d, ok, err := args.GetDuration("duration")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Duration = DefaultFromMQTTDuration
+	} else if o.Duration = values.Duration(d).Duration(); o.Duration <= 0 {
+		return fmt.Errorf("duration must be positive but was %v", o.Duration)
+	}
+
+	n, _, err := args.GetInt("maxMessages")
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("maxMessages must not be negative but was %d", n)
+	}
+	o.MaxMessages = int(n)
+
+	o.Format, ok, err = args.GetString("format")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Format = payload.LineProtocol
+	}
+	if err := payload.ValidateFormat(o.Format); err != nil {
+		return err
+	}
+
+	// Without a client id, each query connects with an id of its own.
+	if o.ClientID, _, err = args.GetString("clientid"); err != nil {
+		return err
+	}
+
+	o.Username, o.Password, err = readCredentials(args)
+	if err != nil {
+		return err
+	}
+
+	timeout, ok, err := args.GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Timeout = DefaultToMQTTTimeout
+	} else {
+		o.Timeout = values.Duration(timeout).Duration()
+	}
+	return nil
+}
+
+func createFromMQTTOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(FromMQTTOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (FromMQTTOpSpec) Kind() flux.OperationKind {
+	return FromMQTTKind
+}
+
+type FromMQTTProcedureSpec struct {
+	plan.DefaultCost
+	Spec *FromMQTTOpSpec
+}
+
+func (o *FromMQTTProcedureSpec) Kind() plan.ProcedureKind {
+	return FromMQTTKind
+}
+
+func (o *FromMQTTProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	return &FromMQTTProcedureSpec{Spec: &s}
+}
+
+func newFromMQTTProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromMQTTOpSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", qs)
+	}
+	return &FromMQTTProcedureSpec{Spec: spec}, nil
+}
+
+func createFromMQTTSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*FromMQTTProcedureSpec)
+	if !ok {
+		return nil, fmt.Errorf("invalid spec type %T", prSpec)
+	}
+	return CreateSource(spec, dsid, a)
+}
+
+func CreateSource(spec *FromMQTTProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	validator, err := flux.GetDependencies(a.Context()).URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.ParseRequestURI(spec.Spec.Broker)
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.Validate(u); err != nil {
+		return nil, fmt.Errorf("mqtt broker url did not pass validation: %v", err)
+	}
+	iterator := &mqttIterator{spec: spec.Spec, alloc: a.Allocator()}
+	return execute.CreateSourceFromIterator(iterator, dsid)
+}
+
+var _ execute.SourceIterator = (*mqttIterator)(nil)
+
+// mqttIterator subscribes to a topic and decodes the messages that
+// it receives until the duration has passed or it has received
+// the maximum number of messages. The time of a message without
+// a time of its own is the time that it was received.
+type mqttIterator struct {
+	spec  *FromMQTTOpSpec
+	alloc *memory.Allocator
+}
+
+func (m *mqttIterator) Do(ctx context.Context, f func(flux.Table) error) error {
+	dec, err := payload.NewDecoder(m.spec.Format, m.alloc)
+	if err != nil {
+		return err
+	}
+	if err := m.collect(ctx, dec); err != nil {
+		return err
+	}
+	return dec.Do(f)
+}
+
+func (m *mqttIterator) collect(ctx context.Context, dec payload.Decoder) error {
+	clientID := m.spec.ClientID
+	if clientID == "" {
+		var err error
+		if clientID, err = randomClientID(); err != nil {
+			return err
+		}
+	}
+	opts := newClientOptions(m.spec.Broker, clientID, m.spec.Username, m.spec.Password, m.spec.Timeout)
+	client := DefaultMQTTClientFactory(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	defer client.Disconnect(250)
+
+	var (
+		msgs = make(chan payload.Message)
+		done = make(chan struct{})
+	)
+	// The handler stops delivering messages once they are no longer collected.
+	defer close(done)
+	handler := func(_ MQTT.Client, msg MQTT.Message) {
+		select {
+		case msgs <- payload.Message{Value: msg.Payload(), Time: time.Now()}:
+		case <-done:
+		}
+	}
+	if token := client.Subscribe(m.spec.Topic, byte(m.spec.QoS), handler); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+
+	timer := time.NewTimer(m.spec.Duration)
+	defer timer.Stop()
+	for n := 0; m.spec.MaxMessages == 0 || n < m.spec.MaxMessages; n++ {
+		select {
+		case msg := <-msgs:
+			if err := dec.Decode(msg); err != nil {
+				return err
+			}
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// randomClientID returns a client id for a query that was not given one.
+// A broker disconnects a client when another one connects with its id,
+// so concurrent queries cannot share a fixed default id.
+func randomClientID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "flux-mqtt-from-" + hex.EncodeToString(b), nil
+}
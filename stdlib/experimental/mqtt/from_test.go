@@ -0,0 +1,231 @@
+package mqtt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/experimental/mqtt"
+)
+
+func TestFromMQTT_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "defaults",
+			Raw: `
+import "experimental/mqtt"
+mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromMQTT0",
+						Spec: &mqtt.FromMQTTOpSpec{
+							Broker:   "tcp://iot.eclipse.org:1883",
+							Topic:    "sensors",
+							Duration: mqtt.DefaultFromMQTTDuration,
+							Format:   "lineprotocol",
+							Timeout:  mqtt.DefaultToMQTTTimeout,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "all options",
+			Raw: `
+import "experimental/mqtt"
+mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors/#", qos: 1, duration: 1m, maxMessages: 100, format: "json", clientid: "reader", username: "user", password: "secret", timeout: 5s)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromMQTT0",
+						Spec: &mqtt.FromMQTTOpSpec{
+							Broker:      "tcp://iot.eclipse.org:1883",
+							Topic:       "sensors/#",
+							QoS:         1,
+							Duration:    time.Minute,
+							MaxMessages: 100,
+							Format:      "json",
+							ClientID:    "reader",
+							Username:    "user",
+							Password:    "secret",
+							Timeout:     5 * time.Second,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "invalid broker",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "http://iot.eclipse.org:1883", topic: "sensors")`,
+			WantErr: true,
+		},
+		{
+			Name:    "invalid qos",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors", qos: 3)`,
+			WantErr: true,
+		},
+		{
+			Name:    "unknown format",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors", format: "xml")`,
+			WantErr: true,
+		},
+		{
+			Name:    "username without password",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.eclipse.org:1883", topic: "sensors", username: "user")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// fakeClient delivers its payloads to the handler of the first
+// subscription. The methods that are not used by mqtt.from are
+// left to the nil embedded client.
+type fakeClient struct {
+	MQTT.Client
+	opts     *MQTT.ClientOptions
+	payloads []string
+	topic    string
+	qos      byte
+}
+
+func (c *fakeClient) Connect() MQTT.Token { return &MQTT.DummyToken{} }
+
+func (c *fakeClient) Disconnect(uint) {}
+
+func (c *fakeClient) Subscribe(topic string, qos byte, callback MQTT.MessageHandler) MQTT.Token {
+	c.topic, c.qos = topic, qos
+	payloads := c.payloads
+	go func() {
+		for _, p := range payloads {
+			callback(c, fakeMessage{topic: topic, payload: []byte(p)})
+		}
+	}()
+	return &MQTT.DummyToken{}
+}
+
+type fakeMessage struct {
+	MQTT.Message
+	topic   string
+	payload []byte
+}
+
+func (m fakeMessage) Topic() string   { return m.topic }
+func (m fakeMessage) Payload() []byte { return m.payload }
+
+func TestFromMQTT_Run(t *testing.T) {
+	payloads := []string{
+		"cpu,host=a usage=1 1609459200000000000",
+		"cpu,host=a usage=2 1609459260000000000",
+		"cpu,host=b usage=3 1609459200000000000",
+	}
+	at := func(d time.Duration) execute.Time {
+		return execute.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).UnixNano())
+	}
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_field", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+	}
+	keyCols := []string{"_field", "_measurement", "host"}
+
+	testCases := []struct {
+		name string
+		spec *mqtt.FromMQTTOpSpec
+		want []*executetest.Table
+	}{
+		{
+			name: "duration",
+			spec: &mqtt.FromMQTTOpSpec{Duration: 100 * time.Millisecond},
+			want: []*executetest.Table{
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{at(0), 1.0, "usage", "cpu", "a"},
+						{at(time.Minute), 2.0, "usage", "cpu", "a"},
+					},
+				},
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{at(0), 3.0, "usage", "cpu", "b"},
+					},
+				},
+			},
+		},
+		{
+			name: "max messages",
+			spec: &mqtt.FromMQTTOpSpec{Duration: time.Minute, MaxMessages: 2},
+			want: []*executetest.Table{
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{at(0), 1.0, "usage", "cpu", "a"},
+						{at(time.Minute), 2.0, "usage", "cpu", "a"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := &fakeClient{payloads: payloads}
+			mqtt.DefaultMQTTClientFactory = func(opts *MQTT.ClientOptions) MQTT.Client {
+				client.opts = opts
+				return client
+			}
+
+			spec := *tc.spec
+			spec.Broker = "tcp://iot.eclipse.org:1883"
+			spec.Topic = "sensors"
+			spec.QoS = 1
+			spec.Format = "lineprotocol"
+			spec.ClientID = "reader"
+			runFromMQTT(t, &spec, tc.want)
+
+			if client.topic != "sensors" || client.qos != 1 {
+				t.Errorf("unexpected subscription: topic %q qos %d", client.topic, client.qos)
+			}
+			if client.opts.ClientID != "reader" {
+				t.Errorf("unexpected client id: %q", client.opts.ClientID)
+			}
+		})
+	}
+}
+
+func runFromMQTT(t *testing.T, spec *mqtt.FromMQTTOpSpec, want []*executetest.Table) {
+	t.Helper()
+	executetest.RunSourceHelper(t,
+		want,
+		nil,
+		func(id execute.DatasetID) execute.Source {
+			ctx := dependenciestest.Default().Inject(context.Background())
+			a := mock.AdministrationWithContext(ctx)
+			s, err := mqtt.CreateSource(&mqtt.FromMQTTProcedureSpec{Spec: spec}, id, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+	)
+}
@@ -100,19 +100,10 @@ func (o *ToMQTTOpSpec) ReadArgs(args flux.Arguments) error {
 		o.ClientID = "flux-mqtt"
 	}
 
-	o.Username, ok, err = args.GetString("username")
+	o.Username, o.Password, err = readCredentials(args)
 	if err != nil {
 		return err
 	}
-	if ok {
-		o.Password, ok, err = args.GetString("password")
-		if err != nil {
-			return err
-		}
-		if !ok {
-			return fmt.Errorf("password required with username %s", o.Username)
-		}
-	}
 
 	q, ok, err := args.GetInt("qos")
 	if err != nil {
@@ -174,6 +165,22 @@ func (o *ToMQTTOpSpec) ReadArgs(args flux.Arguments) error {
 	return err
 }
 
+// readCredentials reads the username and the password that are used to connect to the broker.
+func readCredentials(args flux.Arguments) (username, password string, err error) {
+	username, ok, err := args.GetString("username")
+	if err != nil || !ok {
+		return "", "", err
+	}
+	password, ok, err = args.GetString("password")
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		return "", "", fmt.Errorf("password required with username %s", username)
+	}
+	return username, password, nil
+}
+
 func createToMQTTOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
 	if err := a.AddParentFromArgs(args); err != nil {
 		return nil, err
@@ -190,7 +197,12 @@ func (o *ToMQTTOpSpec) UnmarshalJSON(b []byte) (err error) {
 	if err = json.Unmarshal(b, (*innerToMQTTOpSpec)(o)); err != nil {
 		return err
 	}
-	u, err := url.ParseRequestURI(o.Broker)
+	return validateBroker(o.Broker)
+}
+
+// validateBroker checks that the broker is the url of an MQTT broker.
+func validateBroker(broker string) error {
+	u, err := url.ParseRequestURI(broker)
 	if err != nil {
 		return err
 	}
@@ -200,6 +212,22 @@ func (o *ToMQTTOpSpec) UnmarshalJSON(b []byte) (err error) {
 	return nil
 }
 
+// newClientOptions returns the options of a client that connects to the broker.
+func newClientOptions(broker, clientID, username, password string, timeout time.Duration) *MQTT.ClientOptions {
+	opts := MQTT.NewClientOptions().AddBroker(broker)
+	opts.SetClientID(clientID)
+	if timeout > 0 {
+		opts.SetConnectTimeout(timeout)
+	}
+	if username != "" {
+		opts.SetUsername(username)
+	}
+	if password != "" {
+		opts.SetPassword(password)
+	}
+	return opts
+}
+
 func (ToMQTTOpSpec) Kind() flux.OperationKind {
 	return ToMQTTKind
 }
@@ -306,21 +334,11 @@ type idxType struct {
 
 func (t *ToMQTTTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
 	// set up the MQTT options.
-	opts := MQTT.NewClientOptions().AddBroker(t.spec.Spec.Broker)
-	if t.spec.Spec.ClientID != "" {
-		opts.SetClientID(t.spec.Spec.ClientID)
-	} else {
-		opts.SetClientID("flux-mqtt")
-	}
-	if t.spec.Spec.Timeout > 0 {
-		opts.SetConnectTimeout(t.spec.Spec.Timeout)
-	}
-	if t.spec.Spec.Username != "" {
-		opts.SetUsername(t.spec.Spec.Username)
-	}
-	if t.spec.Spec.Password != "" {
-		opts.SetPassword(t.spec.Spec.Password)
+	clientID := t.spec.Spec.ClientID
+	if clientID == "" {
+		clientID = "flux-mqtt"
 	}
+	opts := newClientOptions(t.spec.Spec.Broker, clientID, t.spec.Spec.Username, t.spec.Spec.Password, t.spec.Spec.Timeout)
 	mqttTopic := t.spec.Spec.Topic
 
 	client := MQTT.NewClient(opts)
@@ -62,6 +62,11 @@ func (s *FillProcedureSpec) Copy() plan.ProcedureSpec {
 	return &ns
 }
 
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+func (s *FillProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
 func createFillTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	_, ok := spec.(*FillProcedureSpec)
 	if !ok {
@@ -77,7 +77,6 @@ func (s *FromGeneratorOpSpec) Kind() flux.OperationKind {
 }
 
 type FromGeneratorProcedureSpec struct {
-	plan.DefaultCost
 	Start time.Time
 	Stop  time.Time
 	Count int64
@@ -105,10 +104,19 @@ func (s *FromGeneratorProcedureSpec) Kind() plan.ProcedureKind {
 
 func (s *FromGeneratorProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromGeneratorProcedureSpec)
-
+	*ns = *s
+	ns.Fn = s.Fn.Copy()
 	return ns
 }
 
+// Cost reports that the generator produces a single table with Count rows.
+func (s *FromGeneratorProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	return plan.Cost{CPU: s.Count}, plan.Statistics{
+		Cardinality:      s.Count,
+		GroupCardinality: 1,
+	}
+}
+
 func createFromGeneratorSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec, ok := prSpec.(*FromGeneratorProcedureSpec)
 	if !ok {
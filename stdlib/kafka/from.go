@@ -0,0 +1,491 @@
+package kafka
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/payload"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// FromKafkaKind is the Kind for the FromKafka Flux function
+	FromKafkaKind = "fromKafka"
+
+	// fetchTimeout is how long a reader waits for a message before it
+	// stops reading. The partitions are read up to the offsets that they
+	// have when the query starts, so a reader only waits this long if the
+	// messages before a stop offset were deleted or compacted away.
+	fetchTimeout = 5 * time.Second
+)
+
+// Position is an offset or a time of the messages of the partitions of a topic.
+type Position struct {
+	// Offset is the offset of a message within each partition.
+	// It is only used if Time is zero.
+	Offset int64     `json:"offset,omitempty"`
+	Time   flux.Time `json:"time,omitempty"`
+}
+
+func (p *Position) isTime() bool {
+	return p != nil && !p.Time.IsZero()
+}
+
+type FromKafkaOpSpec struct {
+	Brokers []string  `json:"brokers"`
+	Topic   string    `json:"topic"`
+	Group   string    `json:"group,omitempty"`
+	Start   *Position `json:"start,omitempty"`
+	Stop    *Position `json:"stop,omitempty"`
+	Format  string    `json:"format"`
+}
+
+func init() {
+	fromKafkaSignature := runtime.MustLookupBuiltinType("kafka", "from")
+	runtime.RegisterPackageValue("kafka", "from", flux.MustValue(flux.FunctionValue(FromKafkaKind, createFromKafkaOpSpec, fromKafkaSignature)))
+	flux.RegisterOpSpec(FromKafkaKind, newFromKafkaOp)
+	plan.RegisterProcedureSpec(FromKafkaKind, newFromKafkaProcedure, FromKafkaKind)
+	execute.RegisterSource(FromKafkaKind, createFromKafkaSource)
+}
+
+// DefaultKafkaReaderFactory makes the readers of kafka.from and is injectable for testing
+var DefaultKafkaReaderFactory = func(conf kafka.ReaderConfig) KafkaReader {
+	return kafka.NewReader(conf)
+}
+
+// KafkaReader is an interface for what we need from DefaultKafkaReaderFactory
+type KafkaReader interface {
+	io.Closer
+	FetchMessage(context.Context) (kafka.Message, error)
+	CommitMessages(context.Context, ...kafka.Message) error
+	SetOffset(offset int64) error
+}
+
+// DefaultKafkaBrokerFactory makes the KafkaBroker that kafka.from
+// uses to look up the partitions of a topic and is injectable for testing
+var DefaultKafkaBrokerFactory = func(brokers []string) KafkaBroker {
+	return &kafkaBroker{brokers: brokers}
+}
+
+// KafkaBroker looks up the partitions of a topic and their offsets.
+type KafkaBroker interface {
+	// Partitions returns the ids of the partitions of the topic.
+	Partitions(ctx context.Context, topic string) ([]int, error)
+	// Offsets returns the offset of the first message of the partition
+	// and the offset that the next message of the partition will have.
+	Offsets(ctx context.Context, topic string, partition int) (first, last int64, err error)
+	// OffsetAt returns the offset of the first message of the partition at
+	// or after t. The offset is negative if there is no such message.
+	OffsetAt(ctx context.Context, topic string, partition int, t time.Time) (int64, error)
+	// Committed returns the offsets that the consumer group has committed
+	// for the partitions of the topic. Partitions without a committed
+	// offset are left out.
+	Committed(ctx context.Context, group, topic string, partitions []int) (map[int]int64, error)
+}
+
+func createFromKafkaOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromKafkaOpSpec)
+
+	brokers, err := args.GetRequiredArray("brokers", semantic.String)
+	if err != nil {
+		return nil, err
+	}
+	if brokers.Len() < 1 {
+		return nil, errors.New(codes.Invalid, "at least one broker is required")
+	}
+	spec.Brokers = make([]string, brokers.Len())
+	for i := range spec.Brokers {
+		spec.Brokers[i] = brokers.Get(i).Str()
+	}
+
+	if spec.Topic, err = args.GetRequiredString("topic"); err != nil {
+		return nil, err
+	}
+	if len(spec.Topic) == 0 {
+		return nil, errors.New(codes.Invalid, "invalid topic name")
+	}
+
+	if spec.Group, _, err = args.GetString("group"); err != nil {
+		return nil, err
+	}
+
+	if spec.Start, err = readPosition(args, "start"); err != nil {
+		return nil, err
+	}
+	if spec.Start != nil && spec.Group != "" {
+		return nil, errors.New(codes.Invalid, "start cannot be used with a consumer group, the group reads from its committed offsets")
+	}
+	if spec.Stop, err = readPosition(args, "stop"); err != nil {
+		return nil, err
+	}
+
+	format, ok, err := args.GetString("format")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		format = payload.LineProtocol
+	}
+	if err := payload.ValidateFormat(format); err != nil {
+		return nil, err
+	}
+	spec.Format = format
+
+	return spec, nil
+}
+
+// readPosition reads an argument that is either
+// an offset or a time of the messages of a topic.
+func readPosition(args flux.Arguments, name string) (*Position, error) {
+	v, ok := args.Get(name)
+	if !ok {
+		return nil, nil
+	}
+	switch v.Type().Nature() {
+	case semantic.Int:
+		if v.Int() < 0 {
+			return nil, errors.Newf(codes.Invalid, "%s offset must not be negative", name)
+		}
+		return &Position{Offset: v.Int()}, nil
+	case semantic.Time, semantic.Duration:
+		t, err := flux.ToQueryTime(v)
+		if err != nil {
+			return nil, err
+		}
+		return &Position{Time: t}, nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "%s must be an offset, a time or a duration, got %v", name, v.Type())
+	}
+}
+
+func newFromKafkaOp() flux.OperationSpec {
+	return new(FromKafkaOpSpec)
+}
+
+func (s *FromKafkaOpSpec) Kind() flux.OperationKind {
+	return FromKafkaKind
+}
+
+type FromKafkaProcedureSpec struct {
+	plan.DefaultCost
+	Spec *FromKafkaOpSpec
+	// Now is the time that relative start and stop times are relative to.
+	Now time.Time
+}
+
+func newFromKafkaProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromKafkaOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromKafkaProcedureSpec{Spec: spec, Now: pa.Now()}, nil
+}
+
+func (s *FromKafkaProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKafkaKind
+}
+
+func (s *FromKafkaProcedureSpec) Copy() plan.ProcedureSpec {
+	copyPosition := func(p *Position) *Position {
+		if p == nil {
+			return nil
+		}
+		cp := *p
+		return &cp
+	}
+	return &FromKafkaProcedureSpec{
+		Spec: &FromKafkaOpSpec{
+			Brokers: append([]string(nil), s.Spec.Brokers...),
+			Topic:   s.Spec.Topic,
+			Group:   s.Spec.Group,
+			Start:   copyPosition(s.Spec.Start),
+			Stop:    copyPosition(s.Spec.Stop),
+			Format:  s.Spec.Format,
+		},
+		Now: s.Now,
+	}
+}
+
+func createFromKafkaSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*FromKafkaProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", prSpec)
+	}
+	return CreateSource(spec, dsid, a)
+}
+
+func CreateSource(spec *FromKafkaProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	deps := flux.GetDependencies(a.Context())
+	if err := validateBrokers(deps, spec.Spec.Brokers); err != nil {
+		return nil, err
+	}
+	iterator := &kafkaIterator{spec: spec, alloc: a.Allocator()}
+	return execute.CreateSourceFromIterator(iterator, dsid)
+}
+
+var _ execute.SourceIterator = (*kafkaIterator)(nil)
+
+// kafkaIterator reads the messages of a topic and decodes them into tables.
+// Without a consumer group, each partition is read from the start position
+// to the stop position or to its last message. A consumer group reads from
+// its committed offsets and commits the offsets of the messages it read.
+type kafkaIterator struct {
+	spec  *FromKafkaProcedureSpec
+	alloc *memory.Allocator
+}
+
+func (k *kafkaIterator) Do(ctx context.Context, f func(flux.Table) error) error {
+	dec, err := payload.NewDecoder(k.spec.Spec.Format, k.alloc)
+	if err != nil {
+		return err
+	}
+	if k.spec.Spec.Group != "" {
+		err = k.readGroup(ctx, dec)
+	} else {
+		err = k.readPartitions(ctx, dec)
+	}
+	if err != nil {
+		return err
+	}
+	return dec.Do(f)
+}
+
+func (k *kafkaIterator) readPartitions(ctx context.Context, dec payload.Decoder) error {
+	s := k.spec.Spec
+	broker := DefaultKafkaBrokerFactory(s.Brokers)
+	partitions, err := broker.Partitions(ctx, s.Topic)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		first, last, err := broker.Offsets(ctx, s.Topic, partition)
+		if err != nil {
+			return err
+		}
+		start := first
+		if s.Start.isTime() {
+			if start, err = broker.OffsetAt(ctx, s.Topic, partition, s.Start.Time.Time(k.spec.Now)); err != nil {
+				return err
+			}
+			if start < 0 {
+				start = last
+			}
+		} else if s.Start != nil && s.Start.Offset > start {
+			start = s.Start.Offset
+		}
+		stop := k.stopOffset(last)
+		if start >= stop {
+			continue
+		}
+		if err := k.readPartition(ctx, dec, partition, start, stop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPartition decodes the messages of the partition
+// from the start offset up to the stop offset.
+func (k *kafkaIterator) readPartition(ctx context.Context, dec payload.Decoder, partition int, start, stop int64) error {
+	s := k.spec.Spec
+	r := DefaultKafkaReaderFactory(kafka.ReaderConfig{
+		Brokers:   s.Brokers,
+		Topic:     s.Topic,
+		Partition: partition,
+	})
+	defer func() { _ = r.Close() }()
+
+	if err := r.SetOffset(start); err != nil {
+		return err
+	}
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		msg, err := r.FetchMessage(fetchCtx)
+		cancel()
+		if err == context.DeadlineExceeded && ctx.Err() == nil {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if msg.Offset >= stop || k.stopped(msg) {
+			return nil
+		}
+		if err := dec.Decode(payload.Message{Value: msg.Value, Time: msg.Time}); err != nil {
+			return err
+		}
+		if msg.Offset >= stop-1 {
+			return nil
+		}
+	}
+}
+
+func (k *kafkaIterator) readGroup(ctx context.Context, dec payload.Decoder) error {
+	s := k.spec.Spec
+	// The partitions are read up to the messages that they have now.
+	broker := DefaultKafkaBrokerFactory(s.Brokers)
+	partitions, err := broker.Partitions(ctx, s.Topic)
+	if err != nil {
+		return err
+	}
+	committed, err := broker.Committed(ctx, s.Group, s.Topic, partitions)
+	if err != nil {
+		return err
+	}
+	var (
+		stops = make(map[int]int64, len(partitions))
+		read  = make(map[int]kafka.Message, len(partitions))
+		done  = make(map[int]bool, len(partitions))
+	)
+	for _, partition := range partitions {
+		first, last, err := broker.Offsets(ctx, s.Topic, partition)
+		if err != nil {
+			return err
+		}
+		stop := k.stopOffset(last)
+		stops[partition] = stop
+		// The partitions that the group has read up to the stop
+		// offset would only be waited on for new messages.
+		if offset, ok := committed[partition]; stop <= first || ok && offset >= stop {
+			done[partition] = true
+		}
+	}
+	if len(done) == len(stops) {
+		return nil
+	}
+
+	r := DefaultKafkaReaderFactory(kafka.ReaderConfig{
+		Brokers: s.Brokers,
+		Topic:   s.Topic,
+		GroupID: s.Group,
+	})
+	defer func() { _ = r.Close() }()
+
+	for len(done) < len(stops) {
+		fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout)
+		msg, err := r.FetchMessage(fetchCtx)
+		cancel()
+		if err == context.DeadlineExceeded && ctx.Err() == nil {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		stop, ok := stops[msg.Partition]
+		if !ok || done[msg.Partition] {
+			continue
+		}
+		if msg.Offset >= stop || k.stopped(msg) {
+			done[msg.Partition] = true
+			continue
+		}
+		if err := dec.Decode(payload.Message{Value: msg.Value, Time: msg.Time}); err != nil {
+			return err
+		}
+		read[msg.Partition] = msg
+		if msg.Offset >= stop-1 {
+			done[msg.Partition] = true
+		}
+	}
+
+	// Commit the last message that was read from each partition.
+	if len(read) == 0 {
+		return nil
+	}
+	msgs := make([]kafka.Message, 0, len(read))
+	for _, partition := range partitions {
+		if msg, ok := read[partition]; ok {
+			msgs = append(msgs, msg)
+		}
+	}
+	return r.CommitMessages(ctx, msgs...)
+}
+
+// stopOffset returns the offset to stop reading a partition at
+// given the offset that the next message of the partition will have.
+func (k *kafkaIterator) stopOffset(last int64) int64 {
+	if stop := k.spec.Spec.Stop; stop != nil && !stop.isTime() && stop.Offset < last {
+		return stop.Offset
+	}
+	return last
+}
+
+// stopped reports whether the message is at or after the stop time.
+func (k *kafkaIterator) stopped(msg kafka.Message) bool {
+	if stop := k.spec.Spec.Stop; stop.isTime() {
+		return !msg.Time.Before(stop.Time.Time(k.spec.Now))
+	}
+	return false
+}
+
+// kafkaBroker looks up the partitions of a topic with the first broker that can be dialed.
+type kafkaBroker struct {
+	brokers []string
+}
+
+func (b *kafkaBroker) dial(ctx context.Context, dial func(broker string) (*kafka.Conn, error)) (*kafka.Conn, error) {
+	var err error
+	for _, broker := range b.brokers {
+		var conn *kafka.Conn
+		if conn, err = dial(broker); err != nil {
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+		return conn, nil
+	}
+	return nil, errors.Wrap(err, codes.Unavailable, "failed to connect to a kafka broker")
+}
+
+func (b *kafkaBroker) dialLeader(ctx context.Context, topic string, partition int) (*kafka.Conn, error) {
+	return b.dial(ctx, func(broker string) (*kafka.Conn, error) {
+		return kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+	})
+}
+
+func (b *kafkaBroker) Partitions(ctx context.Context, topic string) ([]int, error) {
+	conn, err := b.dial(ctx, func(broker string) (*kafka.Conn, error) {
+		return kafka.DialContext(ctx, "tcp", broker)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(partitions))
+	for i, p := range partitions {
+		ids[i] = p.ID
+	}
+	return ids, nil
+}
+
+func (b *kafkaBroker) Offsets(ctx context.Context, topic string, partition int) (int64, int64, error) {
+	conn, err := b.dialLeader(ctx, topic, partition)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.ReadOffsets()
+}
+
+func (b *kafkaBroker) OffsetAt(ctx context.Context, topic string, partition int, t time.Time) (int64, error) {
+	conn, err := b.dialLeader(ctx, topic, partition)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.ReadOffset(t)
+}
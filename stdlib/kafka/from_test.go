@@ -0,0 +1,336 @@
+package kafka_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/querytest"
+	fkafka "github.com/influxdata/flux/stdlib/kafka"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestFromKafka_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "offsets",
+			Raw:  `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"metrics", start: 10, stop: 20)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers: []string{"brokerurl:8989"},
+							Topic:   "metrics",
+							Start:   &fkafka.Position{Offset: 10},
+							Stop:    &fkafka.Position{Offset: 20},
+							Format:  "lineprotocol",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "group and time",
+			Raw:  `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"metrics", group:"tasks", stop: 2021-01-01T00:00:00Z, format: "json")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers: []string{"brokerurl:8989"},
+							Topic:   "metrics",
+							Group:   "tasks",
+							Stop:    &fkafka.Position{Time: flux.Time{Absolute: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}},
+							Format:  "json",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "start with group",
+			Raw:     `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"metrics", group:"tasks", start: 0)`,
+			WantErr: true,
+		},
+		{
+			Name:    "unknown format",
+			Raw:     `import "kafka" kafka.from(brokers:["brokerurl:8989"], topic:"metrics", format: "xml")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// fakeBroker is an in-process broker for a single topic. The offset
+// of each message is its index within the messages of its partition.
+type fakeBroker struct {
+	sync.Mutex
+	partitions [][]kafka.Message
+	committed  map[int]int64
+	readers    int
+}
+
+func newFakeBroker(partitions ...[]kafka.Message) *fakeBroker {
+	for p, msgs := range partitions {
+		for i := range msgs {
+			msgs[i].Partition = p
+			msgs[i].Offset = int64(i)
+		}
+	}
+	return &fakeBroker{partitions: partitions, committed: make(map[int]int64)}
+}
+
+func (b *fakeBroker) Partitions(context.Context, string) ([]int, error) {
+	ids := make([]int, len(b.partitions))
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids, nil
+}
+
+func (b *fakeBroker) Offsets(_ context.Context, _ string, partition int) (int64, int64, error) {
+	return 0, int64(len(b.partitions[partition])), nil
+}
+
+func (b *fakeBroker) OffsetAt(_ context.Context, _ string, partition int, t time.Time) (int64, error) {
+	for _, msg := range b.partitions[partition] {
+		if !msg.Time.Before(t) {
+			return msg.Offset, nil
+		}
+	}
+	return -1, nil
+}
+
+func (b *fakeBroker) Committed(context.Context, string, string, []int) (map[int]int64, error) {
+	b.Lock()
+	defer b.Unlock()
+	committed := make(map[int]int64, len(b.committed))
+	for p, offset := range b.committed {
+		committed[p] = offset
+	}
+	return committed, nil
+}
+
+func (b *fakeBroker) reader(conf kafka.ReaderConfig) fkafka.KafkaReader {
+	r := &fakeReader{b: b}
+	if conf.GroupID == "" {
+		r.pending = b.partitions[conf.Partition]
+		return r
+	}
+	b.Lock()
+	defer b.Unlock()
+	b.readers++
+	for p, msgs := range b.partitions {
+		r.pending = append(r.pending, msgs[b.committed[p]:]...)
+	}
+	return r
+}
+
+type fakeReader struct {
+	b       *fakeBroker
+	pending []kafka.Message
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func (r *fakeReader) SetOffset(offset int64) error {
+	r.pending = r.pending[offset:]
+	return nil
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.pending) == 0 {
+		// A reader waits for new messages until the context is done.
+		return kafka.Message{}, context.DeadlineExceeded
+	}
+	msg := r.pending[0]
+	r.pending = r.pending[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	r.b.Lock()
+	defer r.b.Unlock()
+	for _, msg := range msgs {
+		r.b.committed[msg.Partition] = msg.Offset + 1
+	}
+	return nil
+}
+
+func TestFromKafka_Run(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time {
+		return start.Add(d)
+	}
+	message := func(value string, d time.Duration) kafka.Message {
+		return kafka.Message{Value: []byte(value), Time: at(d)}
+	}
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_field", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "host", Type: flux.TString},
+	}
+	keyCols := []string{"_field", "_measurement", "host"}
+
+	testCases := []struct {
+		name string
+		spec *fkafka.FromKafkaOpSpec
+		want []*executetest.Table
+	}{
+		{
+			name: "all messages",
+			spec: &fkafka.FromKafkaOpSpec{Format: "lineprotocol"},
+			want: []*executetest.Table{
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{execute.Time(at(0).UnixNano()), 1.0, "usage", "cpu", "a"},
+						{execute.Time(at(time.Minute).UnixNano()), 2.0, "usage", "cpu", "a"},
+						{execute.Time(at(2 * time.Minute).UnixNano()), 3.0, "usage", "cpu", "a"},
+					},
+				},
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{execute.Time(at(30 * time.Second).UnixNano()), 4.0, "usage", "cpu", "b"},
+					},
+				},
+			},
+		},
+		{
+			name: "offsets",
+			spec: &fkafka.FromKafkaOpSpec{
+				Start:  &fkafka.Position{Offset: 1},
+				Stop:   &fkafka.Position{Offset: 2},
+				Format: "lineprotocol",
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{execute.Time(at(time.Minute).UnixNano()), 2.0, "usage", "cpu", "a"},
+					},
+				},
+			},
+		},
+		{
+			name: "times",
+			spec: &fkafka.FromKafkaOpSpec{
+				Start:  &fkafka.Position{Time: flux.Time{Absolute: at(30 * time.Second)}},
+				Stop:   &fkafka.Position{Time: flux.Time{Absolute: at(2 * time.Minute)}},
+				Format: "lineprotocol",
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{execute.Time(at(time.Minute).UnixNano()), 2.0, "usage", "cpu", "a"},
+					},
+				},
+				{
+					KeyCols: keyCols,
+					ColMeta: cols,
+					Data: [][]interface{}{
+						{execute.Time(at(30 * time.Second).UnixNano()), 4.0, "usage", "cpu", "b"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			broker := newFakeBroker(
+				[]kafka.Message{
+					message("cpu,host=a usage=1", 0),
+					message("cpu,host=a usage=2", time.Minute),
+					message("cpu,host=a usage=3", 2*time.Minute),
+				},
+				[]kafka.Message{
+					message("cpu,host=b usage=4", 30*time.Second),
+				},
+			)
+			fkafka.DefaultKafkaBrokerFactory = func([]string) fkafka.KafkaBroker { return broker }
+			fkafka.DefaultKafkaReaderFactory = broker.reader
+
+			spec := *tc.spec
+			spec.Brokers = []string{"brokerurl:8989"}
+			spec.Topic = "metrics"
+			runFromKafka(t, &spec, tc.want)
+		})
+	}
+}
+
+func TestFromKafka_Group(t *testing.T) {
+	broker := newFakeBroker([]kafka.Message{
+		{Value: []byte(`{"host": "a", "usage": 1}`), Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Value: []byte(`{"host": "b", "usage": 2}`), Time: time.Date(2021, 1, 1, 0, 1, 0, 0, time.UTC)},
+	})
+	fkafka.DefaultKafkaBrokerFactory = func([]string) fkafka.KafkaBroker { return broker }
+	fkafka.DefaultKafkaReaderFactory = broker.reader
+
+	spec := &fkafka.FromKafkaOpSpec{
+		Brokers: []string{"brokerurl:8989"},
+		Topic:   "metrics",
+		Group:   "tasks",
+		Format:  "json",
+	}
+	runFromKafka(t, spec, []*executetest.Table{{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "host", Type: flux.TString},
+			{Label: "usage", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()), "a", 1.0},
+			{execute.Time(time.Date(2021, 1, 1, 0, 1, 0, 0, time.UTC).UnixNano()), "b", 2.0},
+		},
+	}})
+
+	// The group has committed the offsets of the messages it read.
+	if got, want := broker.committed[0], int64(2); got != want {
+		t.Fatalf("unexpected committed offset: want %d, got %d", want, got)
+	}
+
+	// The group has read every message, so it does not wait for new ones.
+	runFromKafka(t, spec, nil)
+	if got, want := broker.readers, 1; got != want {
+		t.Fatalf("unexpected number of group readers: want %d, got %d", want, got)
+	}
+}
+
+func runFromKafka(t *testing.T, spec *fkafka.FromKafkaOpSpec, want []*executetest.Table) {
+	t.Helper()
+	executetest.RunSourceHelper(t,
+		want,
+		nil,
+		func(id execute.DatasetID) execute.Source {
+			ctx := dependenciestest.Default().Inject(context.Background())
+			a := mock.AdministrationWithContext(ctx)
+			s, err := fkafka.CreateSource(&fkafka.FromKafkaProcedureSpec{Spec: spec}, id, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+	)
+}
@@ -0,0 +1,222 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/segmentio/kafka-go"
+)
+
+// The kafka client reads the offsets that a consumer group has committed
+// when the group is assigned its partitions, but it does not expose them.
+// They are looked up here with the FindCoordinator (v0) and OffsetFetch (v1)
+// requests of the kafka protocol.
+
+const (
+	offsetFetchKey     = 9
+	findCoordinatorKey = 10
+
+	protocolClientID = "flux"
+)
+
+func (b *kafkaBroker) Committed(ctx context.Context, group, topic string, partitions []int) (map[int]int64, error) {
+	var (
+		conn *protocolConn
+		err  error
+	)
+	for _, broker := range b.brokers {
+		if conn, err = dialProtocol(ctx, broker); err == nil {
+			break
+		}
+	}
+	if conn == nil {
+		return nil, errors.Wrap(err, codes.Unavailable, "failed to connect to a kafka broker")
+	}
+	coordinator, err := conn.findCoordinator(group)
+	_ = conn.Close()
+	if err != nil {
+		if kerr, ok := err.(kafka.Error); ok && kerr.Temporary() {
+			// The coordinator of a new group may not be available yet.
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if conn, err = dialProtocol(ctx, coordinator); err != nil {
+		return nil, errors.Wrap(err, codes.Unavailable, "failed to connect to the group coordinator")
+	}
+	defer func() { _ = conn.Close() }()
+	return conn.offsetFetch(group, topic, partitions)
+}
+
+// protocolConn sends requests of the kafka protocol to a broker.
+type protocolConn struct {
+	net.Conn
+	correlationID int32
+}
+
+func dialProtocol(ctx context.Context, broker string) (*protocolConn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	return &protocolConn{Conn: conn}, nil
+}
+
+// findCoordinator returns the address of the coordinator of the group.
+func (c *protocolConn) findCoordinator(group string) (string, error) {
+	var req protocolWriter
+	req.string(group)
+	resp, err := c.roundTrip(findCoordinatorKey, 0, req.Bytes())
+	if err != nil {
+		return "", err
+	}
+	code := resp.int16()
+	_ = resp.int32() // node id
+	host := resp.string()
+	port := resp.int32()
+	if resp.err != nil {
+		return "", resp.err
+	}
+	if code != 0 {
+		return "", kafka.Error(code)
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+}
+
+// offsetFetch returns the offsets that the group has committed for
+// the partitions of the topic. Partitions without a committed offset
+// are left out.
+func (c *protocolConn) offsetFetch(group, topic string, partitions []int) (map[int]int64, error) {
+	var req protocolWriter
+	req.string(group)
+	req.int32(1)
+	req.string(topic)
+	req.int32(int32(len(partitions)))
+	for _, partition := range partitions {
+		req.int32(int32(partition))
+	}
+	resp, err := c.roundTrip(offsetFetchKey, 1, req.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for i, n := 0, resp.int32(); i < int(n) && resp.err == nil; i++ {
+		name := resp.string()
+		for j, m := 0, resp.int32(); j < int(m) && resp.err == nil; j++ {
+			partition := resp.int32()
+			offset := resp.int64()
+			_ = resp.string() // metadata
+			if code := resp.int16(); code != 0 && resp.err == nil {
+				return nil, kafka.Error(code)
+			}
+			if name == topic && offset >= 0 {
+				offsets[int(partition)] = offset
+			}
+		}
+	}
+	if resp.err != nil {
+		return nil, resp.err
+	}
+	return offsets, nil
+}
+
+// roundTrip sends a request and returns the body of its response.
+func (c *protocolConn) roundTrip(apiKey, apiVersion int16, body []byte) (*protocolReader, error) {
+	c.correlationID++
+	var req protocolWriter
+	req.int32(int32(2 + 2 + 4 + 2 + len(protocolClientID) + len(body)))
+	req.int16(apiKey)
+	req.int16(apiVersion)
+	req.int32(c.correlationID)
+	req.string(protocolClientID)
+	req.Write(body)
+	if _, err := c.Write(req.Bytes()); err != nil {
+		return nil, err
+	}
+
+	var size int32
+	if err := binary.Read(c, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(c, buf); err != nil {
+		return nil, err
+	}
+	resp := &protocolReader{r: bytes.NewReader(buf)}
+	if id := resp.int32(); resp.err == nil && id != c.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d in the response to request %d", id, c.correlationID)
+	}
+	return resp, resp.err
+}
+
+// protocolWriter encodes the fields of a request.
+type protocolWriter struct {
+	bytes.Buffer
+}
+
+func (w *protocolWriter) int16(v int16) {
+	_ = binary.Write(&w.Buffer, binary.BigEndian, v)
+}
+
+func (w *protocolWriter) int32(v int32) {
+	_ = binary.Write(&w.Buffer, binary.BigEndian, v)
+}
+
+func (w *protocolWriter) string(s string) {
+	w.int16(int16(len(s)))
+	w.WriteString(s)
+}
+
+// protocolReader decodes the fields of a response
+// and keeps the first error that it runs into.
+type protocolReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+func (r *protocolReader) read(v interface{}) {
+	if r.err == nil {
+		r.err = binary.Read(r.r, binary.BigEndian, v)
+	}
+}
+
+func (r *protocolReader) int16() (v int16) {
+	r.read(&v)
+	return v
+}
+
+func (r *protocolReader) int32() (v int32) {
+	r.read(&v)
+	return v
+}
+
+func (r *protocolReader) int64() (v int64) {
+	r.read(&v)
+	return v
+}
+
+// string reads a string and returns the empty string for a null one.
+func (r *protocolReader) string() string {
+	n := r.int16()
+	if n <= 0 || r.err != nil {
+		return ""
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.err = err
+	}
+	return string(b)
+}
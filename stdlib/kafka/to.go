@@ -229,24 +229,32 @@ func (t *ToKafkaTransformation) RetractTable(id execute.DatasetID, key flux.Grou
 	return t.d.RetractTable(key)
 }
 func NewToKafkaTransformation(d execute.Dataset, deps flux.Dependencies, cache execute.TableBuilderCache, spec *ToKafkaProcedureSpec) (*ToKafkaTransformation, error) {
+	if err := validateBrokers(deps, spec.Spec.Brokers); err != nil {
+		return nil, err
+	}
+	return &ToKafkaTransformation{
+		d:     d,
+		cache: cache,
+		spec:  spec,
+	}, nil
+}
+
+// validateBrokers checks the urls of the brokers with the url validator of the dependencies.
+func validateBrokers(deps flux.Dependencies, brokers []string) error {
 	validator, err := deps.URLValidator()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	for _, b := range spec.Spec.Brokers {
+	for _, b := range brokers {
 		u, err := url.Parse(b)
 		if err != nil {
-			return nil, errors.Newf(codes.Invalid, "invalid kafka broker url: %v", err)
+			return errors.Newf(codes.Invalid, "invalid kafka broker url: %v", err)
 		}
 		if err := validator.Validate(u); err != nil {
-			return nil, errors.Newf(codes.Invalid, "kafka broker url did not pass validation: %v", err)
+			return errors.Newf(codes.Invalid, "kafka broker url did not pass validation: %v", err)
 		}
 	}
-	return &ToKafkaTransformation{
-		d:     d,
-		cache: cache,
-		spec:  spec,
-	}, nil
+	return nil
 }
 
 type toKafkaMetric struct {
@@ -63,6 +63,7 @@ import (
 	_ "github.com/influxdata/flux/stdlib/kafka"
 	_ "github.com/influxdata/flux/stdlib/math"
 	_ "github.com/influxdata/flux/stdlib/pagerduty"
+	_ "github.com/influxdata/flux/stdlib/parquet"
 	_ "github.com/influxdata/flux/stdlib/planner"
 	_ "github.com/influxdata/flux/stdlib/profiler"
 	_ "github.com/influxdata/flux/stdlib/pushbullet"
@@ -76,5 +77,6 @@ import (
 	_ "github.com/influxdata/flux/stdlib/system"
 	_ "github.com/influxdata/flux/stdlib/testing"
 	_ "github.com/influxdata/flux/stdlib/testing/expect"
+	_ "github.com/influxdata/flux/stdlib/timezone"
 	_ "github.com/influxdata/flux/stdlib/universe"
 )
@@ -0,0 +1,498 @@
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/array"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/parquet"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+const (
+	pkgpath         = "parquet"
+	FromParquetKind = "fromParquet"
+)
+
+type FromParquetOpSpec struct {
+	File    string   `json:"file"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+func init() {
+	fromParquetSignature := runtime.MustLookupBuiltinType(pkgpath, "from")
+	runtime.RegisterPackageValue(pkgpath, "from", flux.MustValue(flux.FunctionValue(FromParquetKind, createFromParquetOpSpec, fromParquetSignature)))
+	flux.RegisterOpSpec(FromParquetKind, newFromParquetOp)
+	plan.RegisterProcedureSpec(FromParquetKind, newFromParquetProcedure, FromParquetKind)
+	plan.RegisterPhysicalRules(PushDownRangeRule{}, PushDownFilterRule{})
+	execute.RegisterSource(FromParquetKind, createFromParquetSource)
+}
+
+func createFromParquetOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(FromParquetOpSpec)
+
+	file, err := args.GetRequiredString("file")
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return nil, errors.New(codes.Invalid, "file must not be empty")
+	}
+	spec.File = file
+
+	if columns, ok, err := args.GetArray("columns", semantic.String); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Columns, err = interpreter.ToStringArray(columns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+func newFromParquetOp() flux.OperationSpec {
+	return new(FromParquetOpSpec)
+}
+
+func (s *FromParquetOpSpec) Kind() flux.OperationKind {
+	return FromParquetKind
+}
+
+type FromParquetProcedureSpec struct {
+	plan.DefaultCost
+	File    string
+	Columns []string
+
+	// Predicates are used to skip the row groups that cannot
+	// contain a matching row. They are pushed down by
+	// PushDownRangeRule and PushDownFilterRule.
+	Predicates []ColumnPredicate
+}
+
+func newFromParquetProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromParquetOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+
+	return &FromParquetProcedureSpec{
+		File:    spec.File,
+		Columns: spec.Columns,
+	}, nil
+}
+
+func (s *FromParquetProcedureSpec) Kind() plan.ProcedureKind {
+	return FromParquetKind
+}
+
+func (s *FromParquetProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(FromParquetProcedureSpec)
+	ns.File = s.File
+	if s.Columns != nil {
+		ns.Columns = make([]string, len(s.Columns))
+		copy(ns.Columns, s.Columns)
+	}
+	if s.Predicates != nil {
+		ns.Predicates = make([]ColumnPredicate, len(s.Predicates))
+		copy(ns.Predicates, s.Predicates)
+	}
+	return ns
+}
+
+func (s *FromParquetProcedureSpec) PlanDetails() string {
+	details := []string{fmt.Sprintf("file = %q", s.File)}
+	if len(s.Columns) > 0 {
+		details = append(details, fmt.Sprintf("columns = [%s]", strings.Join(s.Columns, ", ")))
+	}
+	if len(s.Predicates) > 0 {
+		preds := make([]string, len(s.Predicates))
+		for i, p := range s.Predicates {
+			preds[i] = p.String()
+		}
+		details = append(details, fmt.Sprintf("predicates = [%s]", strings.Join(preds, ", ")))
+	}
+	return strings.Join(details, ", ")
+}
+
+func createFromParquetSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*FromParquetProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", prSpec)
+	}
+	return CreateSource(spec, dsid, a)
+}
+
+func CreateSource(spec *FromParquetProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	return &ParquetSource{
+		id:    dsid,
+		spec:  spec,
+		ctx:   a.Context(),
+		alloc: a.Allocator(),
+	}, nil
+}
+
+type ParquetSource struct {
+	execute.ExecutionNode
+	id    execute.DatasetID
+	spec  *FromParquetProcedureSpec
+	ctx   context.Context
+	alloc *memory.Allocator
+	ts    []execute.Transformation
+}
+
+func (s *ParquetSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *ParquetSource) Run(ctx context.Context) {
+	err := s.run(ctx)
+	if err != nil {
+		err = errors.Wrap(err, codes.Inherit, "error in parquet.from()")
+	}
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+func (s *ParquetSource) run(ctx context.Context) error {
+	f, err := filesystem.OpenFile(s.ctx, s.spec.File)
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "failed to open file")
+	}
+	defer func() { _ = f.Close() }()
+
+	pf, err := openFile(f)
+	if err != nil {
+		return err
+	}
+
+	columns, cols, err := s.selectColumns(pf)
+	if err != nil {
+		return err
+	}
+	rowGroups, err := s.selectRowGroups(pf)
+	if err != nil {
+		return err
+	}
+
+	// Each transformation reads the file separately so that
+	// a table instance goes to one and only one transformation.
+	for _, t := range s.ts {
+		tbl := &rowGroupTable{
+			ctx:       ctx,
+			file:      pf,
+			key:       execute.NewGroupKey(nil, nil),
+			columns:   columns,
+			cols:      cols,
+			rowGroups: rowGroups,
+			alloc:     s.alloc,
+		}
+		if err := t.Process(s.id, tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openFile opens the parquet file with random access if the
+// file supports it or reads it into memory if it does not.
+func openFile(f filesystem.File) (*parquet.File, error) {
+	if ra, ok := f.(io.ReaderAt); ok {
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return parquet.Open(ra, fi.Size())
+	}
+	return parquet.ReadFile(f)
+}
+
+// selectColumns returns the columns that are read from the file
+// along with the corresponding flux columns.
+func (s *ParquetSource) selectColumns(pf *parquet.File) ([]*parquet.Column, []flux.ColMeta, error) {
+	var columns []*parquet.Column
+	if s.spec.Columns == nil {
+		for i := range pf.Columns {
+			columns = append(columns, &pf.Columns[i])
+		}
+	} else {
+		for _, label := range s.spec.Columns {
+			col, ok := pf.Column(label)
+			if !ok {
+				return nil, nil, errors.Newf(codes.Invalid, "column %q does not exist in the file", label)
+			}
+			columns = append(columns, col)
+		}
+	}
+
+	cols := make([]flux.ColMeta, len(columns))
+	for j, col := range columns {
+		typ, err := columnType(col)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols[j] = flux.ColMeta{Label: col.Name, Type: typ}
+	}
+	return columns, cols, nil
+}
+
+// selectRowGroups returns the indices of the row groups that
+// may contain rows that match the predicates.
+func (s *ParquetSource) selectRowGroups(pf *parquet.File) ([]int, error) {
+	var rowGroups []int
+	for i := range pf.Metadata.RowGroups {
+		skip, err := skipRowGroup(pf, i, s.spec.Predicates)
+		if err != nil {
+			return nil, err
+		}
+		if !skip {
+			rowGroups = append(rowGroups, i)
+		}
+	}
+	return rowGroups, nil
+}
+
+// columnType returns the flux type of the values of a column.
+func columnType(col *parquet.Column) (flux.ColType, error) {
+	switch col.Type {
+	case parquet.Boolean:
+		return flux.TBool, nil
+	case parquet.Int32, parquet.Int64:
+		if _, ok := timeUnit(col); ok {
+			return flux.TTime, nil
+		}
+		if col.IsUnsigned() {
+			return flux.TUInt, nil
+		}
+		return flux.TInt, nil
+	case parquet.Int96:
+		return flux.TTime, nil
+	case parquet.Float, parquet.Double:
+		return flux.TFloat, nil
+	case parquet.ByteArray:
+		return flux.TString, nil
+	default:
+		return flux.TInvalid, errors.Newf(codes.Unimplemented, "column %q has unsupported type %v", col.Name, col.Type)
+	}
+}
+
+// timeUnit returns the number of nanoseconds in each unit of
+// a timestamp column. It reports false if the column is not a timestamp.
+func timeUnit(col *parquet.Column) (int64, bool) {
+	if col.Type == parquet.Int96 {
+		return 1, true
+	}
+	if col.Type != parquet.Int64 {
+		return 0, false
+	}
+	if lt := col.LogicalType; lt != nil && lt.Timestamp {
+		switch lt.TimestampUnit {
+		case parquet.Millis:
+			return 1e6, true
+		case parquet.Micros:
+			return 1e3, true
+		case parquet.Nanos:
+			return 1, true
+		}
+	}
+	switch col.ConvertedType {
+	case parquet.ConvertedTimestampMillis:
+		return 1e6, true
+	case parquet.ConvertedTimestampMicros:
+		return 1e3, true
+	}
+	return 0, false
+}
+
+// unsignedValue converts the value of an unsigned integer column.
+// The values of 32 bit columns are sign extended when they are decoded.
+func unsignedValue(col *parquet.Column, v int64) uint64 {
+	if col.Type == parquet.Int32 {
+		return uint64(uint32(v))
+	}
+	return uint64(v)
+}
+
+// rowGroupTable is a table that reads and decodes
+// one row group of the file for each buffer.
+type rowGroupTable struct {
+	ctx       context.Context
+	used      int32
+	file      *parquet.File
+	key       flux.GroupKey
+	columns   []*parquet.Column
+	cols      []flux.ColMeta
+	rowGroups []int
+	alloc     *memory.Allocator
+}
+
+func (t *rowGroupTable) Key() flux.GroupKey {
+	return t.key
+}
+
+func (t *rowGroupTable) Cols() []flux.ColMeta {
+	return t.cols
+}
+
+func (t *rowGroupTable) Do(f func(flux.ColReader) error) error {
+	if !atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		return errors.New(codes.Internal, "table already read")
+	}
+	for _, i := range t.rowGroups {
+		if t.file.Metadata.RowGroups[i].NumRows == 0 {
+			continue
+		}
+		if err := t.ctx.Err(); err != nil {
+			return err
+		}
+		buf, err := t.readRowGroup(i)
+		if err != nil {
+			return err
+		}
+		err = f(buf)
+		buf.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *rowGroupTable) readRowGroup(i int) (*arrow.TableBuffer, error) {
+	buf := &arrow.TableBuffer{
+		GroupKey: t.key,
+		Columns:  t.cols,
+		Values:   make([]array.Interface, 0, len(t.cols)),
+	}
+	numRows := int(t.file.Metadata.RowGroups[i].NumRows)
+	for j, col := range t.columns {
+		vs, err := t.file.ReadColumn(i, col)
+		if err != nil {
+			buf.Release()
+			return nil, err
+		}
+		arr, err := t.newArray(col, t.cols[j].Type, vs, numRows)
+		if err != nil {
+			buf.Release()
+			return nil, err
+		}
+		buf.Values = append(buf.Values, arr)
+	}
+	return buf, nil
+}
+
+// newArray builds an array from the decoded values of a column.
+// The values only include the rows where the column is defined.
+func (t *rowGroupTable) newArray(col *parquet.Column, typ flux.ColType, vs *parquet.ColumnValues, numRows int) (array.Interface, error) {
+	defined := vs.Defined
+	if defined == nil {
+		defined = make([]bool, vs.Len())
+		for i := range defined {
+			defined[i] = true
+		}
+	}
+	if len(defined) != numRows {
+		return nil, errors.Newf(codes.Invalid, "column %q has %d values in a row group with %d rows", col.Name, len(defined), numRows)
+	}
+
+	switch typ {
+	case flux.TBool:
+		b := arrow.NewBoolBuilder(t.alloc)
+		b.Resize(numRows)
+		j := 0
+		for _, ok := range defined {
+			if !ok {
+				b.AppendNull()
+				continue
+			}
+			b.Append(vs.Bools[j])
+			j++
+		}
+		return b.NewArray(), nil
+	case flux.TInt, flux.TTime:
+		var unit int64 = 1
+		if typ == flux.TTime {
+			unit, _ = timeUnit(col)
+		}
+		b := arrow.NewIntBuilder(t.alloc)
+		b.Resize(numRows)
+		j := 0
+		for _, ok := range defined {
+			if !ok {
+				b.AppendNull()
+				continue
+			}
+			b.Append(vs.Ints[j] * unit)
+			j++
+		}
+		return b.NewArray(), nil
+	case flux.TUInt:
+		b := arrow.NewUintBuilder(t.alloc)
+		b.Resize(numRows)
+		j := 0
+		for _, ok := range defined {
+			if !ok {
+				b.AppendNull()
+				continue
+			}
+			b.Append(unsignedValue(col, vs.Ints[j]))
+			j++
+		}
+		return b.NewArray(), nil
+	case flux.TFloat:
+		b := arrow.NewFloatBuilder(t.alloc)
+		b.Resize(numRows)
+		j := 0
+		for _, ok := range defined {
+			if !ok {
+				b.AppendNull()
+				continue
+			}
+			b.Append(vs.Floats[j])
+			j++
+		}
+		return b.NewArray(), nil
+	case flux.TString:
+		b := arrow.NewStringBuilder(t.alloc)
+		b.Resize(numRows)
+		j := 0
+		for _, ok := range defined {
+			if !ok {
+				b.AppendNull()
+				continue
+			}
+			b.Append(vs.Strings[j])
+			j++
+		}
+		return b.NewArray(), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "unexpected column type %v", typ)
+	}
+}
+
+func (t *rowGroupTable) Done() {
+	atomic.StoreInt32(&t.used, 1)
+}
+
+func (t *rowGroupTable) Empty() bool {
+	for _, i := range t.rowGroups {
+		if t.file.Metadata.RowGroups[i].NumRows > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,297 @@
+package parquet_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/parquet"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/querytest"
+	fparquet "github.com/influxdata/flux/stdlib/parquet"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+func mustParseTime(s string) values.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return values.ConvertTime(t)
+}
+
+// writeTestFile writes a parquet file with two row groups that
+// hold the first and second hour of the data respectively.
+func writeTestFile(t *testing.T) string {
+	t.Helper()
+
+	fpath := filepath.Join(t.TempDir(), "data.parquet")
+	f, err := os.Create(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w, err := parquet.NewWriter(f, []parquet.SchemaElement{
+		{
+			Name:          "_time",
+			Type:          parquet.Int64,
+			Repetition:    parquet.Required,
+			ConvertedType: parquet.ConvertedTimestampMillis,
+		},
+		{
+			Name:          "host",
+			Type:          parquet.ByteArray,
+			Repetition:    parquet.Required,
+			ConvertedType: parquet.ConvertedUTF8,
+		},
+		{
+			Name:          "_value",
+			Type:          parquet.Double,
+			Repetition:    parquet.Optional,
+			ConvertedType: parquet.ConvertedNone,
+		},
+		{
+			Name:          "count",
+			Type:          parquet.Int32,
+			Repetition:    parquet.Optional,
+			ConvertedType: parquet.ConvertedUint32,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rg := range [][]*parquet.ColumnValues{
+		{
+			{Ints: []int64{int64(mustParseTime("2021-01-01T00:00:00Z")) / 1e6, int64(mustParseTime("2021-01-01T00:30:00Z")) / 1e6}},
+			{Strings: []string{"a", "b"}},
+			{Defined: []bool{true, false}, Floats: []float64{1.5}},
+			{Defined: []bool{true, true}, Ints: []int64{1, 2}},
+		},
+		{
+			{Ints: []int64{int64(mustParseTime("2021-01-01T01:00:00Z")) / 1e6, int64(mustParseTime("2021-01-01T01:30:00Z")) / 1e6}},
+			{Strings: []string{"c", "d"}},
+			{Defined: []bool{true, true}, Floats: []float64{2.5, 3.5}},
+			{Defined: []bool{false, true}, Ints: []int64{4}},
+		},
+	} {
+		if err := w.WriteRowGroup(rg, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return fpath
+}
+
+func TestFromParquet_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name:    "from no args",
+			Raw:     `import "parquet" parquet.from()`,
+			WantErr: true,
+		},
+		{
+			Name:    "from empty file",
+			Raw:     `import "parquet" parquet.from(file: "")`,
+			WantErr: true,
+		},
+		{
+			Name: "from with columns",
+			Raw:  `import "parquet" parquet.from(file: "/data.parquet", columns: ["_time", "_value"])`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromParquet0",
+						Spec: &fparquet.FromParquetOpSpec{
+							File:    "/data.parquet",
+							Columns: []string{"_time", "_value"},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+func TestFromParquet_Run(t *testing.T) {
+	fpath := writeTestFile(t)
+	allCols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "host", Type: flux.TString},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "count", Type: flux.TUInt},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		spec    *fparquet.FromParquetProcedureSpec
+		want    []*executetest.Table
+		wantErr error
+	}{
+		{
+			name: "all columns",
+			spec: &fparquet.FromParquetProcedureSpec{File: fpath},
+			want: []*executetest.Table{{
+				ColMeta: allCols,
+				Data: [][]interface{}{
+					{mustParseTime("2021-01-01T00:00:00Z"), "a", 1.5, uint64(1)},
+					{mustParseTime("2021-01-01T00:30:00Z"), "b", nil, uint64(2)},
+					{mustParseTime("2021-01-01T01:00:00Z"), "c", 2.5, nil},
+					{mustParseTime("2021-01-01T01:30:00Z"), "d", 3.5, uint64(4)},
+				},
+			}},
+		},
+		{
+			name: "selected columns",
+			spec: &fparquet.FromParquetProcedureSpec{
+				File:    fpath,
+				Columns: []string{"_value", "host"},
+			},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_value", Type: flux.TFloat},
+					{Label: "host", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{1.5, "a"},
+					{nil, "b"},
+					{2.5, "c"},
+					{3.5, "d"},
+				},
+			}},
+		},
+		{
+			name: "skip row groups by time",
+			spec: &fparquet.FromParquetProcedureSpec{
+				File: fpath,
+				Predicates: []fparquet.ColumnPredicate{
+					{Column: "_time", Op: ast.GreaterThanEqualOperator, Value: values.NewTime(mustParseTime("2021-01-01T01:15:00Z"))},
+				},
+			},
+			want: []*executetest.Table{{
+				ColMeta: allCols,
+				Data: [][]interface{}{
+					{mustParseTime("2021-01-01T01:00:00Z"), "c", 2.5, nil},
+					{mustParseTime("2021-01-01T01:30:00Z"), "d", 3.5, uint64(4)},
+				},
+			}},
+		},
+		{
+			name: "skip row groups by value",
+			spec: &fparquet.FromParquetProcedureSpec{
+				File: fpath,
+				Predicates: []fparquet.ColumnPredicate{
+					{Column: "host", Op: ast.EqualOperator, Value: values.NewString("b")},
+				},
+			},
+			want: []*executetest.Table{{
+				ColMeta: allCols,
+				Data: [][]interface{}{
+					{mustParseTime("2021-01-01T00:00:00Z"), "a", 1.5, uint64(1)},
+					{mustParseTime("2021-01-01T00:30:00Z"), "b", nil, uint64(2)},
+				},
+			}},
+		},
+		{
+			name: "skip all row groups",
+			spec: &fparquet.FromParquetProcedureSpec{
+				File: fpath,
+				Predicates: []fparquet.ColumnPredicate{
+					{Column: "_value", Op: ast.GreaterThanOperator, Value: values.NewFloat(3.5)},
+				},
+			},
+			want: []*executetest.Table{{
+				ColMeta: allCols,
+			}},
+		},
+		{
+			name: "missing column",
+			spec: &fparquet.FromParquetProcedureSpec{
+				File:    fpath,
+				Columns: []string{"_measurement"},
+			},
+			wantErr: errors.New(codes.Invalid, `error in parquet.from(): column "_measurement" does not exist in the file`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			executetest.RunSourceHelper(t,
+				tc.want,
+				tc.wantErr,
+				func(id execute.DatasetID) execute.Source {
+					ctx := dependenciestest.Default().Inject(context.Background())
+					a := mock.AdministrationWithContext(ctx)
+					s, err := fparquet.CreateSource(tc.spec, id, a)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return s
+				},
+			)
+		})
+	}
+}
+
+func TestFromParquet_RangeFilter(t *testing.T) {
+	fpath := writeTestFile(t)
+	spec := &fparquet.FromParquetProcedureSpec{File: fpath}
+	rangeSpec := &universe.RangeProcedureSpec{
+		Bounds: flux.Bounds{
+			Start: flux.Time{Absolute: mustParseTime("2021-01-01T00:15:00Z").Time()},
+			Stop:  flux.Time{Absolute: mustParseTime("2021-01-01T00:45:00Z").Time()},
+		},
+		TimeColumn: "_time",
+	}
+	bounds := rangeSpec.TimeBounds(nil)
+	spec.Predicates = []fparquet.ColumnPredicate{
+		{Column: "_time", Op: ast.GreaterThanEqualOperator, Value: values.NewTime(bounds.Start)},
+		{Column: "_time", Op: ast.LessThanOperator, Value: values.NewTime(bounds.Stop)},
+	}
+
+	// The predicates only skip row groups so the rows of the
+	// first row group outside of the range are still returned.
+	want := []*executetest.Table{{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "host", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+			{Label: "count", Type: flux.TUInt},
+		},
+		Data: [][]interface{}{
+			{mustParseTime("2021-01-01T00:00:00Z"), "a", 1.5, uint64(1)},
+			{mustParseTime("2021-01-01T00:30:00Z"), "b", nil, uint64(2)},
+		},
+	}}
+	executetest.RunSourceHelper(t,
+		want,
+		nil,
+		func(id execute.DatasetID) execute.Source {
+			ctx := dependenciestest.Default().Inject(context.Background())
+			a := mock.AdministrationWithContext(ctx)
+			s, err := fparquet.CreateSource(spec, id, a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		},
+	)
+}
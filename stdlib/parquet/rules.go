@@ -0,0 +1,251 @@
+package parquet
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/internal/parquet"
+	"github.com/influxdata/flux/internal/pushdown"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+// ColumnPredicate compares the values of a column with a constant.
+// A row group is not read if the statistics of the column
+// show that none of its values can satisfy the predicate.
+type ColumnPredicate = pushdown.ColumnPredicate
+
+// PushDownRangeRule adds the bounds of a range to the predicates of
+// the parquet source it reads from. The range is not removed since
+// the predicates only select the row groups that are read.
+type PushDownRangeRule struct{}
+
+func (PushDownRangeRule) Name() string {
+	return "parquet.PushDownRangeRule"
+}
+
+func (PushDownRangeRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.RangeKind, plan.Any())
+}
+
+func (PushDownRangeRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := findSource(node)
+	if fromNode == nil {
+		return node, false, nil
+	}
+
+	spec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
+	changed, err := addPredicates(fromNode, pushdown.RangePredicates(spec))
+	return node, changed, err
+}
+
+// PushDownFilterRule adds the comparisons of a filter to the predicates
+// of the parquet source it reads from. Only comparisons between a column
+// and a literal that are joined with and are pushed down. The filter is
+// not removed since the predicates only select the row groups that are read.
+type PushDownFilterRule struct{}
+
+func (PushDownFilterRule) Name() string {
+	return "parquet.PushDownFilterRule"
+}
+
+func (PushDownFilterRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.FilterKind, plan.Any())
+}
+
+func (PushDownFilterRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := findSource(node)
+	if fromNode == nil {
+		return node, false, nil
+	}
+
+	preds, _ := pushdown.FilterPredicates(node.ProcedureSpec().(*universe.FilterProcedureSpec))
+	if len(preds) == 0 {
+		return node, false, nil
+	}
+	changed, err := addPredicates(fromNode, preds)
+	return node, changed, err
+}
+
+// findSource returns the parquet source at the start of
+// the chain of range and filter nodes that precede the node.
+func findSource(node plan.Node) plan.Node {
+	fromNode, _ := pushdown.FindSource(node, FromParquetKind, universe.RangeKind, universe.FilterKind)
+	return fromNode
+}
+
+// addPredicates adds the predicates that the source does not already have.
+func addPredicates(fromNode plan.Node, preds []ColumnPredicate) (bool, error) {
+	spec := fromNode.ProcedureSpec().(*FromParquetProcedureSpec)
+	added := pushdown.NewPredicates(spec.Predicates, preds)
+	if len(added) == 0 {
+		return false, nil
+	}
+
+	newSpec := spec.Copy().(*FromParquetProcedureSpec)
+	newSpec.Predicates = append(newSpec.Predicates, added...)
+	if err := fromNode.ReplaceSpec(newSpec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// skipRowGroup reports whether the statistics of a row group show
+// that none of its rows can satisfy all of the predicates.
+func skipRowGroup(pf *parquet.File, i int, preds []ColumnPredicate) (bool, error) {
+	rg := &pf.Metadata.RowGroups[i]
+	for _, p := range preds {
+		col, ok := pf.Column(p.Column)
+		if !ok {
+			continue
+		}
+		md := rg.Columns[col.Index].MetaData
+		if md == nil || md.Statistics == nil {
+			continue
+		}
+		stats := md.Statistics
+		if stats.HasNulls && rg.NumRows > 0 && stats.NullCount == rg.NumRows {
+			// A comparison with a null value is never true.
+			return true, nil
+		}
+
+		min, max, err := statisticValues(col, stats)
+		if err != nil {
+			return false, err
+		}
+		if min == nil || max == nil {
+			continue
+		}
+		if skipPredicate(p, min, max) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// statisticValues returns the minimum and maximum values of a column
+// chunk. It returns nil values if the column has no usable statistics.
+func statisticValues(col *parquet.Column, stats *parquet.Statistics) (min, max values.Value, err error) {
+	minBytes, maxBytes := stats.MinValue, stats.MaxValue
+	if minBytes == nil || maxBytes == nil {
+		// The deprecated min and max fields were written using signed
+		// comparisons so they can only be used for signed values.
+		switch col.Type {
+		case parquet.Int32, parquet.Int64, parquet.Float, parquet.Double:
+			if col.IsUnsigned() {
+				return nil, nil, nil
+			}
+			minBytes, maxBytes = stats.Min, stats.Max
+		default:
+			return nil, nil, nil
+		}
+	}
+	if minBytes == nil || maxBytes == nil || col.Type == parquet.Int96 {
+		return nil, nil, nil
+	}
+
+	typ, err := columnType(col)
+	if err != nil {
+		return nil, nil, err
+	}
+	if min, err = statisticValue(col, typ, minBytes); err != nil {
+		return nil, nil, err
+	}
+	if max, err = statisticValue(col, typ, maxBytes); err != nil {
+		return nil, nil, err
+	}
+	return min, max, nil
+}
+
+func statisticValue(col *parquet.Column, typ flux.ColType, b []byte) (values.Value, error) {
+	vs, err := parquet.DecodeStatistic(col, b)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case flux.TBool:
+		return values.NewBool(vs.Bools[0]), nil
+	case flux.TInt:
+		return values.NewInt(vs.Ints[0]), nil
+	case flux.TUInt:
+		return values.NewUInt(unsignedValue(col, vs.Ints[0])), nil
+	case flux.TFloat:
+		if math.IsNaN(vs.Floats[0]) {
+			return nil, nil
+		}
+		return values.NewFloat(vs.Floats[0]), nil
+	case flux.TString:
+		return values.NewString(vs.Strings[0]), nil
+	case flux.TTime:
+		unit, _ := timeUnit(col)
+		return values.NewTime(values.Time(vs.Ints[0] * unit)), nil
+	default:
+		return nil, nil
+	}
+}
+
+// skipPredicate reports whether no value between min and max satisfies the predicate.
+func skipPredicate(p ColumnPredicate, min, max values.Value) bool {
+	lo, ok := compare(p.Value, min)
+	if !ok {
+		return false
+	}
+	hi, _ := compare(p.Value, max)
+	switch p.Op {
+	case ast.EqualOperator:
+		return lo < 0 || hi > 0
+	case ast.NotEqualOperator:
+		return lo == 0 && hi == 0
+	case ast.LessThanOperator:
+		return lo <= 0
+	case ast.LessThanEqualOperator:
+		return lo < 0
+	case ast.GreaterThanOperator:
+		return hi >= 0
+	case ast.GreaterThanEqualOperator:
+		return hi > 0
+	default:
+		return false
+	}
+}
+
+// compare compares two values of the same type.
+// It reports false if the values cannot be compared.
+func compare(x, y values.Value) (int, bool) {
+	if x.Type().Nature() != y.Type().Nature() {
+		return 0, false
+	}
+	switch x.Type().Nature() {
+	case semantic.Int:
+		return compareOrdered(x.Int() < y.Int(), x.Int() > y.Int()), true
+	case semantic.UInt:
+		return compareOrdered(x.UInt() < y.UInt(), x.UInt() > y.UInt()), true
+	case semantic.Float:
+		return compareOrdered(x.Float() < y.Float(), x.Float() > y.Float()), true
+	case semantic.String:
+		return strings.Compare(x.Str(), y.Str()), true
+	case semantic.Time:
+		return compareOrdered(x.Time() < y.Time(), x.Time() > y.Time()), true
+	case semantic.Bool:
+		a, b := x.Bool(), y.Bool()
+		return compareOrdered(!a && b, a && !b), true
+	default:
+		return 0, false
+	}
+}
+
+func compareOrdered(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,142 @@
+package parquet_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	fparquet "github.com/influxdata/flux/stdlib/parquet"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+func TestPushDownRules(t *testing.T) {
+	fromSpec := &fparquet.FromParquetProcedureSpec{File: "/data.parquet"}
+	rangeSpec := &universe.RangeProcedureSpec{
+		Bounds: flux.Bounds{
+			Start: flux.Time{Absolute: mustParseTime("2021-01-01T00:00:00Z").Time()},
+			Stop:  flux.Time{Absolute: mustParseTime("2021-01-02T00:00:00Z").Time()},
+		},
+		TimeColumn:  "_time",
+		StartColumn: "_start",
+		StopColumn:  "_stop",
+	}
+	rangePredicates := []fparquet.ColumnPredicate{
+		{Column: "_time", Op: ast.GreaterThanEqualOperator, Value: values.NewTime(mustParseTime("2021-01-01T00:00:00Z"))},
+		{Column: "_time", Op: ast.LessThanOperator, Value: values.NewTime(mustParseTime("2021-01-02T00:00:00Z"))},
+	}
+	filterSpec := func(fn string) *universe.FilterProcedureSpec {
+		return &universe.FilterProcedureSpec{
+			Fn: interpreter.ResolvedFunction{
+				Fn:    executetest.FunctionExpression(t, fn),
+				Scope: values.NewScope(),
+			},
+		}
+	}
+	withPredicates := func(preds ...fparquet.ColumnPredicate) *fparquet.FromParquetProcedureSpec {
+		spec := fromSpec.Copy().(*fparquet.FromParquetProcedureSpec)
+		spec.Predicates = preds
+		return spec
+	}
+	rules := []plan.Rule{
+		fparquet.PushDownRangeRule{},
+		fparquet.PushDownFilterRule{},
+	}
+
+	tcs := []plantest.RuleTestCase{
+		{
+			Name:  "range",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", withPredicates(rangePredicates...)),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name:  "range and filter",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host == "a" and 10 < r.count and r._value <= -1.5`)),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					// The planner visits the filter before the range.
+					plan.CreatePhysicalNode("from", withPredicates(
+						fparquet.ColumnPredicate{Column: "host", Op: ast.EqualOperator, Value: values.NewString("a")},
+						fparquet.ColumnPredicate{Column: "count", Op: ast.GreaterThanOperator, Value: values.NewInt(10)},
+						fparquet.ColumnPredicate{Column: "_value", Op: ast.LessThanEqualOperator, Value: values.NewFloat(-1.5)},
+						rangePredicates[0],
+						rangePredicates[1],
+					)),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host == "a" and 10 < r.count and r._value <= -1.5`)),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			Name:  "filter with disjunction",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host == "a" or r.host == "b"`)),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "filter of another record",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host == r.region`)),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "shared source",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{{0, 1}, {0, 2}},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
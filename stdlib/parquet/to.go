@@ -0,0 +1,447 @@
+package parquet
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/parquet"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	ToParquetKind = "toParquet"
+
+	// RowGroupSize is the maximum number of rows in a row group.
+	RowGroupSize = 65536
+
+	// defaultPartition is the directory name used for a null partition value.
+	// It is the name used by Hive and the tools that read Hive partitioned data.
+	defaultPartition = "__HIVE_DEFAULT_PARTITION__"
+)
+
+type ToParquetOpSpec struct {
+	File        string   `json:"file"`
+	PartitionBy []string `json:"partitionBy,omitempty"`
+}
+
+func init() {
+	toParquetSignature := runtime.MustLookupBuiltinType(pkgpath, "to")
+	runtime.RegisterPackageValue(pkgpath, "to", flux.MustValue(flux.FunctionValueWithSideEffect(ToParquetKind, createToParquetOpSpec, toParquetSignature)))
+	flux.RegisterOpSpec(ToParquetKind, func() flux.OperationSpec { return &ToParquetOpSpec{} })
+	plan.RegisterProcedureSpecWithSideEffect(ToParquetKind, newToParquetProcedure, ToParquetKind)
+	execute.RegisterTransformation(ToParquetKind, createToParquetTransformation)
+}
+
+func createToParquetOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+	spec := new(ToParquetOpSpec)
+
+	file, err := args.GetRequiredString("file")
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return nil, errors.New(codes.Invalid, "file must not be empty")
+	}
+	spec.File = file
+
+	if partitionBy, ok, err := args.GetArray("partitionBy", semantic.String); err != nil {
+		return nil, err
+	} else if ok {
+		spec.PartitionBy, err = interpreter.ToStringArray(partitionBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+func (ToParquetOpSpec) Kind() flux.OperationKind {
+	return ToParquetKind
+}
+
+type ToParquetProcedureSpec struct {
+	plan.DefaultCost
+	File        string
+	PartitionBy []string
+}
+
+func newToParquetProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToParquetOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &ToParquetProcedureSpec{
+		File:        spec.File,
+		PartitionBy: spec.PartitionBy,
+	}, nil
+}
+
+func (s *ToParquetProcedureSpec) Kind() plan.ProcedureKind {
+	return ToParquetKind
+}
+
+func (s *ToParquetProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := &ToParquetProcedureSpec{File: s.File}
+	if s.PartitionBy != nil {
+		ns.PartitionBy = make([]string, len(s.PartitionBy))
+		copy(ns.PartitionBy, s.PartitionBy)
+	}
+	return ns
+}
+
+func createToParquetTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToParquetProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	t, d := NewToParquetTransformation(a.Context(), id, s)
+	return t, d, nil
+}
+
+type ToParquetTransformation struct {
+	execute.ExecutionNode
+	ctx     context.Context
+	d       *execute.PassthroughDataset
+	spec    *ToParquetProcedureSpec
+	writers map[string]*fileWriter
+}
+
+// NewToParquetTransformation creates a transformation that writes its
+// tables to parquet files and passes them through unchanged.
+func NewToParquetTransformation(ctx context.Context, id execute.DatasetID, spec *ToParquetProcedureSpec) (*ToParquetTransformation, *execute.PassthroughDataset) {
+	d := execute.NewPassthroughDataset(id)
+	t := &ToParquetTransformation{
+		ctx:     ctx,
+		d:       d,
+		spec:    spec,
+		writers: make(map[string]*fileWriter),
+	}
+	return t, d
+}
+
+func (t *ToParquetTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *ToParquetTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	buf, err := table.Copy(tbl)
+	if err != nil {
+		return err
+	}
+	if err := t.writeTable(buf); err != nil {
+		buf.Done()
+		return errors.Wrap(err, codes.Inherit, "error in parquet.to()")
+	}
+	return t.d.Process(buf)
+}
+
+func (t *ToParquetTransformation) writeTable(tbl flux.BufferedTable) error {
+	fpath, err := t.partitionPath(tbl.Key())
+	if err != nil {
+		return err
+	}
+
+	w, ok := t.writers[fpath]
+	if !ok {
+		f, err := filesystem.CreateFile(t.ctx, fpath)
+		if err != nil {
+			return err
+		}
+		w, err = newFileWriter(f, t.fileColumns(tbl.Cols()))
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		t.writers[fpath] = w
+	}
+
+	for _, col := range tbl.Cols() {
+		if t.isPartitionColumn(col.Label) {
+			continue
+		}
+		if idx := execute.ColIdx(col.Label, w.cols); idx < 0 {
+			return errors.Newf(codes.Invalid, "column %q does not exist in the file", col.Label)
+		} else if w.cols[idx].Type != col.Type {
+			return errors.Newf(codes.Invalid, "column %q has type %v but the file has type %v", col.Label, col.Type, w.cols[idx].Type)
+		}
+	}
+
+	for i, n := 0, tbl.BufferN(); i < n; i++ {
+		if err := w.append(tbl.Buffer(i)); err != nil {
+			return err
+		}
+	}
+	// Each table is written as its own row groups so that
+	// the statistics of a row group describe a single table.
+	return w.flush()
+}
+
+// partitionPath returns the path of the file that a table is written to.
+func (t *ToParquetTransformation) partitionPath(key flux.GroupKey) (string, error) {
+	if len(t.spec.PartitionBy) == 0 {
+		return t.spec.File, nil
+	}
+	dir, base := filepath.Split(t.spec.File)
+	elems := []string{dir}
+	for _, label := range t.spec.PartitionBy {
+		idx := execute.ColIdx(label, key.Cols())
+		if idx < 0 {
+			return "", errors.Newf(codes.Invalid, "partition column %q is not part of the group key", label)
+		}
+		elems = append(elems, label+"="+partitionValue(key.Value(idx)))
+	}
+	elems = append(elems, base)
+	return filepath.Join(elems...), nil
+}
+
+// partitionValue returns the directory name for the value of a partition column.
+func partitionValue(v values.Value) string {
+	if v.IsNull() {
+		return defaultPartition
+	}
+	var s string
+	switch v.Type().Nature() {
+	case semantic.String:
+		s = v.Str()
+	case semantic.Int:
+		s = strconv.FormatInt(v.Int(), 10)
+	case semantic.UInt:
+		s = strconv.FormatUint(v.UInt(), 10)
+	case semantic.Float:
+		s = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case semantic.Bool:
+		s = strconv.FormatBool(v.Bool())
+	case semantic.Time:
+		s = v.Time().Time().UTC().Format(time.RFC3339Nano)
+	default:
+		s = values.DisplayString(v)
+	}
+	return url.PathEscape(s)
+}
+
+func (t *ToParquetTransformation) isPartitionColumn(label string) bool {
+	for _, l := range t.spec.PartitionBy {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// fileColumns returns the columns of a file, which are the
+// columns of the first table written to it without the
+// partition columns.
+func (t *ToParquetTransformation) fileColumns(cols []flux.ColMeta) []flux.ColMeta {
+	fileCols := make([]flux.ColMeta, 0, len(cols))
+	for _, col := range cols {
+		if !t.isPartitionColumn(col.Label) {
+			fileCols = append(fileCols, col)
+		}
+	}
+	return fileCols
+}
+
+func (t *ToParquetTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateWatermark(pt)
+}
+
+func (t *ToParquetTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *ToParquetTransformation) Finish(id execute.DatasetID, err error) {
+	paths := make([]string, 0, len(t.writers))
+	for fpath := range t.writers {
+		paths = append(paths, fpath)
+	}
+	sort.Strings(paths)
+
+	for _, fpath := range paths {
+		if cerr := t.writers[fpath].close(); cerr != nil && err == nil {
+			err = errors.Wrapf(cerr, codes.Inherit, "error in parquet.to(): failed to write %q", fpath)
+		}
+	}
+	t.writers = nil
+	t.d.Finish(err)
+}
+
+// fileWriter buffers the rows written to a parquet file
+// until there are enough of them to write a row group.
+type fileWriter struct {
+	f       io.WriteCloser
+	w       *parquet.Writer
+	cols    []flux.ColMeta
+	values  []*parquet.ColumnValues
+	numRows int
+}
+
+func newFileWriter(f io.WriteCloser, cols []flux.ColMeta) (*fileWriter, error) {
+	schema := make([]parquet.SchemaElement, len(cols))
+	for j, col := range cols {
+		el, err := schemaElement(col)
+		if err != nil {
+			return nil, err
+		}
+		schema[j] = el
+	}
+	w, err := parquet.NewWriter(f, schema)
+	if err != nil {
+		return nil, err
+	}
+	fw := &fileWriter{
+		f:    f,
+		w:    w,
+		cols: cols,
+	}
+	fw.reset()
+	return fw, nil
+}
+
+// schemaElement returns the parquet column used to store a flux column.
+func schemaElement(col flux.ColMeta) (parquet.SchemaElement, error) {
+	el := parquet.SchemaElement{
+		Name:          col.Label,
+		Repetition:    parquet.Optional,
+		ConvertedType: parquet.ConvertedNone,
+	}
+	switch col.Type {
+	case flux.TBool:
+		el.Type = parquet.Boolean
+	case flux.TInt:
+		el.Type = parquet.Int64
+	case flux.TUInt:
+		el.Type = parquet.Int64
+		el.ConvertedType = parquet.ConvertedUint64
+		el.LogicalType = &parquet.LogicalType{Integer: true, IntegerBits: 64}
+	case flux.TFloat:
+		el.Type = parquet.Double
+	case flux.TString:
+		el.Type = parquet.ByteArray
+		el.ConvertedType = parquet.ConvertedUTF8
+		el.LogicalType = &parquet.LogicalType{String: true}
+	case flux.TTime:
+		el.Type = parquet.Int64
+		el.LogicalType = &parquet.LogicalType{Timestamp: true, TimestampUnit: parquet.Nanos, AdjustedToUTC: true}
+	default:
+		return el, errors.Newf(codes.Invalid, "column %q has unsupported type %v", col.Label, col.Type)
+	}
+	return el, nil
+}
+
+func (w *fileWriter) reset() {
+	w.values = make([]*parquet.ColumnValues, len(w.cols))
+	for j := range w.values {
+		w.values[j] = &parquet.ColumnValues{Defined: []bool{}}
+	}
+	w.numRows = 0
+}
+
+// append adds the rows of the column reader to the buffered rows.
+// The columns of the file that the reader does not have are null.
+func (w *fileWriter) append(cr flux.ColReader) error {
+	for start, n := 0, cr.Len(); start < n; {
+		stop := start + RowGroupSize - w.numRows
+		if stop > n {
+			stop = n
+		}
+		for j, col := range w.cols {
+			w.appendColumn(w.values[j], col.Type, cr, execute.ColIdx(col.Label, cr.Cols()), start, stop)
+		}
+		w.numRows += stop - start
+		start = stop
+
+		if w.numRows >= RowGroupSize {
+			if err := w.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *fileWriter) appendColumn(vs *parquet.ColumnValues, typ flux.ColType, cr flux.ColReader, j, start, stop int) {
+	if j < 0 {
+		for i := start; i < stop; i++ {
+			vs.Defined = append(vs.Defined, false)
+		}
+		return
+	}
+	for i := start; i < stop; i++ {
+		var valid bool
+		switch typ {
+		case flux.TBool:
+			arr := cr.Bools(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Bools = append(vs.Bools, arr.Value(i))
+			}
+		case flux.TInt:
+			arr := cr.Ints(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Ints = append(vs.Ints, arr.Value(i))
+			}
+		case flux.TUInt:
+			arr := cr.UInts(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Ints = append(vs.Ints, int64(arr.Value(i)))
+			}
+		case flux.TFloat:
+			arr := cr.Floats(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Floats = append(vs.Floats, arr.Value(i))
+			}
+		case flux.TString:
+			arr := cr.Strings(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Strings = append(vs.Strings, arr.Value(i))
+			}
+		case flux.TTime:
+			arr := cr.Times(j)
+			if valid = arr.IsValid(i); valid {
+				vs.Ints = append(vs.Ints, arr.Value(i))
+			}
+		}
+		vs.Defined = append(vs.Defined, valid)
+	}
+}
+
+// flush writes the buffered rows as a row group.
+func (w *fileWriter) flush() error {
+	if w.numRows == 0 {
+		return nil
+	}
+	if err := w.w.WriteRowGroup(w.values, w.numRows); err != nil {
+		return err
+	}
+	w.reset()
+	return nil
+}
+
+// close flushes the buffered rows, writes the footer and closes the file.
+func (w *fileWriter) close() error {
+	err := w.flush()
+	if err == nil {
+		err = w.w.Close()
+	}
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
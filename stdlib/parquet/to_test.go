@@ -0,0 +1,344 @@
+package parquet_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/parquet"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/csv"
+	fparquet "github.com/influxdata/flux/stdlib/parquet"
+)
+
+func TestToParquet_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name:    "to no file",
+			Raw:     `import "csv" import "parquet" csv.from(csv: "") |> parquet.to()`,
+			WantErr: true,
+		},
+		{
+			Name: "to with partitions",
+			Raw:  `import "csv" import "parquet" csv.from(csv: "") |> parquet.to(file: "/out/data.parquet", partitionBy: ["host"])`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromCSV0",
+						Spec: &csv.FromCSVOpSpec{
+							Mode: "annotations",
+						},
+					},
+					{
+						ID: "toParquet1",
+						Spec: &fparquet.ToParquetOpSpec{
+							File:        "/out/data.parquet",
+							PartitionBy: []string{"host"},
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "fromCSV0", Child: "toParquet1"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// readTestFile reads every column of every row group of a parquet file.
+func readTestFile(t *testing.T, fpath string) (*parquet.File, [][]*parquet.ColumnValues) {
+	t.Helper()
+
+	f, err := os.Open(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	pf, err := parquet.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowGroups := make([][]*parquet.ColumnValues, len(pf.Metadata.RowGroups))
+	for i := range rowGroups {
+		for j := range pf.Columns {
+			vs, err := pf.ReadColumn(i, &pf.Columns[j])
+			if err != nil {
+				t.Fatal(err)
+			}
+			rowGroups[i] = append(rowGroups[i], vs)
+		}
+	}
+	return pf, rowGroups
+}
+
+func TestToParquet_Process(t *testing.T) {
+	dir := t.TempDir()
+	data := []*executetest.Table{
+		{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "ok", Type: flux.TBool},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "a/b", 1.0, true},
+				{execute.Time(2), "a/b", nil, false},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(3), "c", 3.0},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(4), nil, 4.0},
+			},
+		},
+	}
+
+	input := make([]flux.Table, len(data))
+	for i, tbl := range data {
+		input[i] = tbl
+	}
+	executetest.ProcessTestHelper2(
+		t,
+		input,
+		data,
+		nil,
+		func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+			ctx := dependenciestest.Default().Inject(context.Background())
+			return fparquet.NewToParquetTransformation(ctx, id, &fparquet.ToParquetProcedureSpec{
+				File:        filepath.Join(dir, "data.parquet"),
+				PartitionBy: []string{"host"},
+			})
+		},
+	)
+
+	for _, tc := range []struct {
+		partition string
+		columns   []string
+		want      [][]*parquet.ColumnValues
+	}{
+		{
+			partition: "host=a%2Fb",
+			columns:   []string{"_time", "_value", "ok"},
+			want: [][]*parquet.ColumnValues{{
+				{Defined: []bool{true, true}, Ints: []int64{1, 2}},
+				{Defined: []bool{true, false}, Floats: []float64{1}},
+				{Defined: []bool{true, true}, Bools: []bool{true, false}},
+			}},
+		},
+		{
+			partition: "host=c",
+			columns:   []string{"_time", "_value"},
+			want: [][]*parquet.ColumnValues{{
+				{Defined: []bool{true}, Ints: []int64{3}},
+				{Defined: []bool{true}, Floats: []float64{3}},
+			}},
+		},
+		{
+			partition: "host=__HIVE_DEFAULT_PARTITION__",
+			columns:   []string{"_time", "_value"},
+			want: [][]*parquet.ColumnValues{{
+				{Defined: []bool{true}, Ints: []int64{4}},
+				{Defined: []bool{true}, Floats: []float64{4}},
+			}},
+		},
+	} {
+		pf, got := readTestFile(t, filepath.Join(dir, tc.partition, "data.parquet"))
+		var columns []string
+		for _, col := range pf.Columns {
+			columns = append(columns, col.Name)
+		}
+		if !cmp.Equal(tc.columns, columns) {
+			t.Errorf("unexpected columns in %s -want/+got:\n%s", tc.partition, cmp.Diff(tc.columns, columns))
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected values in %s -want/+got:\n%s", tc.partition, cmp.Diff(tc.want, got))
+		}
+	}
+}
+
+func TestToParquet_MissingColumns(t *testing.T) {
+	dir := t.TempDir()
+	data := []*executetest.Table{
+		{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TInt},
+				{Label: "n", Type: flux.TUInt},
+			},
+			Data: [][]interface{}{
+				{"a", int64(1), uint64(1)},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TInt},
+			},
+			Data: [][]interface{}{
+				{"b", int64(2)},
+			},
+		},
+	}
+
+	input := make([]flux.Table, len(data))
+	for i, tbl := range data {
+		input[i] = tbl
+	}
+	fpath := filepath.Join(dir, "data.parquet")
+	executetest.ProcessTestHelper2(
+		t,
+		input,
+		data,
+		nil,
+		func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+			ctx := dependenciestest.Default().Inject(context.Background())
+			return fparquet.NewToParquetTransformation(ctx, id, &fparquet.ToParquetProcedureSpec{File: fpath})
+		},
+	)
+
+	pf, got := readTestFile(t, fpath)
+	if col := pf.Columns[2]; !col.IsUnsigned() {
+		t.Errorf("expected column %q to be unsigned", col.Name)
+	}
+	want := [][]*parquet.ColumnValues{
+		{
+			{Defined: []bool{true}, Strings: []string{"a"}},
+			{Defined: []bool{true}, Ints: []int64{1}},
+			{Defined: []bool{true}, Ints: []int64{1}},
+		},
+		{
+			{Defined: []bool{true}, Strings: []string{"b"}},
+			{Defined: []bool{true}, Ints: []int64{2}},
+			{Defined: []bool{false}},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected values -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestToParquet_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		spec    *fparquet.ToParquetProcedureSpec
+		data    []*executetest.Table
+		wantErr error
+	}{
+		{
+			name: "partition column not in group key",
+			spec: &fparquet.ToParquetProcedureSpec{PartitionBy: []string{"host"}},
+			data: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "host", Type: flux.TString},
+				},
+				Data: [][]interface{}{{"a"}},
+			}},
+			wantErr: errors.New(codes.Invalid, `error in parquet.to(): partition column "host" is not part of the group key`),
+		},
+		{
+			name: "extra column",
+			spec: &fparquet.ToParquetProcedureSpec{},
+			data: []*executetest.Table{
+				{
+					KeyCols: []string{"t"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t", Type: flux.TString},
+					},
+					Data: [][]interface{}{{"a"}},
+				},
+				{
+					KeyCols: []string{"t"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{{"b", 1.0}},
+				},
+			},
+			wantErr: errors.New(codes.Invalid, `error in parquet.to(): column "_value" does not exist in the file`),
+		},
+		{
+			name: "type mismatch",
+			spec: &fparquet.ToParquetProcedureSpec{},
+			data: []*executetest.Table{
+				{
+					KeyCols: []string{"t"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{{"a", 1.0}},
+				},
+				{
+					KeyCols: []string{"t"},
+					ColMeta: []flux.ColMeta{
+						{Label: "t", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{{"b", int64(1)}},
+				},
+			},
+			wantErr: errors.New(codes.Invalid, `error in parquet.to(): column "_value" has type int but the file has type float`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.spec.File = filepath.Join(t.TempDir(), "data.parquet")
+
+			input := make([]flux.Table, len(tc.data))
+			for i, tbl := range tc.data {
+				input[i] = tbl
+			}
+			ctx := dependenciestest.Default().Inject(context.Background())
+			tr, _ := fparquet.NewToParquetTransformation(ctx, executetest.RandomDatasetID(), tc.spec)
+			var gotErr error
+			for _, tbl := range input {
+				if gotErr = tr.Process(executetest.RandomDatasetID(), tbl); gotErr != nil {
+					break
+				}
+			}
+			if gotErr == nil {
+				t.Fatal("expected an error")
+			}
+			if got, want := gotErr.Error(), tc.wantErr.Error(); got != want {
+				t.Errorf("unexpected error -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 // Package socket implements a source that gets input from a socket connection and produces tables given a decoder.
-// This is a good candidate for streaming use cases. For now, it produces a single table for everything
-// that it receives from the start to the end of the connection.
+// By default, it produces a single table for everything that it receives from the start to the end of the connection.
+// In stream mode, it produces a table for each chunk of rows as they are received and advances the watermark and the
+// processing time of the transformations downstream, so windows are triggered while the connection stays open.
+// A query that reads a stream runs until the connection is closed or the query is cancelled.
 package socket
 
 import (
@@ -9,6 +11,7 @@ import (
 	"net"
 	neturl "net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/flux"
@@ -25,8 +28,11 @@ import (
 const FromSocketKind = "fromSocket"
 
 type FromSocketOpSpec struct {
-	URL     string `json:"url"`
-	Decoder string `json:"decoder"`
+	URL           string        `json:"url"`
+	Decoder       string        `json:"decoder"`
+	Stream        bool          `json:"stream,omitempty"`
+	ChunkSize     int64         `json:"chunkSize,omitempty"`
+	FlushInterval time.Duration `json:"flushInterval,omitempty"`
 }
 
 func init() {
@@ -50,6 +56,10 @@ var (
 	schemes  = []string{"tcp", "unix"}
 )
 
+// defaultFlushInterval is how often a stream outputs the
+// rows that it has received when no interval is given.
+const defaultFlushInterval = time.Second
+
 func contains(ss []string, s string) bool {
 	for _, st := range ss {
 		if st == s {
@@ -80,9 +90,50 @@ func createFromSocketOpSpec(args flux.Arguments, a *flux.Administration) (flux.O
 		return nil, errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", spec.Decoder, decoders)
 	}
 
+	if stream, ok, err := args.GetBool("stream"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Stream = stream
+	}
+
+	if n, ok, err := args.GetInt("chunkSize"); err != nil {
+		return nil, err
+	} else if ok {
+		if n <= 0 {
+			return nil, errors.Newf(codes.Invalid, "chunkSize must be positive, got %d", n)
+		}
+		spec.ChunkSize = n
+	}
+
+	if d, ok, err := args.GetDuration("flushInterval"); err != nil {
+		return nil, err
+	} else if ok {
+		if !d.IsPositive() || !d.NanoOnly() {
+			return nil, errors.Newf(codes.Invalid, "flushInterval must be a positive duration without months, got %v", d)
+		}
+		spec.FlushInterval = d.Duration()
+	}
+
+	if !spec.Stream && (spec.ChunkSize > 0 || spec.FlushInterval > 0) {
+		return nil, errors.New(codes.Invalid, "chunkSize and flushInterval require stream: true")
+	}
+	if err := validateStream(spec.Decoder, spec.Stream); err != nil {
+		return nil, err
+	}
+
 	return spec, nil
 }
 
+// validateStream checks that the decoder can decode a stream. The csv
+// decoder reads to the end of its input before it produces any tables,
+// so it would never produce output for a connection that stays open.
+func validateStream(decoder string, stream bool) error {
+	if stream && decoder != "line" {
+		return errors.Newf(codes.Invalid, "stream: true requires the line decoder, got %s", decoder)
+	}
+	return nil
+}
+
 func newFromSocketOp() flux.OperationSpec {
 	return new(FromSocketOpSpec)
 }
@@ -93,8 +144,11 @@ func (s *FromSocketOpSpec) Kind() flux.OperationKind {
 
 type FromSocketProcedureSpec struct {
 	plan.DefaultCost
-	URL     string
-	Decoder string
+	URL           string
+	Decoder       string
+	Stream        bool
+	ChunkSize     int64
+	FlushInterval time.Duration
 }
 
 func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -104,8 +158,11 @@ func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan
 	}
 
 	return &FromSocketProcedureSpec{
-		URL:     spec.URL,
-		Decoder: spec.Decoder,
+		URL:           spec.URL,
+		Decoder:       spec.Decoder,
+		Stream:        spec.Stream,
+		ChunkSize:     spec.ChunkSize,
+		FlushInterval: spec.FlushInterval,
 	}, nil
 }
 
@@ -117,6 +174,9 @@ func (s *FromSocketProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromSocketProcedureSpec)
 	ns.URL = s.URL
 	ns.Decoder = s.Decoder
+	ns.Stream = s.Stream
+	ns.ChunkSize = s.ChunkSize
+	ns.FlushInterval = s.FlushInterval
 	return ns
 }
 
@@ -159,15 +219,28 @@ func createFromSocketSource(s plan.ProcedureSpec, dsid execute.DatasetID, a exec
 }
 
 func NewSocketSource(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.TimeProvider, dsid execute.DatasetID) (execute.Source, error) {
+	if err := validateStream(spec.Decoder, spec.Stream); err != nil {
+		return nil, err
+	}
+	flushInterval := spec.FlushInterval
+	if spec.Stream && flushInterval == 0 {
+		flushInterval = defaultFlushInterval
+	}
+
 	var decoder flux.ResultDecoder
 	switch spec.Decoder {
 	case "csv":
 		decoder = csv.NewResultDecoder(csv.ResultDecoderConfig{})
 	case "line":
-		decoder = line.NewResultDecoder(&line.ResultDecoderConfig{
+		config := &line.ResultDecoderConfig{
 			Separator:    '\n',
 			TimeProvider: tp,
-		})
+		}
+		if spec.Stream {
+			config.ChunkSize = int(spec.ChunkSize)
+			config.FlushInterval = flushInterval
+		}
+		decoder = line.NewResultDecoder(config)
 	}
 
 	if decoder == nil {
@@ -175,9 +248,12 @@ func NewSocketSource(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.Ti
 	}
 
 	return &socketSource{
-		d:       dsid,
-		rc:      rc,
-		decoder: decoder,
+		d:             dsid,
+		rc:            rc,
+		decoder:       decoder,
+		stream:        spec.Stream,
+		flushInterval: flushInterval,
+		watermark:     execute.MinTime,
 	}, nil
 }
 
@@ -186,7 +262,16 @@ type socketSource struct {
 	d       execute.DatasetID
 	rc      io.ReadCloser
 	decoder flux.ResultDecoder
-	ts      []execute.Transformation
+	ts      execute.TransformationSet
+
+	stream        bool
+	flushInterval time.Duration
+
+	// mu serializes the calls to the transformations
+	// between the decoder and the processing time ticker.
+	mu        sync.Mutex
+	watermark execute.Time
+	err       error
 }
 
 func (ss *socketSource) AddTransformation(t execute.Transformation) {
@@ -195,21 +280,120 @@ func (ss *socketSource) AddTransformation(t execute.Transformation) {
 
 func (ss *socketSource) Run(ctx context.Context) {
 	defer ss.rc.Close()
+	stopWatch := func() {}
+	if ss.stream {
+		done, stopped := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(stopped)
+			ss.watch(ctx, done)
+		}()
+		stopWatch = func() {
+			close(done)
+			<-stopped
+		}
+	}
+
 	result, err := ss.decoder.Decode(ss.rc)
 	if err != nil {
 		err = errors.Wrap(err, codes.Inherit, "decode error")
 	} else {
-		err = result.Tables().Do(func(tbl flux.Table) error {
-			for _, t := range ss.ts {
-				if err := t.Process(ss.d, tbl); err != nil {
-					return err
-				}
+		err = result.Tables().Do(ss.process)
+	}
+
+	// The watcher must be stopped before the transformations are
+	// finished so that it does not update their processing time after.
+	stopWatch()
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.err != nil {
+		// The connection was closed because a transformation
+		// failed to update its processing time.
+		err = ss.err
+	} else if ctx.Err() != nil {
+		// The connection was closed because the query was cancelled.
+		err = ctx.Err()
+	}
+	ss.ts.Finish(ss.d, err)
+}
+
+// watch updates the processing time of the transformations at each flush
+// interval until the stream is done. When the query is cancelled, it closes
+// the connection so that the decoder stops waiting for input.
+func (ss *socketSource) watch(ctx context.Context, done <-chan struct{}) {
+	ticker := time.NewTicker(ss.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ss.mu.Lock()
+			err := ss.ts.UpdateProcessingTime(ss.d, execute.Now())
+			if err != nil {
+				ss.err = err
 			}
-			return nil
-		})
+			ss.mu.Unlock()
+			if err != nil {
+				_ = ss.rc.Close()
+				return
+			}
+		case <-ctx.Done():
+			_ = ss.rc.Close()
+			return
+		case <-done:
+			return
+		}
 	}
+}
 
-	for _, t := range ss.ts {
-		t.Finish(ss.d, err)
+// process sends the table to the transformations. In stream mode,
+// it then advances the watermark to the latest time in the table.
+func (ss *socketSource) process(tbl flux.Table) error {
+	if !ss.stream {
+		return ss.ts.Process(ss.d, tbl)
 	}
+
+	buf, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer buf.Done()
+	mark, ok, err := latestTime(buf.Copy())
+	if err != nil {
+		return err
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.ts.Process(ss.d, buf.Copy()); err != nil {
+		return err
+	}
+	if ok && mark > ss.watermark {
+		ss.watermark = mark
+		return ss.ts.UpdateWatermark(ss.d, mark)
+	}
+	return nil
+}
+
+// latestTime returns the latest valid time in
+// the time column of the table, if there is one.
+func latestTime(tbl flux.Table) (execute.Time, bool, error) {
+	var (
+		latest execute.Time
+		found  bool
+	)
+	err := tbl.Do(func(cr flux.ColReader) error {
+		idx := execute.ColIdx(execute.DefaultTimeColLabel, cr.Cols())
+		if idx < 0 || cr.Cols()[idx].Type != flux.TTime {
+			return nil
+		}
+		times := cr.Times(idx)
+		for i := 0; i < times.Len(); i++ {
+			if times.IsValid(i) {
+				if t := execute.Time(times.Value(i)); !found || t > latest {
+					latest, found = t, true
+				}
+			}
+		}
+		return nil
+	})
+	return latest, found, err
 }
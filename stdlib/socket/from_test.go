@@ -3,6 +3,7 @@ package socket_test
 import (
 	"bytes"
 	"context"
+	"io"
 	"io/ioutil"
 	"testing"
 	"time"
@@ -77,6 +78,43 @@ socket.from(url: "url", decoder: "line") |> range(start:-4h, stop:-2h) |> sum()`
 				},
 			},
 		},
+		{
+			Name: "from stream",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "line", stream: true, chunkSize: 10, flushInterval: 100ms)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromSocket0",
+						Spec: &socket.FromSocketOpSpec{
+							URL:           "url",
+							Decoder:       "line",
+							Stream:        true,
+							ChunkSize:     10,
+							FlushInterval: 100 * time.Millisecond,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "from chunk size without stream",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "line", chunkSize: 10)`,
+			WantErr: true,
+		},
+		{
+			Name: "from stream with csv decoder",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "csv", stream: true)`,
+			WantErr: true,
+		},
+		{
+			Name: "from negative flush interval",
+			Raw: `import "socket"
+socket.from(url: "url", decoder: "line", stream: true, flushInterval: -1s)`,
+			WantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -243,3 +281,104 @@ source
 		})
 	}
 }
+
+func TestFromSocketSource_Stream(t *testing.T) {
+	id := executetest.RandomDatasetID()
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	spec := &socket.FromSocketProcedureSpec{
+		Decoder:   "line",
+		Stream:    true,
+		ChunkSize: 2,
+		// The rows are output by the chunk size before the flush interval.
+		FlushInterval: time.Hour,
+	}
+	r := ioutil.NopCloser(bytes.NewReader([]byte("a\nb\nc\nd\n")))
+	ss, err := socket.NewSocketSource(spec, r, &mock.AscendingTimeProvider{}, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.AddTransformation(executetest.NewYieldTransformation(d, c))
+	ss.Run(context.Background())
+
+	if !d.Finished || d.FinishedErr != nil {
+		t.Fatalf("expected the dataset to finish without an error, got finished=%v err=%v", d.Finished, d.FinishedErr)
+	}
+
+	// The watermark advances to the latest time of each chunk.
+	if want := []execute.Time{1, 3}; !cmp.Equal(want, d.WatermarkUpdates) {
+		t.Errorf("unexpected watermark updates -want/+got\n%s", cmp.Diff(want, d.WatermarkUpdates))
+	}
+
+	got, err := executetest.TablesFromCache(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*executetest.Table{{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_value", Type: flux.TString},
+		},
+		Data: [][]interface{}{
+			{execute.Time(0), "a"},
+			{execute.Time(1), "b"},
+			{execute.Time(2), "c"},
+			{execute.Time(3), "d"},
+		},
+	}}
+	executetest.NormalizeTables(got)
+	executetest.NormalizeTables(want)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestFromSocketSource_StreamCancel(t *testing.T) {
+	id := executetest.RandomDatasetID()
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	spec := &socket.FromSocketProcedureSpec{
+		Decoder:       "line",
+		Stream:        true,
+		FlushInterval: 10 * time.Millisecond,
+	}
+
+	// The stream never ends so only cancelling the query stops the source.
+	pr, pw := io.Pipe()
+	defer func() { _ = pw.Close() }()
+	go func() {
+		_, _ = pw.Write([]byte("a\n"))
+	}()
+	ss, err := socket.NewSocketSource(spec, pr, &mock.AscendingTimeProvider{}, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss.AddTransformation(executetest.NewYieldTransformation(d, c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	ss.Run(ctx)
+
+	if !d.Finished || d.FinishedErr != context.DeadlineExceeded {
+		t.Fatalf("expected the dataset to finish with the context error, got finished=%v err=%v", d.Finished, d.FinishedErr)
+	}
+	if len(d.WatermarkUpdates) == 0 {
+		t.Error("expected the watermark to be updated")
+	}
+	if len(d.ProcessingTimeUpdates) == 0 {
+		t.Error("expected the processing time to be updated")
+	}
+}
+
+func TestFromSocketSource_StreamCSV(t *testing.T) {
+	spec := &socket.FromSocketProcedureSpec{
+		Decoder: "csv",
+		Stream:  true,
+	}
+	r := ioutil.NopCloser(bytes.NewReader(nil))
+	if _, err := socket.NewSocketSource(spec, r, &mock.AscendingTimeProvider{}, executetest.RandomDatasetID()); err == nil {
+		t.Fatal("expected an error for a stream with the csv decoder")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	_ "github.com/lib/pq"
 )
 
@@ -23,6 +25,9 @@ type FromSQLOpSpec struct {
 	DriverName     string `json:"driverName,omitempty"`
 	DataSourceName string `json:"dataSourceName,omitempty"`
 	Query          string `json:"query,omitempty"`
+	// Params is an array or a record with the
+	// values of the placeholders of the query.
+	Params values.Value `json:"params,omitempty"`
 }
 
 func init() {
@@ -30,6 +35,7 @@ func init() {
 	runtime.RegisterPackageValue("sql", "from", flux.MustValue(flux.FunctionValue(FromSQLKind, createFromSQLOpSpec, fromSQLSignature)))
 	flux.RegisterOpSpec(FromSQLKind, newFromSQLOp)
 	plan.RegisterProcedureSpec(FromSQLKind, newFromSQLProcedure, FromSQLKind)
+	plan.RegisterPhysicalRules(PushDownRangeRule{}, PushDownFilterRule{}, PushDownKeepRule{}, PushDownLimitRule{})
 	execute.RegisterSource(FromSQLKind, createFromSQLSource)
 }
 
@@ -51,6 +57,14 @@ func createFromSQLOpSpec(args flux.Arguments, administration *flux.Administratio
 	} else {
 		spec.Query = query
 	}
+	if params, ok := args.Get("params"); ok {
+		switch params.Type().Nature() {
+		case semantic.Array, semantic.Object:
+			spec.Params = params
+		default:
+			return nil, errors.Newf(codes.Invalid, "params must be an array or a record, got %v", params.Type())
+		}
+	}
 	return spec, nil
 }
 
@@ -67,6 +81,15 @@ type FromSQLProcedureSpec struct {
 	DriverName     string
 	DataSourceName string
 	Query          string
+	// Args are the arguments for the placeholders of the query.
+	Args []interface{}
+
+	// Predicates, Columns, Limit and Offset are pushed down
+	// into the query by the planner rules in rules.go.
+	Predicates []ColumnPredicate
+	Columns    []string
+	Limit      int64
+	Offset     int64
 }
 
 func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -75,10 +98,15 @@ func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pr
 		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
 	}
 
+	query, args, err := bindParams(spec.DriverName, spec.Query, spec.Params)
+	if err != nil {
+		return nil, err
+	}
 	return &FromSQLProcedureSpec{
 		DriverName:     spec.DriverName,
 		DataSourceName: spec.DataSourceName,
-		Query:          spec.Query,
+		Query:          query,
+		Args:           args,
 	}, nil
 }
 
@@ -91,6 +119,20 @@ func (s *FromSQLProcedureSpec) Copy() plan.ProcedureSpec {
 	ns.DriverName = s.DriverName
 	ns.DataSourceName = s.DataSourceName
 	ns.Query = s.Query
+	if len(s.Args) > 0 {
+		ns.Args = make([]interface{}, len(s.Args))
+		copy(ns.Args, s.Args)
+	}
+	if len(s.Predicates) > 0 {
+		ns.Predicates = make([]ColumnPredicate, len(s.Predicates))
+		copy(ns.Predicates, s.Predicates)
+	}
+	if s.Columns != nil {
+		ns.Columns = make([]string, len(s.Columns))
+		copy(ns.Columns, s.Columns)
+	}
+	ns.Limit = s.Limit
+	ns.Offset = s.Offset
 	return ns
 }
 
@@ -173,7 +215,11 @@ func (c *sqlIterator) Do(ctx context.Context, f func(flux.Table) error) error {
 	}
 	defer func() { _ = db.Close() }()
 
-	rows, err := db.QueryContext(ctx, c.spec.Query)
+	query, args, err := c.spec.query()
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,312 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// placeholderFunc returns the function that names the n-th
+// placeholder of a query for a driver. Placeholders are numbered from 1.
+func placeholderFunc(driverName string) func(n int) string {
+	switch {
+	case driverName == "postgres" || driverName == "sqlmock":
+		return func(n int) string { return fmt.Sprintf("$%d", n) }
+	case isMssqlDriver(driverName):
+		return func(n int) string { return fmt.Sprintf("@p%d", n) }
+	default:
+		return questionMark
+	}
+}
+
+// supportsParams reports whether the driver sends the arguments of a query
+// separately from the query. The awsathena and bigquery drivers instead
+// format the arguments into the text of the query.
+func supportsParams(driverName string) bool {
+	switch driverName {
+	case "awsathena", "bigquery":
+		return false
+	default:
+		return true
+	}
+}
+
+// isPostgresDriver reports whether the placeholders
+// of the driver are numbered like $1.
+func isPostgresDriver(driverName string) bool {
+	return driverName == "postgres" || driverName == "sqlmock"
+}
+
+// bindParams replaces the placeholders of the query with the placeholders
+// of the driver and returns the arguments for them. If params is an array,
+// each ? in the query is a placeholder for the next element of the array,
+// except for postgres where $1, $2 and so on are the placeholders so that
+// ? stays a jsonb operator. If params is a record, each :name in the query
+// is a placeholder for the property of the record with that name.
+// Placeholders within quotes and comments are left as they are.
+func bindParams(driverName, query string, params values.Value) (string, []interface{}, error) {
+	if params == nil {
+		return query, nil, nil
+	}
+	if !supportsParams(driverName) {
+		return "", nil, errors.Newf(codes.Invalid, "sql driver %s does not support query params", driverName)
+	}
+	placeholder := placeholderFunc(driverName)
+
+	var (
+		sb   strings.Builder
+		args []interface{}
+	)
+	switch params.Type().Nature() {
+	case semantic.Array:
+		arr := params.Array()
+		n := 0
+		replace := func(query string) (string, int, error) {
+			if query[0] != '?' {
+				return "", 0, nil
+			}
+			n++
+			return placeholder(n), 1, nil
+		}
+		if isPostgresDriver(driverName) {
+			// The placeholders are left as they are
+			// and n is the highest placeholder.
+			replace = func(query string) (string, int, error) {
+				i, size, ok := numberedPlaceholder(query)
+				if !ok {
+					return "", 0, nil
+				}
+				if i > n {
+					n = i
+				}
+				return query[:size], size, nil
+			}
+		}
+		if err := scanQuery(driverName, query, &sb, replace); err != nil {
+			return "", nil, err
+		}
+		if n != arr.Len() {
+			if isPostgresDriver(driverName) {
+				return "", nil, errors.Newf(codes.Invalid, "query has %d placeholders but %d params were given; the placeholders of postgres queries are $1, $2 and so on", n, arr.Len())
+			}
+			return "", nil, errors.Newf(codes.Invalid, "query has %d placeholders but %d params were given", n, arr.Len())
+		}
+		for i := 0; i < arr.Len(); i++ {
+			arg, err := paramArgument(arr.Get(i))
+			if err != nil {
+				return "", nil, errors.Wrapf(err, codes.Inherit, "param %d", i)
+			}
+			args = append(args, arg)
+		}
+	case semantic.Object:
+		obj := params.Object()
+		if err := scanQuery(driverName, query, &sb, func(query string) (string, int, error) {
+			name, ok := namedPlaceholder(query)
+			if !ok {
+				return "", 0, nil
+			}
+			v, ok := obj.Get(name)
+			if !ok {
+				return "", 0, errors.Newf(codes.Invalid, "query has placeholder :%s that is not in params", name)
+			}
+			arg, err := paramArgument(v)
+			if err != nil {
+				return "", 0, errors.Wrapf(err, codes.Inherit, "param %q", name)
+			}
+			args = append(args, arg)
+			return placeholder(len(args)), len(name) + 1, nil
+		}); err != nil {
+			return "", nil, err
+		}
+	default:
+		return "", nil, errors.Newf(codes.Invalid, "params must be an array or a record, got %v", params.Type())
+	}
+	return sb.String(), args, nil
+}
+
+// scanQuery writes the query to sb and calls replace at each position
+// that is not within quotes or a comment. If replace reports that it
+// consumed n > 0 bytes of the query, the replacement is written instead.
+//
+// A quote that is doubled within quotes is part of the quoted text.
+// A backslash escapes the next character of the strings of mysql and
+// of the E'...' strings of postgres, and postgres strings may also be
+// quoted with dollar quotes like $tag$text$tag$.
+func scanQuery(driverName, query string, sb *strings.Builder, replace func(query string) (string, int, error)) error {
+	postgres := isPostgresDriver(driverName)
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[' && isMssqlDriver(driverName):
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			backslash := c != '`' && driverName == "mysql" ||
+				c == '\'' && postgres && isEscapeString(query, i)
+			end := quotedEnd(query, i+1, closing, backslash)
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		case c == '$' && postgres:
+			if tag, ok := dollarQuote(query[i:]); ok {
+				end := strings.Index(query[i+len(tag):], tag)
+				if end < 0 {
+					end = len(query)
+				} else {
+					end += i + 2*len(tag)
+				}
+				sb.WriteString(query[i:end])
+				i = end
+				continue
+			}
+		case strings.HasPrefix(query[i:], "--") || c == '#' && driverName == "mysql":
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+			sb.WriteString(query[i:end])
+			i = end
+			continue
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			// A postgres type cast is not a named placeholder.
+			sb.WriteString("::")
+			i += 2
+			continue
+		}
+
+		s, n, err := replace(query[i:])
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			sb.WriteString(s)
+			i += n
+			continue
+		}
+		sb.WriteByte(query[i])
+		i++
+	}
+	return nil
+}
+
+// quotedEnd returns the position after the closing quote of
+// the quoted text that starts at i, or the length of the query
+// if the quoted text is not closed.
+func quotedEnd(query string, i int, closing byte, backslash bool) int {
+	for i < len(query) {
+		switch query[i] {
+		case '\\':
+			if backslash {
+				i += 2
+				continue
+			}
+		case closing:
+			if i+1 < len(query) && query[i+1] == closing {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		i++
+	}
+	return len(query)
+}
+
+// isEscapeString reports whether the string that starts
+// at the quote at position i is a postgres E'...' string.
+func isEscapeString(query string, i int) bool {
+	if i == 0 || query[i-1] != 'E' && query[i-1] != 'e' {
+		return false
+	}
+	return i == 1 || !isIdentByte(query[i-2])
+}
+
+// dollarQuote returns the dollar quote like $$ or $tag$
+// at the start of the query.
+func dollarQuote(query string) (string, bool) {
+	for i := 1; i < len(query); i++ {
+		c := query[i]
+		if c == '$' {
+			return query[:i+1], true
+		}
+		if !isIdentByte(c) || i == 1 && c >= '0' && c <= '9' {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// numberedPlaceholder returns the number and the size
+// of the postgres placeholder at the start of the query.
+func numberedPlaceholder(query string) (int, int, bool) {
+	if query[0] != '$' {
+		return 0, 0, false
+	}
+	end := 1
+	for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+		end++
+	}
+	if end == 1 {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(query[1:end])
+	if err != nil || n == 0 {
+		return 0, 0, false
+	}
+	return n, end, true
+}
+
+// namedPlaceholder returns the name of the
+// placeholder at the start of the query.
+func namedPlaceholder(query string) (string, bool) {
+	if query[0] != ':' {
+		return "", false
+	}
+	end := 1
+	for end < len(query) {
+		r, size := utf8.DecodeRuneInString(query[end:])
+		if r != '_' && !unicode.IsLetter(r) && (end == 1 || !unicode.IsDigit(r)) {
+			break
+		}
+		end += size
+	}
+	if end == 1 {
+		return "", false
+	}
+	return query[1:end], true
+}
+
+// paramArgument converts a param to an argument for database/sql.
+func paramArgument(v values.Value) (interface{}, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch v.Type().Nature() {
+	case semantic.Bytes:
+		return v.Bytes(), nil
+	case semantic.Int, semantic.UInt, semantic.Float, semantic.String, semantic.Bool, semantic.Time:
+		return sqlArgument(v)
+	default:
+		return nil, errors.Newf(codes.Invalid, "cannot use a value of type %v as a query param", v.Type())
+	}
+}
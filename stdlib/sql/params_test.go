@@ -0,0 +1,156 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+func TestBindParams(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name       string
+		driverName string
+		query      string
+		params     values.Value
+		wantQuery  string
+		wantArgs   []interface{}
+		wantErr    error
+	}{
+		{
+			name:       "no params",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE host = '?'",
+			wantQuery:  "SELECT * FROM t WHERE host = '?'",
+		},
+		{
+			name:       "array postgres",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE host = $2 AND note <> '$3' AND value > $1 AND tags ? 'a' AND tags ?| array['b'] AND tags ?& array['c']",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), []values.Value{values.NewString("a"), values.NewString("b")}),
+			wantQuery:  "SELECT * FROM t WHERE host = $2 AND note <> '$3' AND value > $1 AND tags ? 'a' AND tags ?| array['b'] AND tags ?& array['c']",
+			wantArgs:   []interface{}{"a", "b"},
+		},
+		{
+			name:       "record postgres with jsonb operators",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE tags ? :tag AND tags ?| array[:tag] AND note = $$:tag$$ AND x = $q$ ' :tag $q$",
+			params:     values.NewObjectWithValues(map[string]values.Value{"tag": values.NewString("a")}),
+			wantQuery:  "SELECT * FROM t WHERE tags ? $1 AND tags ?| array[$2] AND note = $$:tag$$ AND x = $q$ ' :tag $q$",
+			wantArgs:   []interface{}{"a", "a"},
+		},
+		{
+			name:       "postgres escape strings",
+			driverName: "postgres",
+			query:      `SELECT * FROM t WHERE a = E'\' :x' AND b = 'c:\' AND c = :y AND d = 'it''s :x'`,
+			params:     values.NewObjectWithValues(map[string]values.Value{"y": values.NewInt(1)}),
+			wantQuery:  `SELECT * FROM t WHERE a = E'\' :x' AND b = 'c:\' AND c = $1 AND d = 'it''s :x'`,
+			wantArgs:   []interface{}{int64(1)},
+		},
+		{
+			name:       "mysql escaped quotes",
+			driverName: "mysql",
+			query:      `SELECT * FROM t WHERE a = 'it\'s ?' AND b = "say \"?\"" AND c = 'it''s ?' AND d = ? # or ?`,
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicInt), []values.Value{values.NewInt(1)}),
+			wantQuery:  `SELECT * FROM t WHERE a = 'it\'s ?' AND b = "say \"?\"" AND c = 'it''s ?' AND d = ? # or ?`,
+			wantArgs:   []interface{}{int64(1)},
+		},
+		{
+			name:       "sqlserver brackets",
+			driverName: "sqlserver",
+			query:      "SELECT [a?]]b] FROM t WHERE x = 'it''s :y' AND y = :y",
+			params:     values.NewObjectWithValues(map[string]values.Value{"y": values.NewInt(1)}),
+			wantQuery:  "SELECT [a?]]b] FROM t WHERE x = 'it''s :y' AND y = @p1",
+			wantArgs:   []interface{}{int64(1)},
+		},
+		{
+			name:       "array sqlserver",
+			driverName: "sqlserver",
+			query:      "SELECT * FROM t WHERE host = ? -- or ?\nAND value > ?",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicInt), []values.Value{values.NewInt(1), values.NewInt(2)}),
+			wantQuery:  "SELECT * FROM t WHERE host = @p1 -- or ?\nAND value > @p2",
+			wantArgs:   []interface{}{int64(1), int64(2)},
+		},
+		{
+			name:       "array mysql",
+			driverName: "mysql",
+			query:      "SELECT * FROM t WHERE `?` = ?",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicTime), []values.Value{values.NewTime(values.ConvertTime(now))}),
+			wantQuery:  "SELECT * FROM t WHERE `?` = ?",
+			wantArgs:   []interface{}{now},
+		},
+		{
+			name:       "record",
+			driverName: "postgres",
+			query:      "SELECT value::text FROM t WHERE host = :host AND region = :region /* :other */ OR host = :host",
+			params: values.NewObjectWithValues(map[string]values.Value{
+				"host":   values.NewString("a"),
+				"region": values.NewNull(semantic.BasicString),
+			}),
+			wantQuery: "SELECT value::text FROM t WHERE host = $1 AND region = $2 /* :other */ OR host = $3",
+			wantArgs:  []interface{}{"a", nil, "a"},
+		},
+		{
+			name:       "wrong number of params",
+			driverName: "sqlite3",
+			query:      "SELECT * FROM t WHERE host = ?",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), nil),
+			wantErr:    errors.New(codes.Invalid, "query has 1 placeholders but 0 params were given"),
+		},
+		{
+			name:       "postgres question mark placeholder",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE host = ?",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), []values.Value{values.NewString("a")}),
+			wantErr:    errors.New(codes.Invalid, "query has 0 placeholders but 1 params were given; the placeholders of postgres queries are $1, $2 and so on"),
+		},
+		{
+			name:       "athena",
+			driverName: "awsathena",
+			query:      "SELECT * FROM t WHERE host = ?",
+			params:     values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicString), []values.Value{values.NewString("a")}),
+			wantErr:    errors.New(codes.Invalid, "sql driver awsathena does not support query params"),
+		},
+		{
+			name:       "bigquery",
+			driverName: "bigquery",
+			query:      "SELECT * FROM t WHERE host = :host",
+			params:     values.NewObjectWithValues(map[string]values.Value{"host": values.NewString("a")}),
+			wantErr:    errors.New(codes.Invalid, "sql driver bigquery does not support query params"),
+		},
+		{
+			name:       "missing param",
+			driverName: "sqlite3",
+			query:      "SELECT * FROM t WHERE host = :host",
+			params:     values.NewObjectWithValues(map[string]values.Value{"region": values.NewString("a")}),
+			wantErr:    errors.New(codes.Invalid, "query has placeholder :host that is not in params"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotQuery, gotArgs, err := bindParams(tc.driverName, tc.query, tc.params)
+			if tc.wantErr != nil {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if got, want := err.Error(), tc.wantErr.Error(); got != want {
+					t.Fatalf("unexpected error -want/+got:\n\t- %s\n\t+ %s", want, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !cmp.Equal(tc.wantQuery, gotQuery) {
+				t.Errorf("unexpected query -want/+got:\n%s", cmp.Diff(tc.wantQuery, gotQuery))
+			}
+			if !cmp.Equal(tc.wantArgs, gotArgs) {
+				t.Errorf("unexpected arguments -want/+got:\n%s", cmp.Diff(tc.wantArgs, gotArgs))
+			}
+		})
+	}
+}
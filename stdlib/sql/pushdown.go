@@ -0,0 +1,427 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// pushDownAlias is the name given to the query of the
+// user when it is wrapped by the pushed down query.
+const pushDownAlias = "flux_pushdown"
+
+var orderByRegexp = regexp.MustCompile(`(?i)\border\s+by\b`)
+
+// sqlDialect describes how a driver quotes identifiers, names the
+// placeholders of parameters and limits the number of rows of a query.
+// foldIdent converts an unquoted identifier to the name of the
+// column that the database returns for it.
+type sqlDialect struct {
+	quoteIdent  func(name string) string
+	foldIdent   func(name string) string
+	placeholder func(n int) string
+	limit       func(n, offset int64) string
+}
+
+func quoteWith(open, close string) func(name string) string {
+	return func(name string) string {
+		return open + strings.Replace(name, close, close+close, -1) + close
+	}
+}
+
+func sameIdent(name string) string {
+	return name
+}
+
+func questionMark(n int) string {
+	return "?"
+}
+
+func limitOffset(n, offset int64) string {
+	if offset > 0 {
+		return fmt.Sprintf("LIMIT %d OFFSET %d", n, offset)
+	}
+	return fmt.Sprintf("LIMIT %d", n)
+}
+
+// getDialect returns the dialect used to push down into the queries
+// of a driver. Queries are only pushed down for the drivers that
+// sql.to can write with and that send the values of the predicates
+// separately from the query.
+func getDialect(driverName string) (*sqlDialect, bool) {
+	if _, err := getTranslationFunc(driverName); err != nil || !supportsParams(driverName) {
+		return nil, false
+	}
+	switch driverName {
+	case "postgres", "sqlmock":
+		return &sqlDialect{
+			quoteIdent:  quoteWith(`"`, `"`),
+			foldIdent:   strings.ToLower,
+			placeholder: placeholderFunc(driverName),
+			limit:       limitOffset,
+		}, true
+	case "mssql", "sqlserver":
+		return &sqlDialect{
+			quoteIdent:  quoteWith("[", "]"),
+			foldIdent:   sameIdent,
+			placeholder: placeholderFunc(driverName),
+			limit: func(n, offset int64) string {
+				return fmt.Sprintf("ORDER BY (SELECT NULL) OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, n)
+			},
+		}, true
+	case "mysql":
+		return &sqlDialect{
+			quoteIdent:  quoteWith("`", "`"),
+			foldIdent:   sameIdent,
+			placeholder: questionMark,
+			limit:       limitOffset,
+		}, true
+	case "sqlite3":
+		return &sqlDialect{
+			quoteIdent:  quoteWith(`"`, `"`),
+			foldIdent:   sameIdent,
+			placeholder: questionMark,
+			limit:       limitOffset,
+		}, true
+	case "snowflake", "hdb":
+		return &sqlDialect{
+			quoteIdent:  quoteWith(`"`, `"`),
+			foldIdent:   strings.ToUpper,
+			placeholder: questionMark,
+			limit:       limitOffset,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// trimQuery removes the whitespace and the
+// semicolons that terminate the query.
+func trimQuery(query string) string {
+	return strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+}
+
+func hasOrderBy(query string) bool {
+	return orderByRegexp.MatchString(query)
+}
+
+// canPushDown reports whether the query of the source
+// can be wrapped in another query as a subquery.
+func (s *FromSQLProcedureSpec) canPushDown() bool {
+	if _, ok := getDialect(s.DriverName); !ok {
+		return false
+	}
+	query := trimQuery(s.Query)
+	if len(query) < len("select") || !strings.EqualFold(query[:len("select")], "select") {
+		return false
+	}
+	if strings.Contains(query, ";") {
+		// Multiple statements cannot be used as a subquery.
+		return false
+	}
+	if isMssqlDriver(s.DriverName) && hasOrderBy(query) {
+		// SQL Server does not allow an order by
+		// in a subquery without a limit.
+		return false
+	}
+	return true
+}
+
+// resultColumns returns the names of the columns of the result of the
+// query of the source. The names are only known when each item of the
+// select list of the query is a column or has an alias, so queries
+// that select * or an expression without an alias return false.
+func (s *FromSQLProcedureSpec) resultColumns() ([]string, bool) {
+	dialect, ok := getDialect(s.DriverName)
+	if !ok {
+		return nil, false
+	}
+	query := trimQuery(s.Query)
+	if len(query) < len("select") || !strings.EqualFold(query[:len("select")], "select") {
+		return nil, false
+	}
+	toks, ok := selectList(query[len("select"):])
+	if !ok || len(toks) == 0 {
+		return nil, false
+	}
+	if first := toks[0]; !first.quoted && (strings.EqualFold(first.text, "distinct") || strings.EqualFold(first.text, "all")) {
+		toks = toks[1:]
+	}
+
+	var columns []string
+	for len(toks) > 0 {
+		end := 0
+		for end < len(toks) && toks[end].kind != ',' {
+			end++
+		}
+		name, ok := selectItemName(toks[:end], dialect)
+		if !ok || contains(columns, name) {
+			return nil, false
+		}
+		columns = append(columns, name)
+		if end == len(toks) {
+			break
+		}
+		toks = toks[end+1:]
+		if len(toks) == 0 {
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
+// selectItemName returns the name of the column of an item of a select list.
+// The item is either a possibly qualified column or an expression with an alias.
+func selectItemName(toks []sqlToken, dialect *sqlDialect) (string, bool) {
+	n := len(toks)
+	if n == 0 {
+		return "", false
+	}
+	last := toks[n-1]
+	if last.kind != 'w' {
+		return "", false
+	}
+	name := last.text
+	if !last.quoted {
+		if !isIdentifier(name) {
+			return "", false
+		}
+		name = dialect.foldIdent(name)
+	}
+	if n >= 3 && toks[n-2].kind == 'w' && !toks[n-2].quoted && strings.EqualFold(toks[n-2].text, "as") {
+		return name, true
+	}
+	// A column may be qualified with the names of its table and schema.
+	for i := n - 2; i >= 0; i -= 2 {
+		if toks[i].kind != '.' || i == 0 || toks[i-1].kind != 'w' {
+			return "", false
+		}
+		if i == 1 {
+			break
+		}
+	}
+	return name, true
+}
+
+// sqlToken is a token of a select list. Words and quoted
+// identifiers have the kind 'w', the other tokens have the
+// kind of the punctuation character and the text is not kept.
+type sqlToken struct {
+	kind   byte
+	text   string
+	quoted bool
+}
+
+// selectList splits the select list of a query into tokens.
+// The select list ends at the from clause at the top level of the query.
+// The tokens within parentheses are dropped and the parentheses are
+// kept as a single '(' token.
+func selectList(q string) ([]sqlToken, bool) {
+	var (
+		toks  []sqlToken
+		depth int
+	)
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			var sb strings.Builder
+			j := i + 1
+			for {
+				if j >= len(q) {
+					return nil, false
+				}
+				if q[j] == closing {
+					if j+1 < len(q) && q[j+1] == closing {
+						sb.WriteByte(closing)
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(q[j])
+				j++
+			}
+			if depth == 0 {
+				toks = append(toks, sqlToken{kind: 'w', text: sb.String(), quoted: true})
+			}
+			i = j + 1
+		case c == '\'':
+			j := i + 1
+			for {
+				if j >= len(q) {
+					return nil, false
+				}
+				if q[j] == '\'' {
+					if j+1 < len(q) && q[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if depth == 0 {
+				toks = append(toks, sqlToken{kind: '\''})
+			}
+			i = j + 1
+		case isIdentByte(c):
+			j := i
+			for j < len(q) && isIdentByte(q[j]) {
+				j++
+			}
+			word := q[i:j]
+			if depth == 0 {
+				if strings.EqualFold(word, "from") {
+					return toks, true
+				}
+				toks = append(toks, sqlToken{kind: 'w', text: word})
+			}
+			i = j
+		case c == '(':
+			if depth == 0 {
+				toks = append(toks, sqlToken{kind: '('})
+			}
+			depth++
+			i++
+		case c == ')':
+			if depth == 0 {
+				return nil, false
+			}
+			depth--
+			i++
+		default:
+			if depth == 0 {
+				toks = append(toks, sqlToken{kind: c})
+			}
+			i++
+		}
+	}
+	return toks, depth == 0
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// isIdentifier reports whether an unquoted word is an identifier
+// and not a number.
+func isIdentifier(word string) bool {
+	return word != "" && !(word[0] >= '0' && word[0] <= '9') && word[0] != '$'
+}
+
+func (s *FromSQLProcedureSpec) pushedDown() bool {
+	return len(s.Predicates) > 0 || s.Columns != nil || s.Limit > 0
+}
+
+// query returns the query that is sent to the database and its arguments.
+// If anything has been pushed down into the source, the query of the user
+// is wrapped in a query that selects the columns, filters the rows with the
+// predicates and limits the number of rows.
+func (s *FromSQLProcedureSpec) query() (string, []interface{}, error) {
+	if !s.pushedDown() {
+		return s.Query, s.Args, nil
+	}
+	dialect, ok := getDialect(s.DriverName)
+	if !ok {
+		return "", nil, errors.Newf(codes.Internal, "cannot push down into a query of sql driver %s", s.DriverName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	if s.Columns == nil {
+		sb.WriteString("*")
+	} else {
+		for i, c := range s.Columns {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(dialect.quoteIdent(c))
+		}
+	}
+	sb.WriteString(" FROM (")
+	sb.WriteString(trimQuery(s.Query))
+	sb.WriteString(") AS ")
+	sb.WriteString(pushDownAlias)
+
+	// The placeholders of the predicates follow the placeholders of the params.
+	args := append([]interface{}(nil), s.Args...)
+	for i, p := range s.Predicates {
+		op, err := sqlOperator(p.Op)
+		if err != nil {
+			return "", nil, err
+		}
+		arg, err := sqlArgument(p.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, arg)
+
+		if i == 0 {
+			sb.WriteString(" WHERE ")
+		} else {
+			sb.WriteString(" AND ")
+		}
+		sb.WriteString(dialect.quoteIdent(p.Column))
+		sb.WriteString(" ")
+		sb.WriteString(op)
+		sb.WriteString(" ")
+		sb.WriteString(dialect.placeholder(len(args)))
+	}
+
+	if s.Limit > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(dialect.limit(s.Limit, s.Offset))
+	}
+	return sb.String(), args, nil
+}
+
+func sqlOperator(op ast.OperatorKind) (string, error) {
+	switch op {
+	case ast.EqualOperator:
+		return "=", nil
+	case ast.NotEqualOperator:
+		return "<>", nil
+	case ast.LessThanOperator:
+		return "<", nil
+	case ast.LessThanEqualOperator:
+		return "<=", nil
+	case ast.GreaterThanOperator:
+		return ">", nil
+	case ast.GreaterThanEqualOperator:
+		return ">=", nil
+	default:
+		return "", errors.Newf(codes.Internal, "cannot push down operator %s", op)
+	}
+}
+
+func sqlArgument(v values.Value) (interface{}, error) {
+	switch v.Type().Nature() {
+	case semantic.Int:
+		return v.Int(), nil
+	case semantic.UInt:
+		return v.UInt(), nil
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Bool:
+		return v.Bool(), nil
+	case semantic.Time:
+		return v.Time().Time(), nil
+	default:
+		return nil, errors.Newf(codes.Internal, "cannot push down value of type %v", v.Type())
+	}
+}
@@ -0,0 +1,143 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/values"
+)
+
+func TestFromSQLProcedureSpec_Query(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	preds := []ColumnPredicate{
+		{Column: "_time", Op: ast.GreaterThanEqualOperator, Value: values.NewTime(values.ConvertTime(start))},
+		{Column: "host", Op: ast.NotEqualOperator, Value: values.NewString("a")},
+	}
+	for _, tc := range []struct {
+		name     string
+		spec     *FromSQLProcedureSpec
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name: "nothing pushed down",
+			spec: &FromSQLProcedureSpec{
+				DriverName: "postgres",
+				Query:      "SELECT * FROM t;",
+			},
+			wantSQL: "SELECT * FROM t;",
+		},
+		{
+			name: "postgres",
+			spec: &FromSQLProcedureSpec{
+				DriverName: "postgres",
+				Query:      "SELECT * FROM t;",
+				Predicates: preds,
+				Columns:    []string{"_time", `my"host`},
+				Limit:      10,
+			},
+			wantSQL:  `SELECT "_time", "my""host" FROM (SELECT * FROM t) AS flux_pushdown WHERE "_time" >= $1 AND "host" <> $2 LIMIT 10`,
+			wantArgs: []interface{}{start, "a"},
+		},
+		{
+			name: "postgres with params",
+			spec: &FromSQLProcedureSpec{
+				DriverName: "postgres",
+				Query:      "SELECT * FROM t WHERE region = $1",
+				Args:       []interface{}{"b"},
+				Predicates: preds,
+			},
+			wantSQL:  `SELECT * FROM (SELECT * FROM t WHERE region = $1) AS flux_pushdown WHERE "_time" >= $2 AND "host" <> $3`,
+			wantArgs: []interface{}{"b", start, "a"},
+		},
+		{
+			name: "mysql",
+			spec: &FromSQLProcedureSpec{
+				DriverName: "mysql",
+				Query:      "SELECT * FROM t",
+				Predicates: preds,
+				Limit:      10,
+				Offset:     5,
+			},
+			wantSQL:  "SELECT * FROM (SELECT * FROM t) AS flux_pushdown WHERE `_time` >= ? AND `host` <> ? LIMIT 10 OFFSET 5",
+			wantArgs: []interface{}{start, "a"},
+		},
+		{
+			name: "sqlserver",
+			spec: &FromSQLProcedureSpec{
+				DriverName: "sqlserver",
+				Query:      "SELECT * FROM t",
+				Predicates: preds,
+				Columns:    []string{"host"},
+				Limit:      10,
+			},
+			wantSQL:  "SELECT [host] FROM (SELECT * FROM t) AS flux_pushdown WHERE [_time] >= @p1 AND [host] <> @p2 ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY",
+			wantArgs: []interface{}{start, "a"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := tc.spec.query()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !cmp.Equal(tc.wantSQL, gotSQL) {
+				t.Errorf("unexpected query -want/+got:\n%s", cmp.Diff(tc.wantSQL, gotSQL))
+			}
+			if !cmp.Equal(tc.wantArgs, gotArgs) {
+				t.Errorf("unexpected arguments -want/+got:\n%s", cmp.Diff(tc.wantArgs, gotArgs))
+			}
+		})
+	}
+}
+
+func TestFromSQLProcedureSpec_CanPushDown(t *testing.T) {
+	for _, tc := range []struct {
+		driverName string
+		query      string
+		want       bool
+	}{
+		{driverName: "postgres", query: "select * from t", want: true},
+		{driverName: "postgres", query: "SELECT * FROM t ORDER BY _time;", want: true},
+		{driverName: "sqlserver", query: "SELECT * FROM t ORDER BY _time", want: false},
+		{driverName: "mysql", query: "SELECT * FROM a; SELECT * FROM b", want: false},
+		{driverName: "sqlite3", query: "PRAGMA table_info(t)", want: false},
+		{driverName: "awsathena", query: "SELECT * FROM t", want: false},
+		{driverName: "bigquery", query: "SELECT * FROM t", want: false},
+	} {
+		spec := &FromSQLProcedureSpec{DriverName: tc.driverName, Query: tc.query}
+		if got := spec.canPushDown(); got != tc.want {
+			t.Errorf("unexpected result for %s query %q: want=%v got=%v", tc.driverName, tc.query, tc.want, got)
+		}
+	}
+}
+
+func TestFromSQLProcedureSpec_ResultColumns(t *testing.T) {
+	for _, tc := range []struct {
+		driverName string
+		query      string
+		want       []string
+	}{
+		{driverName: "postgres", query: "SELECT _time, host FROM t", want: []string{"_time", "host"}},
+		{driverName: "postgres", query: `select distinct t.Host, "_Value" from t`, want: []string{"host", "_Value"}},
+		{driverName: "mysql", query: "SELECT `t`.`Host`, count(*) AS n FROM t GROUP BY 1", want: []string{"Host", "n"}},
+		{driverName: "sqlserver", query: "SELECT [a b], x AS [c] FROM t", want: []string{"a b", "c"}},
+		{driverName: "snowflake", query: "SELECT host, 'a, b' AS \"tag\" FROM db.s.t", want: []string{"HOST", "tag"}},
+		{driverName: "postgres", query: "SELECT (SELECT max(v) FROM u) AS m, h FROM t", want: []string{"m", "h"}},
+		{driverName: "postgres", query: "SELECT * FROM t"},
+		{driverName: "postgres", query: "SELECT t.* FROM t"},
+		{driverName: "postgres", query: "SELECT count(*) FROM t"},
+		{driverName: "postgres", query: "SELECT host h FROM t"},
+		{driverName: "postgres", query: "SELECT 1 FROM t"},
+		{driverName: "postgres", query: "SELECT a.id, b.id FROM a JOIN b ON a.id = b.id"},
+		{driverName: "sqlserver", query: "SELECT TOP 10 host FROM t"},
+		{driverName: "awsathena", query: "SELECT host FROM t"},
+	} {
+		spec := &FromSQLProcedureSpec{DriverName: tc.driverName, Query: tc.query}
+		got, ok := spec.resultColumns()
+		if ok != (tc.want != nil) || !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected columns for %s query %q: want=%v got=%v (%v)", tc.driverName, tc.query, tc.want, got, ok)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package sql
+
+import (
+	"context"
+
+	"github.com/influxdata/flux/internal/pushdown"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+// ColumnPredicate compares the values of a column with a constant.
+// The predicates of a sql.from source are added to the where clause
+// of the query that wraps the query of the user.
+type ColumnPredicate = pushdown.ColumnPredicate
+
+// PushDownRangeRule adds the bounds of a range to the predicates of
+// the sql.from source it reads from. The range is not removed since
+// it also adds the start and stop columns to the group key.
+type PushDownRangeRule struct{}
+
+func (PushDownRangeRule) Name() string {
+	return "sql.PushDownRangeRule"
+}
+
+func (PushDownRangeRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.RangeKind, plan.Any())
+}
+
+func (PushDownRangeRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := findSource(node)
+	if fromNode == nil {
+		return node, false, nil
+	}
+
+	spec := node.ProcedureSpec().(*universe.RangeProcedureSpec)
+	changed, err := addPredicates(fromNode, pushdown.RangePredicates(spec))
+	return node, changed, err
+}
+
+// PushDownFilterRule adds the comparisons of a filter to the predicates
+// of the sql.from source it reads from. Only comparisons between a column
+// and a literal that are joined with and are pushed down. The filter is
+// not removed so the parts of the predicate that cannot be pushed down
+// and the handling of empty tables are left to the filter.
+type PushDownFilterRule struct{}
+
+func (PushDownFilterRule) Name() string {
+	return "sql.PushDownFilterRule"
+}
+
+func (PushDownFilterRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.FilterKind, plan.Any())
+}
+
+func (PushDownFilterRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := findSource(node)
+	if fromNode == nil {
+		return node, false, nil
+	}
+
+	spec := node.ProcedureSpec().(*universe.FilterProcedureSpec)
+	preds, _ := pushdown.FilterPredicates(spec)
+	if len(preds) == 0 {
+		return node, false, nil
+	}
+	changed, err := addPredicates(fromNode, preds)
+	return node, changed, err
+}
+
+// PushDownKeepRule selects only the columns of a keep in the query
+// of the sql.from source that it directly follows. The keep is only
+// pushed down when the columns of the result of the query are known
+// from its select list, so that the columns the keep lists but the
+// query does not return are left out and the columns are selected
+// in the order of the result. The keep is not removed.
+type PushDownKeepRule struct{}
+
+func (PushDownKeepRule) Name() string {
+	return "sql.PushDownKeepRule"
+}
+
+func (PushDownKeepRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.SchemaMutationKind, plan.Pat(FromSQLKind))
+}
+
+func (PushDownKeepRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := node.Predecessors()[0]
+	if len(fromNode.Successors()) != 1 {
+		return node, false, nil
+	}
+	fromSpec := fromNode.ProcedureSpec().(*FromSQLProcedureSpec)
+	if fromSpec.Columns != nil || !fromSpec.canPushDown() {
+		return node, false, nil
+	}
+
+	columns, ok := keptColumns(node.ProcedureSpec().(*universe.SchemaMutationProcedureSpec), fromSpec)
+	if !ok || len(columns) == 0 {
+		return node, false, nil
+	}
+
+	newSpec := fromSpec.Copy().(*FromSQLProcedureSpec)
+	newSpec.Columns = columns
+	if err := fromNode.ReplaceSpec(newSpec); err != nil {
+		return nil, false, err
+	}
+	return node, true, nil
+}
+
+// PushDownLimitRule adds the limit and offset of a limit to the query
+// of the sql.from source it reads from. It is only pushed down when
+// nothing but pushed down keep nodes are between the limit and the
+// source and the source has no predicates. A range or a filter is never
+// pushed down under a limit, even when its predicates are, because the
+// database may compare values differently than Flux does, for example
+// with the collation of a column or by converting the types of the
+// values, and the limit would then count other rows.
+// The limit is not removed.
+type PushDownLimitRule struct{}
+
+func (PushDownLimitRule) Name() string {
+	return "sql.PushDownLimitRule"
+}
+
+func (PushDownLimitRule) Pattern() plan.Pattern {
+	return plan.Pat(universe.LimitKind, plan.Any())
+}
+
+func (PushDownLimitRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	fromNode := findSource(node)
+	if fromNode == nil {
+		return node, false, nil
+	}
+	fromSpec := fromNode.ProcedureSpec().(*FromSQLProcedureSpec)
+	if fromSpec.Limit > 0 || len(fromSpec.Predicates) > 0 || hasOrderBy(fromSpec.Query) {
+		// The order of the rows of a subquery is not guaranteed
+		// so a limit cannot be applied to an ordered query.
+		return node, false, nil
+	}
+
+	for pred := node.Predecessors()[0]; pred != fromNode; pred = pred.Predecessors()[0] {
+		if !isPushedKeep(pred, fromSpec) {
+			return node, false, nil
+		}
+	}
+
+	spec := node.ProcedureSpec().(*universe.LimitProcedureSpec)
+	if spec.N <= 0 {
+		return node, false, nil
+	}
+	newSpec := fromSpec.Copy().(*FromSQLProcedureSpec)
+	newSpec.Limit = spec.N
+	newSpec.Offset = spec.Offset
+	if err := fromNode.ReplaceSpec(newSpec); err != nil {
+		return nil, false, err
+	}
+	return node, true, nil
+}
+
+// findSource walks the chain of range, filter and pushed down keep
+// nodes that precede the node and returns the sql.from source at its
+// start if its query can be pushed down into. Each node of the chain
+// must have a single successor so that the pushed down query does not
+// change the rows seen by other branches of the plan.
+func findSource(node plan.Node) plan.Node {
+	fromNode, chain := pushdown.FindSource(node, FromSQLKind,
+		universe.RangeKind, universe.FilterKind, universe.SchemaMutationKind)
+	if fromNode == nil {
+		return nil
+	}
+	spec := fromNode.ProcedureSpec().(*FromSQLProcedureSpec)
+	if !spec.canPushDown() {
+		return nil
+	}
+	for _, n := range chain {
+		if n.Kind() == universe.SchemaMutationKind && !isPushedKeep(n, spec) {
+			return nil
+		}
+	}
+	return fromNode
+}
+
+// addPredicates adds the predicates that the source does not already have.
+// Predicates are not added if the query of the source only selects some
+// of the columns and the predicate compares a column that is not selected.
+func addPredicates(fromNode plan.Node, preds []ColumnPredicate) (bool, error) {
+	spec := fromNode.ProcedureSpec().(*FromSQLProcedureSpec)
+	if spec.Limit > 0 {
+		// The predicates would be applied before the limit.
+		return false, nil
+	}
+
+	for _, p := range preds {
+		if spec.Columns != nil && !contains(spec.Columns, p.Column) {
+			return false, nil
+		}
+	}
+	added := pushdown.NewPredicates(spec.Predicates, preds)
+	if len(added) == 0 {
+		return false, nil
+	}
+
+	newSpec := spec.Copy().(*FromSQLProcedureSpec)
+	newSpec.Predicates = append(newSpec.Predicates, added...)
+	if err := fromNode.ReplaceSpec(newSpec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isPushedKeep reports whether the node is a keep
+// whose columns are selected by the query of the source.
+func isPushedKeep(node plan.Node, fromSpec *FromSQLProcedureSpec) bool {
+	spec, ok := node.ProcedureSpec().(*universe.SchemaMutationProcedureSpec)
+	if !ok || fromSpec.Columns == nil {
+		return false
+	}
+	columns, ok := keptColumns(spec, fromSpec)
+	if !ok || len(columns) != len(fromSpec.Columns) {
+		return false
+	}
+	for i := range columns {
+		if columns[i] != fromSpec.Columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// keptColumns returns the columns of the result of the query of the
+// source that a schema mutation keeps, in the order of the result.
+// It returns false if the mutation does not only keep a list of columns
+// or the columns of the result are not known.
+func keptColumns(spec *universe.SchemaMutationProcedureSpec, fromSpec *FromSQLProcedureSpec) ([]string, bool) {
+	if len(spec.Mutations) != 1 {
+		return nil, false
+	}
+	keep, ok := spec.Mutations[0].(*universe.KeepOpSpec)
+	if !ok || keep.Predicate.Fn != nil || keep.Columns == nil {
+		return nil, false
+	}
+	result, ok := fromSpec.resultColumns()
+	if !ok {
+		return nil, false
+	}
+	columns := make([]string, 0, len(keep.Columns))
+	for _, c := range result {
+		if contains(keep.Columns, c) {
+			columns = append(columns, c)
+		}
+	}
+	return columns, true
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package sql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	fsql "github.com/influxdata/flux/stdlib/sql"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
+)
+
+func TestPushDownRules(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	stop := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	fromSpec := func(driverName, query string) *fsql.FromSQLProcedureSpec {
+		return &fsql.FromSQLProcedureSpec{
+			DriverName:     driverName,
+			DataSourceName: "postgres://localhost/db",
+			Query:          query,
+		}
+	}
+	rangeSpec := &universe.RangeProcedureSpec{
+		Bounds: flux.Bounds{
+			Start: flux.Time{Absolute: start},
+			Stop:  flux.Time{Absolute: stop},
+		},
+		TimeColumn:  "_time",
+		StartColumn: "_start",
+		StopColumn:  "_stop",
+	}
+	rangePredicates := []fsql.ColumnPredicate{
+		{Column: "_time", Op: ast.GreaterThanEqualOperator, Value: values.NewTime(values.ConvertTime(start))},
+		{Column: "_time", Op: ast.LessThanOperator, Value: values.NewTime(values.ConvertTime(stop))},
+	}
+	filterSpec := func(fn string) *universe.FilterProcedureSpec {
+		return &universe.FilterProcedureSpec{
+			Fn: interpreter.ResolvedFunction{
+				Fn:    executetest.FunctionExpression(t, fn),
+				Scope: values.NewScope(),
+			},
+		}
+	}
+	keepSpec := &universe.SchemaMutationProcedureSpec{
+		Mutations: []universe.SchemaMutation{
+			&universe.KeepOpSpec{Columns: []string{"_time", "host", "missing"}},
+		},
+	}
+	limitSpec := &universe.LimitProcedureSpec{N: 10, Offset: 5}
+	rules := []plan.Rule{
+		fsql.PushDownRangeRule{},
+		fsql.PushDownFilterRule{},
+		fsql.PushDownKeepRule{},
+		fsql.PushDownLimitRule{},
+	}
+
+	tcs := []plantest.RuleTestCase{
+		{
+			Name:  "range",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &fsql.FromSQLProcedureSpec{
+						DriverName:     "postgres",
+						DataSourceName: "postgres://localhost/db",
+						Query:          "SELECT * FROM t",
+						Predicates:     rangePredicates,
+					}),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name:  "keep and filter",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("mysql", "SELECT host, region, _time FROM t")),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host != "a" and 10 < r._time`)),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &fsql.FromSQLProcedureSpec{
+						DriverName:     "mysql",
+						DataSourceName: "postgres://localhost/db",
+						Query:          "SELECT host, region, _time FROM t",
+						Predicates: []fsql.ColumnPredicate{
+							{Column: "host", Op: ast.NotEqualOperator, Value: values.NewString("a")},
+							{Column: "_time", Op: ast.GreaterThanOperator, Value: values.NewInt(10)},
+						},
+						// The columns are selected in the order of the result
+						// and the missing column is left out.
+						Columns: []string{"host", "_time"},
+					}),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host != "a" and 10 < r._time`)),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {2, 3}},
+			},
+		},
+		{
+			Name:  "keep and limit",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", `SELECT t."_time", upper(h) AS host FROM t`)),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &fsql.FromSQLProcedureSpec{
+						DriverName:     "postgres",
+						DataSourceName: "postgres://localhost/db",
+						Query:          `SELECT t."_time", upper(h) AS host FROM t`,
+						Columns:        []string{"_time", "host"},
+						Limit:          10,
+						Offset:         5,
+					}),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			Name:  "keep of unknown columns",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("keep", keepSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "filter of a column that is not kept",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT _time, host, region FROM t")),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.region == "a"`)),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &fsql.FromSQLProcedureSpec{
+						DriverName:     "postgres",
+						DataSourceName: "postgres://localhost/db",
+						Query:          "SELECT _time, host, region FROM t",
+						Columns:        []string{"_time", "host"},
+					}),
+					plan.CreatePhysicalNode("keep", keepSpec),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.region == "a"`)),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			// The database may compare the values of the pushed down
+			// predicates differently, so the limit is left to Flux.
+			Name:  "limit after a range",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &fsql.FromSQLProcedureSpec{
+						DriverName:     "postgres",
+						DataSourceName: "postgres://localhost/db",
+						Query:          "SELECT * FROM t",
+						Predicates:     rangePredicates,
+					}),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			Name:  "limit after a filter with disjunction",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("filter", filterSpec(`(r) => r.host == "a" or r.host == "b"`)),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "limit of an ordered query",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t ORDER BY _time")),
+					plan.CreatePhysicalNode("limit", limitSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "driver without pushdown",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("awsathena", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "query that is not a select",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("mysql", "SHOW TABLES")),
+					plan.CreatePhysicalNode("range", rangeSpec),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "shared source",
+			Rules: rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", fromSpec("postgres", "SELECT * FROM t")),
+					plan.CreatePhysicalNode("range", rangeSpec),
+					plan.CreatePhysicalNode("count", &universe.CountProcedureSpec{}),
+				},
+				Edges: [][2]int{{0, 1}, {0, 2}},
+			},
+			NoChange: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+)
+
+// prepareTable creates the table if it does not exist and adds the
+// columns of the flux table that it does not have, as configured by
+// the spec. The table is created at most once by the transformation.
+func (t *ToSQLTransformation) prepareTable(cols []flux.ColMeta, colDefs []string) error {
+	s := t.spec.Spec
+	if s.DriverName == "sqlmock" {
+		return nil
+	}
+	if s.CreateTable && !t.created {
+		if _, err := t.tx.Exec(createTableQuery(s, cols, colDefs)); err != nil {
+			return err
+		}
+		t.created = true
+	}
+	if s.AddColumns {
+		return t.addColumns(cols, colDefs)
+	}
+	return nil
+}
+
+// addColumns adds the columns that the table does not have.
+// The columns of the table are loaded once and then kept up to date.
+func (t *ToSQLTransformation) addColumns(cols []flux.ColMeta, colDefs []string) error {
+	if t.columns == nil {
+		columns, err := t.tableColumns()
+		if err != nil {
+			return err
+		}
+		t.columns = columns
+	}
+	for i, col := range cols {
+		name := strings.ToLower(col.Label)
+		if t.columns[name] {
+			continue
+		}
+		if _, err := t.tx.Exec(addColumnQuery(t.spec.Spec, colDefs[i])); err != nil {
+			return err
+		}
+		t.columns[name] = true
+	}
+	return nil
+}
+
+// tableColumns returns the lowercase names of the columns of the table.
+func (t *ToSQLTransformation) tableColumns() (map[string]bool, error) {
+	rows, err := t.tx.Query(fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", t.spec.Spec.Table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]bool, len(names))
+	for _, name := range names {
+		columns[strings.ToLower(name)] = true
+	}
+	return columns, rows.Close()
+}
+
+// createTableQuery returns the query that creates the table if it does not exist.
+// If the spec has upsert keys, the table has a primary key on them for the drivers
+// that need a unique constraint to upsert.
+func createTableQuery(s *ToSQLOpSpec, cols []flux.ColMeta, colDefs []string) string {
+	defs := strings.Join(colDefs, ",")
+	if key := primaryKey(s, cols); key != "" {
+		defs += "," + key
+	}
+	switch {
+	case isMssqlDriver(s.DriverName): // SQL Server does not support IF NOT EXIST
+		return fmt.Sprintf("IF OBJECT_ID('%s', 'U') IS NULL BEGIN CREATE TABLE %s (%s) END", s.Table, s.Table, defs)
+	case s.DriverName == "hdb": // SAP HANA does not support IF NOT EXIST
+		// wrap CREATE TABLE statement with HDB-specific "if not exists" SQLScript check
+		q := fmt.Sprintf("CREATE TABLE %s (%s)", hdbEscapeName(s.Table, true), defs)
+		return hdbAddIfNotExist(s.Table, q)
+	default:
+		return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", s.Table, defs)
+	}
+}
+
+// primaryKey returns the primary key constraint on the upsert keys.
+// SQL Server and Snowflake merge on the keys and do not need one.
+func primaryKey(s *ToSQLOpSpec, cols []flux.ColMeta) string {
+	if len(s.UpsertKeys) == 0 {
+		return ""
+	}
+	switch s.DriverName {
+	case "postgres", "sqlite3", "sqlmock":
+		return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(s.UpsertKeys, ","))
+	case "mysql":
+		// MySQL can only index a prefix of a TEXT column.
+		keys := make([]string, len(s.UpsertKeys))
+		for i, key := range s.UpsertKeys {
+			keys[i] = key
+			if j := execute.ColIdx(key, cols); j >= 0 && cols[j].Type == flux.TString {
+				keys[i] += "(255)"
+			}
+		}
+		return fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ","))
+	default:
+		return ""
+	}
+}
+
+// addColumnQuery returns the query that adds a column to the table.
+func addColumnQuery(s *ToSQLOpSpec, colDef string) string {
+	if isMssqlDriver(s.DriverName) {
+		return fmt.Sprintf("ALTER TABLE %s ADD %s", s.Table, colDef)
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", s.Table, colDef)
+}
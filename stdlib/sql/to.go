@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 )
 
@@ -20,10 +21,13 @@ const (
 )
 
 type ToSQLOpSpec struct {
-	DriverName     string `json:"driverName,omitempty"`
-	DataSourceName string `json:"dataSourcename,omitempty"`
-	Table          string `json:"table,omitempty"`
-	BatchSize      int    `json:"batchSize,omitempty"`
+	DriverName     string   `json:"driverName,omitempty"`
+	DataSourceName string   `json:"dataSourcename,omitempty"`
+	Table          string   `json:"table,omitempty"`
+	BatchSize      int      `json:"batchSize,omitempty"`
+	CreateTable    bool     `json:"createTable,omitempty"`
+	AddColumns     bool     `json:"addColumns,omitempty"`
+	UpsertKeys     []string `json:"upsertKeys,omitempty"`
 }
 
 func init() {
@@ -72,7 +76,37 @@ func (o *ToSQLOpSpec) ReadArgs(args flux.Arguments) error {
 		o.BatchSize = int(b)
 	}
 
-	return err
+	// Tables have always been created when they do not exist.
+	o.CreateTable = true
+	if create, ok, err := args.GetBool("createTable"); err != nil {
+		return err
+	} else if ok {
+		o.CreateTable = create
+	}
+
+	if o.AddColumns, _, err = args.GetBool("addColumns"); err != nil {
+		return err
+	}
+	if o.AddColumns && !supportsSchemaChanges(o.DriverName) {
+		return errors.Newf(codes.Invalid, "adding columns is not supported for sql driver %s", o.DriverName)
+	}
+
+	if keys, ok, err := args.GetArray("upsertKeys", semantic.String); err != nil {
+		return err
+	} else if ok {
+		if !supportsSchemaChanges(o.DriverName) {
+			return errors.Newf(codes.Invalid, "upserts are not supported for sql driver %s", o.DriverName)
+		}
+		if keys.Len() == 0 {
+			return errors.New(codes.Invalid, "upsertKeys must contain at least one column")
+		}
+		o.UpsertKeys = make([]string, keys.Len())
+		keys.Range(func(i int, v values.Value) {
+			o.UpsertKeys[i] = v.Str()
+		})
+	}
+
+	return nil
 }
 
 func createToSQLOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
@@ -107,6 +141,9 @@ func (o *ToSQLProcedureSpec) Copy() plan.ProcedureSpec {
 			DataSourceName: s.DataSourceName,
 			Table:          s.Table,
 			BatchSize:      s.BatchSize,
+			CreateTable:    s.CreateTable,
+			AddColumns:     s.AddColumns,
+			UpsertKeys:     append([]string(nil), s.UpsertKeys...),
 		},
 	}
 	return res
@@ -142,6 +179,12 @@ type ToSQLTransformation struct {
 	spec  *ToSQLProcedureSpec
 	db    *sql.DB
 	tx    *sql.Tx
+
+	// created reports whether the table has been created.
+	created bool
+	// columns holds the lowercase names of the columns
+	// of the table once they have been loaded.
+	columns map[string]bool
 }
 
 func (t *ToSQLTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
@@ -282,6 +325,17 @@ func getTranslationFunc(driverName string) (func() translationFunc, error) {
 	}
 }
 
+// supportsSchemaChanges reports whether sql.to can add
+// columns to the tables of a driver and upsert into them.
+func supportsSchemaChanges(driverName string) bool {
+	switch driverName {
+	case "postgres", "mysql", "sqlite3", "mssql", "sqlserver", "snowflake", "sqlmock":
+		return true
+	default:
+		return false
+	}
+}
+
 func supportsTx(driverName string) bool {
 	return driverName != "sqlmock" && driverName != "awsathena"
 }
@@ -289,6 +343,10 @@ func supportsTx(driverName string) bool {
 func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []string, valStringArray [][]string, valArgsArray [][]interface{}, err error) {
 	cols := tbl.Cols()
 	batchSize := correctBatchSize(t.spec.Spec.BatchSize, len(cols))
+	if t.spec.Spec.DriverName == "hdb" {
+		// SAP HANA does not support INSERT/UPDATE batching via a single SQL command
+		batchSize = 1
+	}
 
 	labels := make(map[string]idxType, len(cols))
 	var questionMarks, newSQLTableCols []string
@@ -316,6 +374,12 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 		}
 	}
 
+	for _, key := range t.spec.Spec.UpsertKeys {
+		if _, ok := labels[key]; !ok {
+			return nil, nil, nil, errors.Newf(codes.Invalid, "upsert key %q is not a column of the table", key)
+		}
+	}
+
 	// Creates the placeholders for values in the query
 	// eg: (?,?)
 	valuePlaceHolders := fmt.Sprintf("(%s)", strings.Join(questionMarks, ","))
@@ -335,23 +399,8 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 		// valueArgs holds all the values to pass into the query
 		valueArgs := make([]interface{}, 0, l*len(cols))
 
-		if t.spec.Spec.DriverName != "sqlmock" {
-			var q string
-			if isMssqlDriver(t.spec.Spec.DriverName) { // SQL Server does not support IF NOT EXIST
-				q = fmt.Sprintf("IF OBJECT_ID('%s', 'U') IS NULL BEGIN CREATE TABLE %s (%s) END", t.spec.Spec.Table, t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
-			} else if t.spec.Spec.DriverName == "hdb" { // SAP HANA does not support IF NOT EXIST
-				// wrap CREATE TABLE statement with HDB-specific "if not exists" SQLScript check
-				q = fmt.Sprintf("CREATE TABLE %s (%s)", hdbEscapeName(t.spec.Spec.Table, true), strings.Join(newSQLTableCols, ","))
-				q = hdbAddIfNotExist(t.spec.Spec.Table, q)
-				// SAP HANA does not support INSERT/UPDATE batching via a single SQL command
-				batchSize = 1
-			} else {
-				q = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
-			}
-			_, err = t.tx.Exec(q)
-			if err != nil {
-				return err
-			}
+		if err := t.prepareTable(cols, newSQLTableCols); err != nil {
+			return err
 		}
 
 		for i := 0; i < l; i++ {
@@ -424,6 +473,10 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 }
 
 func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings *[]string, valueArgs *[]interface{}) (err error) {
+	if len(s.UpsertKeys) > 0 {
+		strs, args := dedupeRows(colNames, s.UpsertKeys, *valueStrings, *valueArgs)
+		valueStrings, valueArgs = &strs, &args
+	}
 	concatValueStrings := strings.Join(*valueStrings, ",")
 
 	// PostgreSQL uses $n instead of ? for placeholders
@@ -439,8 +492,10 @@ func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings
 		}
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.Table, strings.Join(colNames, ","), concatValueStrings)
+	query := insertQuery(s, colNames, concatValueStrings)
 	if isMssqlDriver(s.DriverName) && mssqlCheckParameter(s.DataSourceName, mssqlIdentityInsertEnabled) {
+		// The statements are joined with their terminators below.
+		query = strings.TrimSuffix(query, ";")
 		prologue := fmt.Sprintf("DECLARE @tableHasIdentity INT = OBJECTPROPERTY(OBJECT_ID('%s'), 'TableHasIdentity'); IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s ON END", s.Table, s.Table)
 		epilogue := fmt.Sprintf("IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s OFF END", s.Table)
 		query = strings.Join([]string{prologue, query, epilogue}, "; ")
@@ -40,6 +40,7 @@ func TestSqlTo(t *testing.T) {
 							DataSourceName: "root@/db",
 							Table:          "TestTable",
 							BatchSize:      fsql.DefaultBatchSize,
+							CreateTable:    true,
 						},
 					},
 				},
@@ -48,6 +49,39 @@ func TestSqlTo(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "with upsert keys",
+			Raw:  `import "sql" from(bucket: "mybucket") |> sql.to(driverName:"sqlmock", dataSourceName:"root@/db", table:"TestTable", createTable: false, addColumns: true, upsertKeys: ["host", "_time"])`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: influxdb.NameOrID{Name: "mybucket"},
+						},
+					},
+					{
+						ID: "toSQL1",
+						Spec: &fsql.ToSQLOpSpec{
+							DriverName:     "sqlmock",
+							DataSourceName: "root@/db",
+							Table:          "TestTable",
+							BatchSize:      fsql.DefaultBatchSize,
+							AddColumns:     true,
+							UpsertKeys:     []string{"host", "_time"},
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "from0", Child: "toSQL1"},
+				},
+			},
+		},
+		{
+			Name:    "upsert keys with unsupported driver",
+			Raw:     `import "sql" from(bucket: "mybucket") |> sql.to(driverName:"bigquery", dataSourceName:"bigquery://project/dataset", table:"TestTable", upsertKeys: ["host"])`,
+			WantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
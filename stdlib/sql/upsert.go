@@ -0,0 +1,178 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// insertQuery returns the query that inserts the rows of values into the table.
+// If the spec has upsert keys, a row that has the same keys as a row of the
+// table updates that row instead.
+func insertQuery(s *ToSQLOpSpec, colNames []string, values string) string {
+	if len(s.UpsertKeys) == 0 {
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.Table, strings.Join(colNames, ","), values)
+	}
+
+	// The columns of an upsert are quoted so that keys like user or order
+	// can be named. They are converted to the case that the database gives
+	// unquoted names so they name the same columns as the unquoted column
+	// definitions of the table.
+	ident := upsertIdent(s.DriverName)
+	cols := make([]string, len(colNames))
+	for i, c := range colNames {
+		cols[i] = ident(c)
+	}
+	keys := make([]string, len(s.UpsertKeys))
+	for i, key := range s.UpsertKeys {
+		keys[i] = ident(key)
+	}
+	updated := nonKeyColumns(cols, keys)
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.Table, strings.Join(cols, ","), values)
+	switch {
+	case s.DriverName == "mysql":
+		if len(updated) == 0 {
+			// Updating a key to itself ignores the row.
+			updated = keys[:1]
+		}
+		return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insert, assignments(updated, "VALUES(%s)"))
+	case isMssqlDriver(s.DriverName):
+		// MERGE statements must be terminated by a semicolon.
+		return fmt.Sprintf("MERGE INTO %s AS tgt USING (VALUES %s) AS src (%s) %s;",
+			s.Table, values, strings.Join(cols, ","), mergeClauses(cols, keys, updated))
+	case s.DriverName == "snowflake":
+		// The VALUES of Snowflake name their columns column1, column2 and so on.
+		selected := make([]string, len(cols))
+		for i, c := range cols {
+			selected[i] = fmt.Sprintf("column%d AS %s", i+1, c)
+		}
+		return fmt.Sprintf("MERGE INTO %s AS tgt USING (SELECT %s FROM VALUES %s) AS src %s",
+			s.Table, strings.Join(selected, ","), values, mergeClauses(cols, keys, updated))
+	default:
+		if len(updated) == 0 {
+			return fmt.Sprintf("%s ON CONFLICT (%s) DO NOTHING", insert, strings.Join(keys, ","))
+		}
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", insert, strings.Join(keys, ","), assignments(updated, "excluded.%s"))
+	}
+}
+
+// upsertIdent returns the function that quotes the
+// names of the columns of an upsert for a driver.
+func upsertIdent(driverName string) func(name string) string {
+	dialect, ok := getDialect(driverName)
+	if !ok {
+		return func(name string) string { return name }
+	}
+	return func(name string) string {
+		return dialect.quoteIdent(dialect.foldIdent(name))
+	}
+}
+
+// mergeClauses returns the clauses of a MERGE statement that match
+// the rows of src to the rows of tgt on the keys, update the matched
+// rows and insert the rows that do not match.
+func mergeClauses(colNames, keys, updated []string) string {
+	on := make([]string, len(keys))
+	for i, key := range keys {
+		on[i] = fmt.Sprintf("tgt.%s=src.%s", key, key)
+	}
+	src := make([]string, len(colNames))
+	for i, c := range colNames {
+		src[i] = "src." + c
+	}
+
+	var sb strings.Builder
+	sb.WriteString("ON ")
+	sb.WriteString(strings.Join(on, " AND "))
+	if len(updated) > 0 {
+		sb.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		sb.WriteString(assignments(updated, "src.%s"))
+	}
+	fmt.Fprintf(&sb, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(colNames, ","), strings.Join(src, ","))
+	return sb.String()
+}
+
+// assignments returns the assignments of an update of the columns.
+// The format is given the name of the column to assign the value of.
+func assignments(cols []string, format string) string {
+	set := make([]string, len(cols))
+	for i, c := range cols {
+		set[i] = c + "=" + fmt.Sprintf(format, c)
+	}
+	return strings.Join(set, ",")
+}
+
+// dedupeRows removes the rows of a batch that have the same upsert keys
+// as a later row of the batch, so that the last row for the keys wins.
+// A single statement cannot update a row twice, so the databases reject
+// an upsert that has the same keys more than once. Each row has one value
+// string and len(colNames) arguments. Rows with a null key are kept since
+// null keys never conflict.
+func dedupeRows(colNames, keys []string, valueStrings []string, valueArgs []interface{}) ([]string, []interface{}) {
+	n := len(colNames)
+	idx := make([]int, 0, len(keys))
+	for _, key := range keys {
+		for j, c := range colNames {
+			if c == key {
+				idx = append(idx, j)
+				break
+			}
+		}
+	}
+
+	rows := len(valueStrings)
+	last := make(map[string]int, rows)
+	rowKeys := make([]string, rows)
+	dups := 0
+	for i := 0; i < rows; i++ {
+		var sb strings.Builder
+		for _, j := range idx {
+			v := valueArgs[i*n+j]
+			if v == nil {
+				sb.Reset()
+				break
+			}
+			fmt.Fprintf(&sb, "%T:%v\x00", v, v)
+		}
+		if sb.Len() == 0 {
+			continue
+		}
+		rowKeys[i] = sb.String()
+		if _, ok := last[rowKeys[i]]; ok {
+			dups++
+		}
+		last[rowKeys[i]] = i
+	}
+	if dups == 0 {
+		return valueStrings, valueArgs
+	}
+
+	strs := make([]string, 0, rows-dups)
+	args := make([]interface{}, 0, (rows-dups)*n)
+	for i := 0; i < rows; i++ {
+		if rowKeys[i] != "" && last[rowKeys[i]] != i {
+			continue
+		}
+		strs = append(strs, valueStrings[i])
+		args = append(args, valueArgs[i*n:(i+1)*n]...)
+	}
+	return strs, args
+}
+
+// nonKeyColumns returns the columns that are not keys.
+func nonKeyColumns(colNames, keys []string) []string {
+	var cols []string
+	for _, c := range colNames {
+		isKey := false
+		for _, key := range keys {
+			if c == key {
+				isKey = true
+				break
+			}
+		}
+		if !isKey {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
@@ -0,0 +1,140 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+)
+
+func TestInsertQuery(t *testing.T) {
+	colNames := []string{"_time", "host", "_value"}
+	values := "(?,?,?),(?,?,?)"
+	for _, tc := range []struct {
+		name string
+		spec *ToSQLOpSpec
+		want string
+	}{
+		{
+			name: "insert",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t"},
+			want: "INSERT INTO t (_time,host,_value) VALUES (?,?,?),(?,?,?)",
+		},
+		{
+			name: "postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", UpsertKeys: []string{"_time", "host"}},
+			want: `INSERT INTO t ("_time","host","_value") VALUES (?,?,?),(?,?,?) ON CONFLICT ("_time","host") DO UPDATE SET "_value"=excluded."_value"`,
+		},
+		{
+			name: "postgres key in another case",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", UpsertKeys: []string{"_time", "Host"}},
+			want: `INSERT INTO t ("_time","host","_value") VALUES (?,?,?),(?,?,?) ON CONFLICT ("_time","host") DO UPDATE SET "_value"=excluded."_value"`,
+		},
+		{
+			name: "sqlite only keys",
+			spec: &ToSQLOpSpec{DriverName: "sqlite3", Table: "t", UpsertKeys: []string{"_time", "host", "_value"}},
+			want: `INSERT INTO t ("_time","host","_value") VALUES (?,?,?),(?,?,?) ON CONFLICT ("_time","host","_value") DO NOTHING`,
+		},
+		{
+			name: "mysql",
+			spec: &ToSQLOpSpec{DriverName: "mysql", Table: "t", UpsertKeys: []string{"_time"}},
+			want: "INSERT INTO t (`_time`,`host`,`_value`) VALUES (?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE `host`=VALUES(`host`),`_value`=VALUES(`_value`)",
+		},
+		{
+			name: "sqlserver",
+			spec: &ToSQLOpSpec{DriverName: "sqlserver", Table: "t", UpsertKeys: []string{"_time", "host"}},
+			want: "MERGE INTO t AS tgt USING (VALUES (?,?,?),(?,?,?)) AS src ([_time],[host],[_value]) ON tgt.[_time]=src.[_time] AND tgt.[host]=src.[host]" +
+				" WHEN MATCHED THEN UPDATE SET [_value]=src.[_value] WHEN NOT MATCHED THEN INSERT ([_time],[host],[_value]) VALUES (src.[_time],src.[host],src.[_value]);",
+		},
+		{
+			name: "snowflake",
+			spec: &ToSQLOpSpec{DriverName: "snowflake", Table: "t", UpsertKeys: []string{"host"}},
+			want: `MERGE INTO t AS tgt USING (SELECT column1 AS "_TIME",column2 AS "HOST",column3 AS "_VALUE" FROM VALUES (?,?,?),(?,?,?)) AS src ON tgt."HOST"=src."HOST"` +
+				` WHEN MATCHED THEN UPDATE SET "_TIME"=src."_TIME","_VALUE"=src."_VALUE" WHEN NOT MATCHED THEN INSERT ("_TIME","HOST","_VALUE") VALUES (src."_TIME",src."HOST",src."_VALUE")`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := insertQuery(tc.spec, colNames, values); !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected query -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestDedupeRows(t *testing.T) {
+	colNames := []string{"_time", "host", "_value"}
+	valueStrings := []string{"(?,?,?)", "(?,?,?)", "(?,?,?)", "(?,?,?)", "(?,?,?)"}
+	valueArgs := []interface{}{
+		int64(1), "a", 1.0,
+		int64(1), "b", 2.0,
+		int64(1), "a", 3.0,
+		int64(1), nil, 4.0,
+		int64(1), nil, 5.0,
+	}
+	gotStrings, gotArgs := dedupeRows(colNames, []string{"_time", "host"}, valueStrings, valueArgs)
+	wantArgs := []interface{}{
+		int64(1), "b", 2.0,
+		int64(1), "a", 3.0,
+		int64(1), nil, 4.0,
+		int64(1), nil, 5.0,
+	}
+	if !cmp.Equal(valueStrings[:4], gotStrings) {
+		t.Errorf("unexpected value strings -want/+got:\n%s", cmp.Diff(valueStrings[:4], gotStrings))
+	}
+	if !cmp.Equal(wantArgs, gotArgs) {
+		t.Errorf("unexpected arguments -want/+got:\n%s", cmp.Diff(wantArgs, gotArgs))
+	}
+}
+
+func TestCreateTableQuery(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "host", Type: flux.TString},
+	}
+	for _, tc := range []struct {
+		name    string
+		spec    *ToSQLOpSpec
+		colDefs []string
+		want    string
+	}{
+		{
+			name:    "postgres",
+			spec:    &ToSQLOpSpec{DriverName: "postgres", Table: "t"},
+			colDefs: []string{"_time TIMESTAMP", "host TEXT"},
+			want:    "CREATE TABLE IF NOT EXISTS t (_time TIMESTAMP,host TEXT)",
+		},
+		{
+			name:    "postgres with upsert keys",
+			spec:    &ToSQLOpSpec{DriverName: "postgres", Table: "t", UpsertKeys: []string{"_time", "host"}},
+			colDefs: []string{"_time TIMESTAMP", "host TEXT"},
+			want:    "CREATE TABLE IF NOT EXISTS t (_time TIMESTAMP,host TEXT,PRIMARY KEY (_time,host))",
+		},
+		{
+			name:    "mysql with upsert keys",
+			spec:    &ToSQLOpSpec{DriverName: "mysql", Table: "t", UpsertKeys: []string{"_time", "host"}},
+			colDefs: []string{"_time DATETIME", "host TEXT(16383)"},
+			want:    "CREATE TABLE IF NOT EXISTS t (_time DATETIME,host TEXT(16383),PRIMARY KEY (_time,host(255)))",
+		},
+		{
+			name:    "sqlserver with upsert keys",
+			spec:    &ToSQLOpSpec{DriverName: "sqlserver", Table: "t", UpsertKeys: []string{"host"}},
+			colDefs: []string{"_time DATETIMEOFFSET", "host VARCHAR(MAX)"},
+			want:    "IF OBJECT_ID('t', 'U') IS NULL BEGIN CREATE TABLE t (_time DATETIMEOFFSET,host VARCHAR(MAX)) END",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := createTableQuery(tc.spec, cols, tc.colDefs); !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected query -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestAddColumnQuery(t *testing.T) {
+	if got, want := addColumnQuery(&ToSQLOpSpec{DriverName: "postgres", Table: "t"}, "host TEXT"), "ALTER TABLE t ADD COLUMN host TEXT"; got != want {
+		t.Errorf("unexpected query: want=%q got=%q", want, got)
+	}
+	if got, want := addColumnQuery(&ToSQLOpSpec{DriverName: "mssql", Table: "t"}, "host VARCHAR(MAX)"), "ALTER TABLE t ADD host VARCHAR(MAX)"; got != want {
+		t.Errorf("unexpected query: want=%q got=%q", want, got)
+	}
+}
@@ -3,6 +3,7 @@ package universe
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/apache/arrow/go/arrow/bitutil"
 	arrowmem "github.com/apache/arrow/go/arrow/memory"
@@ -41,6 +42,7 @@ func init() {
 	execute.RegisterTransformation(FilterKind, createFilterTransformation)
 	plan.RegisterPhysicalRules(
 		RemoveTrivialFilterRule{},
+		plan.CostBased(SwapFiltersRule{}),
 	)
 }
 
@@ -84,7 +86,6 @@ func (s *FilterOpSpec) Kind() flux.OperationKind {
 }
 
 type FilterProcedureSpec struct {
-	plan.DefaultCost
 	Fn              interpreter.ResolvedFunction
 	KeepEmptyTables bool
 }
@@ -121,6 +122,155 @@ func (s *FilterProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
 }
 
+// Cost evaluates the predicate once per row and estimates
+// the number of rows that pass it from its selectivity.
+func (s *FilterProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := sumStatistics(inStats)
+	selectivity, cost := s.estimate()
+	n := float64(stats.Cardinality)
+	stats.Cardinality = int64(math.Round(n * selectivity))
+	return plan.Cost{CPU: int64(math.Round(n * cost))}, stats
+}
+
+// Estimates for the predicates of a filter. Comparisons have the
+// selectivities commonly assumed by query planners when nothing
+// is known about the distribution of the values.
+const (
+	equalSelectivity   = 0.1
+	rangeSelectivity   = 1.0 / 3
+	regexpSelectivity  = 0.25
+	defaultSelectivity = 1.0 / 3
+
+	// callCost is the cost of calling a function or matching
+	// a regular expression relative to a comparison.
+	callCost = 10
+)
+
+// estimate returns the fraction of the rows that are expected to pass
+// the predicate and the cost of evaluating the predicate for each row.
+func (s *FilterProcedureSpec) estimate() (selectivity, cost float64) {
+	if s.Fn.Fn == nil || s.Fn.Fn.Block == nil {
+		return defaultSelectivity, 1
+	}
+	expr, ok := s.Fn.Fn.GetFunctionBodyExpression()
+	if !ok {
+		return defaultSelectivity, 1
+	}
+	return estimatePredicate(expr)
+}
+
+func estimatePredicate(expr semantic.Expression) (selectivity, cost float64) {
+	switch e := expr.(type) {
+	case *semantic.BooleanLiteral:
+		if e.Value {
+			return 1, 0
+		}
+		return 0, 0
+	case *semantic.LogicalExpression:
+		ls, lc := estimatePredicate(e.Left)
+		rs, rc := estimatePredicate(e.Right)
+		// The right side is only evaluated when
+		// the left side does not decide the result.
+		if e.Operator == ast.AndOperator {
+			return ls * rs, lc + ls*rc
+		}
+		return ls + rs - ls*rs, lc + (1-ls)*rc
+	case *semantic.UnaryExpression:
+		if e.Operator == ast.NotOperator {
+			s, c := estimatePredicate(e.Argument)
+			return 1 - s, c + 1
+		}
+	case *semantic.BinaryExpression:
+		cost := 1 + operandCost(e.Left) + operandCost(e.Right)
+		switch e.Operator {
+		case ast.EqualOperator:
+			return equalSelectivity, cost
+		case ast.NotEqualOperator:
+			return 1 - equalSelectivity, cost
+		case ast.LessThanOperator, ast.LessThanEqualOperator,
+			ast.GreaterThanOperator, ast.GreaterThanEqualOperator:
+			return rangeSelectivity, cost
+		case ast.RegexpMatchOperator:
+			return regexpSelectivity, cost + callCost
+		case ast.NotRegexpMatchOperator:
+			return 1 - regexpSelectivity, cost + callCost
+		}
+		return defaultSelectivity, cost
+	case *semantic.CallExpression:
+		return defaultSelectivity, callCost
+	}
+	return defaultSelectivity, 1
+}
+
+// isTotal reports whether the predicate can be evaluated for any row
+// without failing. Predicates that call functions or compute values
+// from the columns may fail.
+func (s *FilterProcedureSpec) isTotal() bool {
+	if s.Fn.Fn == nil || s.Fn.Fn.Block == nil {
+		return false
+	}
+	expr, ok := s.Fn.Fn.GetFunctionBodyExpression()
+	if !ok {
+		return false
+	}
+	return isTotalPredicate(expr)
+}
+
+func isTotalPredicate(expr semantic.Expression) bool {
+	switch e := expr.(type) {
+	case *semantic.BooleanLiteral:
+		return true
+	case *semantic.LogicalExpression:
+		return isTotalPredicate(e.Left) && isTotalPredicate(e.Right)
+	case *semantic.UnaryExpression:
+		return e.Operator == ast.NotOperator && isTotalPredicate(e.Argument)
+	case *semantic.BinaryExpression:
+		switch e.Operator {
+		case ast.EqualOperator, ast.NotEqualOperator,
+			ast.LessThanOperator, ast.LessThanEqualOperator,
+			ast.GreaterThanOperator, ast.GreaterThanEqualOperator,
+			ast.RegexpMatchOperator, ast.NotRegexpMatchOperator:
+			return isTotalOperand(e.Left) && isTotalOperand(e.Right)
+		}
+	}
+	return false
+}
+
+// isTotalOperand reports whether the operand of a comparison is
+// a column of the row, an identifier or a literal.
+func isTotalOperand(expr semantic.Expression) bool {
+	switch e := expr.(type) {
+	case *semantic.MemberExpression:
+		_, ok := e.Object.(*semantic.IdentifierExpression)
+		return ok
+	case *semantic.IdentifierExpression,
+		*semantic.BooleanLiteral,
+		*semantic.DateTimeLiteral,
+		*semantic.DurationLiteral,
+		*semantic.FloatLiteral,
+		*semantic.IntegerLiteral,
+		*semantic.RegexpLiteral,
+		*semantic.StringLiteral,
+		*semantic.UnsignedIntegerLiteral:
+		return true
+	}
+	return false
+}
+
+// operandCost is the cost of computing an operand of a comparison.
+func operandCost(expr semantic.Expression) float64 {
+	if _, ok := expr.(*semantic.CallExpression); ok {
+		return callCost
+	}
+	return 0
+}
+
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+// Filtering rows never changes the group key of a table.
+func (s *FilterProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
 func createFilterTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*FilterProcedureSpec)
 	if !ok {
@@ -475,3 +625,52 @@ func (MergeFiltersRule) Rewrite(ctx context.Context, filterNode plan.Node) (plan
 	anyNode := filterNode.Predecessors()[0]
 	return anyNode, true, nil
 }
+
+// SwapFiltersRule moves a filter in front of the filter that precedes it
+// when its predicate is more selective. Fewer rows then reach the other
+// predicate, but all of the rows are evaluated with the more selective
+// predicate, so the swap only pays off when that predicate is not much
+// more expensive to evaluate.
+//
+// The first filter may guard the second one against rows that it would
+// fail to evaluate, such as a regular expression that checks that a
+// string can be converted with int. The filters are only swapped when
+// neither predicate can fail.
+type SwapFiltersRule struct{}
+
+func (SwapFiltersRule) Name() string {
+	return "SwapFiltersRule"
+}
+
+func (SwapFiltersRule) Pattern() plan.Pattern {
+	return plan.PhysPat(FilterKind, plan.PhysPat(FilterKind, plan.Any()))
+}
+
+func (SwapFiltersRule) Rewrite(ctx context.Context, filterNode plan.Node) (plan.Node, bool, error) {
+	predNode := filterNode.Predecessors()[0]
+	if len(predNode.Successors()) != 1 || len(predNode.Predecessors()) != 1 {
+		return filterNode, false, nil
+	}
+	filterSpec := filterNode.ProcedureSpec().(*FilterProcedureSpec)
+	predSpec := predNode.ProcedureSpec().(*FilterProcedureSpec)
+	// The filters drop the tables they empty in a different order
+	// when only one of them keeps empty tables.
+	if filterSpec.KeepEmptyTables != predSpec.KeepEmptyTables {
+		return filterNode, false, nil
+	}
+	if !filterSpec.isTotal() || !predSpec.isTotal() {
+		return filterNode, false, nil
+	}
+	// Only the more selective filter is moved first
+	// so the filters are never swapped back.
+	s, _ := filterSpec.estimate()
+	predS, _ := predSpec.estimate()
+	if s >= predS {
+		return filterNode, false, nil
+	}
+	swapped, err := plan.SwapPlanNodes(filterNode, predNode)
+	if err != nil {
+		return nil, false, err
+	}
+	return swapped, true, nil
+}
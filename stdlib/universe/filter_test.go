@@ -926,6 +926,179 @@ func TestFilter_MergeFilterRule(t *testing.T) {
 	}
 }
 
+func TestSwapFiltersRule(t *testing.T) {
+	rows := make([][]interface{}, 90)
+	for i := range rows {
+		rows[i] = []interface{}{float64(i)}
+	}
+	var (
+		from = executetest.NewFromProcedureSpec([]*executetest.Table{{
+			ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TFloat}},
+			Data:    rows,
+		}})
+		filter = func(fn string, keepEmpty bool) *universe.FilterProcedureSpec {
+			return &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn: executetest.FunctionExpression(t, fn),
+				},
+				KeepEmptyTables: keepEmpty,
+			}
+		}
+		greater = func() *universe.FilterProcedureSpec { return filter(`(r) => r._value > 0.0`, false) }
+		equal   = func() *universe.FilterProcedureSpec { return filter(`(r) => r._value == 1.0`, false) }
+		regexp  = func() *universe.FilterProcedureSpec { return filter(`(r) => r.host =~ /^server/`, false) }
+	)
+	tests := []plantest.RuleTestCase{
+		{
+			// 90 + 30 rows are evaluated before and 90 + 9 after.
+			Name:  "more selective",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter0", greater()),
+					plan.CreatePhysicalNode("filter1", equal()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter1_copy", equal()),
+					plan.CreatePhysicalNode("filter0", greater()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			// The regular expression is more selective, but matching it against
+			// every row costs more than comparing the rows that it would remove.
+			Name:  "more selective but more expensive",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter0", greater()),
+					plan.CreatePhysicalNode("filter1", regexp()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "less selective",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter0", equal()),
+					plan.CreatePhysicalNode("filter1", greater()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			// The first filter guards the conversion in the second one,
+			// which would fail on the rows that the first filter removes.
+			Name:  "guard",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter0", filter(`(r) => r._value =~ /^[0-9]+$/`, false)),
+					plan.CreatePhysicalNode("filter1", filter(`(r) => int(v: r._value) == 5`, false)),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			// Without statistics the swap does not lower
+			// the estimated cost so the plan is kept.
+			Name:  "no statistics",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &influxdb.FromProcedureSpec{}),
+					plan.CreatePhysicalNode("filter0", greater()),
+					plan.CreatePhysicalNode("filter1", equal()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+		{
+			Name:  "different onEmpty",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapFiltersRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter0", filter(`(r) => r._value > 0.0`, true)),
+					plan.CreatePhysicalNode("filter1", equal()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestFilterProcedureSpec_Cost(t *testing.T) {
+	testCases := []struct {
+		fn    string
+		cost  plan.Cost
+		stats plan.Statistics
+	}{
+		{
+			fn:    `(r) => r._value > 0.0`,
+			cost:  plan.Cost{CPU: 90},
+			stats: plan.Statistics{Cardinality: 30, GroupCardinality: 3},
+		},
+		{
+			// The regular expression is only matched
+			// against the rows that pass the comparison.
+			fn:    `(r) => r._value == 1.0 and r.host =~ /^server/`,
+			cost:  plan.Cost{CPU: 189},
+			stats: plan.Statistics{Cardinality: 2, GroupCardinality: 3},
+		},
+		{
+			fn:    `(r) => r._value == 1.0 or r._value == 2.0`,
+			cost:  plan.Cost{CPU: 171},
+			stats: plan.Statistics{Cardinality: 17, GroupCardinality: 3},
+		},
+		{
+			fn:    `(r) => true`,
+			cost:  plan.Cost{},
+			stats: plan.Statistics{Cardinality: 90, GroupCardinality: 3},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.fn, func(t *testing.T) {
+			spec := &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn: executetest.FunctionExpression(t, tc.fn),
+				},
+			}
+			cost, stats := spec.Cost([]plan.Statistics{{Cardinality: 90, GroupCardinality: 3}})
+			if cost != tc.cost {
+				t.Errorf("unexpected cost -want/+got:\n\t- %v\n\t+ %v", tc.cost, cost)
+			}
+			if stats != tc.stats {
+				t.Errorf("unexpected statistics -want/+got:\n\t- %v\n\t+ %v", tc.stats, stats)
+			}
+		})
+	}
+}
+
 func BenchmarkFilter_Values(b *testing.B) {
 	b.Run("1000", func(b *testing.B) {
 		fn := executetest.FunctionExpression(b, `(r) => r._value > 0.0`)
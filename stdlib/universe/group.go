@@ -189,7 +189,7 @@ func NewGroupTransformation(ctx context.Context, spec *GroupProcedureSpec, id ex
 	t := &groupTransformation{
 		cache: table.BuilderCache{
 			New: func(key flux.GroupKey) table.Builder {
-				return table.NewBufferedBuilder(key, mem)
+				return table.NewSpillableBufferedBuilder(key, mem)
 			},
 		},
 		mem:  mem,
@@ -440,6 +440,22 @@ func (t *groupTransformation) appendValueFromRow(b array.Builder, cr flux.ColRea
 		} else {
 			b.Append(vs.Value(i))
 		}
+	case flux.TDuration:
+		b := b.(*array.IntBuilder)
+		vs := cr.Durations(j)
+		if vs.IsNull(i) {
+			b.AppendNull()
+		} else {
+			b.Append(vs.Value(i))
+		}
+	case flux.TBytes:
+		b := b.(*array.BinaryBuilder)
+		vs := cr.Bytes(j)
+		if vs.IsNull(i) {
+			b.AppendNull()
+		} else {
+			b.Append(vs.Value(i))
+		}
 	default:
 		return errors.New(codes.Internal, "invalid builder type")
 	}
@@ -284,6 +284,9 @@ func (b linearBins) Time() values.Time {
 func (b linearBins) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Duration, semantic.Function))
 }
+func (b linearBins) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 
 func (b linearBins) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Regexp, semantic.Function))
@@ -410,6 +413,9 @@ func (b logarithmicBins) Time() values.Time {
 func (b logarithmicBins) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Duration, semantic.Function))
 }
+func (b logarithmicBins) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 
 func (b logarithmicBins) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Regexp, semantic.Function))
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/table"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
@@ -33,6 +34,9 @@ func init() {
 // All supported join types in Flux
 var methods = map[string]bool{
 	"inner": true,
+	"left":  true,
+	"right": true,
+	"full":  true,
 }
 
 // JoinOpSpec specifies a particular join operation
@@ -163,6 +167,7 @@ type MergeJoinProcedureSpec struct {
 	plan.DefaultCost
 	TableNames []string `json:"table_names"`
 	On         []string `json:"keys"`
+	Method     string   `json:"method"`
 }
 
 func newMergeJoinProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -189,6 +194,7 @@ func newMergeJoinProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.
 	return &MergeJoinProcedureSpec{
 		On:         on,
 		TableNames: tableNames,
+		Method:     spec.Method,
 	}, nil
 }
 
@@ -198,9 +204,13 @@ func (s *MergeJoinProcedureSpec) Kind() plan.ProcedureKind {
 func (s *MergeJoinProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(MergeJoinProcedureSpec)
 
+	ns.TableNames = make([]string, len(s.TableNames))
+	copy(ns.TableNames, s.TableNames)
+
 	ns.On = make([]string, len(s.On))
 	copy(ns.On, s.On)
 
+	ns.Method = s.Method
 	return ns
 }
 
@@ -252,6 +262,7 @@ func NewMergeJoinTransformation(d execute.Dataset, cache *MergeJoinCache, spec *
 		leftName:  tableNames[parents[0]],
 		rightName: tableNames[parents[1]],
 	}
+	cache.method = spec.Method
 	t.parentState = make(map[execute.DatasetID]*mergeJoinParentState)
 	for _, id := range parents {
 		t.parentState[id] = new(mergeJoinParentState)
@@ -286,6 +297,11 @@ func (t *mergeJoinTransformation) Process(id execute.DatasetID, tbl flux.Table)
 		}
 	}
 	if numOnCols < len(t.cache.on) {
+		// Tables from a stream whose rows are preserved by an
+		// outer join are kept and output with null-filled columns.
+		if t.cache.preserves(id) {
+			return t.cache.buffers[id].hold(tbl)
+		}
 		// Discard this table
 		tbl.Done()
 		return nil
@@ -351,7 +367,11 @@ func (t *mergeJoinTransformation) Finish(id execute.DatasetID, err error) {
 	}
 
 	if finished {
+		if err := t.cache.finish(); err != nil && t.err == nil {
+			t.err = err
+		}
 		t.d.Finish(t.err)
+		t.cache.release()
 	}
 }
 
@@ -372,6 +392,12 @@ func (t *mergeJoinTransformation) Finish(id execute.DatasetID, err error) {
 //
 // tables:          All output tables are materialized and stored in this
 //                  map before being sent to downstream operators.
+//
+// For the left, right and full outer join methods, output is held back
+// until both input streams have finished. Only then is it known which rows
+// did not match any row from the opposing stream. Those rows are output
+// with nulls in the columns of the opposing stream, in a table whose group
+// key is built from the group key of their own input table.
 type MergeJoinCache struct {
 	leftID  execute.DatasetID
 	rightID execute.DatasetID
@@ -394,20 +420,36 @@ type MergeJoinCache struct {
 	tables      map[flux.GroupKey]flux.Table
 	alloc       *memory.Allocator
 	triggerSpec plan.TriggerSpec
+
+	method   string
+	finished bool
 }
 
 type streamBuffer struct {
 	data     map[flux.GroupKey]*execute.ColListTableBuilder
+	matched  map[flux.GroupKey][]bool
+	held     []*table.BufferedBuilder
 	consumed map[values.Value]int
 	ready    map[values.Value]bool
 	stale    map[flux.GroupKey]bool
 	last     values.Value
 	alloc    *memory.Allocator
+
+	// pending holds the tables that have not been joined yet.
+	// They are buffered in builders that can be spilled to disk
+	// when the memory limit is reached and are moved to data
+	// when the table is first needed for a join.
+	pending map[flux.GroupKey]*table.BufferedBuilder
+	// err is set when a pending table could not be read back.
+	// It is returned for any table that is requested after.
+	err error
 }
 
 func newStreamBuffer(alloc *memory.Allocator) *streamBuffer {
 	return &streamBuffer{
 		data:     make(map[flux.GroupKey]*execute.ColListTableBuilder),
+		pending:  make(map[flux.GroupKey]*table.BufferedBuilder),
+		matched:  make(map[flux.GroupKey][]bool),
 		consumed: make(map[values.Value]int),
 		ready:    make(map[values.Value]bool),
 		stale:    make(map[flux.GroupKey]bool),
@@ -415,29 +457,79 @@ func newStreamBuffer(alloc *memory.Allocator) *streamBuffer {
 	}
 }
 
-func (buf *streamBuffer) table(key flux.GroupKey) *execute.ColListTableBuilder {
-	return buf.data[key]
+// table returns the builder for the table with the given key.
+// A pending table is read back into memory the first time it is needed.
+func (buf *streamBuffer) table(key flux.GroupKey) (*execute.ColListTableBuilder, error) {
+	if buf.err != nil {
+		return nil, buf.err
+	}
+	if builder, ok := buf.data[key]; ok {
+		return builder, nil
+	}
+	b, ok := buf.pending[key]
+	if !ok {
+		return nil, nil
+	}
+	delete(buf.pending, key)
+	builder, err := buf.load(b)
+	if err != nil {
+		buf.err = err
+		return nil, err
+	}
+	buf.data[key] = builder
+	return builder, nil
 }
 
-func (buf *streamBuffer) insert(table flux.Table) error {
+func (buf *streamBuffer) copy(tbl flux.Table) (*execute.ColListTableBuilder, error) {
 	// Construct a new table builder with same schema as input table
-	builder := execute.NewColListTableBuilder(table.Key(), buf.alloc)
+	builder := execute.NewColListTableBuilder(tbl.Key(), buf.alloc)
 	// this will only error if we try to add a duplicate column to the builder.
 	// since this is a new table, that won't happen.
-	if err := execute.AddTableCols(table, builder); err != nil {
-		return err
+	if err := execute.AddTableCols(tbl, builder); err != nil {
+		return nil, err
 	}
 
 	// Append the input table to this builder, safe to ignore errors
-	if err := execute.AppendTable(table, builder); err != nil {
+	if err := execute.AppendTable(tbl, builder); err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+// buffer stores the table in a builder that will be
+// spilled to disk if the memory limit is reached.
+func (buf *streamBuffer) buffer(tbl flux.Table) (*table.BufferedBuilder, error) {
+	b := table.NewSpillableBufferedBuilder(tbl.Key(), buf.alloc)
+	if err := b.AppendTable(tbl); err != nil {
+		b.Release()
+		return nil, err
+	}
+	return b, nil
+}
+
+// load copies the table stored by buffer into a new builder.
+func (buf *streamBuffer) load(b *table.BufferedBuilder) (*execute.ColListTableBuilder, error) {
+	tbl, err := b.Table()
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	defer tbl.Done()
+	return buf.copy(tbl)
+}
+
+func (buf *streamBuffer) insert(tbl flux.Table) error {
+	b, err := buf.buffer(tbl)
+	if err != nil {
 		return err
 	}
 
 	// Insert this table into the buffer
-	buf.data[table.Key()] = builder
+	buf.evict(tbl.Key())
+	buf.pending[tbl.Key()] = b
 
-	if len(table.Key().Cols()) > 0 {
-		leftKeyValue := table.Key().Value(0)
+	if len(tbl.Key().Cols()) > 0 {
+		leftKeyValue := tbl.Key().Value(0)
 
 		tablesConsumed := buf.consumed[leftKeyValue]
 		buf.consumed[leftKeyValue] = tablesConsumed + 1
@@ -454,7 +546,33 @@ func (buf *streamBuffer) insert(table flux.Table) error {
 	return nil
 }
 
+// hold stores a table that cannot be joined with any table
+// from the opposing stream because it has nulls or is missing
+// one of the join columns. Its rows are only output by outer joins.
+func (buf *streamBuffer) hold(tbl flux.Table) error {
+	b, err := buf.buffer(tbl)
+	if err != nil {
+		return err
+	}
+	buf.held = append(buf.held, b)
+	return nil
+}
+
+// match marks a row of the table with the given key
+// as having been joined with a row from the opposing stream.
+func (buf *streamBuffer) match(key flux.GroupKey, row int) {
+	matched, ok := buf.matched[key]
+	if !ok {
+		matched = make([]bool, buf.data[key].NRows())
+		buf.matched[key] = matched
+	}
+	matched[row] = true
+}
+
 func (buf *streamBuffer) expire(key flux.GroupKey) {
+	if key == nil {
+		return
+	}
 	if !buf.stale[key] && len(key.Cols()) > 0 {
 		leftKeyValue := key.Value(0)
 		consumedTables := buf.consumed[leftKeyValue]
@@ -467,9 +585,29 @@ func (buf *streamBuffer) evict(key flux.GroupKey) {
 	if builder, ok := buf.data[key]; ok {
 		builder.ClearData()
 		delete(buf.data, key)
+		delete(buf.matched, key)
+	}
+	if b, ok := buf.pending[key]; ok {
+		b.Release()
+		delete(buf.pending, key)
 	}
 }
 
+// release releases the pending tables that are not needed
+// so that any data that was spilled to disk is removed.
+func (buf *streamBuffer) release(needed map[flux.GroupKey]bool) {
+	for key, b := range buf.pending {
+		if !needed[key] {
+			b.Release()
+			delete(buf.pending, key)
+		}
+	}
+	for _, b := range buf.held {
+		b.Release()
+	}
+	buf.held = nil
+}
+
 func (buf *streamBuffer) clear(f func(flux.GroupKey) bool) {
 	for key := range buf.stale {
 		if f(key) {
@@ -483,6 +621,13 @@ func (buf *streamBuffer) iterate(f func(flux.GroupKey)) {
 	for key := range buf.data {
 		f(key)
 	}
+	for key := range buf.pending {
+		f(key)
+	}
+}
+
+func (buf *streamBuffer) len() int {
+	return len(buf.data) + len(buf.pending)
 }
 
 type tableCol struct {
@@ -560,13 +705,17 @@ func (c *MergeJoinCache) Table(key flux.GroupKey) (flux.Table, error) {
 
 	if _, ok := c.tables[key]; !ok {
 
-		left := c.buffers[c.leftID].table(preJoinGroupKeys.left)
-		if left == nil {
+		left, err := c.buffers[c.leftID].table(preJoinGroupKeys.left)
+		if err != nil {
+			return nil, err
+		} else if left == nil {
 			return nil, errors.Newf(codes.FailedPrecondition, "no table in left join buffer with key: %v", key)
 		}
 
-		right := c.buffers[c.rightID].table(preJoinGroupKeys.right)
-		if right == nil {
+		right, err := c.buffers[c.rightID].table(preJoinGroupKeys.right)
+		if err != nil {
+			return nil, err
+		} else if right == nil {
 			return nil, errors.Newf(codes.FailedPrecondition, "no table in right join buffer with key: %v", key)
 		}
 
@@ -582,6 +731,9 @@ func (c *MergeJoinCache) Table(key flux.GroupKey) (flux.Table, error) {
 
 // ForEach iterates over each table in the output stream
 func (c *MergeJoinCache) ForEach(f func(flux.GroupKey)) {
+	if c.isOuter() && !c.finished {
+		return
+	}
 	c.postJoinKeys.Range(func(key flux.GroupKey, value interface{}) {
 
 		if _, ok := c.tables[key]; !ok {
@@ -591,8 +743,12 @@ func (c *MergeJoinCache) ForEach(f func(flux.GroupKey)) {
 			leftKey := preJoinGroupKeys.left
 			rightKey := preJoinGroupKeys.right
 
-			leftBuilder := c.buffers[c.leftID].table(leftKey)
-			rightBuilder := c.buffers[c.rightID].table(rightKey)
+			leftBuilder, rightBuilder, err := c.builders(leftKey, rightKey)
+			if err != nil {
+				// The error is reported when the table is requested.
+				f(key)
+				return
+			}
 
 			table, err := c.join(leftBuilder, rightBuilder)
 			if err != nil || table.Empty() {
@@ -608,6 +764,9 @@ func (c *MergeJoinCache) ForEach(f func(flux.GroupKey)) {
 
 // ForEachWithContext iterates over each table in the output stream
 func (c *MergeJoinCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigger, execute.TableContext)) {
+	if c.isOuter() && !c.finished {
+		return
+	}
 	trigger := execute.NewTriggerFromSpec(c.triggerSpec)
 
 	c.postJoinKeys.Range(func(key flux.GroupKey, value interface{}) {
@@ -617,8 +776,12 @@ func (c *MergeJoinCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigge
 		leftKey := preJoinGroupKeys.left
 		rightKey := preJoinGroupKeys.right
 
-		leftBuilder := c.buffers[c.leftID].table(leftKey)
-		rightBuilder := c.buffers[c.rightID].table(rightKey)
+		leftBuilder, rightBuilder, err := c.builders(leftKey, rightKey)
+		if err != nil {
+			// The error is reported when the table is requested.
+			f(key, trigger, execute.TableContext{Key: key})
+			return
+		}
 
 		if _, ok := c.tables[key]; !ok {
 
@@ -632,18 +795,34 @@ func (c *MergeJoinCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigge
 			c.tables[key] = table
 		}
 
-		leftsize := leftBuilder.NRows()
-		rightsize := rightBuilder.NRows()
+		var count int
+		for _, builder := range []*execute.ColListTableBuilder{leftBuilder, rightBuilder} {
+			if builder != nil {
+				count += builder.NRows()
+			}
+		}
 
 		ctx := execute.TableContext{
 			Key:   key,
-			Count: leftsize + rightsize,
+			Count: count,
 		}
 
 		f(key, trigger, ctx)
 	})
 }
 
+// builders returns the builders for the tables with the
+// given keys from the left and right buffers.
+func (c *MergeJoinCache) builders(leftKey, rightKey flux.GroupKey) (left, right *execute.ColListTableBuilder, err error) {
+	if left, err = c.buffers[c.leftID].table(leftKey); err != nil {
+		return nil, nil, err
+	}
+	if right, err = c.buffers[c.rightID].table(rightKey); err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
 // DiscardTable removes a table from the output buffer
 func (c *MergeJoinCache) DiscardTable(key flux.GroupKey) {
 	delete(c.tables, key)
@@ -689,6 +868,10 @@ func (c *MergeJoinCache) SetTriggerSpec(spec plan.TriggerSpec) {
 // Currently tables are the smallest unit of data that can be evicted from the join's internal
 // buffers. This is the rule that specifies whether a data cache can early evict tables.
 func (c *MergeJoinCache) canEvictTables() bool {
+	// Outer joins need every input table to find the rows that did not match.
+	if c.isOuter() {
+		return false
+	}
 	leftKey := c.schemas[c.leftID].key
 	rightKey := c.schemas[c.rightID].key
 	return len(leftKey) > 0 && len(rightKey) > 0 &&
@@ -727,6 +910,9 @@ func (c *MergeJoinCache) insertIntoBuffer(id execute.DatasetID, tbl flux.Table)
 	for j, col := range k.Cols() {
 		if c.on[col.Label] {
 			if k.IsNull(j) {
+				if c.preserves(id) {
+					return c.buffers[id].hold(tbl)
+				}
 				// Discard the table and return.  Note: we need to iterate over the
 				// table at least once:
 				// https://github.com/influxdata/flux/issues/643
@@ -797,8 +983,213 @@ func (c *MergeJoinCache) registerKey(id execute.DatasetID, key flux.GroupKey) {
 	}
 }
 
+// preserves reports whether the join method keeps the rows
+// from the stream with the given id that do not match any row
+// from the opposing stream.
+func (c *MergeJoinCache) preserves(id execute.DatasetID) bool {
+	switch c.method {
+	case "left":
+		return id == c.leftID
+	case "right":
+		return id == c.rightID
+	case "full":
+		return true
+	}
+	return false
+}
+
+// release releases the buffered tables that are not needed
+// for any of the output tables that are left once the join
+// has finished.
+func (c *MergeJoinCache) release() {
+	needed := make(map[flux.GroupKey]bool)
+	c.postJoinKeys.Range(func(key flux.GroupKey, value interface{}) {
+		preJoinGroupKeys := c.reverseLookup[key]
+		needed[preJoinGroupKeys.left] = true
+		needed[preJoinGroupKeys.right] = true
+	})
+	for _, buf := range c.buffers {
+		buf.release(needed)
+	}
+}
+
+func (c *MergeJoinCache) isOuter() bool {
+	return c.preserves(c.leftID) || c.preserves(c.rightID)
+}
+
+// finish is called once both input streams have finished.
+// For outer joins, all output tables are materialized at this point,
+// including the tables that hold the rows that were not matched.
+func (c *MergeJoinCache) finish() error {
+	c.finished = true
+	if !c.isOuter() {
+		return nil
+	}
+	if !c.postJoinSchemaBuilt() {
+		c.buildPostJoinSchema()
+	}
+
+	builders := execute.NewGroupLookup()
+	var err error
+	c.postJoinKeys.Range(func(key flux.GroupKey, value interface{}) {
+		if err != nil {
+			return
+		}
+		preJoinGroupKeys := c.reverseLookup[key]
+		var left, right *execute.ColListTableBuilder
+		if left, right, err = c.builders(preJoinGroupKeys.left, preJoinGroupKeys.right); err != nil {
+			return
+		}
+
+		var builder *execute.ColListTableBuilder
+		if builder, err = c.joinBuilder(left, right); err != nil {
+			return
+		}
+		builders.Set(key, builder)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range []execute.DatasetID{c.leftID, c.rightID} {
+		if !c.preserves(id) {
+			continue
+		}
+		buf := c.buffers[id]
+		keys := make([]flux.GroupKey, 0, buf.len())
+		buf.iterate(func(key flux.GroupKey) {
+			keys = append(keys, key)
+		})
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].Less(keys[j])
+		})
+		for _, key := range keys {
+			builder, err := buf.table(key)
+			if err != nil {
+				return err
+			}
+			if err := c.appendUnmatched(builders, id, builder, buf.matched[key]); err != nil {
+				return err
+			}
+		}
+		for _, b := range buf.held {
+			builder, err := buf.load(b)
+			if err != nil {
+				return err
+			}
+			err = c.appendUnmatched(builders, id, builder, nil)
+			builder.Release()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var empty struct{}
+	builders.Range(func(key flux.GroupKey, value interface{}) {
+		if err != nil {
+			return
+		}
+		builder := value.(*execute.ColListTableBuilder)
+		if builder.NRows() == 0 {
+			c.postJoinKeys.Delete(key)
+			return
+		}
+		// Unmatched rows were appended after the joined rows.
+		builder.Sort(c.order, false)
+
+		var table flux.Table
+		if table, err = builder.Table(); err != nil {
+			return
+		}
+		c.postJoinKeys.Set(key, empty)
+		c.tables[key] = table
+	})
+	return err
+}
+
+// appendUnmatched appends the rows of a table from the stream with the
+// given id that were not matched to the output table for its group key.
+// The columns from the opposing stream are filled with nulls.
+func (c *MergeJoinCache) appendUnmatched(builders *execute.GroupLookup, id execute.DatasetID, table *execute.ColListTableBuilder, matched []bool) error {
+	n := table.NRows()
+	if n == 0 || len(matched) == n && allTrue(matched) {
+		return nil
+	}
+
+	key := c.postJoinGroupKey(map[execute.DatasetID]flux.GroupKey{id: table.Key()})
+	var builder *execute.ColListTableBuilder
+	if value, ok := builders.Lookup(key); ok {
+		builder = value.(*execute.ColListTableBuilder)
+	} else {
+		builder = execute.NewColListTableBuilder(key, c.alloc)
+		for _, column := range c.schema.columns {
+			if _, err := builder.AddCol(column); err != nil {
+				return err
+			}
+		}
+		builders.Set(key, builder)
+		if _, ok := c.reverseLookup[key]; !ok {
+			var preJoinGroupKeys preJoinGroupKeys
+			if id == c.leftID {
+				preJoinGroupKeys.left = table.Key()
+			} else {
+				preJoinGroupKeys.right = table.Key()
+			}
+			c.reverseLookup[key] = preJoinGroupKeys
+		}
+	}
+
+	appended := make([]bool, len(c.schema.columns))
+	for i := 0; i < n; i++ {
+		if i < len(matched) && matched[i] {
+			continue
+		}
+		for j := range appended {
+			appended[j] = false
+		}
+
+		var err error
+		table.GetRow(i).Range(func(columnName string, columnVal values.Value) {
+			if err != nil {
+				return
+			}
+			newColumn, ok := c.schemaMap[tableCol{
+				table: c.names[id],
+				col:   columnName,
+			}]
+			if !ok {
+				return
+			}
+			newColumnIdx := c.colIndex[newColumn]
+			err = builder.AppendValue(newColumnIdx, columnVal)
+			appended[newColumnIdx] = true
+		})
+		if err != nil {
+			return err
+		}
+		for j, ok := range appended {
+			if !ok {
+				if err := builder.AppendNil(j); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func allTrue(vs []bool) bool {
+	for _, v := range vs {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *MergeJoinCache) isBufferEmpty(id execute.DatasetID) bool {
-	return len(c.buffers[id].data) == 0
+	return c.buffers[id].len() == 0
 }
 
 func (c *MergeJoinCache) postJoinSchemaBuilt() bool {
@@ -897,6 +1288,16 @@ func (c *MergeJoinCache) missingColIdx(lCols, rCols []flux.ColMeta) []int {
 }
 
 func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Table, error) {
+	builder, err := c.joinBuilder(left, right)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Table()
+}
+
+// joinBuilder performs a sort merge join of two tables
+// and returns the builder with the joined rows.
+func (c *MergeJoinCache) joinBuilder(left, right *execute.ColListTableBuilder) (*execute.ColListTableBuilder, error) {
 	// Sort input tables
 	left.Sort(c.order, false)
 	right.Sort(c.order, false)
@@ -967,6 +1368,19 @@ func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Ta
 					}
 				}
 			}
+
+			// Keep track of matched rows so outer joins
+			// can find the rows that were not matched.
+			if c.preserves(c.leftID) {
+				for l := leftSet.Start; l < leftSet.Stop; l++ {
+					c.buffers[c.leftID].match(left.Key(), l)
+				}
+			}
+			if c.preserves(c.rightID) {
+				for r := rightSet.Start; r < rightSet.Stop; r++ {
+					c.buffers[c.rightID].match(right.Key(), r)
+				}
+			}
 			leftSet, leftKey = c.advance(leftSet.Stop, left)
 			rightSet, rightKey = c.advance(rightSet.Stop, right)
 		} else if leftKey.Less(rightKey) {
@@ -976,7 +1390,7 @@ func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Ta
 		}
 	}
 
-	return builder.Table()
+	return builder, nil
 }
 
 // postJoinGroupKey produces a new group key value from a left and a right group key value
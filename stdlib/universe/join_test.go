@@ -2,6 +2,7 @@ package universe_test
 
 import (
 	"errors"
+	"math"
 	"sort"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
@@ -171,6 +173,15 @@ func TestJoin_NewQuery(t *testing.T) {
 			`,
 			WantErr: true,
 		},
+		{
+			Name: "invalid method",
+			Raw: `
+				a = from(bucket:"flux") |> range(start:-1h)
+				b = from(bucket:"flux") |> range(start:-1h)
+				join(tables:{a:a,b:b}, on: ["_time"], method: "outer")
+			`,
+			WantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -1624,6 +1635,371 @@ func TestMergeJoin_Process(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "left outer",
+			spec: &universe.MergeJoinProcedureSpec{
+				On:         []string{"_time", "tag"},
+				TableNames: tableNames,
+				Method:     "left",
+			},
+			data0: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, "x"},
+						{execute.Time(2), 2.0, "x"},
+						{execute.Time(3), 3.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 4.0, "y"},
+					},
+				},
+			},
+			data1: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 20.0, "x"},
+						{execute.Time(4), 40.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 50.0, "z"},
+					},
+				},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, nil, "x"},
+						{execute.Time(2), 2.0, 20.0, "x"},
+						{execute.Time(3), 3.0, nil, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 4.0, nil, "y"},
+					},
+				},
+			},
+		},
+		{
+			name: "right outer",
+			spec: &universe.MergeJoinProcedureSpec{
+				On:         []string{"_time", "tag"},
+				TableNames: tableNames,
+				Method:     "right",
+			},
+			data0: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, "x"},
+						{execute.Time(2), 2.0, "x"},
+						{execute.Time(3), 3.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 4.0, "y"},
+					},
+				},
+			},
+			data1: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 20.0, "x"},
+						{execute.Time(4), 40.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 50.0, "z"},
+					},
+				},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 2.0, 20.0, "x"},
+						{execute.Time(4), nil, 40.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), nil, 50.0, "z"},
+					},
+				},
+			},
+		},
+		{
+			name: "full outer",
+			spec: &universe.MergeJoinProcedureSpec{
+				On:         []string{"_time", "tag"},
+				TableNames: tableNames,
+				Method:     "full",
+			},
+			data0: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, "x"},
+						{execute.Time(2), 2.0, "x"},
+						{execute.Time(3), 3.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 4.0, "y"},
+					},
+				},
+			},
+			data1: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 20.0, "x"},
+						{execute.Time(4), 40.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 50.0, "z"},
+					},
+				},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, nil, "x"},
+						{execute.Time(2), 2.0, 20.0, "x"},
+						{execute.Time(3), 3.0, nil, "x"},
+						{execute.Time(4), nil, 40.0, "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 4.0, nil, "y"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), nil, 50.0, "z"},
+					},
+				},
+			},
+		},
+		{
+			name: "full outer with different group keys",
+			spec: &universe.MergeJoinProcedureSpec{
+				On:         []string{"_time", "tag"},
+				TableNames: tableNames,
+				Method:     "full",
+			},
+			data0: []*executetest.Table{
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, "x"},
+						{execute.Time(2), 2.0, "x"},
+						{execute.Time(3), 3.0, "x"},
+					},
+				},
+			},
+			data1: []*executetest.Table{
+				{
+					KeyCols: []string{"host", "tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 10.0, "A", "x"},
+					},
+				},
+				{
+					KeyCols: []string{"host", "tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 20.0, "B", "x"},
+						{execute.Time(5), 50.0, "B", "x"},
+					},
+				},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"host", "tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 1.0, 10.0, "A", "x"},
+					},
+				},
+				{
+					KeyCols: []string{"host", "tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(2), 2.0, 20.0, "B", "x"},
+						{execute.Time(5), nil, 50.0, "B", "x"},
+					},
+				},
+				{
+					KeyCols: []string{"tag"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value_a", Type: flux.TFloat},
+						{Label: "_value_b", Type: flux.TFloat},
+						{Label: "host", Type: flux.TString},
+						{Label: "tag", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(3), 3.0, nil, nil, "x"},
+					},
+				},
+			},
+		},
 		{
 			name: "two failures",
 			spec: &universe.MergeJoinProcedureSpec{
@@ -1708,3 +2084,90 @@ func TestMergeJoin_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeJoin_Spill(t *testing.T) {
+	// The limit only leaves room for one of the input tables
+	// so the table from the left stream is spilled to disk
+	// when the table from the right stream is processed.
+	limit := int64(512)
+	alloc := &memory.Allocator{Limit: &limit, SpillDir: t.TempDir()}
+
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+	}
+	left := &executetest.Table{
+		ColMeta: cols,
+		Data: [][]interface{}{
+			{execute.Time(1), 1.0},
+			{execute.Time(2), 2.0},
+			{execute.Time(3), 3.0},
+		},
+		Alloc: alloc,
+	}
+	right := &executetest.Table{
+		ColMeta: cols,
+		Data: [][]interface{}{
+			{execute.Time(1), 10.0},
+			{execute.Time(2), 20.0},
+			{execute.Time(4), 40.0},
+		},
+		Alloc: alloc,
+	}
+	want := []*executetest.Table{
+		{
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value_a", Type: flux.TFloat},
+				{Label: "_value_b", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 1.0, 10.0},
+				{execute.Time(2), 2.0, 20.0},
+			},
+		},
+	}
+
+	spec := &universe.MergeJoinProcedureSpec{
+		On:         []string{"_time"},
+		TableNames: []string{"a", "b"},
+	}
+	parents := []execute.DatasetID{
+		executetest.RandomDatasetID(),
+		executetest.RandomDatasetID(),
+	}
+	tableNames := map[execute.DatasetID]string{
+		parents[0]: "a",
+		parents[1]: "b",
+	}
+
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	c := universe.NewMergeJoinCache(alloc, parents, tableNames, spec.On)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	jt := universe.NewMergeJoinTransformation(d, c, spec, parents, tableNames)
+
+	if err := jt.Process(parents[0], left); err != nil {
+		t.Fatal(err)
+	}
+	if err := jt.Process(parents[1], right); err != nil {
+		t.Fatal(err)
+	}
+	if alloc.SpillCount() == 0 {
+		t.Fatal("expected the left table to be spilled to disk")
+	}
+
+	// Allow the spilled table to be read back to join it.
+	limit = math.MaxInt64
+	jt.Finish(parents[0], nil)
+	jt.Finish(parents[1], nil)
+
+	got, err := executetest.TablesFromCache(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executetest.NormalizeTables(got)
+	executetest.NormalizeTables(want)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
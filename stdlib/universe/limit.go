@@ -63,7 +63,6 @@ func (s *LimitOpSpec) Kind() flux.OperationKind {
 }
 
 type LimitProcedureSpec struct {
-	plan.DefaultCost
 	N      int64 `json:"n"`
 	Offset int64 `json:"offset"`
 }
@@ -93,6 +92,24 @@ func (s *LimitProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
 }
 
+// Cost estimates that at most N rows are read from each table.
+func (s *LimitProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := sumStatistics(inStats)
+	groups := stats.GroupCardinality
+	if groups == 0 {
+		groups = 1
+	}
+	if n := (s.N + s.Offset) * groups; n < stats.Cardinality {
+		stats.Cardinality = n
+	}
+	return plan.Cost{CPU: stats.Cardinality}, stats
+}
+
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+func (s *LimitProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
 func createLimitTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*LimitProcedureSpec)
 	if !ok {
@@ -221,6 +238,20 @@ func appendSlicedCols(reader flux.ColReader, builder execute.TableBuilder, start
 				return err
 			}
 			s.Release()
+		case flux.TDuration:
+			s := arrow.IntSlice(reader.Durations(j), start, stop)
+			if err := builder.AppendDurations(j, s); err != nil {
+				s.Release()
+				return err
+			}
+			s.Release()
+		case flux.TBytes:
+			s := arrow.BinarySlice(reader.Bytes(j), start, stop)
+			if err := builder.AppendBinaries(j, s); err != nil {
+				s.Release()
+				return err
+			}
+			s.Release()
 		default:
 			execute.PanicUnknownType(c.Type)
 		}
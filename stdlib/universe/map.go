@@ -96,6 +96,35 @@ func (s *MapProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+// The group key columns are only known to be preserved when the function
+// returns an object literal that extends its input such as {r with _value: r._value * 2.0}.
+// In that case, only the properties of the literal may modify the group key.
+func (s *MapProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	if s.Fn.Fn == nil {
+		return nil, false
+	}
+	expr, ok := s.Fn.Fn.GetFunctionBodyExpression()
+	if !ok {
+		return nil, false
+	}
+	obj, ok := expr.(*semantic.ObjectExpression)
+	if !ok {
+		return nil, false
+	}
+	if !s.MergeKey {
+		params := s.Fn.Fn.Parameters
+		if obj.With == nil || params == nil || len(params.List) != 1 || obj.With.Name != params.List[0].Key.Name {
+			return nil, false
+		}
+	}
+	columns := make([]string, len(obj.Properties))
+	for i, p := range obj.Properties {
+		columns[i] = p.Key.Key()
+	}
+	return columns, true
+}
+
 func createMapTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*MapProcedureSpec)
 	if !ok {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/dependencies/dependenciestest"
 	"github.com/influxdata/flux/execute"
@@ -1017,3 +1018,56 @@ f
 		})
 	}
 }
+
+func TestMapProcedureSpec_ModifiedGroupKeyColumns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fn       string
+		mergeKey bool
+		want     []string
+		wantOK   bool
+	}{
+		{
+			name:   "with",
+			fn:     `(r) => ({r with _value: r._value * 2.0, t0: "a"})`,
+			want:   []string{"_value", "t0"},
+			wantOK: true,
+		},
+		{
+			name:   "object",
+			fn:     `(r) => ({_time: r._time, _value: r._value})`,
+			wantOK: false,
+		},
+		{
+			name:     "object merge key",
+			fn:       `(r) => ({_time: r._time, _value: r._value})`,
+			mergeKey: true,
+			want:     []string{"_time", "_value"},
+			wantOK:   true,
+		},
+		{
+			name:   "not an object",
+			fn:     `(r) => r`,
+			wantOK: false,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			spec := &universe.MapProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn:    executetest.FunctionExpression(t, tc.fn),
+					Scope: runtime.Prelude(),
+				},
+				MergeKey: tc.mergeKey,
+			}
+			got, ok := spec.ModifiedGroupKeyColumns()
+			if ok != tc.wantOK {
+				t.Fatalf("unexpected ok: want %v, got %v", tc.wantOK, ok)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected columns -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
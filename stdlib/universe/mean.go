@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/values"
 )
 
 const MeanKind = "mean"
@@ -108,6 +109,10 @@ func (a *MeanAgg) NewStringAgg() execute.DoStringAgg {
 	return nil
 }
 
+func (a *MeanAgg) NewDecimalAgg() execute.DoDecimalAgg {
+	return new(MeanDecimalAgg)
+}
+
 func (a *MeanAgg) DoInt(vs *array.Int) {
 	if l := vs.Len() - vs.NullN(); l > 0 {
 		a.count += int64(l)
@@ -162,3 +167,39 @@ func (a *MeanAgg) ValueFloat() float64 {
 func (a *MeanAgg) IsNull() bool {
 	return a.count == 0
 }
+
+// MeanDecimalAgg computes the mean of decimals as a decimal
+// so the mean does not lose the precision of the values.
+type MeanDecimalAgg struct {
+	count int64
+	sum   values.Decimal
+}
+
+func (a *MeanDecimalAgg) DoDecimal(vs *array.Decimal) error {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			sum, err := a.sum.Add(values.NewDecimalFromNum(vs.Value(i)))
+			if err != nil {
+				return err
+			}
+			a.sum = sum
+			a.count++
+		}
+	}
+	return nil
+}
+func (a *MeanDecimalAgg) Type() flux.ColType {
+	return flux.TDecimal
+}
+func (a *MeanDecimalAgg) ValueDecimal() values.Decimal {
+	if a.count < 1 {
+		return values.Decimal{}
+	}
+	// The quotient of a decimal and a positive count is never larger
+	// than the decimal so the division cannot fail.
+	mean, _ := a.sum.DivInt(a.count)
+	return mean
+}
+func (a *MeanDecimalAgg) IsNull() bool {
+	return a.count == 0
+}
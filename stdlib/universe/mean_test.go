@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
 )
 
 func TestMeanOperation_Marshaling(t *testing.T) {
@@ -91,6 +92,57 @@ func TestMean_Process(t *testing.T) {
 	}
 }
 
+func TestMean_ProcessDecimal(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []string
+		want interface{}
+	}{
+		{
+			name: "nonzero",
+			data: []string{"1", "2", "2"},
+			want: mustParseDecimal("1.666666667"),
+		},
+		{
+			name: "with nulls",
+			data: []string{"1.5", "", "2.25"},
+			want: mustParseDecimal("1.875"),
+		},
+		{
+			name: "empty",
+			data: []string{},
+			want: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := arrow.NewDecimalBuilder(nil)
+			for _, s := range tc.data {
+				if s == "" {
+					b.AppendNull()
+					continue
+				}
+				v, err := values.ParseDecimal(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b.Append(v.Num())
+			}
+			data := b.NewDecimalArray()
+			b.Release()
+			defer data.Release()
+
+			executetest.DecimalAggFuncTestHelper(
+				t,
+				new(universe.MeanAgg),
+				data,
+				tc.want,
+			)
+		})
+	}
+}
+
 func BenchmarkMean(b *testing.B) {
 	data := arrow.NewFloat(NormalData, &memory.Allocator{})
 	executetest.AggFuncBenchmarkHelper(
@@ -159,12 +159,103 @@ func createPivotTransformation(id execute.DatasetID, mode execute.AccumulationMo
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
 
-	cache := execute.NewTableBuilderCache(a.Allocator())
-	d := execute.NewDataset(id, mode, cache)
-	t := NewPivotTransformation(d, cache, s)
+	t, d := newPivotTransformation(s, id, mode, a.Allocator())
 	return t, d, nil
 }
 
+func newPivotTransformation(spec *PivotProcedureSpec, id execute.DatasetID, mode execute.AccumulationMode, mem *memory.Allocator) (*pivotTransformation, execute.Dataset) {
+	cache := execute.NewTableBuilderCache(mem)
+	if !mem.SpillEnabled() {
+		d := execute.NewDataset(id, mode, cache)
+		return NewPivotTransformation(d, cache, spec), d
+	}
+
+	sc := &pivotSpillCache{
+		DataCache: cache,
+		inputs:    execute.NewGroupLookup(),
+		mem:       mem,
+	}
+	d := execute.NewDataset(id, mode, sc)
+	t := NewPivotTransformation(d, cache, spec)
+	t.spill, sc.pivot = sc, t.pivot
+	return t, d
+}
+
+// pivotSpillCache is the data cache for pivot when spilling is enabled.
+// The input tables are buffered by the group key of their output table
+// in builders that can be spilled to disk. A group is only pivoted when
+// its table is output so that one pivoted table is held in memory at a time.
+// The tables are output when the input has finished.
+type pivotSpillCache struct {
+	execute.DataCache
+	inputs *execute.GroupLookup
+	mem    *memory.Allocator
+	pivot  func(tbl flux.Table) error
+}
+
+func (c *pivotSpillCache) insert(key flux.GroupKey, tbl flux.Table) error {
+	b := table.NewSpillableBufferedBuilder(tbl.Key(), c.mem)
+	if err := b.AppendTable(tbl); err != nil {
+		b.Release()
+		return err
+	}
+	var inputs []*table.BufferedBuilder
+	if v, ok := c.inputs.Lookup(key); ok {
+		inputs = v.([]*table.BufferedBuilder)
+	}
+	c.inputs.Set(key, append(inputs, b))
+	return nil
+}
+
+func (c *pivotSpillCache) Table(key flux.GroupKey) (flux.Table, error) {
+	if v, ok := c.inputs.Delete(key); ok {
+		inputs := v.([]*table.BufferedBuilder)
+		for i, b := range inputs {
+			tbl, err := b.Table()
+			if err == nil {
+				err = c.pivot(tbl)
+				tbl.Done()
+			}
+			if err != nil {
+				releaseBuilders(inputs[i+1:])
+				return nil, err
+			}
+		}
+	}
+	return c.DataCache.Table(key)
+}
+
+func (c *pivotSpillCache) ForEach(f func(flux.GroupKey)) {
+	c.inputs.Range(func(key flux.GroupKey, value interface{}) {
+		f(key)
+	})
+}
+
+// ForEachWithContext does nothing because the tables
+// are only output when the input has finished.
+func (c *pivotSpillCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigger, execute.TableContext)) {
+}
+
+func (c *pivotSpillCache) ExpireTable(key flux.GroupKey) {
+	if v, ok := c.inputs.Delete(key); ok {
+		releaseBuilders(v.([]*table.BufferedBuilder))
+	}
+	c.DataCache.ExpireTable(key)
+}
+
+// release releases the input tables that were not output.
+func (c *pivotSpillCache) release() {
+	c.inputs.Range(func(key flux.GroupKey, value interface{}) {
+		c.ExpireTable(key)
+	})
+}
+
+func releaseBuilders(bs []*table.BufferedBuilder) {
+	for _, b := range bs {
+		b.Release()
+	}
+}
+
 type rowCol struct {
 	nextCol int
 	nextRow int
@@ -179,6 +270,9 @@ type pivotTransformation struct {
 	colKeyMaps map[string]map[string]int
 	rowKeyMaps map[string]map[string]int
 	nextRowCol map[string]rowCol
+	// spill is set when the input tables are buffered
+	// so they can be spilled to disk before they are pivoted.
+	spill *pivotSpillCache
 }
 
 func NewPivotTransformation(d execute.Dataset, cache execute.TableBuilderCache, spec *PivotProcedureSpec) *pivotTransformation {
@@ -198,6 +292,33 @@ func (t *pivotTransformation) RetractTable(id execute.DatasetID, key flux.GroupK
 }
 
 func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	if t.spill != nil {
+		return t.spill.insert(t.groupKey(tbl), tbl)
+	}
+	return t.pivot(tbl)
+}
+
+// groupKey returns the group key of the pivoted table for the table.
+// This is the group key of the table without the column key columns
+// and the value column.
+func (t *pivotTransformation) groupKey(tbl flux.Table) flux.GroupKey {
+	keyCols := make([]flux.ColMeta, 0, len(tbl.Key().Cols()))
+	keyValues := make([]values.Value, 0, len(tbl.Key().Cols()))
+	for _, c := range tbl.Cols() {
+		if c.Label == t.spec.ValueColumn || execute.ContainsStr(t.spec.ColumnKey, c.Label) {
+			continue
+		}
+		if tbl.Key().HasCol(c.Label) {
+			keyCols = append(keyCols, c)
+			keyValues = append(keyValues, tbl.Key().LabelValue(c.Label))
+		}
+	}
+	return execute.NewGroupKey(keyCols, keyValues)
+}
+
+// pivot pivots the rows of the table into the
+// builder for the table with its output group key.
+func (t *pivotTransformation) pivot(tbl flux.Table) error {
 	rowKeyIndex := make(map[string]int)
 	for _, v := range t.spec.RowKey {
 		idx := execute.ColIdx(v, tbl.Cols())
@@ -217,8 +338,6 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 	}
 
 	cols := make([]flux.ColMeta, 0, len(tbl.Cols()))
-	keyCols := make([]flux.ColMeta, 0, len(tbl.Key().Cols()))
-	keyValues := make([]values.Value, 0, len(tbl.Key().Cols()))
 	newIDX := 0
 	colMap := make([]int, len(tbl.Cols()))
 
@@ -228,9 +347,7 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 			if tbl.Key().HasCol(v.Label) {
 				colMap[newIDX] = colIDX
 				newIDX++
-				keyCols = append(keyCols, tbl.Cols()[colIDX])
 				cols = append(cols, tbl.Cols()[colIDX])
-				keyValues = append(keyValues, tbl.Key().LabelValue(v.Label))
 			} else if _, ok := rowKeyIndex[v.Label]; ok {
 				cols = append(cols, tbl.Cols()[colIDX])
 				colMap[newIDX] = colIDX
@@ -255,7 +372,7 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 		}
 	}
 
-	newGroupKey := execute.NewGroupKey(keyCols, keyValues)
+	newGroupKey := t.groupKey(tbl)
 	builder, created := t.cache.TableBuilder(newGroupKey)
 	groupKeyString := newGroupKey.String()
 	if created {
@@ -406,8 +523,10 @@ func (t *pivotTransformation) UpdateProcessingTime(id execute.DatasetID, pt exec
 }
 
 func (t *pivotTransformation) Finish(id execute.DatasetID, err error) {
-
 	t.d.Finish(err)
+	if t.spill != nil {
+		t.spill.release()
+	}
 }
 
 type SortedPivotProcedureSpec struct {
@@ -14,3 +14,9 @@ import (
 func NewSortedPivotTransformation(ctx context.Context, spec SortedPivotProcedureSpec, id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset, error) {
 	return newSortedPivotTransformation(ctx, spec, id, alloc)
 }
+
+// NewSpillablePivotTransformation is exposed so the tests can create a pivot
+// transformation with an allocator that spills its buffers to disk.
+func NewSpillablePivotTransformation(spec *PivotProcedureSpec, id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+	return newPivotTransformation(spec, id, execute.DiscardingMode, alloc)
+}
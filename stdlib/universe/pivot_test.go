@@ -2,9 +2,11 @@ package universe_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
@@ -12,6 +14,7 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/gen"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
 	"github.com/influxdata/flux/stdlib/universe"
@@ -786,6 +789,97 @@ func TestPivot_Process(t *testing.T) {
 	}
 }
 
+func TestPivot_Spill(t *testing.T) {
+	// The limit only leaves room for one of the input tables
+	// so the first table is spilled to disk when the second
+	// table is processed.
+	limit := int64(512)
+	alloc := &memory.Allocator{Limit: &limit, SpillDir: t.TempDir()}
+
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_field", Type: flux.TString},
+		{Label: "_measurement", Type: flux.TString},
+	}
+	data := []flux.Table{
+		&executetest.Table{
+			KeyCols: []string{"_field", "_measurement"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(1), 1.0, "f1", "m1"},
+				{execute.Time(2), 2.0, "f1", "m1"},
+				{execute.Time(3), 3.0, "f1", "m1"},
+			},
+			Alloc: alloc,
+		},
+		&executetest.Table{
+			KeyCols: []string{"_field", "_measurement"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(1), 10.0, "f2", "m1"},
+				{execute.Time(2), 20.0, "f2", "m1"},
+				{execute.Time(4), 40.0, "f2", "m1"},
+			},
+			Alloc: alloc,
+		},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"_measurement"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "f1", Type: flux.TFloat},
+				{Label: "f2", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "m1", 1.0, 10.0},
+				{execute.Time(2), "m1", 2.0, 20.0},
+				{execute.Time(3), "m1", 3.0, nil},
+				{execute.Time(4), "m1", nil, 40.0},
+			},
+		},
+	}
+
+	spec := &universe.PivotProcedureSpec{
+		RowKey:      []string{"_time"},
+		ColumnKey:   []string{"_field"},
+		ValueColumn: "_value",
+	}
+	tx, d := universe.NewSpillablePivotTransformation(spec, executetest.RandomDatasetID(), alloc)
+	d.SetTriggerSpec(plan.DefaultTriggerSpec)
+	store := executetest.NewDataStore()
+	d.AddTransformation(store)
+
+	parentID := executetest.RandomDatasetID()
+	for _, tbl := range data {
+		if err := tx.Process(parentID, tbl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if alloc.SpillCount() == 0 {
+		t.Fatal("expected the first table to be spilled to disk")
+	}
+
+	// Allow the spilled table to be read back to pivot it.
+	limit = math.MaxInt64
+	tx.Finish(parentID, nil)
+	if err := store.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := executetest.TablesFromCache(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executetest.NormalizeTables(got)
+	executetest.NormalizeTables(want)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
 func TestSortedPivot_ProcessWithTags(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -407,6 +407,34 @@ func (s *SchemaMutationProcedureSpec) Copy() plan.ProcedureSpec {
 	}
 }
 
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+// The modified columns are only known when the mutations list
+// the columns they change instead of using a function.
+func (s *SchemaMutationProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	var columns []string
+	for _, m := range s.Mutations {
+		switch m := m.(type) {
+		case *RenameOpSpec:
+			if m.Fn.Fn != nil {
+				return nil, false
+			}
+			for from, to := range m.Columns {
+				columns = append(columns, from, to)
+			}
+		case *DropOpSpec:
+			if m.Predicate.Fn != nil {
+				return nil, false
+			}
+			columns = append(columns, m.Columns...)
+		case *DuplicateOpSpec:
+			columns = append(columns, m.As)
+		default:
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
 func newSchemaMutationProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
 	s, ok := qs.(SchemaMutation)
 	if !ok {
@@ -1,6 +1,9 @@
 package universe
 
 import (
+	"context"
+	"math/bits"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
@@ -26,6 +29,7 @@ func init() {
 	flux.RegisterOpSpec(SortKind, newSortOp)
 	plan.RegisterProcedureSpec(SortKind, newSortProcedure, SortKind)
 	execute.RegisterTransformation(SortKind, createSortTransformation)
+	plan.RegisterPhysicalRules(plan.CostBased(SwapSortFilterRule{}))
 }
 
 func createSortOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
@@ -65,7 +69,6 @@ func (s *SortOpSpec) Kind() flux.OperationKind {
 }
 
 type SortProcedureSpec struct {
-	plan.DefaultCost
 	Columns []string
 	Desc    bool
 
@@ -100,6 +103,32 @@ func (s *SortProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
 }
 
+// Cost estimates sorting as n log n comparisons
+// with every row held in memory.
+func (s *SortProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	stats := sumStatistics(inStats)
+	n := stats.Cardinality
+	return plan.Cost{
+		CPU: n * int64(bits.Len64(uint64(n))),
+		MEM: n,
+	}, stats
+}
+
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+func (s *SortProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return nil, true
+}
+
+// sumStatistics combines the statistics of each input.
+func sumStatistics(inStats []plan.Statistics) plan.Statistics {
+	var stats plan.Statistics
+	for _, in := range inStats {
+		stats.Cardinality += in.Cardinality
+		stats.GroupCardinality += in.GroupCardinality
+	}
+	return stats
+}
+
 func createSortTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*SortProcedureSpec)
 	if !ok {
@@ -193,3 +222,29 @@ func (t *sortTransformation) sortedKey(key flux.GroupKey) flux.GroupKey {
 	}
 	return execute.NewGroupKey(cols, vs)
 }
+
+// SwapSortFilterRule moves a filter in front of the sort that
+// precedes it so that the sort processes fewer rows.
+// The filter does not depend on the order of the rows
+// so the result is the same.
+type SwapSortFilterRule struct{}
+
+func (SwapSortFilterRule) Name() string {
+	return "SwapSortFilterRule"
+}
+
+func (SwapSortFilterRule) Pattern() plan.Pattern {
+	return plan.PhysPat(FilterKind, plan.PhysPat(SortKind, plan.Any()))
+}
+
+func (SwapSortFilterRule) Rewrite(ctx context.Context, filterNode plan.Node) (plan.Node, bool, error) {
+	sortNode := filterNode.Predecessors()[0]
+	if len(sortNode.Successors()) != 1 || len(sortNode.Predecessors()) != 1 {
+		return filterNode, false, nil
+	}
+	swapped, err := plan.SwapPlanNodes(filterNode, sortNode)
+	if err != nil {
+		return nil, false, err
+	}
+	return swapped, true, nil
+}
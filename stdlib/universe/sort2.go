@@ -3,6 +3,7 @@ package universe
 import (
 	"container/heap"
 	"context"
+	"io"
 	"sort"
 
 	"github.com/apache/arrow/go/arrow/memory"
@@ -13,6 +14,7 @@ import (
 	"github.com/influxdata/flux/internal/arrowutil"
 	"github.com/influxdata/flux/internal/execute/table"
 	"github.com/influxdata/flux/internal/mutable"
+	fluxmemory "github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 )
 
@@ -20,6 +22,7 @@ type sortTransformation2 struct {
 	execute.ExecutionNode
 	d       *execute.PassthroughDataset
 	mem     memory.Allocator
+	alloc   *fluxmemory.Allocator
 	cols    []string
 	compare arrowutil.CompareFunc
 }
@@ -28,6 +31,7 @@ func newSortTransformation2(id execute.DatasetID, spec *SortProcedureSpec, a exe
 	t := &sortTransformation2{
 		d:       execute.NewPassthroughDataset(id),
 		mem:     a.Allocator(),
+		alloc:   a.Allocator(),
 		cols:    spec.Columns,
 		compare: arrowutil.Compare,
 	}
@@ -57,6 +61,17 @@ func (s *sortTransformation2) Process(id execute.DatasetID, tbl flux.Table) erro
 		sortCols: sortCols,
 		compare:  s.compare,
 	}
+	if s.alloc.SpillEnabled() {
+		// Allow the sorted buffers to be merged and written
+		// to disk if we run out of memory.
+		mh.spill = &sortSpill{
+			alloc: s.alloc,
+			lock:  make(chan struct{}, 1),
+		}
+		s.alloc.RegisterSpiller(mh)
+	}
+	defer mh.Release()
+
 	if err := tbl.Do(func(cr flux.ColReader) error {
 		return s.processView(mh, cr)
 	}); err != nil {
@@ -81,7 +96,9 @@ func (s *sortTransformation2) processView(mh *sortTableMergeHeap, cr flux.ColRea
 		item.indices = s.sort(cr, mh.sortCols)
 		item.offset = int(item.indices.Value(0))
 	}
+	mh.lock()
 	mh.items = append(mh.items, item)
+	mh.unlock()
 	return nil
 }
 
@@ -158,12 +175,18 @@ type sortTableMergeHeapItem struct {
 	cr        flux.ColReader
 	indices   *array.Int
 	i, offset int
+
+	// run and mem are set when this item reads
+	// a sorted run back from a spill file.
+	run *table.SpillReader
+	mem memory.Allocator
+	err error
 }
 
 func (s *sortTableMergeHeapItem) Next() bool {
 	s.i++
 	if s.i >= s.cr.Len() {
-		return false
+		return s.nextBuffer()
 	}
 	s.offset = s.i
 	if s.indices != nil {
@@ -172,6 +195,24 @@ func (s *sortTableMergeHeapItem) Next() bool {
 	return true
 }
 
+// nextBuffer reads the next buffer of the sorted run
+// from the spill file if this item has one.
+func (s *sortTableMergeHeapItem) nextBuffer() bool {
+	if s.run == nil {
+		return false
+	}
+	buf, err := s.run.Read(s.mem)
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return false
+	}
+	s.cr.Release()
+	s.cr, s.i, s.offset = buf, 0, 0
+	return true
+}
+
 func (s *sortTableMergeHeapItem) Release() {
 	if s.indices != nil {
 		s.indices.Release()
@@ -189,6 +230,23 @@ type sortTableMergeHeap struct {
 	items    []*sortTableMergeHeapItem
 	sortCols []int
 	compare  arrowutil.CompareFunc
+	err      error
+
+	// spill is set when the heap is registered
+	// with the allocator to spill to disk.
+	spill *sortSpill
+}
+
+// sortSpill holds the state for a sortTableMergeHeap
+// that can write its data to disk.
+type sortSpill struct {
+	alloc *fluxmemory.Allocator
+	file  *table.SpillFile
+	runs  [][2]int64
+
+	// lock is used as a mutex that can be acquired
+	// without blocking when the allocator asks to spill.
+	lock chan struct{}
 }
 
 func (s *sortTableMergeHeap) Len() int {
@@ -231,8 +289,30 @@ func (s *sortTableMergeHeap) ValueLen() int {
 
 func (s *sortTableMergeHeap) Table(mem memory.Allocator) (flux.Table, error) {
 	// Construct the buffered builder that will contain the full table.
-	builder := table.NewBufferedBuilder(s.key, mem)
+	var builder *table.BufferedBuilder
+	if s.spill != nil {
+		// Stop spilling the heap now that we are reading it.
+		// The output can still be written to disk by the builder.
+		s.spill.alloc.UnregisterSpiller(s)
+		if err := s.openRuns(mem); err != nil {
+			return nil, err
+		}
+		builder = table.NewSpillableBufferedBuilder(s.key, s.spill.alloc)
+	} else {
+		builder = table.NewBufferedBuilder(s.key, mem)
+	}
+
+	if err := s.merge(mem, builder.AppendBuffer); err != nil {
+		builder.Release()
+		return nil, err
+	}
+	return builder.Table()
+}
 
+// merge will merge all of the items in the heap and pass each
+// of the resulting buffers to the function in sorted order.
+// The buffer is released after the function returns.
+func (s *sortTableMergeHeap) merge(mem memory.Allocator, fn func(cr flux.ColReader) error) error {
 	// Initialize the heap now that we have all of the data.
 	heap.Init(s)
 
@@ -274,15 +354,112 @@ func (s *sortTableMergeHeap) Table(mem memory.Allocator) (flux.Table, error) {
 		}
 
 		buffer := s.NextBuffer(builders, keys, n, mem)
-		if err := builder.AppendBuffer(&buffer); err != nil {
+		if s.err != nil {
 			buffer.Release()
-			return nil, err
+			return s.err
+		}
+		if err := fn(&buffer); err != nil {
+			buffer.Release()
+			return err
 		}
 		buffer.Release()
 	}
+	return nil
+}
 
-	// Determine the next buffer size.
-	return builder.Table()
+// Spill merges the buffers in the heap into a sorted run
+// and writes it to disk. It returns the number of bytes written.
+//
+// This implements the memory.Spiller interface.
+func (s *sortTableMergeHeap) Spill() (int64, error) {
+	if s.spill == nil || !s.tryLock() {
+		return 0, nil
+	}
+	defer s.unlock()
+
+	if len(s.items) == 0 {
+		return 0, nil
+	}
+
+	if s.spill.file == nil {
+		f, err := table.NewSpillFile(s.spill.alloc.SpillDir)
+		if err != nil {
+			return 0, err
+		}
+		s.spill.file = f
+	}
+
+	// The merge cannot use the query allocator since
+	// it is the one that asked us to spill. The memory
+	// used here is only temporary until it is written to disk.
+	start := s.spill.file.Size()
+	if err := s.merge(fluxmemory.DefaultAllocator, s.spill.file.Write); err != nil {
+		return 0, err
+	}
+	end := s.spill.file.Size()
+	s.spill.runs = append(s.spill.runs, [2]int64{start, end})
+	return end - start, nil
+}
+
+// openRuns adds an item to the heap for each run
+// that was written to disk.
+func (s *sortTableMergeHeap) openRuns(mem memory.Allocator) error {
+	for _, run := range s.spill.runs {
+		r, err := s.spill.file.Open(s.key, run[0], run[1])
+		if err != nil {
+			return err
+		}
+		buf, err := r.Read(mem)
+		if err == io.EOF {
+			continue
+		} else if err != nil {
+			return err
+		}
+		s.items = append(s.items, &sortTableMergeHeapItem{
+			cr:  buf,
+			run: r,
+			mem: mem,
+		})
+	}
+	s.spill.runs = nil
+	return nil
+}
+
+// Release releases any items that are left in the heap and removes
+// the spill file if one was created.
+func (s *sortTableMergeHeap) Release() {
+	if s.spill != nil {
+		s.spill.alloc.UnregisterSpiller(s)
+		if s.spill.file != nil {
+			_ = s.spill.file.Close()
+			s.spill.file = nil
+		}
+	}
+	for _, item := range s.items {
+		item.Release()
+	}
+	s.items = nil
+}
+
+func (s *sortTableMergeHeap) lock() {
+	if s.spill != nil {
+		s.spill.lock <- struct{}{}
+	}
+}
+
+func (s *sortTableMergeHeap) tryLock() bool {
+	select {
+	case s.spill.lock <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *sortTableMergeHeap) unlock() {
+	if s.spill != nil {
+		<-s.spill.lock
+	}
 }
 
 func (s *sortTableMergeHeap) NextBuffer(builders []array.Builder, keys []array.Interface, n int, mem memory.Allocator) arrow.TableBuffer {
@@ -294,7 +471,7 @@ func (s *sortTableMergeHeap) NextBuffer(builders []array.Builder, keys []array.I
 		b.Resize(n)
 	}
 
-	for i := 0; i < n; i++ {
+	for i := 0; i < n && s.err == nil; i++ {
 		// Append the next row to the builders.
 		item := s.items[0]
 		for j, b := range builders {
@@ -311,6 +488,9 @@ func (s *sortTableMergeHeap) NextBuffer(builders []array.Builder, keys []array.I
 		} else {
 			// Remove this item from the heap since it
 			// no longer has anymore rows.
+			if item.err != nil && s.err == nil {
+				s.err = item.err
+			}
 			item.Release()
 			heap.Pop(s)
 		}
@@ -6,7 +6,11 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
 	"github.com/influxdata/flux/stdlib/universe"
 )
 
@@ -406,3 +410,94 @@ func TestSort_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestSwapSortFilterRule(t *testing.T) {
+	rows := make([][]interface{}, 90)
+	for i := range rows {
+		rows[i] = []interface{}{float64(i)}
+	}
+	var (
+		from = executetest.NewFromProcedureSpec([]*executetest.Table{{
+			ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TFloat}},
+			Data:    rows,
+		}})
+		sort   = &universe.SortProcedureSpec{Columns: []string{"_value"}}
+		filter = func() *universe.FilterProcedureSpec {
+			return &universe.FilterProcedureSpec{
+				Fn: interpreter.ResolvedFunction{
+					Fn: executetest.FunctionExpression(t, `(r) => r._value > 0.0`),
+				},
+			}
+		}
+		yield = &universe.YieldProcedureSpec{Name: "_result"}
+	)
+	tests := []plantest.RuleTestCase{
+		{
+			Name:  "swap",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapSortFilterRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("sort", sort),
+					plan.CreatePhysicalNode("filter", filter()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("filter_copy", filter()),
+					plan.CreatePhysicalNode("sort", sort),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+		},
+		{
+			Name:  "sort with multiple successors",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapSortFilterRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", from),
+					plan.CreatePhysicalNode("sort", sort),
+					plan.CreatePhysicalNode("filter", filter()),
+					plan.CreatePhysicalNode("yield", yield),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}, {1, 3}},
+			},
+			NoChange: true,
+		},
+		{
+			// Without statistics the swap does not lower
+			// the estimated cost so the plan is kept.
+			Name:  "no statistics",
+			Rules: []plan.Rule{plan.CostBased(universe.SwapSortFilterRule{})},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("from", &influxdb.FromProcedureSpec{}),
+					plan.CreatePhysicalNode("sort", sort),
+					plan.CreatePhysicalNode("filter", filter()),
+				},
+				Edges: [][2]int{{0, 1}, {1, 2}},
+			},
+			NoChange: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestSortProcedureSpec_Cost(t *testing.T) {
+	spec := &universe.SortProcedureSpec{Columns: []string{"_value"}}
+	cost, stats := spec.Cost([]plan.Statistics{{Cardinality: 8, GroupCardinality: 2}})
+	if want := (plan.Cost{CPU: 32, MEM: 8}); cost != want {
+		t.Errorf("unexpected cost -want/+got:\n\t- %v\n\t+ %v", want, cost)
+	}
+	if want := (plan.Statistics{Cardinality: 8, GroupCardinality: 2}); stats != want {
+		t.Errorf("unexpected statistics -want/+got:\n\t- %v\n\t+ %v", want, stats)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/values"
 )
 
 const SumKind = "sum"
@@ -106,6 +107,9 @@ func (a *SumAgg) NewFloatAgg() execute.DoFloatAgg {
 func (a *SumAgg) NewStringAgg() execute.DoStringAgg {
 	return nil
 }
+func (a *SumAgg) NewDecimalAgg() execute.DoDecimalAgg {
+	return new(SumDecimalAgg)
+}
 
 type SumIntAgg struct {
 	sum int64
@@ -196,3 +200,31 @@ func (a *SumFloatAgg) ValueFloat() float64 {
 func (a *SumFloatAgg) IsNull() bool {
 	return !a.ok
 }
+
+type SumDecimalAgg struct {
+	sum values.Decimal
+	ok  bool
+}
+
+func (a *SumDecimalAgg) DoDecimal(vs *array.Decimal) error {
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsValid(i) {
+			sum, err := a.sum.Add(values.NewDecimalFromNum(vs.Value(i)))
+			if err != nil {
+				return err
+			}
+			a.sum = sum
+			a.ok = true
+		}
+	}
+	return nil
+}
+func (a *SumDecimalAgg) Type() flux.ColType {
+	return flux.TDecimal
+}
+func (a *SumDecimalAgg) ValueDecimal() values.Decimal {
+	return a.sum
+}
+func (a *SumDecimalAgg) IsNull() bool {
+	return !a.ok
+}
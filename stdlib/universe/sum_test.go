@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/values"
 )
 
 func TestSumOperation_Marshaling(t *testing.T) {
@@ -91,6 +92,57 @@ func TestSum_Process(t *testing.T) {
 	}
 }
 
+func TestSum_ProcessDecimal(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []string
+		want interface{}
+	}{
+		{
+			name: "nonzero",
+			data: []string{"0.1", "0.2", "0.3", "-1.05"},
+			want: mustParseDecimal("-0.45"),
+		},
+		{
+			name: "with nulls",
+			data: []string{"1.5", "", "2.25"},
+			want: mustParseDecimal("3.75"),
+		},
+		{
+			name: "only nulls",
+			data: []string{"", ""},
+			want: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := arrow.NewDecimalBuilder(nil)
+			for _, s := range tc.data {
+				if s == "" {
+					b.AppendNull()
+					continue
+				}
+				v, err := values.ParseDecimal(s)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b.Append(v.Num())
+			}
+			data := b.NewDecimalArray()
+			b.Release()
+			defer data.Release()
+
+			executetest.DecimalAggFuncTestHelper(
+				t,
+				new(universe.SumAgg),
+				data,
+				tc.want,
+			)
+		})
+	}
+}
+
 func BenchmarkSum(b *testing.B) {
 	data := arrow.NewFloat(NormalData, &memory.Allocator{})
 	executetest.AggFuncBenchmarkHelper(
@@ -100,3 +152,11 @@ func BenchmarkSum(b *testing.B) {
 		9998472.67384332,
 	)
 }
+
+func mustParseDecimal(s string) values.Decimal {
+	d, err := values.ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
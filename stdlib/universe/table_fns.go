@@ -3,6 +3,7 @@ package universe
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -226,6 +227,18 @@ func arrayFromColumn(idx int, cr flux.ColReader) values.Array {
 			} else {
 				vsSlice = append(vsSlice, values.NewNull(semantic.BasicTime))
 			}
+		case flux.TDuration:
+			if vs := cr.Durations(idx); vs.IsValid(i) {
+				vsSlice = append(vsSlice, values.NewDuration(values.ConvertDurationNsecs(time.Duration(vs.Value(i)))))
+			} else {
+				vsSlice = append(vsSlice, values.NewNull(semantic.BasicDuration))
+			}
+		case flux.TBytes:
+			if vs := cr.Bytes(idx); vs.IsValid(i) {
+				vsSlice = append(vsSlice, values.NewBytes(append([]byte(nil), vs.Value(i)...)))
+			} else {
+				vsSlice = append(vsSlice, values.NewNull(semantic.BasicBytes))
+			}
 		default:
 			execute.PanicUnknownType(typ)
 		}
@@ -310,6 +323,18 @@ func objectFromRow(idx int, cr flux.ColReader) values.Object {
 			} else {
 				v = values.NewNull(semantic.BasicTime)
 			}
+		case flux.TDuration:
+			if vs := cr.Durations(j); vs.IsValid(idx) {
+				v = values.NewDuration(values.ConvertDurationNsecs(time.Duration(vs.Value(idx))))
+			} else {
+				v = values.NewNull(semantic.BasicDuration)
+			}
+		case flux.TBytes:
+			if vs := cr.Bytes(j); vs.IsValid(idx) {
+				v = values.NewBytes(append([]byte(nil), vs.Value(idx)...))
+			} else {
+				v = values.NewNull(semantic.BasicBytes)
+			}
 		default:
 			execute.PanicUnknownType(c.Type)
 		}
@@ -20,6 +20,7 @@ func init() {
 	runtime.RegisterPackageValue("universe", "int", intConv)
 	runtime.RegisterPackageValue("universe", "uint", uintConv)
 	runtime.RegisterPackageValue("universe", "float", floatConv)
+	runtime.RegisterPackageValue("universe", "decimal", decimalConv)
 	runtime.RegisterPackageValue("universe", "bool", boolConv)
 	runtime.RegisterPackageValue("universe", "time", timeConv)
 	runtime.RegisterPackageValue("universe", "duration", durationConv)
@@ -31,6 +32,7 @@ var (
 	convIntType      = runtime.MustLookupBuiltinType("universe", "int")
 	convUintType     = runtime.MustLookupBuiltinType("universe", "uint")
 	convFloatType    = runtime.MustLookupBuiltinType("universe", "float")
+	convDecimalType  = runtime.MustLookupBuiltinType("universe", "decimal")
 	convStringType   = runtime.MustLookupBuiltinType("universe", "string")
 	convTimeType     = runtime.MustLookupBuiltinType("universe", "time")
 	convDurationType = runtime.MustLookupBuiltinType("universe", "duration")
@@ -63,6 +65,8 @@ var stringConv = values.NewFunction(
 			str = strconv.FormatUint(v.UInt(), 10)
 		case semantic.Float:
 			str = strconv.FormatFloat(v.Float(), 'f', -1, 64)
+		case semantic.Decimal:
+			str = v.Decimal().String()
 		case semantic.Bool:
 			str = strconv.FormatBool(v.Bool())
 		case semantic.Time:
@@ -114,6 +118,12 @@ var intConv = values.NewFunction(
 			i = int64(v.UInt())
 		case semantic.Float:
 			i = int64(v.Float())
+		case semantic.Decimal:
+			n, err := v.Decimal().Int()
+			if err != nil {
+				return nil, err
+			}
+			i = n
 		case semantic.Bool:
 			if v.Bool() {
 				i = 1
@@ -156,6 +166,12 @@ var uintConv = values.NewFunction(
 			i = v.UInt()
 		case semantic.Float:
 			i = uint64(v.Float())
+		case semantic.Decimal:
+			n, err := v.Decimal().UInt()
+			if err != nil {
+				return nil, err
+			}
+			i = n
 		case semantic.Bool:
 			if v.Bool() {
 				i = 1
@@ -198,6 +214,8 @@ var floatConv = values.NewFunction(
 			float = float64(v.UInt())
 		case semantic.Float:
 			float = v.Float()
+		case semantic.Decimal:
+			float = v.Decimal().Float()
 		case semantic.Bool:
 			if v.Bool() {
 				float = 1
@@ -212,6 +230,48 @@ var floatConv = values.NewFunction(
 	false,
 )
 
+var decimalConv = values.NewFunction(
+	"decimal",
+	convDecimalType,
+	func(ctx context.Context, args values.Object) (values.Value, error) {
+		var d values.Decimal
+		v, ok := args.Get(conversionArg)
+		if !ok {
+			return nil, errMissingArg
+		} else if v.IsNull() {
+			return values.Null, nil
+		}
+		switch v.Type().Nature() {
+		case semantic.String:
+			n, err := values.ParseDecimal(v.Str())
+			if err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "cannot convert string %q to decimal", v.Str())
+			}
+			d = n
+		case semantic.Int:
+			d = values.NewDecimalFromInt(v.Int())
+		case semantic.UInt:
+			d = values.NewDecimalFromUInt(v.UInt())
+		case semantic.Float:
+			n, err := values.NewDecimalFromFloat(v.Float())
+			if err != nil {
+				return nil, err
+			}
+			d = n
+		case semantic.Decimal:
+			d = v.Decimal()
+		case semantic.Bool:
+			if v.Bool() {
+				d = values.NewDecimalFromInt(1)
+			}
+		default:
+			return nil, errors.Newf(codes.Invalid, "cannot convert %v to decimal", v.Type())
+		}
+		return values.NewDecimal(d), nil
+	},
+	false,
+)
+
 var boolConv = values.NewFunction(
 	"bool",
 	convBoolType,
@@ -341,10 +401,14 @@ var byteConv = values.NewFunction(
 		v, ok := args.Get(conversionArg)
 		if !ok {
 			return nil, errMissingArg
+		} else if v.IsNull() {
+			return values.Null, nil
 		}
 		switch v.Type().Nature() {
 		case semantic.String:
 			return values.NewBytes([]byte(v.Str())), nil
+		case semantic.Bytes:
+			return v, nil
 		default:
 			return nil, errors.Newf(codes.Invalid, "cannot convert %v to bytes", v.Type())
 		}
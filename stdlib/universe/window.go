@@ -20,6 +20,7 @@ type WindowOpSpec struct {
 	Every       flux.Duration `json:"every"`
 	Period      flux.Duration `json:"period"`
 	Offset      flux.Duration `json:"offset"`
+	Location    plan.Location `json:"location"`
 	TimeColumn  string        `json:"timeColumn"`
 	StopColumn  string        `json:"stopColumn"`
 	StartColumn string        `json:"startColumn"`
@@ -79,6 +80,18 @@ func CreateWindowOpSpec(args flux.Arguments, a *flux.Administration) (flux.Opera
 	} else if ok {
 		spec.Offset = offset
 	}
+	if location, ok, err := args.GetObject("location"); err != nil {
+		return nil, err
+	} else if ok {
+		loc, err := plan.LocationFromObject(location)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := loc.Load(); err != nil {
+			return nil, err
+		}
+		spec.Location = loc
+	}
 
 	if !everySet && !periodSet {
 		const docURL = "https://v2.docs.influxdata.com/v2.0/reference/flux/stdlib/built-in/transformations/window/"
@@ -152,9 +165,10 @@ func newWindowProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pro
 	}
 	p := &WindowProcedureSpec{
 		Window: plan.WindowSpec{
-			Every:  s.Every,
-			Period: s.Period,
-			Offset: s.Offset,
+			Every:    s.Every,
+			Period:   s.Period,
+			Offset:   s.Offset,
+			Location: s.Location,
 		},
 		TimeColumn:  s.TimeColumn,
 		StartColumn: s.StartColumn,
@@ -172,6 +186,12 @@ func (s *WindowProcedureSpec) Copy() plan.ProcedureSpec {
 	return &ns
 }
 
+// ModifiedGroupKeyColumns implements plan.PartitionableProcedureSpec.
+// Window replaces the start and stop columns in the group key.
+func (s *WindowProcedureSpec) ModifiedGroupKeyColumns() ([]string, bool) {
+	return []string{s.StartColumn, s.StopColumn}, true
+}
+
 func createWindowTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*WindowProcedureSpec)
 	if !ok {
@@ -194,12 +214,7 @@ func createWindowTransformation(id execute.DatasetID, mode execute.AccumulationM
 
 	newBounds := interval.NewBounds(bounds.Start, bounds.Stop)
 
-	w, err := interval.NewWindow(
-		s.Window.Every,
-		s.Window.Period,
-		s.Window.Offset,
-	)
-
+	w, err := newWindow(a.Context(), s.Window)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -216,6 +231,20 @@ func createWindowTransformation(id execute.DatasetID, mode execute.AccumulationM
 	return t, d, nil
 }
 
+// newWindow creates the window described by the spec in the spec's
+// location. A spec without a location uses the location option.
+func newWindow(ctx context.Context, spec plan.WindowSpec) (interval.Window, error) {
+	location := spec.Location
+	if location == (plan.Location{}) && execute.HaveExecutionDependencies(ctx) {
+		location = execute.GetExecutionDependencies(ctx).ExecutionOptions.Location
+	}
+	loc, err := location.Load()
+	if err != nil {
+		return interval.Window{}, err
+	}
+	return interval.NewWindowInLocation(spec.Every, spec.Period, spec.Offset, loc)
+}
+
 type fixedWindowTransformation struct {
 	execute.ExecutionNode
 	d         execute.Dataset
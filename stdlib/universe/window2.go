@@ -33,7 +33,7 @@ type windowTransformation2 struct {
 }
 
 func newWindowTransformation2(id execute.DatasetID, spec *WindowProcedureSpec, bounds *execute.Bounds, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
-	window, err := interval.NewWindow(spec.Window.Every, spec.Window.Period, spec.Window.Offset)
+	window, err := newWindow(a.Context(), spec.Window)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -50,6 +50,40 @@ func TestWindow_NewQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "window with location",
+			Raw: `import "timezone"
+from(bucket:"mybucket") |> window(every:1d, location: timezone.location(name: "Europe/Berlin"))`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: influxdb.NameOrID{Name: "mybucket"},
+						},
+					},
+					{
+						ID: "window1",
+						Spec: &universe.WindowOpSpec{
+							Every:       flux.ConvertDuration(24 * time.Hour),
+							Period:      flux.ConvertDuration(24 * time.Hour),
+							Location:    plan.Location{Name: "Europe/Berlin"},
+							TimeColumn:  execute.DefaultTimeColLabel,
+							StartColumn: execute.DefaultStartColLabel,
+							StopColumn:  execute.DefaultStopColLabel,
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "from0", Child: "window1"},
+				},
+			},
+		},
+		{
+			Name:    "unknown location window",
+			Raw:     `from(bucket:"mybucket") |> window(every:1d, location: {zone: "Mars/Olympus_Mons", offset: 0h})`,
+			WantErr: true,
+		},
 		{
 			Name:    "negative every window",
 			Raw:     `from(bucket:"mybucket") |> window(every:-1h)`,
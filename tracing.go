@@ -2,6 +2,10 @@ package flux
 
 import (
 	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/nonrecording"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -27,3 +31,47 @@ func IsQueryTracingEnabled(ctx context.Context) bool {
 	}
 	return b
 }
+
+// InstrumentationName is the name of the OpenTelemetry
+// tracer and meter that instrument query execution.
+const InstrumentationName = "github.com/influxdata/flux"
+
+const (
+	tracerProviderContextKey contextKey = "otel-tracer-provider"
+	meterProviderContextKey  contextKey = "otel-meter-provider"
+)
+
+// WithTracerProvider returns a child context that records OpenTelemetry
+// spans for the compile, plan and execute phases of a query
+// and for each of its transformations with the tracer provider.
+func WithTracerProvider(parentCtx context.Context, tp trace.TracerProvider) context.Context {
+	return context.WithValue(parentCtx, tracerProviderContextKey, tp)
+}
+
+// Tracer returns the OpenTelemetry tracer of the context.
+// It returns a tracer that does not record spans
+// if the context has no tracer provider.
+func Tracer(ctx context.Context) trace.Tracer {
+	tp, ok := ctx.Value(tracerProviderContextKey).(trace.TracerProvider)
+	if !ok {
+		tp = trace.NewNoopTracerProvider()
+	}
+	return tp.Tracer(InstrumentationName)
+}
+
+// WithMeterProvider returns a child context that exports the
+// per-operator counters of a query with the meter provider.
+func WithMeterProvider(parentCtx context.Context, mp metric.MeterProvider) context.Context {
+	return context.WithValue(parentCtx, meterProviderContextKey, mp)
+}
+
+// Meter returns the OpenTelemetry meter of the context.
+// The second return value is false if the context has no
+// meter provider, in which case the meter does not record anything.
+func Meter(ctx context.Context) (metric.Meter, bool) {
+	mp, ok := ctx.Value(meterProviderContextKey).(metric.MeterProvider)
+	if !ok {
+		return nonrecording.NewNoopMeter(), false
+	}
+	return mp.Meter(InstrumentationName), true
+}
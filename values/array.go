@@ -105,6 +105,9 @@ func (a *array) Time() Time {
 func (a *array) Duration() Duration {
 	panic(UnexpectedKind(semantic.Array, semantic.Duration))
 }
+func (a *array) Decimal() Decimal {
+	panic(UnexpectedKind(semantic.Array, semantic.Decimal))
+}
 func (a *array) Regexp() *regexp.Regexp {
 	panic(UnexpectedKind(semantic.Array, semantic.Regexp))
 }
@@ -80,6 +80,15 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		d := ConvertDurationNsecs(l.Duration() + r.Duration())
 		return NewDuration(d), nil
 	},
+	{Operator: ast.AdditionOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		d, err := l.Add(r)
+		if err != nil {
+			return nil, err
+		}
+		return NewDecimal(d), nil
+	},
 	{Operator: ast.SubtractionOperator, Left: semantic.Int, Right: semantic.Int}: func(lv, rv Value) (Value, error) {
 		l := lv.Int()
 		r := rv.Int()
@@ -101,6 +110,15 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		d := ConvertDurationNsecs(l.Duration() - r.Duration())
 		return NewDuration(d), nil
 	},
+	{Operator: ast.SubtractionOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		d, err := l.Sub(r)
+		if err != nil {
+			return nil, err
+		}
+		return NewDecimal(d), nil
+	},
 	{Operator: ast.MultiplicationOperator, Left: semantic.Int, Right: semantic.Int}: func(lv, rv Value) (Value, error) {
 		l := lv.Int()
 		r := rv.Int()
@@ -116,6 +134,15 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Float()
 		return NewFloat(l * r), nil
 	},
+	{Operator: ast.MultiplicationOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		d, err := l.Mul(r)
+		if err != nil {
+			return nil, err
+		}
+		return NewDecimal(d), nil
+	},
 	{Operator: ast.DivisionOperator, Left: semantic.Int, Right: semantic.Int}: func(lv, rv Value) (Value, error) {
 		l := lv.Int()
 		r := rv.Int()
@@ -137,6 +164,18 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Float()
 		return NewFloat(l / r), nil
 	},
+	{Operator: ast.DivisionOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		if r.Sign() == 0 {
+			return nil, errors.Newf(codes.FailedPrecondition, "cannot divide by zero")
+		}
+		d, err := l.Div(r)
+		if err != nil {
+			return nil, err
+		}
+		return NewDecimal(d), nil
+	},
 	{Operator: ast.ModuloOperator, Left: semantic.Int, Right: semantic.Int}: func(lv, rv Value) (Value, error) {
 		l := lv.Int()
 		r := rv.Int()
@@ -240,6 +279,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(!l.After(r)), nil
 	},
+	{Operator: ast.LessThanEqualOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) <= 0), nil
+	},
 
 	// LessThanOperator
 
@@ -304,6 +348,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(l.Before(r)), nil
 	},
+	{Operator: ast.LessThanOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) < 0), nil
+	},
 
 	// GreaterThanEqualOperator
 
@@ -368,6 +417,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(!r.After(l)), nil
 	},
+	{Operator: ast.GreaterThanEqualOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) >= 0), nil
+	},
 
 	// GreaterThanOperator
 
@@ -432,6 +486,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(l.After(r)), nil
 	},
+	{Operator: ast.GreaterThanOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) > 0), nil
+	},
 
 	// EqualOperator
 
@@ -501,6 +560,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(l.Equal(r)), nil
 	},
+	{Operator: ast.EqualOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) == 0), nil
+	},
 	{Operator: ast.EqualOperator, Left: semantic.Array, Right: semantic.Array}: func(lv, rv Value) (Value, error) {
 		return NewBool(lv.Equal(rv)), nil
 	},
@@ -579,6 +643,11 @@ var binaryFuncLookup = map[BinaryFuncSignature]BinaryFunction{
 		r := rv.Time().Time()
 		return NewBool(!l.Equal(r)), nil
 	},
+	{Operator: ast.NotEqualOperator, Left: semantic.Decimal, Right: semantic.Decimal}: func(lv, rv Value) (Value, error) {
+		l := lv.Decimal()
+		r := rv.Decimal()
+		return NewBool(l.Cmp(r) != 0), nil
+	},
 
 	{Operator: ast.RegexpMatchOperator, Left: semantic.String, Right: semantic.Regexp}: func(lv, rv Value) (Value, error) {
 		l := lv.Str()
@@ -21,6 +21,7 @@ var (
 	stringNullValue   = (*string)(nil)
 	timeNullValue     = (*values.Time)(nil)
 	durationNullValue = (*values.Duration)(nil)
+	decimalNullValue  = (*values.Decimal)(nil)
 )
 
 func TestBinaryOperator(t *testing.T) {
@@ -49,6 +50,10 @@ func TestBinaryOperator(t *testing.T) {
 		// duration + duration
 		{lhs: values.ConvertDurationNsecs(1), op: "+", rhs: values.ConvertDurationNsecs(2), want: values.ConvertDurationNsecs(3)},
 		{lhs: values.ConvertDurationNsecs(1), op: "+", rhs: durationNullValue, want: durationNullValue},
+		// decimal + decimal
+		{lhs: mustParseDecimal("0.1"), op: "+", rhs: mustParseDecimal("0.2"), want: mustParseDecimal("0.3")},
+		{lhs: mustParseDecimal("0.1"), op: "+", rhs: decimalNullValue, want: decimalNullValue},
+		{lhs: mustParseDecimal("99999999999999999999999999999.999999999"), op: "+", rhs: mustParseDecimal("0.000000001"), wantErr: errors.New(codes.Invalid, "decimal overflows 38 digits")},
 		// int - int
 		{lhs: int64(6), op: "-", rhs: int64(4), want: int64(2)},
 		{lhs: int64(6), op: "-", rhs: intNullValue, want: intNullValue},
@@ -61,6 +66,8 @@ func TestBinaryOperator(t *testing.T) {
 		// duration - duration
 		{lhs: values.ConvertDurationNsecs(5), op: "-", rhs: values.ConvertDurationNsecs(3), want: values.ConvertDurationNsecs(2)},
 		{lhs: values.ConvertDurationNsecs(5), op: "-", rhs: durationNullValue, want: durationNullValue},
+		// decimal - decimal
+		{lhs: mustParseDecimal("1.5"), op: "-", rhs: mustParseDecimal("2.25"), want: mustParseDecimal("-0.75")},
 		// int * int
 		{lhs: int64(6), op: "*", rhs: int64(4), want: int64(24)},
 		{lhs: int64(6), op: "*", rhs: intNullValue, want: intNullValue},
@@ -70,6 +77,8 @@ func TestBinaryOperator(t *testing.T) {
 		// float * float
 		{lhs: 4.5, op: "*", rhs: 8.2, want: 36.9},
 		{lhs: 4.5, op: "*", rhs: floatNullValue, want: floatNullValue},
+		// decimal * decimal
+		{lhs: mustParseDecimal("4.5"), op: "*", rhs: mustParseDecimal("8.2"), want: mustParseDecimal("36.9")},
 		// int / int
 		{lhs: int64(6), op: "/", rhs: int64(4), want: int64(1)},
 		{lhs: int64(6), op: "/", rhs: intNullValue, want: intNullValue},
@@ -78,6 +87,9 @@ func TestBinaryOperator(t *testing.T) {
 		{lhs: uint64(6), op: "/", rhs: uintNullValue, want: uintNullValue},
 		// float / float
 		{lhs: 5.0, op: "/", rhs: 2.0, want: 2.5},
+		// decimal / decimal
+		{lhs: mustParseDecimal("2"), op: "/", rhs: mustParseDecimal("3"), want: mustParseDecimal("0.666666667")},
+		{lhs: mustParseDecimal("1"), op: "/", rhs: mustParseDecimal("0"), wantErr: errors.New(codes.FailedPrecondition, "cannot divide by zero")},
 		{lhs: 4.5, op: "/", rhs: floatNullValue, want: floatNullValue},
 		// int / zero
 		{lhs: int64(8), op: "/", rhs: int64(0), want: nil, wantErr: errors.New(codes.FailedPrecondition, "cannot divide by zero")},
@@ -168,6 +180,11 @@ func TestBinaryOperator(t *testing.T) {
 		{lhs: values.Time(0), op: "<=", rhs: timeNullValue, want: boolNullValue},
 		// time <= null
 		{lhs: values.Time(0), op: "<=", rhs: timeNullValue, want: boolNullValue},
+		// decimal <= decimal
+		{lhs: mustParseDecimal("-1.5"), op: "<=", rhs: mustParseDecimal("1"), want: true},
+		{lhs: mustParseDecimal("1.5"), op: "<=", rhs: mustParseDecimal("1.5"), want: true},
+		{lhs: mustParseDecimal("2"), op: "<=", rhs: mustParseDecimal("1.999999999"), want: false},
+		{lhs: mustParseDecimal("1"), op: "<=", rhs: decimalNullValue, want: boolNullValue},
 		// null <= int
 		{lhs: intNullValue, op: "<=", rhs: int64(8), want: boolNullValue},
 		// null <= uint
@@ -467,6 +484,10 @@ func TestBinaryOperator(t *testing.T) {
 		{lhs: values.Time(0), op: "==", rhs: timeNullValue, want: boolNullValue},
 		// time == null
 		{lhs: values.Time(0), op: "==", rhs: timeNullValue, want: boolNullValue},
+		// decimal == decimal
+		{lhs: mustParseDecimal("1.10"), op: "==", rhs: mustParseDecimal("1.1"), want: true},
+		{lhs: mustParseDecimal("-1"), op: "==", rhs: mustParseDecimal("1"), want: false},
+		{lhs: mustParseDecimal("1"), op: "==", rhs: decimalNullValue, want: boolNullValue},
 		// null == bool
 		{lhs: boolNullValue, op: "==", rhs: true, want: boolNullValue},
 		// null == int
@@ -628,10 +649,23 @@ func Value(v interface{}) values.Value {
 			return values.NewNull(semantic.BasicDuration)
 		}
 		return values.NewDuration(*v)
+	case *values.Decimal:
+		if v == nil {
+			return values.NewNull(semantic.BasicDecimal)
+		}
+		return values.NewDecimal(*v)
 	}
 	return values.New(v)
 }
 
+func mustParseDecimal(s string) values.Decimal {
+	d, err := values.ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
 // ValueEqual compares two values and considers two null or two NaNs
 // values to be equal to each other.
 //
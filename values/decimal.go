@@ -0,0 +1,263 @@
+package values
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow/go/arrow/decimal128"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+const (
+	// DecimalPrecision is the maximum number of digits of a Decimal.
+	DecimalPrecision = 38
+	// DecimalScale is the number of digits of a Decimal
+	// after the decimal point.
+	DecimalScale = 9
+)
+
+// Decimal is a fixed-precision decimal number.
+// It is stored as a 128-bit integer that holds the number
+// multiplied by 10^DecimalScale, which is also how arrow
+// stores the values of a decimal128 array.
+//
+// Arithmetic on decimals is exact until the result needs more than
+// DecimalScale digits after the decimal point, when it is rounded
+// half away from zero. A result with more than DecimalPrecision
+// digits is an error.
+type Decimal decimal128.Num
+
+var (
+	decimalUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(DecimalScale), nil)
+	decimalMax  = new(big.Int).Exp(big.NewInt(10), big.NewInt(DecimalPrecision), nil)
+	uint64Mask  = new(big.Int).SetUint64(math.MaxUint64)
+)
+
+// NewDecimalFromNum returns the decimal stored in an arrow decimal128 value.
+func NewDecimalFromNum(n decimal128.Num) Decimal {
+	return Decimal(n)
+}
+
+// NewDecimalFromInt returns the decimal with the value of an int.
+func NewDecimalFromInt(v int64) Decimal {
+	d, _ := decimalFromBig(new(big.Int).Mul(big.NewInt(v), decimalUnit))
+	return d
+}
+
+// NewDecimalFromUInt returns the decimal with the value of a uint.
+func NewDecimalFromUInt(v uint64) Decimal {
+	d, _ := decimalFromBig(new(big.Int).Mul(new(big.Int).SetUint64(v), decimalUnit))
+	return d
+}
+
+// NewDecimalFromFloat returns the decimal that is closest to
+// the shortest decimal representation of the float.
+func NewDecimalFromFloat(v float64) (Decimal, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Decimal{}, errors.Newf(codes.Invalid, "cannot convert %v to a decimal", v)
+	}
+	return ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// ParseDecimal parses a decimal such as -12.345.
+// Digits past DecimalScale are rounded half away from zero.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := s
+	neg := false
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		neg = digits[0] == '-'
+		digits = digits[1:]
+	}
+	whole, frac := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, frac = digits[:i], digits[i+1:]
+	}
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return Decimal{}, errors.Newf(codes.Invalid, "invalid decimal %q", s)
+	}
+
+	var round bool
+	if len(frac) > DecimalScale {
+		round = frac[DecimalScale] >= '5'
+		frac = frac[:DecimalScale]
+	}
+	frac += strings.Repeat("0", DecimalScale-len(frac))
+	n, ok := new(big.Int).SetString(whole+frac, 10)
+	if !ok {
+		return Decimal{}, errors.Newf(codes.Invalid, "invalid decimal %q", s)
+	}
+	if round {
+		n.Add(n, big.NewInt(1))
+	}
+	if neg {
+		n.Neg(n)
+	}
+	d, err := decimalFromBig(n)
+	if err != nil {
+		return Decimal{}, errors.Wrapf(err, codes.Inherit, "cannot parse decimal %q", s)
+	}
+	return d, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Num returns the decimal as an arrow decimal128 value.
+func (d Decimal) Num() decimal128.Num {
+	return decimal128.Num(d)
+}
+
+// Sign returns -1, 0 or 1 when the decimal is negative, zero or positive.
+func (d Decimal) Sign() int {
+	return decimal128.Num(d).Sign()
+}
+
+// Cmp returns -1, 0 or 1 when the decimal is less than,
+// equal to or greater than the other decimal.
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := decimal128.Num(d), decimal128.Num(other)
+	switch {
+	case a.HighBits() < b.HighBits():
+		return -1
+	case a.HighBits() > b.HighBits():
+		return 1
+	case a.LowBits() < b.LowBits():
+		return -1
+	case a.LowBits() > b.LowBits():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Equal reports whether the decimals are equal.
+func (d Decimal) Equal(other Decimal) bool {
+	return d == other
+}
+
+// Neg returns the negation of the decimal.
+func (d Decimal) Neg() Decimal {
+	n, _ := decimalFromBig(new(big.Int).Neg(d.big()))
+	return n
+}
+
+// Add returns the sum of the decimals.
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	return decimalFromBig(new(big.Int).Add(d.big(), other.big()))
+}
+
+// Sub returns the difference of the decimals.
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	return decimalFromBig(new(big.Int).Sub(d.big(), other.big()))
+}
+
+// Mul returns the product of the decimals.
+func (d Decimal) Mul(other Decimal) (Decimal, error) {
+	n := new(big.Int).Mul(d.big(), other.big())
+	return decimalFromBig(quoRound(n, decimalUnit))
+}
+
+// Div returns the quotient of the decimals.
+func (d Decimal) Div(other Decimal) (Decimal, error) {
+	if other.Sign() == 0 {
+		return Decimal{}, errors.New(codes.Invalid, "decimal division by zero")
+	}
+	n := new(big.Int).Mul(d.big(), decimalUnit)
+	return decimalFromBig(quoRound(n, other.big()))
+}
+
+// DivInt returns the quotient of the decimal and an int.
+func (d Decimal) DivInt(v int64) (Decimal, error) {
+	if v == 0 {
+		return Decimal{}, errors.New(codes.Invalid, "decimal division by zero")
+	}
+	return decimalFromBig(quoRound(d.big(), big.NewInt(v)))
+}
+
+// Int returns the integer part of the decimal.
+func (d Decimal) Int() (int64, error) {
+	n := new(big.Int).Quo(d.big(), decimalUnit)
+	if !n.IsInt64() {
+		return 0, errors.Newf(codes.Invalid, "decimal %s overflows int", d)
+	}
+	return n.Int64(), nil
+}
+
+// UInt returns the integer part of the decimal.
+func (d Decimal) UInt() (uint64, error) {
+	n := new(big.Int).Quo(d.big(), decimalUnit)
+	if !n.IsUint64() {
+		return 0, errors.Newf(codes.Invalid, "decimal %s overflows uint", d)
+	}
+	return n.Uint64(), nil
+}
+
+// Float returns the float that is closest to the decimal.
+func (d Decimal) Float() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats the decimal without trailing zeros after the decimal point.
+func (d Decimal) String() string {
+	n := d.big()
+	neg := n.Sign() < 0
+	digits := new(big.Int).Abs(n).String()
+	if len(digits) <= DecimalScale {
+		digits = strings.Repeat("0", DecimalScale-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-DecimalScale], strings.TrimRight(digits[len(digits)-DecimalScale:], "0")
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	b.WriteString(whole)
+	if frac != "" {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+func (d Decimal) big() *big.Int {
+	n := decimal128.Num(d)
+	v := big.NewInt(n.HighBits())
+	v.Lsh(v, 64)
+	return v.Add(v, new(big.Int).SetUint64(n.LowBits()))
+}
+
+func decimalFromBig(n *big.Int) (Decimal, error) {
+	if n.CmpAbs(decimalMax) >= 0 {
+		return Decimal{}, errors.Newf(codes.Invalid, "decimal overflows %d digits", DecimalPrecision)
+	}
+	// The bitwise operations of big.Int use two's complement
+	// so the high bits keep the sign of the number.
+	lo := new(big.Int).And(n, uint64Mask)
+	hi := new(big.Int).Rsh(n, 64)
+	return Decimal(decimal128.New(hi.Int64(), lo.Uint64())), nil
+}
+
+// quoRound returns x / y rounded half away from zero.
+func quoRound(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	if new(big.Int).Lsh(new(big.Int).Abs(r), 1).CmpAbs(y) >= 0 {
+		if x.Sign() == y.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,139 @@
+package values_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/influxdata/flux/values"
+)
+
+func TestParseDecimal(t *testing.T) {
+	for _, tt := range []struct {
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{s: "0", want: "0"},
+		{s: "12.345", want: "12.345"},
+		{s: "-12.345", want: "-12.345"},
+		{s: "+1.5", want: "1.5"},
+		{s: ".5", want: "0.5"},
+		{s: "5.", want: "5"},
+		{s: "1.000000000", want: "1"},
+		{s: "-0.000000001", want: "-0.000000001"},
+		{s: "0.0000000015", want: "0.000000002"},
+		{s: "-0.0000000015", want: "-0.000000002"},
+		{s: "0.0000000014", want: "0.000000001"},
+		{s: "99999999999999999999999999999.999999999", want: "99999999999999999999999999999.999999999"},
+		{s: "100000000000000000000000000000", wantErr: true},
+		{s: "", wantErr: true},
+		{s: "-", wantErr: true},
+		{s: ".", wantErr: true},
+		{s: "1e5", wantErr: true},
+		{s: "1.2.3", wantErr: true},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			d, err := values.ParseDecimal(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", d)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("unexpected decimal: want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := mustParseDecimal("-7.5"), mustParseDecimal("2")
+	for _, tt := range []struct {
+		name string
+		fn   func() (values.Decimal, error)
+		want string
+	}{
+		{name: "add", fn: func() (values.Decimal, error) { return a.Add(b) }, want: "-5.5"},
+		{name: "sub", fn: func() (values.Decimal, error) { return a.Sub(b) }, want: "-9.5"},
+		{name: "mul", fn: func() (values.Decimal, error) { return a.Mul(b) }, want: "-15"},
+		{name: "div", fn: func() (values.Decimal, error) { return a.Div(b) }, want: "-3.75"},
+		{name: "div int", fn: func() (values.Decimal, error) { return a.DivInt(4) }, want: "-1.875"},
+		{name: "div round", fn: func() (values.Decimal, error) { return b.DivInt(-3) }, want: "-0.666666667"},
+		{name: "mul round", fn: func() (values.Decimal, error) {
+			return mustParseDecimal("0.000000005").Mul(mustParseDecimal("0.5"))
+		}, want: "0.000000003"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := tt.fn()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := d.String(); got != tt.want {
+				t.Errorf("unexpected decimal: want %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := a.Div(values.Decimal{}); err == nil {
+		t.Error("expected division by zero to fail")
+	}
+	max := mustParseDecimal("99999999999999999999999999999")
+	if _, err := max.Mul(mustParseDecimal("10")); err == nil {
+		t.Error("expected multiplication to overflow")
+	}
+}
+
+func TestDecimal_Conversions(t *testing.T) {
+	d := values.NewDecimalFromInt(math.MinInt64)
+	if got, err := d.Int(); err != nil || got != math.MinInt64 {
+		t.Errorf("unexpected int: want %d, got %d (%v)", int64(math.MinInt64), got, err)
+	}
+	if _, err := d.UInt(); err == nil {
+		t.Error("expected a negative decimal to overflow uint")
+	}
+	d = values.NewDecimalFromUInt(math.MaxUint64)
+	if got, err := d.UInt(); err != nil || got != math.MaxUint64 {
+		t.Errorf("unexpected uint: want %d, got %d (%v)", uint64(math.MaxUint64), got, err)
+	}
+	if _, err := d.Int(); err == nil {
+		t.Error("expected decimal to overflow int")
+	}
+
+	d, err := values.NewDecimalFromFloat(-2.675)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "-2.675", d.String(); want != got {
+		t.Errorf("unexpected decimal: want %s, got %s", want, got)
+	}
+	if want, got := -2.675, d.Float(); want != got {
+		t.Errorf("unexpected float: want %v, got %v", want, got)
+	}
+	if got, err := d.Int(); err != nil || got != -2 {
+		t.Errorf("unexpected int: want -2, got %d (%v)", got, err)
+	}
+	if _, err := values.NewDecimalFromFloat(math.NaN()); err == nil {
+		t.Error("expected NaN to fail")
+	}
+}
+
+func TestDecimal_Cmp(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{a: "1", b: "2", want: -1},
+		{a: "-1", b: "1", want: -1},
+		{a: "-1", b: "-2", want: 1},
+		{a: "-0.000000001", b: "0", want: -1},
+		{a: "18446744073.709551616", b: "18446744073.709551615", want: 1},
+		{a: "1.5", b: "1.50", want: 0},
+	} {
+		if got := mustParseDecimal(tt.a).Cmp(mustParseDecimal(tt.b)); got != tt.want {
+			t.Errorf("unexpected comparison of %s and %s: want %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
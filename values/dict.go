@@ -103,6 +103,9 @@ func (d emptyDict) Time() Time {
 func (d emptyDict) Duration() Duration {
 	panic(UnexpectedKind(semantic.Dictionary, semantic.Duration))
 }
+func (d emptyDict) Decimal() Decimal {
+	panic(UnexpectedKind(semantic.Dictionary, semantic.Decimal))
+}
 func (d emptyDict) Regexp() *regexp.Regexp {
 	panic(UnexpectedKind(semantic.Dictionary, semantic.Regexp))
 }
@@ -195,6 +198,9 @@ func (d dict) Time() Time {
 func (d dict) Duration() Duration {
 	panic(UnexpectedKind(semantic.Dictionary, semantic.Duration))
 }
+func (d dict) Decimal() Decimal {
+	panic(UnexpectedKind(semantic.Dictionary, semantic.Decimal))
+}
 func (d dict) Regexp() *regexp.Regexp {
 	panic(UnexpectedKind(semantic.Dictionary, semantic.Regexp))
 }
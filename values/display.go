@@ -63,6 +63,9 @@ func display(w *bufio.Writer, v Value, indent int) (err error) {
 	case semantic.Duration:
 		_, err = w.WriteString(v.Duration().String())
 		return
+	case semantic.Decimal:
+		_, err = w.WriteString(v.Decimal().String())
+		return
 	case semantic.Regexp:
 		_, err = w.WriteString(v.Regexp().String())
 		return
@@ -75,6 +75,9 @@ func (f *function) Time() Time {
 func (f *function) Duration() Duration {
 	panic(UnexpectedKind(semantic.Function, semantic.Duration))
 }
+func (f *function) Decimal() Decimal {
+	panic(UnexpectedKind(semantic.Function, semantic.Decimal))
+}
 
 func (f *function) Regexp() *regexp.Regexp {
 	panic(UnexpectedKind(semantic.Function, semantic.Regexp))
@@ -216,6 +216,9 @@ func (o *object) Time() Time {
 func (o *object) Duration() Duration {
 	panic(UnexpectedKind(semantic.Object, semantic.Duration))
 }
+func (o *object) Decimal() Decimal {
+	panic(UnexpectedKind(semantic.Object, semantic.Decimal))
+}
 func (o *object) Regexp() *regexp.Regexp {
 	panic(UnexpectedKind(semantic.Object, semantic.Regexp))
 }
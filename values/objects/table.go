@@ -123,6 +123,9 @@ func (t *Table) Time() values.Time {
 func (t *Table) Duration() values.Duration {
 	panic(values.UnexpectedKind(semantic.Object, semantic.Duration))
 }
+func (t *Table) Decimal() values.Decimal {
+	panic(values.UnexpectedKind(semantic.Object, semantic.Decimal))
+}
 
 func (t *Table) Regexp() *regexp.Regexp {
 	panic(values.UnexpectedKind(semantic.Object, semantic.Regexp))
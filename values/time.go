@@ -170,6 +170,82 @@ func (t Time) Time() time.Time {
 	return time.Unix(0, int64(t)).UTC()
 }
 
+// ToLocal returns the time whose UTC wall clock reading matches
+// the wall clock reading of t in the given location.
+// This allows calendar arithmetic that is performed in UTC
+// to be performed in a different time zone.
+// A nil location is treated as UTC.
+func (t Time) ToLocal(loc *time.Location) Time {
+	if loc == nil || loc == time.UTC {
+		return t
+	}
+	_, offset := t.Time().In(loc).Zone()
+	return t + Time(int64(offset)*int64(time.Second))
+}
+
+// FromLocal is the inverse of ToLocal. It interprets the UTC
+// wall clock reading of t as a wall clock reading in the given
+// location and returns the corresponding time.
+//
+// Wall clock readings that are skipped by a daylight saving
+// transition are moved forward by the length of the transition
+// and readings that occur twice resolve to the first occurrence.
+// A nil location is treated as UTC.
+func (t Time) FromLocal(loc *time.Location) Time {
+	if loc == nil || loc == time.UTC {
+		return t
+	}
+	// The offset used for the wall clock reading is the offset
+	// from either before or after a nearby transition.
+	// The time package does not guarantee which one is chosen
+	// for ambiguous readings so we determine it ourselves.
+	offsetAt := func(t Time) Time {
+		_, offset := t.Time().In(loc).Zone()
+		return Time(int64(offset) * int64(time.Second))
+	}
+	const day = Time(24 * time.Hour)
+	before, after := offsetAt(t-day), offsetAt(t+day)
+	if before == after {
+		return t - before
+	}
+	first, second := t-before, t-after
+	if second < first {
+		first, second = second, first
+	}
+	if offsetAt(first) == t-first {
+		return first
+	} else if offsetAt(second) == t-second {
+		return second
+	}
+	// The wall clock reading was skipped so use the offset from
+	// before the transition which moves it forward.
+	return t - before
+}
+
+// AddInLocation adds the duration to t using the calendar of the given location.
+// The months and whole days of the duration are added to the wall clock
+// reading of t in the location so the time of day is kept when a daylight
+// saving transition is crossed. The rest of the duration is added as
+// elapsed time. A nil location is treated as UTC.
+func (t Time) AddInLocation(d Duration, loc *time.Location) Time {
+	if loc == nil || loc == time.UTC {
+		return t.Add(d)
+	}
+	const day = int64(24 * time.Hour)
+	calendar := Duration{
+		months:   d.months,
+		nsecs:    d.nsecs / day * day,
+		negative: d.negative,
+	}
+	if !calendar.IsZero() {
+		t = t.ToLocal(loc).Add(calendar).FromLocal(loc)
+	}
+	return t.Add(Duration{
+		nsecs:    d.nsecs % day,
+		negative: d.negative,
+	})
+}
+
 // Mul will multiply the Duration by a scalar.
 // This multiplies each component of the vector.
 func (d Duration) Mul(scale int) Duration {
@@ -224,6 +300,19 @@ func (d Duration) IsMixed() bool {
 func (d Duration) Months() int64      { return d.months }
 func (d Duration) Nanoseconds() int64 { return d.nsecs }
 
+// FixedNanoseconds returns the signed number of nanoseconds of the duration.
+// A month does not have a fixed number of nanoseconds so it is an error
+// if the duration has months.
+func (d Duration) FixedNanoseconds() (int64, error) {
+	if d.months != 0 {
+		return 0, errors.Newf(codes.Invalid, "duration %v has months and cannot be converted to a fixed number of nanoseconds", d)
+	}
+	if d.negative {
+		return -d.nsecs, nil
+	}
+	return d.nsecs, nil
+}
+
 // Normalize will normalize the duration within the interval.
 // It will ensure that the output duration is the smallest positive
 // duration that is the equivalent of the current duration.
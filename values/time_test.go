@@ -245,6 +245,157 @@ func TestTime_Add(t *testing.T) {
 	}
 }
 
+func TestTime_Local(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		t     string
+		loc   *time.Location
+		local string
+		// from is the time returned when converting local back.
+		// It is only set when the conversion is not reversible.
+		from string
+	}{
+		{
+			name:  "utc",
+			t:     "2021-03-01T10:00:00Z",
+			loc:   time.UTC,
+			local: "2021-03-01T10:00:00Z",
+		},
+		{
+			name:  "fixed",
+			t:     "2021-03-01T10:00:00Z",
+			loc:   time.FixedZone("", -5*60*60),
+			local: "2021-03-01T05:00:00Z",
+		},
+		{
+			name:  "standard time",
+			t:     "2021-03-01T10:00:00Z",
+			loc:   berlin,
+			local: "2021-03-01T11:00:00Z",
+		},
+		{
+			name:  "daylight saving time",
+			t:     "2021-07-01T10:00:00Z",
+			loc:   berlin,
+			local: "2021-07-01T12:00:00Z",
+		},
+		{
+			name:  "first occurrence of repeated hour",
+			t:     "2021-10-31T00:30:00Z",
+			loc:   berlin,
+			local: "2021-10-31T02:30:00Z",
+		},
+		{
+			name:  "second occurrence of repeated hour",
+			t:     "2021-10-31T01:30:00Z",
+			loc:   berlin,
+			local: "2021-10-31T02:30:00Z",
+			from:  "2021-10-31T00:30:00Z",
+		},
+		{
+			name:  "after skipped hour",
+			t:     "2021-03-28T01:30:00Z",
+			loc:   berlin,
+			local: "2021-03-28T03:30:00Z",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			local := mustParseTime(tt.t).ToLocal(tt.loc)
+			if want := mustParseTime(tt.local); local != want {
+				t.Fatalf("unexpected local time -want/+got:\n\t- %s\n\t+ %s", want, local)
+			}
+			from := tt.from
+			if from == "" {
+				from = tt.t
+			}
+			if got, want := local.FromLocal(tt.loc), mustParseTime(from); got != want {
+				t.Fatalf("unexpected time -want/+got:\n\t- %s\n\t+ %s", want, got)
+			}
+		})
+	}
+
+	// A wall clock time that was skipped is moved forward.
+	skipped := mustParseTime("2021-03-28T02:30:00Z").FromLocal(berlin)
+	if want := mustParseTime("2021-03-28T01:30:00Z"); skipped != want {
+		t.Fatalf("unexpected time for skipped wall clock -want/+got:\n\t- %s\n\t+ %s", want, skipped)
+	}
+}
+
+func TestTime_AddInLocation(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		t    string
+		d    string
+		loc  *time.Location
+		want string
+	}{
+		{
+			name: "utc",
+			t:    "2021-01-31T18:00:00Z",
+			d:    "1mo",
+			loc:  time.UTC,
+			want: "2021-02-28T18:00:00Z",
+		},
+		{
+			name: "month",
+			t:    "2021-01-31T18:00:00Z",
+			d:    "1mo",
+			loc:  chicago,
+			want: "2021-02-28T18:00:00Z",
+		},
+		{
+			name: "month across midnight utc",
+			t:    "2021-02-01T03:00:00Z",
+			d:    "1mo",
+			loc:  chicago,
+			want: "2021-03-01T03:00:00Z",
+		},
+		{
+			name: "negative month across daylight saving time",
+			t:    "2021-03-31T05:00:00Z",
+			d:    "-1mo",
+			loc:  chicago,
+			want: "2021-02-28T06:00:00Z",
+		},
+		{
+			name: "day across daylight saving time",
+			t:    "2021-03-13T18:00:00Z",
+			d:    "1d",
+			loc:  chicago,
+			want: "2021-03-14T17:00:00Z",
+		},
+		{
+			name: "day and hour across daylight saving time",
+			t:    "2021-03-13T18:00:00Z",
+			d:    "1d1h",
+			loc:  chicago,
+			want: "2021-03-14T18:00:00Z",
+		},
+		{
+			name: "hour across daylight saving time",
+			t:    "2021-03-14T07:30:00Z",
+			d:    "1h",
+			loc:  chicago,
+			want: "2021-03-14T08:30:00Z",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustParseTime(tt.t).AddInLocation(mustParseDuration(tt.d), tt.loc)
+			if want := mustParseTime(tt.want); got != want {
+				t.Fatalf("unexpected time -want/+got:\n\t- %s\n\t+ %s", want, got)
+			}
+		})
+	}
+}
+
 func TestParseDuration(t *testing.T) {
 	for _, tt := range []struct {
 		s    string
@@ -481,3 +632,40 @@ func mustParseDuration(s string) Duration {
 	}
 	return d
 }
+
+func TestDuration_FixedNanoseconds(t *testing.T) {
+	for _, tt := range []struct {
+		d       Duration
+		want    int64
+		wantErr bool
+	}{
+		{
+			d:    ConvertDurationNsecs(90 * time.Minute),
+			want: int64(90 * time.Minute),
+		},
+		{
+			d:    ConvertDurationNsecs(-5 * time.Millisecond),
+			want: int64(-5 * time.Millisecond),
+		},
+		{
+			d:       ConvertDurationMonths(1),
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.d.String(), func(t *testing.T) {
+			got, err := tt.d.FixedNanoseconds()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected nanoseconds -want/+got:\n\t- %d\n\t+ %d", tt.want, got)
+			}
+		})
+	}
+}
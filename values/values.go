@@ -28,6 +28,7 @@ type Value interface {
 	Bool() bool
 	Time() Time
 	Duration() Duration
+	Decimal() Decimal
 	Regexp() *regexp.Regexp
 	Array() Array
 	Object() Object
@@ -79,6 +80,10 @@ func (v value) Duration() Duration {
 	CheckKind(v.t.Nature(), semantic.Duration)
 	return v.v.(Duration)
 }
+func (v value) Decimal() Decimal {
+	CheckKind(v.t.Nature(), semantic.Decimal)
+	return v.v.(Decimal)
+}
 func (v value) Regexp() *regexp.Regexp {
 	CheckKind(v.t.Nature(), semantic.Regexp)
 	return v.v.(*regexp.Regexp)
@@ -125,6 +130,8 @@ func (v value) Equal(r Value) bool {
 		return v.Time() == r.Time()
 	case semantic.Duration:
 		return v.Duration() == r.Duration()
+	case semantic.Decimal:
+		return v.Decimal() == r.Decimal()
 	case semantic.Regexp:
 		return v.Regexp().String() == r.Regexp().String()
 	case semantic.Object:
@@ -177,6 +184,8 @@ func Unwrap(v Value) interface{} {
 		return v.Time()
 	case semantic.Duration:
 		return v.Duration()
+	case semantic.Decimal:
+		return v.Decimal()
 	case semantic.Regexp:
 		return v.Regexp()
 	case semantic.Array:
@@ -235,6 +244,8 @@ func New(v interface{}) Value {
 		return NewTime(v)
 	case Duration:
 		return NewDuration(v)
+	case Decimal:
+		return NewDecimal(v)
 	case *regexp.Regexp:
 		return NewRegexp(v)
 	default:
@@ -291,6 +302,12 @@ func NewDuration(v Duration) Value {
 		v: v,
 	}
 }
+func NewDecimal(v Decimal) Value {
+	return value{
+		t: semantic.BasicDecimal,
+		v: v,
+	}
+}
 func NewRegexp(v *regexp.Regexp) Value {
 	return value{
 		t: semantic.BasicRegexp,
@@ -313,6 +330,8 @@ func Stringify(v Value) (Value, error) {
 		return NewString(val.(Time).String()), nil
 	case semantic.Duration:
 		return NewString(val.(Duration).String()), nil
+	case semantic.Decimal:
+		return NewString(val.(Decimal).String()), nil
 	case semantic.String:
 		return v, nil
 	}
@@ -367,6 +386,7 @@ func (n null) Float() float64          { panic(UnexpectedKind(semantic.Invalid,
 func (n null) Bool() bool              { panic(UnexpectedKind(semantic.Invalid, semantic.Bool)) }
 func (n null) Time() Time              { panic(UnexpectedKind(semantic.Invalid, semantic.Time)) }
 func (n null) Duration() Duration      { panic(UnexpectedKind(semantic.Invalid, semantic.Duration)) }
+func (n null) Decimal() Decimal        { panic(UnexpectedKind(semantic.Invalid, semantic.Decimal)) }
 func (n null) Regexp() *regexp.Regexp  { panic(UnexpectedKind(semantic.Invalid, semantic.Regexp)) }
 func (n null) Array() Array            { panic(UnexpectedKind(semantic.Invalid, semantic.Array)) }
 func (n null) Object() Object          { panic(UnexpectedKind(semantic.Invalid, semantic.Object)) }